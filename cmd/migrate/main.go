@@ -0,0 +1,106 @@
+package main
+
+import (
+	"4h-recordbook-backend/internal/config"
+	"4h-recordbook-backend/internal/utils"
+	"4h-recordbook-backend/pkg/db"
+	"4h-recordbook-backend/pkg/log"
+	"bufio"
+	"context"
+	"flag"
+	"os"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// rewrites stored documents for the users listed one ID per line in the users file, or on stdin when no file is given.
+// cosmos queries are scoped to a single user's partition, so the user IDs have to be supplied
+func main() {
+
+	debug := flag.Bool("d", false, "enable debug mode")
+	logFile := flag.String("l", "", "log file")
+	usersFile := flag.String("u", "", "file of user IDs to migrate, one per line. defaults to stdin")
+	flag.Parse()
+
+	logOptions := log.LoggerOptions{
+		Level:      zap.NewAtomicLevelAt(zap.InfoLevel),
+		OutputFile: *logFile,
+	}
+	if *debug {
+		logOptions.Level = zap.NewAtomicLevelAt(zap.DebugLevel)
+	}
+
+	logger, err := log.New(logOptions)
+	if err != nil {
+		panic(err)
+	}
+	defer logger.Sync()
+
+	cfg, err := config.New(logger)
+	if err != nil {
+		panic(err)
+	}
+
+	dbInstance, err := db.New(logger, cfg)
+	if err != nil {
+		panic(err)
+	}
+
+	timeZone, err := time.LoadLocation(cfg.TimeZone)
+	if err != nil {
+		panic(err)
+	}
+
+	programYear := utils.NewProgramYear(cfg.ProgramYear.StartMonth, cfg.ProgramYear.StartDay)
+
+	input := os.Stdin
+	if *usersFile != "" {
+		input, err = os.Open(*usersFile)
+		if err != nil {
+			panic(err)
+		}
+		defer input.Close()
+	}
+
+	scanner := bufio.NewScanner(input)
+	for scanner.Scan() {
+
+		userID := strings.TrimSpace(scanner.Text())
+		if userID == "" {
+			continue
+		}
+
+		migrated, err := dbInstance.MigrateMoney(context.Background(), userID)
+		if err != nil {
+			logger.Errorf("Failed to migrate money for user %s after %d documents: %v", userID, migrated, err)
+			continue
+		}
+
+		logger.Infof("Migrated money in %d documents for user %s", migrated, userID)
+
+		migrated, err = dbInstance.MigrateDates(context.Background(), userID, timeZone)
+		if err != nil {
+			logger.Errorf("Failed to migrate dates for user %s after %d documents: %v", userID, migrated, err)
+			continue
+		}
+
+		logger.Infof("Migrated dates in %d documents for user %s", migrated, userID)
+
+		migrated, err = dbInstance.MigrateYears(context.Background(), userID, programYear)
+		if err != nil {
+			logger.Errorf("Failed to migrate years for user %s after %d documents: %v", userID, migrated, err)
+			continue
+		}
+
+		logger.Infof("Migrated years in %d documents for user %s", migrated, userID)
+
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		panic(err)
+	}
+
+}
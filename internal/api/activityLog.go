@@ -0,0 +1,348 @@
+package api
+
+import (
+	"4h-recordbook-backend/internal/auth"
+	"4h-recordbook-backend/internal/utils"
+	"4h-recordbook-backend/pkg/db"
+	"strconv"
+
+	"github.com/beevik/guid"
+	"github.com/gin-gonic/gin"
+)
+
+type GetActivityLogsOutput struct {
+	ActivityLogs []db.ActivityLog `json:"activity_logs"`
+	Next         string           `json:"next"`
+}
+
+type GetActivityLogOutput struct {
+	ActivityLog db.ActivityLog `json:"activity_log"`
+}
+
+type UpsertActivityLogInput struct {
+	Date        string   `json:"date" validate:"required"`
+	Title       string   `json:"title" validate:"required"`
+	Description string   `json:"description" validate:"required"`
+	Hours       *float64 `json:"hours" validate:"required,min=0"`
+	ProjectID   string   `json:"project_id" validate:"required"`
+}
+
+type UpsertActivityLogOutput GetActivityLogOutput
+
+// GetActivityLogs godoc
+// @Summary Get activity logs by project
+// @Description Gets all of a user's activity logs given a project ID
+// @Tags Activity Log
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param projectID path string true "Project ID"
+// @Param page query int false "Page number, default 0"
+// @Param per_page query int false "Max number of items to return. Can be [1-200], default 100"
+// @Param sort_by_newest query bool false "Sort results by most recently added, default false"
+// @Success 200 {object} api.GetActivityLogsOutput
+// @Failure 400
+// @Failure 401
+// @Router /project/{projectID}/activity-log [get]
+func (e *env) getActivityLogs(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	projectID := c.Param("projectID")
+
+	var output GetActivityLogsOutput
+
+	paginationOptions := db.PaginationOptions{
+		Page:         c.GetInt(CONTEXT_KEY_PAGE),
+		PerPage:      c.GetInt(CONTEXT_KEY_PER_PAGE),
+		SortByNewest: c.GetBool(CONTEXT_KEY_SORT_BY_NEWEST),
+	}
+
+	output.ActivityLogs, err = e.db.GetActivityLogsByProject(c.Request.Context(), principal.UserID, projectID, paginationOptions)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	if len(output.ActivityLogs) == paginationOptions.PerPage {
+
+		queryParamsMap := make(map[string]string)
+		queryParamsMap[CONTEXT_KEY_PAGE] = strconv.Itoa(paginationOptions.Page + 1)
+		queryParamsMap[CONTEXT_KEY_PER_PAGE] = strconv.Itoa(paginationOptions.PerPage)
+		queryParamsMap[CONTEXT_KEY_SORT_BY_NEWEST] = strconv.FormatBool(paginationOptions.SortByNewest)
+
+		nextUrlInput := utils.NextUrlInput{
+			Context:     c,
+			QueryParams: queryParamsMap,
+		}
+
+		output.Next = utils.BuildNextUrl(nextUrlInput)
+	}
+
+	c.JSON(200, output)
+
+}
+
+// GetActivityLog godoc
+// @Summary Get an activity log
+// @Description Get a user's activity log by ID
+// @Tags Activity Log
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param activityLogID path string true "Activity Log ID"
+// @Success 200 {object} api.GetActivityLogOutput
+// @Failure 401
+// @Failure 404
+// @Router /activity-log/{activityLogID} [get]
+func (e *env) getActivityLog(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	activityLogID := c.Param("activityLogID")
+
+	var output GetActivityLogOutput
+
+	output.ActivityLog, err = e.db.GetActivityLogByID(c.Request.Context(), principal.UserID, activityLogID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(200, output)
+
+}
+
+// AddActivityLog godoc
+// @Summary Adds an activity log
+// @Description Adds an activity log to a user's personal records
+// @Tags Activity Log
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param UpsertActivityLogInput body api.UpsertActivityLogInput true "Activity Log information"
+// @Success 201 {object} api.UpsertActivityLogOutput
+// @Failure 400
+// @Failure 401
+// @Failure 404
+// @Router /activity-log [post]
+func (e *env) addActivityLog(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	var input UpsertActivityLogInput
+	err = c.BindJSON(&input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrBadRequest,
+		})
+		return
+	}
+
+	err = e.validator.Struct(input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrMissingFields,
+		})
+		return
+	}
+
+	location := e.userLocation(c.Request.Context(), principal.UserID)
+
+	date, err := utils.StringToDate(input.Date, location)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrBadDate,
+		})
+		return
+	}
+
+	if !e.checkProjectRecordPage(c, principal.UserID, input.ProjectID, db.RECORD_PAGE_ACTIVITY_LOGS) {
+		return
+	}
+
+	g := guid.New()
+	timestamp := utils.TimeNow()
+
+	activityLog := db.ActivityLog{
+		ID:          g.String(),
+		Date:        date.String(),
+		Title:       input.Title,
+		Description: input.Description,
+		Hours:       *input.Hours,
+		ProjectID:   input.ProjectID,
+		UserID:      principal.UserID,
+		GenericDatabaseInfo: db.GenericDatabaseInfo{
+			Created: timestamp.String(),
+			Updated: timestamp.String(),
+		},
+	}
+
+	var output UpsertActivityLogOutput
+
+	output.ActivityLog, err = e.db.UpsertActivityLog(c.Request.Context(), activityLog)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(201, output)
+
+}
+
+// UpdateActivityLog godoc
+// @Summary Update an activity log
+// @Description Updates a user's activity log information
+// @Tags Activity Log
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param activityLogID path string true "Activity Log ID"
+// @Param UpsertActivityLogInput body api.UpsertActivityLogInput true "Activity Log information"
+// @Success 200 {object} api.UpsertActivityLogOutput
+// @Failure 400
+// @Failure 401
+// @Failure 404
+// @Router /activity-log/{activityLogID} [put]
+func (e *env) updateActivityLog(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	var input UpsertActivityLogInput
+	err = c.BindJSON(&input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrBadRequest,
+		})
+		return
+	}
+
+	err = e.validator.Struct(input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrMissingFields,
+		})
+		return
+	}
+
+	location := e.userLocation(c.Request.Context(), principal.UserID)
+
+	date, err := utils.StringToDate(input.Date, location)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrBadDate,
+		})
+		return
+	}
+
+	activityLogID := c.Param("activityLogID")
+
+	activityLog, err := e.db.GetActivityLogByID(c.Request.Context(), principal.UserID, activityLogID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	timestamp := utils.TimeNow()
+
+	updatedActivityLog := db.ActivityLog{
+		ID:          activityLog.ID,
+		Date:        date.String(),
+		Title:       input.Title,
+		Description: input.Description,
+		Hours:       *input.Hours,
+		ProjectID:   activityLog.ProjectID,
+		UserID:      principal.UserID,
+		GenericDatabaseInfo: db.GenericDatabaseInfo{
+			Created: activityLog.Created,
+			Updated: timestamp.String(),
+		},
+	}
+
+	var output UpsertActivityLogOutput
+
+	output.ActivityLog, err = e.db.UpsertActivityLog(c.Request.Context(), updatedActivityLog)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(200, output)
+
+}
+
+// DeleteActivityLog godoc
+// @Summary Removes an activity log
+// @Description Deletes a user's activity log given the activity log ID
+// @Tags Activity Log
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param activityLogID path string true "Activity Log ID"
+// @Success 204
+// @Failure 401
+// @Failure 404
+// @Router /activity-log/{activityLogID} [delete]
+func (e *env) deleteActivityLog(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	activityLogID := c.Param("activityLogID")
+
+	response, err := e.db.RemoveActivityLog(c.Request.Context(), principal.UserID, activityLogID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(204, response)
+
+}
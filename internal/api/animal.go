@@ -1,9 +1,12 @@
 package api
 
 import (
+	"4h-recordbook-backend/internal/auth"
 	"4h-recordbook-backend/internal/utils"
 	"4h-recordbook-backend/pkg/db"
+	"context"
 	"strconv"
+	"strings"
 
 	"github.com/beevik/guid"
 	"github.com/gin-gonic/gin"
@@ -19,17 +22,28 @@ type GetAnimalOutput struct {
 }
 
 type UpsertAnimalInput struct {
-	Name         string   `json:"name" validate:"required"`
-	Species      string   `json:"species" validate:"required"`
-	BirthDate    string   `json:"birth_date" validate:"required"`
-	PurchaseDate string   `json:"purchase_date" validate:"required"`
-	SireBreed    string   `json:"sire_breed" validate:"required"`
-	DamBreed     string   `json:"dam_breed" validate:"required"`
-	AnimalCost   *float64 `json:"animal_cost" validate:"required"`
-	SalePrice    *float64 `json:"sale_price" validate:"required"`
-	YieldGrade   string   `json:"yield_grade" validate:"required"`
-	QualityGrade string   `json:"quality_grade" validate:"required"`
-	ProjectID    string   `json:"project_id" validate:"required"`
+	Name         string       `json:"name" validate:"required"`
+	Species      string       `json:"species" validate:"required"`
+	BirthDate    string       `json:"birth_date" validate:"required"`
+	PurchaseDate string       `json:"purchase_date" validate:"required"`
+	SireBreed    string       `json:"sire_breed" validate:"required"`
+	DamBreed     string       `json:"dam_breed" validate:"required"`
+	AnimalCost   *utils.Money `json:"animal_cost" validate:"required"`
+	SalePrice    *utils.Money `json:"sale_price" validate:"required"`
+	YieldGrade   string       `json:"yield_grade" validate:"required"`
+	QualityGrade string       `json:"quality_grade" validate:"required"`
+	ProjectID    string       `json:"project_id" validate:"required"`
+	// leaving identifiers out of an update keeps the animal's existing identifiers
+	Identifiers []AnimalIdentifierInput `json:"identifiers" validate:"dive"`
+}
+
+type AnimalIdentifierInput struct {
+	Type  string `json:"type" validate:"required,oneof=ear_tag tattoo scrapie_tag premises_id rfid"`
+	Value string `json:"value" validate:"required"`
+}
+
+type LookupAnimalOutput struct {
+	Animals []db.Animal `json:"animals"`
 }
 
 type UpdateRateOfGainInput struct {
@@ -37,6 +51,15 @@ type UpdateRateOfGainInput struct {
 	BeginningDate   string   `json:"beginning_date" validate:"required"`
 	EndWeight       *float64 `json:"end_weight" validate:"required"`
 	EndDate         string   `json:"end_date" validate:"required"`
+	WeightUnit      string   `json:"weight_unit"`
+}
+
+type UpdateAnimalStatusInput struct {
+	Status          string       `json:"status" validate:"required,oneof=active sold retained deceased transferred"`
+	DispositionDate string       `json:"disposition_date"`
+	Buyer           string       `json:"buyer"`
+	Auction         string       `json:"auction"`
+	SalePrice       *utils.Money `json:"sale_price"`
 }
 
 type UpsertAnimalOutput GetAnimalOutput
@@ -49,6 +72,7 @@ type UpsertAnimalOutput GetAnimalOutput
 // @Produce json
 // @Security ApiKeyAuth
 // @Param projectID path string true "Project ID"
+// @Param status query string false "Only return animals with this status (active, sold, retained, deceased, transferred)"
 // @Param page query int false "Page number, default 0"
 // @Param per_page query int false "Max number of items to return. Can be [1-200], default 100"
 // @Param sort_by_newest query bool false "Sort results by most recently added, default false"
@@ -58,7 +82,7 @@ type UpsertAnimalOutput GetAnimalOutput
 // @Router /project/{projectID}/animal [get]
 func (e *env) getAnimals(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -68,6 +92,14 @@ func (e *env) getAnimals(c *gin.Context) {
 
 	projectID := c.Param("projectID")
 
+	status := c.Query("status")
+	if _, ok := db.AnimalStatusTransitions[status]; status != "" && !ok {
+		c.JSON(400, gin.H{
+			"message": ErrBadAnimalStatus,
+		})
+		return
+	}
+
 	var output GetAnimalsOutput
 
 	paginationOptions := db.PaginationOptions{
@@ -76,7 +108,7 @@ func (e *env) getAnimals(c *gin.Context) {
 		SortByNewest: c.GetBool(CONTEXT_KEY_SORT_BY_NEWEST),
 	}
 
-	output.Animals, err = e.db.GetAnimalsByProject(c.Request.Context(), claims.ID, projectID, paginationOptions)
+	output.Animals, err = e.db.GetAnimalsByProject(c.Request.Context(), principal.UserID, projectID, status, paginationOptions)
 	if err != nil {
 		e.logger.Info(err)
 		response := InterpretCosmosError(err)
@@ -92,6 +124,9 @@ func (e *env) getAnimals(c *gin.Context) {
 		queryParamsMap[CONTEXT_KEY_PAGE] = strconv.Itoa(paginationOptions.Page + 1)
 		queryParamsMap[CONTEXT_KEY_PER_PAGE] = strconv.Itoa(paginationOptions.PerPage)
 		queryParamsMap[CONTEXT_KEY_SORT_BY_NEWEST] = strconv.FormatBool(paginationOptions.SortByNewest)
+		if status != "" {
+			queryParamsMap["status"] = status
+		}
 
 		nextUrlInput := utils.NextUrlInput{
 			Context:     c,
@@ -101,6 +136,8 @@ func (e *env) getAnimals(c *gin.Context) {
 		output.Next = utils.BuildNextUrl(nextUrlInput)
 	}
 
+	output.Animals = localizeAnimals(output.Animals, e.preferredMassUnit(c.Request.Context(), principal.UserID))
+
 	c.JSON(200, output)
 
 }
@@ -119,7 +156,7 @@ func (e *env) getAnimals(c *gin.Context) {
 // @Router /animal/{animalID} [get]
 func (e *env) getAnimal(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -131,7 +168,7 @@ func (e *env) getAnimal(c *gin.Context) {
 
 	var output GetAnimalOutput
 
-	output.Animal, err = e.db.GetAnimalByID(c.Request.Context(), claims.ID, animalID)
+	output.Animal, err = e.db.GetAnimalByID(c.Request.Context(), principal.UserID, animalID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -140,6 +177,8 @@ func (e *env) getAnimal(c *gin.Context) {
 		return
 	}
 
+	output.Animal = localizeAnimal(output.Animal, e.preferredMassUnit(c.Request.Context(), principal.UserID))
+
 	c.JSON(200, output)
 
 }
@@ -155,10 +194,11 @@ func (e *env) getAnimal(c *gin.Context) {
 // @Success 201 {object} api.UpsertAnimalOutput
 // @Failure 400
 // @Failure 401
+// @Failure 404
 // @Router /animal [post]
 func (e *env) addAnimal(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -183,7 +223,9 @@ func (e *env) addAnimal(c *gin.Context) {
 		return
 	}
 
-	birthDate, err := utils.StringToTimestamp(input.BirthDate)
+	location := e.userLocation(c.Request.Context(), principal.UserID)
+
+	birthDate, err := utils.StringToDate(input.BirthDate, location)
 	if err != nil {
 		c.JSON(400, gin.H{
 			"message": ErrBadDate,
@@ -191,7 +233,7 @@ func (e *env) addAnimal(c *gin.Context) {
 		return
 	}
 
-	purchaseDate, err := utils.StringToTimestamp(input.PurchaseDate)
+	purchaseDate, err := utils.StringToDate(input.PurchaseDate, location)
 	if err != nil {
 		c.JSON(400, gin.H{
 			"message": ErrBadDate,
@@ -199,6 +241,10 @@ func (e *env) addAnimal(c *gin.Context) {
 		return
 	}
 
+	if !e.checkProjectRecordPage(c, principal.UserID, input.ProjectID, db.RECORD_PAGE_ANIMALS) {
+		return
+	}
+
 	g := guid.New()
 	timestamp := utils.TimeNow()
 
@@ -217,15 +263,33 @@ func (e *env) addAnimal(c *gin.Context) {
 		BeginningWeight: 0,
 		BeginningDate:   "",
 		EndWeight:       0,
+		WeightUnit:      utils.CANONICAL_MASS_UNIT,
 		EndDate:         "",
+		Status:          db.ANIMAL_STATUS_ACTIVE,
+		Identifiers:     normalizeAnimalIdentifiers(input.Identifiers),
 		ProjectID:       input.ProjectID,
-		UserID:          claims.ID,
+		UserID:          principal.UserID,
 		GenericDatabaseInfo: db.GenericDatabaseInfo{
 			Created: timestamp.String(),
 			Updated: timestamp.String(),
 		},
 	}
 
+	conflict, err := e.hasAnimalIdentifierConflict(c.Request.Context(), animal)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+	if conflict {
+		c.JSON(409, gin.H{
+			"message": ErrAnimalIdentifierConflict,
+		})
+		return
+	}
+
 	var output UpsertAnimalOutput
 
 	output.Animal, err = e.db.UpsertAnimal(c.Request.Context(), animal)
@@ -237,10 +301,116 @@ func (e *env) addAnimal(c *gin.Context) {
 		return
 	}
 
+	output.Animal = localizeAnimal(output.Animal, e.preferredMassUnit(c.Request.Context(), principal.UserID))
+
 	c.JSON(201, output)
 
 }
 
+// LookupAnimal godoc
+// @Summary Look up an animal by tag
+// @Description Finds a user's animals across all projects by an identifier such as an ear tag, tattoo, scrapie tag, premises ID or RFID
+// @Tags Animal
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param tag query string true "Identifier value"
+// @Param type query string false "Identifier type (ear_tag, tattoo, scrapie_tag, premises_id, rfid)"
+// @Success 200 {object} api.LookupAnimalOutput
+// @Failure 400
+// @Failure 401
+// @Failure 404
+// @Router /animal/lookup [get]
+func (e *env) lookupAnimal(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	tag := normalizeIdentifierValue(c.Query("tag"))
+	if tag == "" {
+		c.JSON(400, gin.H{
+			"message": ErrMissingFields,
+		})
+		return
+	}
+
+	var output LookupAnimalOutput
+
+	output.Animals, err = e.db.GetAnimalsByIdentifier(c.Request.Context(), principal.UserID, c.Query("type"), tag)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	if len(output.Animals) == 0 {
+		c.JSON(404, gin.H{
+			"message": ErrNotFound,
+		})
+		return
+	}
+
+	output.Animals = localizeAnimals(output.Animals, e.preferredMassUnit(c.Request.Context(), principal.UserID))
+
+	c.JSON(200, output)
+
+}
+
+// tags are matched without regard to case or surrounding whitespace
+func normalizeIdentifierValue(value string) string {
+	return strings.ToUpper(strings.TrimSpace(value))
+}
+
+func normalizeAnimalIdentifiers(input []AnimalIdentifierInput) []db.AnimalIdentifier {
+
+	identifiers := []db.AnimalIdentifier{}
+	for _, identifier := range input {
+		identifiers = append(identifiers, db.AnimalIdentifier{
+			Type:  identifier.Type,
+			Value: normalizeIdentifierValue(identifier.Value),
+		})
+	}
+
+	return identifiers
+
+}
+
+// identifiers must be unique among all of a user's animals of the same species
+func (e *env) hasAnimalIdentifierConflict(ctx context.Context, animal db.Animal) (bool, error) {
+
+	seen := make(map[db.AnimalIdentifier]bool)
+
+	for _, identifier := range animal.Identifiers {
+
+		if seen[identifier] {
+			return true, nil
+		}
+		seen[identifier] = true
+
+		matches, err := e.db.GetAnimalsByIdentifier(ctx, animal.UserID, identifier.Type, identifier.Value)
+		if err != nil {
+			return false, err
+		}
+
+		for _, match := range matches {
+			if match.OriginID() != animal.OriginID() && strings.EqualFold(match.Species, animal.Species) {
+				return true, nil
+			}
+		}
+
+	}
+
+	return false, nil
+
+}
+
 // UpdateAnimal godoc
 // @Summary Update an animal
 // @Description Updates a user's animal information
@@ -257,7 +427,7 @@ func (e *env) addAnimal(c *gin.Context) {
 // @Router /animal/{animalID} [put]
 func (e *env) updateAnimal(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -282,7 +452,9 @@ func (e *env) updateAnimal(c *gin.Context) {
 		return
 	}
 
-	birthDate, err := utils.StringToTimestamp(input.BirthDate)
+	location := e.userLocation(c.Request.Context(), principal.UserID)
+
+	birthDate, err := utils.StringToDate(input.BirthDate, location)
 	if err != nil {
 		c.JSON(400, gin.H{
 			"message": ErrBadDate,
@@ -290,7 +462,7 @@ func (e *env) updateAnimal(c *gin.Context) {
 		return
 	}
 
-	purchaseDate, err := utils.StringToTimestamp(input.PurchaseDate)
+	purchaseDate, err := utils.StringToDate(input.PurchaseDate, location)
 	if err != nil {
 		c.JSON(400, gin.H{
 			"message": ErrBadDate,
@@ -300,7 +472,7 @@ func (e *env) updateAnimal(c *gin.Context) {
 
 	animalID := c.Param("animalID")
 
-	animal, err := e.db.GetAnimalByID(c.Request.Context(), claims.ID, animalID)
+	animal, err := e.db.GetAnimalByID(c.Request.Context(), principal.UserID, animalID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -326,15 +498,44 @@ func (e *env) updateAnimal(c *gin.Context) {
 		BeginningWeight: animal.BeginningWeight,
 		BeginningDate:   animal.BeginningDate,
 		EndWeight:       animal.EndWeight,
+		WeightUnit:      utils.CANONICAL_MASS_UNIT,
 		EndDate:         animal.EndDate,
+		Status:          animal.Status,
+		DispositionDate: animal.DispositionDate,
+		Buyer:           animal.Buyer,
+		Auction:         animal.Auction,
+		Identifiers:     animal.Identifiers,
+		DamID:           animal.DamID,
+		SireID:          animal.SireID,
+		BirthID:         animal.BirthID,
+		OriginAnimalID:  animal.OriginAnimalID,
 		ProjectID:       animal.ProjectID,
-		UserID:          claims.ID,
+		UserID:          principal.UserID,
 		GenericDatabaseInfo: db.GenericDatabaseInfo{
 			Created: animal.Created,
 			Updated: timestamp.String(),
 		},
 	}
 
+	if input.Identifiers != nil {
+		updatedAnimal.Identifiers = normalizeAnimalIdentifiers(input.Identifiers)
+	}
+
+	conflict, err := e.hasAnimalIdentifierConflict(c.Request.Context(), updatedAnimal)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+	if conflict {
+		c.JSON(409, gin.H{
+			"message": ErrAnimalIdentifierConflict,
+		})
+		return
+	}
+
 	var output UpsertAnimalOutput
 
 	output.Animal, err = e.db.UpsertAnimal(c.Request.Context(), updatedAnimal)
@@ -346,13 +547,15 @@ func (e *env) updateAnimal(c *gin.Context) {
 		return
 	}
 
+	output.Animal = localizeAnimal(output.Animal, e.preferredMassUnit(c.Request.Context(), principal.UserID))
+
 	c.JSON(200, output)
 
 }
 
 // UpdateRateOfGain godoc
 // @Summary Update an animal's rate of gain
-// @Description Updates a user's animal rate of gain information
+// @Description Updates a user's animal rate of gain information. Weights are in weight_unit (lb, oz, kg or g), default the user's preferred unit.
 // @Tags Animal
 // @Accept json
 // @Produce json
@@ -366,7 +569,7 @@ func (e *env) updateAnimal(c *gin.Context) {
 // @Router /rate-of-gain/{animalID} [put]
 func (e *env) updateRateOfGain(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -391,7 +594,9 @@ func (e *env) updateRateOfGain(c *gin.Context) {
 		return
 	}
 
-	beginningDate, err := utils.StringToTimestamp(input.BeginningDate)
+	location := e.userLocation(c.Request.Context(), principal.UserID)
+
+	beginningDate, err := utils.StringToDate(input.BeginningDate, location)
 	if err != nil {
 		c.JSON(400, gin.H{
 			"message": ErrBadDate,
@@ -399,7 +604,7 @@ func (e *env) updateRateOfGain(c *gin.Context) {
 		return
 	}
 
-	endDate, err := utils.StringToTimestamp(input.EndDate)
+	endDate, err := utils.StringToDate(input.EndDate, location)
 	if err != nil {
 		c.JSON(400, gin.H{
 			"message": ErrBadDate,
@@ -407,9 +612,23 @@ func (e *env) updateRateOfGain(c *gin.Context) {
 		return
 	}
 
+	unit := e.preferredMassUnit(c.Request.Context(), principal.UserID)
+
+	beginningWeight, err := massToPounds(*input.BeginningWeight, input.WeightUnit, unit)
+	if err != nil {
+		respondUnitError(c, err)
+		return
+	}
+
+	endWeight, err := massToPounds(*input.EndWeight, input.WeightUnit, unit)
+	if err != nil {
+		respondUnitError(c, err)
+		return
+	}
+
 	animalID := c.Param("animalID")
 
-	animal, err := e.db.GetAnimalByID(c.Request.Context(), claims.ID, animalID)
+	animal, err := e.db.GetAnimalByID(c.Request.Context(), principal.UserID, animalID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -432,12 +651,22 @@ func (e *env) updateRateOfGain(c *gin.Context) {
 		SalePrice:       animal.SalePrice,
 		YieldGrade:      animal.YieldGrade,
 		QualityGrade:    animal.QualityGrade,
-		BeginningWeight: *input.BeginningWeight,
+		BeginningWeight: beginningWeight,
 		BeginningDate:   beginningDate.String(),
-		EndWeight:       *input.EndWeight,
+		EndWeight:       endWeight,
+		WeightUnit:      utils.CANONICAL_MASS_UNIT,
 		EndDate:         endDate.String(),
+		Status:          animal.Status,
+		DispositionDate: animal.DispositionDate,
+		Buyer:           animal.Buyer,
+		Auction:         animal.Auction,
+		Identifiers:     animal.Identifiers,
+		DamID:           animal.DamID,
+		SireID:          animal.SireID,
+		BirthID:         animal.BirthID,
+		OriginAnimalID:  animal.OriginAnimalID,
 		ProjectID:       animal.ProjectID,
-		UserID:          claims.ID,
+		UserID:          principal.UserID,
 		GenericDatabaseInfo: db.GenericDatabaseInfo{
 			Created: animal.Created,
 			Updated: timestamp.String(),
@@ -455,6 +684,121 @@ func (e *env) updateRateOfGain(c *gin.Context) {
 		return
 	}
 
+	output.Animal = localizeAnimal(output.Animal, unit)
+
+	c.JSON(200, output)
+
+}
+
+// UpdateAnimalStatus godoc
+// @Summary Update an animal's status
+// @Description Moves an animal to a new lifecycle status. Active animals can become sold, retained, deceased or transferred,
+// @Description retained animals can return to active or become sold, deceased or transferred, and the rest are final.
+// @Description A disposition date is required for every status except active and retained.
+// @Tags Animal
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param animalID path string true "Animal ID"
+// @Param UpdateAnimalStatusInput body api.UpdateAnimalStatusInput true "Animal status information"
+// @Success 200 {object} api.UpsertAnimalOutput
+// @Failure 400
+// @Failure 401
+// @Failure 404
+// @Failure 409
+// @Router /animal/{animalID}/status [put]
+func (e *env) updateAnimalStatus(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	var input UpdateAnimalStatusInput
+	err = c.BindJSON(&input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrBadRequest,
+		})
+		return
+	}
+
+	err = e.validator.Struct(input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrBadAnimalStatus,
+		})
+		return
+	}
+
+	location := e.userLocation(c.Request.Context(), principal.UserID)
+
+	dispositionDate := ""
+	if input.DispositionDate != "" {
+		date, err := utils.StringToDate(input.DispositionDate, location)
+		if err != nil {
+			c.JSON(400, gin.H{
+				"message": ErrBadDate,
+			})
+			return
+		}
+		dispositionDate = date.String()
+	} else if input.Status != db.ANIMAL_STATUS_ACTIVE && input.Status != db.ANIMAL_STATUS_RETAINED {
+		c.JSON(400, gin.H{
+			"message": ErrMissingFields,
+		})
+		return
+	}
+
+	animalID := c.Param("animalID")
+
+	animal, err := e.db.GetAnimalByID(c.Request.Context(), principal.UserID, animalID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	if !animal.CanTransitionTo(input.Status) {
+		c.JSON(409, gin.H{
+			"message": ErrAnimalStatusTransition,
+		})
+		return
+	}
+
+	salePrice := animal.SalePrice
+	if input.SalePrice != nil {
+		salePrice = *input.SalePrice
+	}
+
+	timestamp := utils.TimeNow()
+
+	updatedAnimal := animal
+	updatedAnimal.SalePrice = salePrice
+	updatedAnimal.Status = input.Status
+	updatedAnimal.DispositionDate = dispositionDate
+	updatedAnimal.Buyer = input.Buyer
+	updatedAnimal.Auction = input.Auction
+	updatedAnimal.Updated = timestamp.String()
+
+	var output UpsertAnimalOutput
+
+	output.Animal, err = e.db.UpsertAnimal(c.Request.Context(), updatedAnimal)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	output.Animal = localizeAnimal(output.Animal, e.preferredMassUnit(c.Request.Context(), principal.UserID))
+
 	c.JSON(200, output)
 
 }
@@ -473,7 +817,7 @@ func (e *env) updateRateOfGain(c *gin.Context) {
 // @Router /animal/{animalID} [delete]
 func (e *env) deleteAnimal(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -483,7 +827,7 @@ func (e *env) deleteAnimal(c *gin.Context) {
 
 	animalID := c.Param("animalID")
 
-	response, err := e.db.RemoveAnimal(c.Request.Context(), claims.ID, animalID)
+	response, err := e.db.RemoveAnimal(c.Request.Context(), principal.UserID, animalID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"4h-recordbook-backend/internal/auth"
+	"4h-recordbook-backend/internal/utils"
+	"4h-recordbook-backend/pkg/db"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GetAuditEntriesOutput struct {
+	AuditEntries []db.AuditEntry `json:"audit_entries"`
+	Next         string          `json:"next"`
+}
+
+// GetAuditEntries godoc
+// @Summary Get a member's audit trail
+// @Description Returns the changes made to the member's records and the requests guardians made for them.
+// @Description Each entry holds the member and whoever made the request.
+// @Tags Guardians
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param page query int false "Page number, default 0"
+// @Param per_page query int false "Max number of items to return. Can be [1-200], default 100"
+// @Param sort_by_newest query bool false "Sort results by most recent, default true"
+// @Success 200 {object} api.GetAuditEntriesOutput
+// @Failure 401
+// @Failure 403
+// @Router /audit [get]
+func (e *env) getAuditEntries(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	var output GetAuditEntriesOutput
+
+	paginationOptions := db.PaginationOptions{
+		Page:         c.GetInt(CONTEXT_KEY_PAGE),
+		PerPage:      c.GetInt(CONTEXT_KEY_PER_PAGE),
+		SortByNewest: c.GetBool(CONTEXT_KEY_SORT_BY_NEWEST),
+	}
+
+	output.AuditEntries, err = e.db.GetAuditEntriesByUser(c.Request.Context(), principal.UserID, paginationOptions)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	if len(output.AuditEntries) == paginationOptions.PerPage {
+
+		queryParamsMap := make(map[string]string)
+		queryParamsMap[CONTEXT_KEY_PAGE] = strconv.Itoa(paginationOptions.Page + 1)
+		queryParamsMap[CONTEXT_KEY_PER_PAGE] = strconv.Itoa(paginationOptions.PerPage)
+		queryParamsMap[CONTEXT_KEY_SORT_BY_NEWEST] = strconv.FormatBool(paginationOptions.SortByNewest)
+
+		nextUrlInput := utils.NextUrlInput{
+			Context:     c,
+			QueryParams: queryParamsMap,
+		}
+
+		output.Next = utils.BuildNextUrl(nextUrlInput)
+	}
+
+	c.JSON(200, output)
+
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"4h-recordbook-backend/internal/auth"
 	"4h-recordbook-backend/internal/utils"
 	"4h-recordbook-backend/pkg/db"
 	"strconv"
@@ -40,7 +41,7 @@ type AddBookmarkOutput GetBookmarkOutput
 // @Router /bookmarks [get]
 func (e *env) getUserBookmarks(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -56,7 +57,7 @@ func (e *env) getUserBookmarks(c *gin.Context) {
 		SortByNewest: c.GetBool(CONTEXT_KEY_SORT_BY_NEWEST),
 	}
 
-	output.Bookmarks, err = e.db.GetBookmarks(c.Request.Context(), claims.ID, paginationOptions)
+	output.Bookmarks, err = e.db.GetBookmarks(c.Request.Context(), principal.UserID, paginationOptions)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -97,7 +98,7 @@ func (e *env) getUserBookmarks(c *gin.Context) {
 // @Router /bookmarks/{link} [get]
 func (e *env) getBookmarkByLink(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -109,7 +110,7 @@ func (e *env) getBookmarkByLink(c *gin.Context) {
 
 	link := c.Param("link")
 
-	output.Bookmark, err = e.db.GetBookmarkByLink(c.Request.Context(), claims.ID, link)
+	output.Bookmark, err = e.db.GetBookmarkByLink(c.Request.Context(), principal.UserID, link)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -145,7 +146,7 @@ func (e *env) getBookmarkByLink(c *gin.Context) {
 // @Router /bookmarks [post]
 func (e *env) addUserBookmark(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -177,14 +178,14 @@ func (e *env) addUserBookmark(c *gin.Context) {
 		ID:     g.String(),
 		Link:   input.Link,
 		Label:  input.Label,
-		UserID: claims.ID,
+		UserID: principal.UserID,
 		GenericDatabaseInfo: db.GenericDatabaseInfo{
 			Created: timestamp.String(),
 			Updated: timestamp.String(),
 		},
 	}
 
-	existingBookmark, err := e.db.GetBookmarkByLink(c.Request.Context(), claims.ID, input.Link)
+	existingBookmark, err := e.db.GetBookmarkByLink(c.Request.Context(), principal.UserID, input.Link)
 	if existingBookmark != (db.Bookmark{}) {
 		c.JSON(409, gin.H{
 			"message": ErrBookmarkConflict,
@@ -230,7 +231,7 @@ func (e *env) addUserBookmark(c *gin.Context) {
 // @Router /bookmarks/{bookmarkID} [delete]
 func (e *env) deleteUserBookmark(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -240,7 +241,7 @@ func (e *env) deleteUserBookmark(c *gin.Context) {
 
 	bookmarkID := c.Param("bookmarkID")
 
-	response, err := e.db.RemoveBookmark(c.Request.Context(), claims.ID, bookmarkID)
+	response, err := e.db.RemoveBookmark(c.Request.Context(), principal.UserID, bookmarkID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -0,0 +1,908 @@
+package api
+
+import (
+	"4h-recordbook-backend/internal/auth"
+	"4h-recordbook-backend/internal/utils"
+	"4h-recordbook-backend/pkg/db"
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/beevik/guid"
+	"github.com/gin-gonic/gin"
+)
+
+// offspring written for a birth that then fails to save are removed in the background, for no longer than this
+const BIRTH_CLEANUP_TIMEOUT = 5 * time.Second
+
+/*******************************
+* BREEDINGS
+********************************/
+
+type GetBreedingsOutput struct {
+	Breedings []db.Breeding `json:"breedings"`
+	Next      string        `json:"next"`
+}
+
+type GetBreedingOutput struct {
+	Breeding db.Breeding `json:"breeding"`
+}
+
+type UpsertBreedingInput struct {
+	DamID           string `json:"dam_id" validate:"required"`
+	SireID          string `json:"sire_id"`
+	SireName        string `json:"sire_name"`
+	SireBreed       string `json:"sire_breed"`
+	Method          string `json:"method" validate:"required,oneof=natural ai embryo_transfer"`
+	BreedingDate    string `json:"breeding_date" validate:"required"`
+	ExpectedDueDate string `json:"expected_due_date"`
+	Notes           string `json:"notes"`
+}
+
+type UpsertBreedingOutput GetBreedingOutput
+
+// GetBreedings godoc
+// @Summary Get breedings by dam
+// @Description Gets all of a user's breeding records for a given dam
+// @Tags Breeding
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param animalID path string true "Dam's Animal ID"
+// @Param page query int false "Page number, default 0"
+// @Param per_page query int false "Max number of items to return. Can be [1-200], default 100"
+// @Param sort_by_newest query bool false "Sort results by most recently added, default false"
+// @Success 200 {object} api.GetBreedingsOutput
+// @Failure 400
+// @Failure 401
+// @Router /animal/{animalID}/breeding [get]
+func (e *env) getBreedings(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	animalID := c.Param("animalID")
+
+	var output GetBreedingsOutput
+
+	paginationOptions := db.PaginationOptions{
+		Page:         c.GetInt(CONTEXT_KEY_PAGE),
+		PerPage:      c.GetInt(CONTEXT_KEY_PER_PAGE),
+		SortByNewest: c.GetBool(CONTEXT_KEY_SORT_BY_NEWEST),
+	}
+
+	output.Breedings, err = e.db.GetBreedingsByDam(c.Request.Context(), principal.UserID, animalID, paginationOptions)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	if len(output.Breedings) == paginationOptions.PerPage {
+
+		queryParamsMap := make(map[string]string)
+		queryParamsMap[CONTEXT_KEY_PAGE] = strconv.Itoa(paginationOptions.Page + 1)
+		queryParamsMap[CONTEXT_KEY_PER_PAGE] = strconv.Itoa(paginationOptions.PerPage)
+		queryParamsMap[CONTEXT_KEY_SORT_BY_NEWEST] = strconv.FormatBool(paginationOptions.SortByNewest)
+
+		nextUrlInput := utils.NextUrlInput{
+			Context:     c,
+			QueryParams: queryParamsMap,
+		}
+
+		output.Next = utils.BuildNextUrl(nextUrlInput)
+	}
+
+	c.JSON(200, output)
+
+}
+
+// GetBreeding godoc
+// @Summary Get a breeding
+// @Description Get a user's breeding record by ID
+// @Tags Breeding
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param breedingID path string true "Breeding ID"
+// @Success 200 {object} api.GetBreedingOutput
+// @Failure 401
+// @Failure 404
+// @Router /breeding/{breedingID} [get]
+func (e *env) getBreeding(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	breedingID := c.Param("breedingID")
+
+	var output GetBreedingOutput
+
+	output.Breeding, err = e.db.GetBreedingByID(c.Request.Context(), principal.UserID, breedingID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(200, output)
+
+}
+
+// AddBreeding godoc
+// @Summary Add a breeding
+// @Description Adds a mating record for one of a user's animals. If no expected due date is given it is estimated from the dam's species.
+// @Tags Breeding
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param UpsertBreedingInput body api.UpsertBreedingInput true "Breeding information"
+// @Success 201 {object} api.UpsertBreedingOutput
+// @Failure 400
+// @Failure 401
+// @Failure 404
+// @Router /breeding [post]
+func (e *env) addBreeding(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	var input UpsertBreedingInput
+	err = c.BindJSON(&input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrBadRequest,
+		})
+		return
+	}
+
+	err = e.validator.Struct(input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrMissingFields,
+		})
+		return
+	}
+
+	dam, err := e.db.GetAnimalByID(c.Request.Context(), principal.UserID, input.DamID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	location := e.userLocation(c.Request.Context(), principal.UserID)
+
+	breedingDate, expectedDueDate, ok := breedingDates(input, dam, location)
+	if !ok {
+		c.JSON(400, gin.H{
+			"message": ErrBadDueDate,
+		})
+		return
+	}
+
+	g := guid.New()
+	timestamp := utils.TimeNow()
+
+	breeding := db.Breeding{
+		ID:              g.String(),
+		DamID:           dam.ID,
+		SireID:          input.SireID,
+		SireName:        input.SireName,
+		SireBreed:       input.SireBreed,
+		Method:          input.Method,
+		BreedingDate:    breedingDate,
+		ExpectedDueDate: expectedDueDate,
+		Notes:           input.Notes,
+		ProjectID:       dam.ProjectID,
+		UserID:          principal.UserID,
+		GenericDatabaseInfo: db.GenericDatabaseInfo{
+			Created: timestamp.String(),
+			Updated: timestamp.String(),
+		},
+	}
+
+	var output UpsertBreedingOutput
+
+	output.Breeding, err = e.db.UpsertBreeding(c.Request.Context(), breeding)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(201, output)
+
+}
+
+// UpdateBreeding godoc
+// @Summary Update a breeding
+// @Description Updates a user's breeding record. The dam cannot be changed.
+// @Tags Breeding
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param breedingID path string true "Breeding ID"
+// @Param UpsertBreedingInput body api.UpsertBreedingInput true "Breeding information"
+// @Success 200 {object} api.UpsertBreedingOutput
+// @Failure 400
+// @Failure 401
+// @Failure 404
+// @Router /breeding/{breedingID} [put]
+func (e *env) updateBreeding(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	var input UpsertBreedingInput
+	err = c.BindJSON(&input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrBadRequest,
+		})
+		return
+	}
+
+	err = e.validator.Struct(input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrMissingFields,
+		})
+		return
+	}
+
+	breedingID := c.Param("breedingID")
+
+	breeding, err := e.db.GetBreedingByID(c.Request.Context(), principal.UserID, breedingID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	dam, err := e.db.GetAnimalByID(c.Request.Context(), principal.UserID, breeding.DamID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	location := e.userLocation(c.Request.Context(), principal.UserID)
+
+	breedingDate, expectedDueDate, ok := breedingDates(input, dam, location)
+	if !ok {
+		c.JSON(400, gin.H{
+			"message": ErrBadDueDate,
+		})
+		return
+	}
+
+	timestamp := utils.TimeNow()
+
+	updatedBreeding := db.Breeding{
+		ID:              breeding.ID,
+		DamID:           breeding.DamID,
+		SireID:          input.SireID,
+		SireName:        input.SireName,
+		SireBreed:       input.SireBreed,
+		Method:          input.Method,
+		BreedingDate:    breedingDate,
+		ExpectedDueDate: expectedDueDate,
+		Notes:           input.Notes,
+		ProjectID:       breeding.ProjectID,
+		UserID:          principal.UserID,
+		GenericDatabaseInfo: db.GenericDatabaseInfo{
+			Created: breeding.Created,
+			Updated: timestamp.String(),
+		},
+	}
+
+	var output UpsertBreedingOutput
+
+	output.Breeding, err = e.db.UpsertBreeding(c.Request.Context(), updatedBreeding)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(200, output)
+
+}
+
+// DeleteBreeding godoc
+// @Summary Removes a breeding
+// @Description Deletes a user's breeding record and its births given the breeding ID. Offspring animals are kept.
+// @Tags Breeding
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param breedingID path string true "Breeding ID"
+// @Success 204
+// @Failure 401
+// @Failure 404
+// @Router /breeding/{breedingID} [delete]
+func (e *env) deleteBreeding(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	breedingID := c.Param("breedingID")
+
+	response, err := e.db.RemoveBreeding(c.Request.Context(), principal.UserID, breedingID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(204, response)
+
+}
+
+// parses the breeding date and either parses the expected due date or estimates it from the dam's gestation length
+func breedingDates(input UpsertBreedingInput, dam db.Animal, location *time.Location) (string, string, bool) {
+
+	breedingDate, err := utils.StringToDate(input.BreedingDate, location)
+	if err != nil {
+		return "", "", false
+	}
+
+	if input.ExpectedDueDate != "" {
+		expectedDueDate, err := utils.StringToDate(input.ExpectedDueDate, location)
+		if err != nil {
+			return "", "", false
+		}
+		return breedingDate.String(), expectedDueDate.String(), true
+	}
+
+	gestationDays, ok := db.GestationDays[strings.ToLower(dam.Species)]
+	if !ok {
+		return "", "", false
+	}
+
+	return breedingDate.String(), breedingDate.AddDays(gestationDays).String(), true
+
+}
+
+/*******************************
+* BIRTHS
+********************************/
+
+type GetBirthsOutput struct {
+	Births []db.Birth `json:"births"`
+	Next   string     `json:"next"`
+}
+
+type GetBirthOutput struct {
+	Birth db.Birth `json:"birth"`
+}
+
+type OffspringInput struct {
+	Name        string   `json:"name" validate:"required"`
+	BirthWeight *float64 `json:"birth_weight"`
+	WeightUnit  string   `json:"weight_unit"`
+	SireBreed   string   `json:"sire_breed"`
+	DamBreed    string   `json:"dam_breed"`
+}
+
+type AddBirthInput struct {
+	BreedingID string           `json:"breeding_id"`
+	DamID      string           `json:"dam_id" validate:"required"`
+	BirthType  string           `json:"birth_type" validate:"required,oneof=farrowing lambing kidding calving kindling other"`
+	BirthDate  string           `json:"birth_date" validate:"required"`
+	BornAlive  *int             `json:"born_alive" validate:"required,min=0"`
+	BornDead   *int             `json:"born_dead" validate:"required,min=0"`
+	Notes      string           `json:"notes"`
+	Offspring  []OffspringInput `json:"offspring" validate:"max=100,dive"`
+}
+
+type UpdateBirthInput struct {
+	BirthType string `json:"birth_type" validate:"required,oneof=farrowing lambing kidding calving kindling other"`
+	BirthDate string `json:"birth_date" validate:"required"`
+	BornAlive *int   `json:"born_alive" validate:"required,min=0"`
+	BornDead  *int   `json:"born_dead" validate:"required,min=0"`
+	Notes     string `json:"notes"`
+}
+
+type AddBirthOutput struct {
+	Birth     db.Birth    `json:"birth"`
+	Offspring []db.Animal `json:"offspring"`
+}
+
+type UpdateBirthOutput GetBirthOutput
+
+// GetBirths godoc
+// @Summary Get births by dam
+// @Description Gets all of a user's birth records for a given dam
+// @Tags Breeding
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param animalID path string true "Dam's Animal ID"
+// @Param page query int false "Page number, default 0"
+// @Param per_page query int false "Max number of items to return. Can be [1-200], default 100"
+// @Param sort_by_newest query bool false "Sort results by most recently added, default false"
+// @Success 200 {object} api.GetBirthsOutput
+// @Failure 400
+// @Failure 401
+// @Router /animal/{animalID}/birth [get]
+func (e *env) getBirths(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	animalID := c.Param("animalID")
+
+	var output GetBirthsOutput
+
+	paginationOptions := db.PaginationOptions{
+		Page:         c.GetInt(CONTEXT_KEY_PAGE),
+		PerPage:      c.GetInt(CONTEXT_KEY_PER_PAGE),
+		SortByNewest: c.GetBool(CONTEXT_KEY_SORT_BY_NEWEST),
+	}
+
+	output.Births, err = e.db.GetBirthsByDam(c.Request.Context(), principal.UserID, animalID, paginationOptions)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	if len(output.Births) == paginationOptions.PerPage {
+
+		queryParamsMap := make(map[string]string)
+		queryParamsMap[CONTEXT_KEY_PAGE] = strconv.Itoa(paginationOptions.Page + 1)
+		queryParamsMap[CONTEXT_KEY_PER_PAGE] = strconv.Itoa(paginationOptions.PerPage)
+		queryParamsMap[CONTEXT_KEY_SORT_BY_NEWEST] = strconv.FormatBool(paginationOptions.SortByNewest)
+
+		nextUrlInput := utils.NextUrlInput{
+			Context:     c,
+			QueryParams: queryParamsMap,
+		}
+
+		output.Next = utils.BuildNextUrl(nextUrlInput)
+	}
+
+	c.JSON(200, output)
+
+}
+
+// GetBirth godoc
+// @Summary Get a birth
+// @Description Get a user's birth record by ID
+// @Tags Breeding
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param birthID path string true "Birth ID"
+// @Success 200 {object} api.GetBirthOutput
+// @Failure 401
+// @Failure 404
+// @Router /birth/{birthID} [get]
+func (e *env) getBirth(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	birthID := c.Param("birthID")
+
+	var output GetBirthOutput
+
+	output.Birth, err = e.db.GetBirthByID(c.Request.Context(), principal.UserID, birthID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(200, output)
+
+}
+
+// AddBirth godoc
+// @Summary Add a birth
+// @Description Records a farrowing, lambing, kidding or other birth for one of a user's animals.
+// @Description Each entry in offspring is created as a new animal in the dam's project, linked back to the dam. The
+// @Description offspring are written together in one transaction, before the birth. If the birth then can't be saved
+// @Description the offspring are removed again.
+// @Tags Breeding
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param AddBirthInput body api.AddBirthInput true "Birth information"
+// @Success 201 {object} api.AddBirthOutput
+// @Failure 400
+// @Failure 401
+// @Failure 404
+// @Router /birth [post]
+func (e *env) addBirth(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	var input AddBirthInput
+	err = c.BindJSON(&input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrBadRequest,
+		})
+		return
+	}
+
+	err = e.validator.Struct(input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrMissingFields,
+		})
+		return
+	}
+
+	if len(input.Offspring) > *input.BornAlive {
+		c.JSON(400, gin.H{
+			"message": ErrTooManyOffspring,
+		})
+		return
+	}
+
+	location := e.userLocation(c.Request.Context(), principal.UserID)
+
+	birthDate, err := utils.StringToDate(input.BirthDate, location)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrBadDate,
+		})
+		return
+	}
+
+	dam, err := e.db.GetAnimalByID(c.Request.Context(), principal.UserID, input.DamID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	var breeding db.Breeding
+	if input.BreedingID != "" {
+		breeding, err = e.db.GetBreedingByID(c.Request.Context(), principal.UserID, input.BreedingID)
+		if err != nil {
+			response := InterpretCosmosError(err)
+			c.JSON(response.Code, gin.H{
+				"message": response.Message,
+			})
+			return
+		}
+		if breeding.DamID != dam.ID {
+			c.JSON(400, gin.H{
+				"message": ErrBreedingDamMismatch,
+			})
+			return
+		}
+	}
+
+	timestamp := utils.TimeNow()
+	birthID := guid.New().String()
+	unit := e.preferredMassUnit(c.Request.Context(), principal.UserID)
+
+	offspring := []db.Animal{}
+	offspringIDs := []string{}
+
+	for _, child := range input.Offspring {
+
+		birthWeight := 0.0
+		if child.BirthWeight != nil {
+			birthWeight, err = massToPounds(*child.BirthWeight, child.WeightUnit, unit)
+			if err != nil {
+				respondUnitError(c, err)
+				return
+			}
+		}
+
+		animal := db.Animal{
+			ID:              guid.New().String(),
+			Name:            child.Name,
+			Species:         dam.Species,
+			BirthDate:       birthDate.String(),
+			PurchaseDate:    "",
+			SireBreed:       ternary(child.SireBreed, breeding.SireBreed),
+			DamBreed:        child.DamBreed,
+			BeginningWeight: birthWeight,
+			BeginningDate:   birthDate.String(),
+			WeightUnit:      utils.CANONICAL_MASS_UNIT,
+			Status:          db.ANIMAL_STATUS_ACTIVE,
+			Identifiers:     []db.AnimalIdentifier{},
+			DamID:           dam.ID,
+			SireID:          breeding.SireID,
+			BirthID:         birthID,
+			ProjectID:       dam.ProjectID,
+			UserID:          principal.UserID,
+			GenericDatabaseInfo: db.GenericDatabaseInfo{
+				Created: timestamp.String(),
+				Updated: timestamp.String(),
+			},
+		}
+
+		offspring = append(offspring, animal)
+		offspringIDs = append(offspringIDs, animal.ID)
+
+	}
+
+	if len(offspring) > 0 {
+
+		statusCodes, err := e.db.UpsertAnimalBatch(c.Request.Context(), principal.UserID, offspring)
+		if err != nil {
+			response := InterpretCosmosError(err)
+			c.JSON(response.Code, gin.H{
+				"message": response.Message,
+			})
+			return
+		}
+
+		for _, statusCode := range statusCodes {
+			if statusCode >= 300 && statusCode != 424 {
+				c.JSON(statusCode, gin.H{
+					"message": ternary(HTTPResponseCodeMap[statusCode], "unexpected error"),
+				})
+				return
+			}
+		}
+
+	}
+
+	birth := db.Birth{
+		ID:           birthID,
+		BreedingID:   input.BreedingID,
+		DamID:        dam.ID,
+		BirthType:    input.BirthType,
+		BirthDate:    birthDate.String(),
+		BornAlive:    *input.BornAlive,
+		BornDead:     *input.BornDead,
+		LitterSize:   *input.BornAlive + *input.BornDead,
+		OffspringIDs: offspringIDs,
+		Notes:        input.Notes,
+		ProjectID:    dam.ProjectID,
+		UserID:       principal.UserID,
+		GenericDatabaseInfo: db.GenericDatabaseInfo{
+			Created: timestamp.String(),
+			Updated: timestamp.String(),
+		},
+	}
+
+	var output AddBirthOutput
+
+	output.Birth, err = e.db.UpsertBirth(c.Request.Context(), birth)
+	if err != nil {
+		e.removeOffspring(c.Request.Context(), principal.UserID, offspringIDs)
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	output.Offspring = localizeAnimals(offspring, unit)
+
+	c.JSON(201, output)
+
+}
+
+// removes the offspring written for a birth that failed to save. the request may have timed out, so they are removed
+// with a context of their own
+func (e *env) removeOffspring(ctx context.Context, userID string, offspringIDs []string) {
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), BIRTH_CLEANUP_TIMEOUT)
+	defer cancel()
+
+	for _, animalID := range offspringIDs {
+		_, err := e.db.RemoveAnimal(ctx, userID, animalID)
+		if err != nil {
+			e.logger.Errorf("Failed to remove offspring %s of a birth that failed to save: %v", animalID, err)
+		}
+	}
+
+}
+
+// UpdateBirth godoc
+// @Summary Update a birth
+// @Description Updates a user's birth record. Offspring are managed as animals and are not changed.
+// @Tags Breeding
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param birthID path string true "Birth ID"
+// @Param UpdateBirthInput body api.UpdateBirthInput true "Birth information"
+// @Success 200 {object} api.UpdateBirthOutput
+// @Failure 400
+// @Failure 401
+// @Failure 404
+// @Router /birth/{birthID} [put]
+func (e *env) updateBirth(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	var input UpdateBirthInput
+	err = c.BindJSON(&input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrBadRequest,
+		})
+		return
+	}
+
+	err = e.validator.Struct(input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrMissingFields,
+		})
+		return
+	}
+
+	location := e.userLocation(c.Request.Context(), principal.UserID)
+
+	birthDate, err := utils.StringToDate(input.BirthDate, location)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrBadDate,
+		})
+		return
+	}
+
+	birthID := c.Param("birthID")
+
+	birth, err := e.db.GetBirthByID(c.Request.Context(), principal.UserID, birthID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	if len(birth.OffspringIDs) > *input.BornAlive {
+		c.JSON(400, gin.H{
+			"message": ErrTooManyOffspring,
+		})
+		return
+	}
+
+	timestamp := utils.TimeNow()
+
+	updatedBirth := db.Birth{
+		ID:           birth.ID,
+		BreedingID:   birth.BreedingID,
+		DamID:        birth.DamID,
+		BirthType:    input.BirthType,
+		BirthDate:    birthDate.String(),
+		BornAlive:    *input.BornAlive,
+		BornDead:     *input.BornDead,
+		LitterSize:   *input.BornAlive + *input.BornDead,
+		OffspringIDs: birth.OffspringIDs,
+		Notes:        input.Notes,
+		ProjectID:    birth.ProjectID,
+		UserID:       principal.UserID,
+		GenericDatabaseInfo: db.GenericDatabaseInfo{
+			Created: birth.Created,
+			Updated: timestamp.String(),
+		},
+	}
+
+	var output UpdateBirthOutput
+
+	output.Birth, err = e.db.UpsertBirth(c.Request.Context(), updatedBirth)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(200, output)
+
+}
+
+// DeleteBirth godoc
+// @Summary Removes a birth
+// @Description Deletes a user's birth record given the birth ID. Offspring animals are kept.
+// @Tags Breeding
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param birthID path string true "Birth ID"
+// @Success 204
+// @Failure 401
+// @Failure 404
+// @Router /birth/{birthID} [delete]
+func (e *env) deleteBirth(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	birthID := c.Param("birthID")
+
+	response, err := e.db.RemoveBirth(c.Request.Context(), principal.UserID, birthID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(204, response)
+
+}
@@ -0,0 +1,889 @@
+package api
+
+import (
+	"4h-recordbook-backend/internal/auth"
+	"4h-recordbook-backend/internal/utils"
+	"4h-recordbook-backend/pkg/db"
+	"context"
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"strings"
+
+	"github.com/beevik/guid"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// easy to read aloud and to type, without the letters and digits that get mistaken for each other
+	CLUB_CODE_ALPHABET = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	CLUB_CODE_LENGTH   = 8
+	CLUB_CODE_ATTEMPTS = 5
+)
+
+var errClubCodeTaken = errors.New("could not find an unused club code")
+
+type GetClubOutput struct {
+	Club db.Club `json:"club"`
+}
+
+type GetMembershipsOutput struct {
+	Memberships []db.Membership `json:"memberships"`
+}
+
+type GetMembershipOutput struct {
+	Membership db.Membership `json:"membership"`
+}
+
+type AddClubInput struct {
+	Name       string `json:"name" validate:"required"`
+	CountyName string `json:"county_name"`
+	Year       string `json:"year" validate:"omitempty,program_year"`
+}
+
+type AddClubOutput struct {
+	Club       db.Club       `json:"club"`
+	Membership db.Membership `json:"membership"`
+}
+
+type JoinClubInput struct {
+	Code string `json:"code" validate:"required"`
+	Year string `json:"year" validate:"omitempty,program_year"`
+	Role string `json:"role"`
+}
+
+type JoinClubOutput GetMembershipOutput
+
+type ApproveClubMemberOutput GetMembershipOutput
+
+type ResetClubCodeOutput GetClubOutput
+
+func clubCode() (string, error) {
+	code := make([]byte, CLUB_CODE_LENGTH)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(CLUB_CODE_ALPHABET))))
+		if err != nil {
+			return "", err
+		}
+		code[i] = CLUB_CODE_ALPHABET[n.Int64()]
+	}
+	return string(code), nil
+}
+
+// reserves a new join code for the club, trying again when a code is already taken
+func (e *env) reserveClubCode(ctx context.Context, clubID string) (string, error) {
+
+	for i := 0; i < CLUB_CODE_ATTEMPTS; i++ {
+
+		code, err := clubCode()
+		if err != nil {
+			return "", err
+		}
+
+		_, err = e.db.AddClubCode(ctx, db.ClubCode{ID: code, ClubID: clubID})
+		if err == nil {
+			return code, nil
+		}
+		if InterpretCosmosError(err).Code != 409 {
+			return "", err
+		}
+
+	}
+
+	return "", errClubCodeTaken
+
+}
+
+func normalizeClubCode(code string) string {
+	return strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+}
+
+func membershipName(user db.User) string {
+	return strings.TrimSpace(user.FirstName + " " + user.LastNameInitial)
+}
+
+// whether the user is an approved leader of the club in the program year. leading it in another year doesn't count
+func (e *env) leadsClub(ctx context.Context, userID string, clubID string, year string) (bool, error) {
+
+	roster, err := e.db.GetMembershipsByClub(ctx, clubID, year)
+	if err != nil {
+		return false, err
+	}
+
+	for _, membership := range roster {
+		if membership.UserID == userID && membership.Role == db.MEMBERSHIP_ROLE_LEADER && membership.Status == db.MEMBERSHIP_STATUS_APPROVED {
+			return true, nil
+		}
+	}
+
+	return false, nil
+
+}
+
+// whether the principal oversees the club in the program year, as one of its leaders, an agent for its county, or an
+// admin
+func (e *env) overseesClub(ctx context.Context, principal auth.Principal, club db.Club, year string) (bool, error) {
+
+	if principal.HasRole(auth.ROLE_ADMIN) {
+		return true, nil
+	}
+
+	if principal.HasRole(auth.ROLE_AGENT) {
+		counties, err := e.agentCounties(ctx, principal.UserID)
+		if err != nil {
+			return false, err
+		}
+		for _, county := range counties {
+			if strings.EqualFold(county, club.CountyName) {
+				return true, nil
+			}
+		}
+	}
+
+	return e.leadsClub(ctx, principal.UserID, club.ID, year)
+
+}
+
+// reads the club in the route and checks that the principal may see it, or lead it when leading is required, in the
+// program year being acted on. the response is written when they may not
+func (e *env) routeClub(c *gin.Context, principal auth.Principal, requireLeader bool, year string) (db.Club, bool) {
+
+	club, err := e.db.GetClubByID(c.Request.Context(), c.Param("clubID"))
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return db.Club{}, false
+	}
+
+	var allowed bool
+	if requireLeader {
+		allowed, err = e.leadsClub(c.Request.Context(), principal.UserID, club.ID, year)
+	} else {
+		allowed, err = e.overseesClub(c.Request.Context(), principal, club, year)
+	}
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return db.Club{}, false
+	}
+
+	if !allowed && requireLeader {
+		c.JSON(403, gin.H{
+			"message": ErrNotClubLeader,
+		})
+		return db.Club{}, false
+	}
+
+	if !allowed {
+		c.JSON(403, gin.H{
+			"message": ErrNotClubOverseer,
+		})
+		return db.Club{}, false
+	}
+
+	return club, true
+
+}
+
+// reads the membership in the route and checks that the principal led the club in the membership's year. the
+// response is written when they didn't
+func (e *env) routeClubMembership(c *gin.Context, principal auth.Principal) (db.Club, db.Membership, bool) {
+
+	membership, err := e.db.GetMembershipByID(c.Request.Context(), c.Param("clubID"), c.Param("membershipID"))
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return db.Club{}, db.Membership{}, false
+	}
+
+	club, ok := e.routeClub(c, principal, true, membership.Year)
+	if !ok {
+		return db.Club{}, db.Membership{}, false
+	}
+
+	return club, membership, true
+
+}
+
+// fills in a Section 1's club fields from the club the member was an approved member of in the section's year.
+// reports whether there was one
+func (e *env) setSection1Club(ctx context.Context, section *db.Section1) (bool, error) {
+
+	memberships, err := e.db.GetMembershipsByUser(ctx, section.UserID)
+	if err != nil {
+		return false, err
+	}
+
+	var membership db.Membership
+	for _, m := range memberships {
+		if m.Year == section.Year && m.Role == db.MEMBERSHIP_ROLE_MEMBER && m.Status == db.MEMBERSHIP_STATUS_APPROVED {
+			membership = m
+			break
+		}
+	}
+
+	if membership.ID == "" {
+		return false, nil
+	}
+
+	club, err := e.db.GetClubByID(ctx, membership.ClubID)
+	if err != nil {
+		return false, err
+	}
+
+	roster, err := e.db.GetMembershipsByClub(ctx, club.ID, section.Year)
+	if err != nil {
+		return false, err
+	}
+
+	leaders := []string{}
+	members := 0
+	for _, m := range roster {
+		if m.Status != db.MEMBERSHIP_STATUS_APPROVED {
+			continue
+		}
+		switch m.Role {
+		case db.MEMBERSHIP_ROLE_LEADER:
+			leaders = append(leaders, m.UserName)
+		case db.MEMBERSHIP_ROLE_MEMBER:
+			members++
+		}
+	}
+
+	section.ClubID = club.ID
+	section.ClubName = club.Name
+	section.ClubLeader = strings.Join(leaders, ", ")
+	section.NumInClub = members
+
+	return true, nil
+
+}
+
+// AddClub godoc
+// @Summary Creates a club
+// @Description Creates a club led by the signed-in leader for the given program year, the current one by default.
+// @Description The club's county defaults to the leader's. Members join it with the returned join code.
+// @Tags Clubs
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param AddClubInput body api.AddClubInput true "Club information"
+// @Success 201 {object} api.AddClubOutput
+// @Failure 400
+// @Failure 401
+// @Failure 403
+// @Router /clubs [post]
+func (e *env) addClub(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	var input AddClubInput
+	err = c.BindJSON(&input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrBadRequest,
+		})
+		return
+	}
+
+	err = e.validator.Struct(input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": validationMessage(err),
+		})
+		return
+	}
+
+	user, err := e.db.GetUser(c.Request.Context(), principal.UserID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	countyName := ternary(input.CountyName, user.CountyName)
+	if countyName == "" {
+		c.JSON(400, gin.H{
+			"message": ErrMissingClubCounty,
+		})
+		return
+	}
+
+	location := e.userLocation(c.Request.Context(), principal.UserID)
+	year := ternary(input.Year, e.programYear.Current(location))
+
+	clubID := guid.New().String()
+
+	code, err := e.reserveClubCode(c.Request.Context(), clubID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	timestamp := utils.TimeNow()
+
+	club := db.Club{
+		ID:         clubID,
+		Name:       input.Name,
+		CountyName: countyName,
+		JoinCode:   code,
+		CreatedBy:  principal.UserID,
+		GenericDatabaseInfo: db.GenericDatabaseInfo{
+			Created: timestamp.String(),
+			Updated: timestamp.String(),
+		},
+	}
+
+	var output AddClubOutput
+
+	output.Club, err = e.db.UpsertClub(c.Request.Context(), club)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	membership := db.Membership{
+		ID:         guid.New().String(),
+		ClubID:     club.ID,
+		ClubName:   club.Name,
+		CountyName: club.CountyName,
+		UserID:     principal.UserID,
+		UserName:   membershipName(user),
+		Year:       year,
+		Role:       db.MEMBERSHIP_ROLE_LEADER,
+		Status:     db.MEMBERSHIP_STATUS_APPROVED,
+		ApprovedBy: principal.UserID,
+		ApprovedAt: timestamp.String(),
+		GenericDatabaseInfo: db.GenericDatabaseInfo{
+			Created: timestamp.String(),
+			Updated: timestamp.String(),
+		},
+	}
+
+	output.Membership, err = e.db.UpsertMembership(c.Request.Context(), membership)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(201, output)
+
+}
+
+// GetMemberships godoc
+// @Summary Get the user's club memberships
+// @Description Returns the clubs the user has joined or asked to join, with one membership per program year
+// @Tags Clubs
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} api.GetMembershipsOutput
+// @Failure 401
+// @Router /memberships [get]
+func (e *env) getMemberships(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	var output GetMembershipsOutput
+
+	output.Memberships, err = e.db.GetMembershipsByUser(c.Request.Context(), principal.UserID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(200, output)
+
+}
+
+// JoinClub godoc
+// @Summary Asks to join a club
+// @Description Asks to join the club with the given join code for a program year, the current one by default.
+// @Description Members join as members and leaders may join as leaders. One of the club's leaders must approve it.
+// @Tags Clubs
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param JoinClubInput body api.JoinClubInput true "Join code"
+// @Success 201 {object} api.JoinClubOutput
+// @Failure 400
+// @Failure 401
+// @Failure 403
+// @Failure 404
+// @Failure 409
+// @Router /clubs/join [post]
+func (e *env) joinClub(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	var input JoinClubInput
+	err = c.BindJSON(&input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrBadRequest,
+		})
+		return
+	}
+
+	err = e.validator.Struct(input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": validationMessage(err),
+		})
+		return
+	}
+
+	role := ternary(input.Role, db.MEMBERSHIP_ROLE_MEMBER)
+	switch role {
+	case db.MEMBERSHIP_ROLE_MEMBER:
+		if !principal.Can(auth.PERMISSION_RECORDS_WRITE) {
+			c.JSON(403, gin.H{
+				"message": ErrMemberJoin,
+			})
+			return
+		}
+	case db.MEMBERSHIP_ROLE_LEADER:
+		if !principal.HasRole(auth.ROLE_LEADER) {
+			c.JSON(403, gin.H{
+				"message": ErrLeaderJoin,
+			})
+			return
+		}
+	default:
+		c.JSON(400, gin.H{
+			"message": ErrBadMembershipRole,
+		})
+		return
+	}
+
+	clubCode, err := e.db.GetClubCode(c.Request.Context(), normalizeClubCode(input.Code))
+	if err != nil {
+		response := InterpretCosmosError(err)
+		if response.Code == 404 {
+			response.Message = ErrClubCodeNotFound
+		}
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	club, err := e.db.GetClubByID(c.Request.Context(), clubCode.ClubID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	location := e.userLocation(c.Request.Context(), principal.UserID)
+	year := ternary(input.Year, e.programYear.Current(location))
+
+	memberships, err := e.db.GetMembershipsByUser(c.Request.Context(), principal.UserID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	for _, membership := range memberships {
+		if membership.ClubID == club.ID && membership.Year == year {
+			c.JSON(409, gin.H{
+				"message": ErrMembershipConflict,
+			})
+			return
+		}
+	}
+
+	user, err := e.db.GetUser(c.Request.Context(), principal.UserID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	timestamp := utils.TimeNow()
+
+	membership := db.Membership{
+		ID:         guid.New().String(),
+		ClubID:     club.ID,
+		ClubName:   club.Name,
+		CountyName: club.CountyName,
+		UserID:     principal.UserID,
+		UserName:   membershipName(user),
+		Year:       year,
+		Role:       role,
+		Status:     db.MEMBERSHIP_STATUS_PENDING,
+		GenericDatabaseInfo: db.GenericDatabaseInfo{
+			Created: timestamp.String(),
+			Updated: timestamp.String(),
+		},
+	}
+
+	var output JoinClubOutput
+
+	output.Membership, err = e.db.UpsertMembership(c.Request.Context(), membership)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(201, output)
+
+}
+
+// LeaveClub godoc
+// @Summary Leaves a club
+// @Description Removes one of the user's club memberships, or withdraws a request to join that hasn't been approved
+// @Tags Clubs
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param membershipID path string true "Membership ID"
+// @Success 204
+// @Failure 401
+// @Failure 404
+// @Router /memberships/{membershipID} [delete]
+func (e *env) leaveClub(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	membership, err := e.db.GetUserMembershipByID(c.Request.Context(), principal.UserID, c.Param("membershipID"))
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	response, err := e.db.RemoveMembership(c.Request.Context(), membership)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(204, response)
+
+}
+
+// GetClub godoc
+// @Summary Get a club
+// @Description Returns a club to its leaders, the agents for its county, and admins. Only leaders see the join code.
+// @Tags Clubs
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param clubID path string true "Club ID"
+// @Success 200 {object} api.GetClubOutput
+// @Failure 401
+// @Failure 403
+// @Failure 404
+// @Router /club/{clubID} [get]
+func (e *env) getClub(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	location := e.userLocation(c.Request.Context(), principal.UserID)
+	year := e.programYear.Current(location)
+
+	club, ok := e.routeClub(c, principal, false, year)
+	if !ok {
+		return
+	}
+
+	leads, err := e.leadsClub(c.Request.Context(), principal.UserID, club.ID, year)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	if !leads {
+		club.JoinCode = ""
+	}
+
+	c.JSON(200, GetClubOutput{
+		Club: club,
+	})
+
+}
+
+// GetClubMembers godoc
+// @Summary Get a club's roster
+// @Description Returns the club's members and leaders for a program year, the current one by default, including
+// @Description requests to join that haven't been approved
+// @Tags Clubs
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param clubID path string true "Club ID"
+// @Param year query string false "Program year"
+// @Success 200 {object} api.GetMembershipsOutput
+// @Failure 400
+// @Failure 401
+// @Failure 403
+// @Failure 404
+// @Router /club/{clubID}/members [get]
+func (e *env) getClubMembers(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	location := e.userLocation(c.Request.Context(), principal.UserID)
+	year := ternary(c.Query("year"), e.programYear.Current(location))
+	if !e.programYear.IsValid(year) {
+		c.JSON(400, gin.H{
+			"message": ErrBadProgramYear,
+		})
+		return
+	}
+
+	club, ok := e.routeClub(c, principal, false, year)
+	if !ok {
+		return
+	}
+
+	var output GetMembershipsOutput
+
+	output.Memberships, err = e.db.GetMembershipsByClub(c.Request.Context(), club.ID, year)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(200, output)
+
+}
+
+// ApproveClubMember godoc
+// @Summary Approves a request to join a club
+// @Description Approves a member's or leader's request to join the club. Only the club's leaders can approve them.
+// @Tags Clubs
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param clubID path string true "Club ID"
+// @Param membershipID path string true "Membership ID"
+// @Success 200 {object} api.ApproveClubMemberOutput
+// @Failure 401
+// @Failure 403
+// @Failure 404
+// @Router /club/{clubID}/members/{membershipID}/approve [post]
+func (e *env) approveClubMember(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	_, membership, ok := e.routeClubMembership(c, principal)
+	if !ok {
+		return
+	}
+
+	timestamp := utils.TimeNow()
+
+	if membership.Status != db.MEMBERSHIP_STATUS_APPROVED {
+		membership.Status = db.MEMBERSHIP_STATUS_APPROVED
+		membership.ApprovedBy = principal.UserID
+		membership.ApprovedAt = timestamp.String()
+		membership.Updated = timestamp.String()
+	}
+
+	var output ApproveClubMemberOutput
+
+	output.Membership, err = e.db.UpsertMembership(c.Request.Context(), membership)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(200, output)
+
+}
+
+// RemoveClubMember godoc
+// @Summary Removes a member from a club
+// @Description Removes a membership from the club's roster, or turns down a request to join.
+// @Description Only the club's leaders can remove members.
+// @Tags Clubs
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param clubID path string true "Club ID"
+// @Param membershipID path string true "Membership ID"
+// @Success 204
+// @Failure 401
+// @Failure 403
+// @Failure 404
+// @Router /club/{clubID}/members/{membershipID} [delete]
+func (e *env) removeClubMember(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	_, membership, ok := e.routeClubMembership(c, principal)
+	if !ok {
+		return
+	}
+
+	response, err := e.db.RemoveMembership(c.Request.Context(), membership)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(204, response)
+
+}
+
+// ResetClubCode godoc
+// @Summary Replaces a club's join code
+// @Description Gives the club a new join code. The old code stops working. Only the club's leaders can reset it.
+// @Tags Clubs
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param clubID path string true "Club ID"
+// @Success 200 {object} api.ResetClubCodeOutput
+// @Failure 401
+// @Failure 403
+// @Failure 404
+// @Router /club/{clubID}/code [post]
+func (e *env) resetClubCode(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	location := e.userLocation(c.Request.Context(), principal.UserID)
+
+	club, ok := e.routeClub(c, principal, true, e.programYear.Current(location))
+	if !ok {
+		return
+	}
+
+	code, err := e.reserveClubCode(c.Request.Context(), club.ID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	oldCode := club.JoinCode
+	club.JoinCode = code
+	club.Updated = utils.TimeNow().String()
+
+	var output ResetClubCodeOutput
+
+	output.Club, err = e.db.UpsertClub(c.Request.Context(), club)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	if oldCode != "" {
+		_, err = e.db.RemoveClubCode(c.Request.Context(), oldCode)
+		if err != nil && InterpretCosmosError(err).Code != 404 {
+			e.logger.Errorf("Failed to remove old join code of club %s: %v", club.ID, err)
+		}
+	}
+
+	c.JSON(200, output)
+
+}
@@ -0,0 +1,678 @@
+package api
+
+import (
+	"4h-recordbook-backend/internal/auth"
+	"4h-recordbook-backend/internal/utils"
+	"4h-recordbook-backend/pkg/db"
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/beevik/guid"
+	"github.com/gin-gonic/gin"
+)
+
+// someone reading or writing comments on a member's records, and in what role. staff are the people overseeing the
+// member's records rather than the member and their guardians
+type commenter struct {
+	MemberID string
+	ReaderID string
+	Name     string
+	Role     auth.Role
+	Staff    bool
+}
+
+type CommentThread struct {
+	Thread  db.Comment   `json:"thread"`
+	Replies []db.Comment `json:"replies"`
+	Unread  int          `json:"unread"`
+}
+
+type GetCommentThreadsOutput struct {
+	Threads []CommentThread `json:"threads"`
+}
+
+type AddCommentInput struct {
+	EntityType string `json:"entity_type"`
+	EntityID   string `json:"entity_id"`
+	ThreadID   string `json:"thread_id"`
+	Body       string `json:"body" validate:"required"`
+	Visibility string `json:"visibility" validate:"omitempty,oneof=all staff"`
+}
+
+type GetCommentOutput struct {
+	Comment db.Comment `json:"comment"`
+}
+
+type GetCommentReadOutput struct {
+	Read db.CommentRead `json:"read"`
+}
+
+type UnreadComments struct {
+	EntityType string `json:"entity_type"`
+	EntityID   string `json:"entity_id"`
+	Unread     int    `json:"unread"`
+}
+
+type GetUnreadCommentsOutput struct {
+	Unread   int              `json:"unread"`
+	Entities []UnreadComments `json:"entities"`
+}
+
+// the role in which the principal oversees the member's records: as an admin, an agent for the county of one of the
+// member's clubs, a leader of one of them, or a judge assigned to the member's record book. clubs count only for the
+// member's current program year, so leaders and agents lose access when the member moves on. the role is empty when
+// they don't
+func (e *env) reviewerRole(ctx context.Context, principal auth.Principal, memberID string) (auth.Role, error) {
+
+	if principal.HasRole(auth.ROLE_ADMIN) {
+		return auth.ROLE_ADMIN, nil
+	}
+
+	memberships, err := e.db.GetMembershipsByUser(ctx, memberID)
+	if err != nil {
+		return "", err
+	}
+
+	year := e.programYear.Current(e.userLocation(ctx, memberID))
+
+	memberships = slices.DeleteFunc(memberships, func(membership db.Membership) bool {
+		return membership.Year != year || membership.Role != db.MEMBERSHIP_ROLE_MEMBER || membership.Status != db.MEMBERSHIP_STATUS_APPROVED
+	})
+
+	if principal.HasRole(auth.ROLE_AGENT) {
+		counties, err := e.agentCounties(ctx, principal.UserID)
+		if err != nil {
+			return "", err
+		}
+		for _, membership := range memberships {
+			if slices.ContainsFunc(counties, func(county string) bool {
+				return strings.EqualFold(county, membership.CountyName)
+			}) {
+				return auth.ROLE_AGENT, nil
+			}
+		}
+	}
+
+	if principal.HasRole(auth.ROLE_LEADER) {
+		for _, membership := range memberships {
+			leads, err := e.leadsClub(ctx, principal.UserID, membership.ClubID, membership.Year)
+			if err != nil {
+				return "", err
+			}
+			if leads {
+				return auth.ROLE_LEADER, nil
+			}
+		}
+	}
+
+	if principal.HasRole(auth.ROLE_JUDGE) {
+		counties, err := e.judgeCounties(ctx, principal.UserID)
+		if err != nil {
+			return "", err
+		}
+		for _, countyName := range counties {
+			scoresheets, err := e.db.GetScoresheetsByJudge(ctx, countyName, principal.UserID)
+			if err != nil {
+				return "", err
+			}
+			if slices.ContainsFunc(scoresheets, func(scoresheet db.Scoresheet) bool {
+				return scoresheet.MemberID == memberID
+			}) {
+				return auth.ROLE_JUDGE, nil
+			}
+		}
+	}
+
+	return "", nil
+
+}
+
+// whose records the route's comments are on and who is commenting. routes without a member are on the principal's
+// own records, where a guardian acting for the member comments as themselves. the response is written when the
+// principal doesn't oversee the member in the route
+func (e *env) routeCommenter(c *gin.Context) (commenter, bool) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return commenter{}, false
+	}
+
+	memberID := c.Param("memberID")
+
+	if memberID == "" {
+		if principal.IsActing() {
+			return commenter{
+				MemberID: principal.UserID,
+				ReaderID: principal.ActorID,
+				Name:     principal.ActorName,
+				Role:     auth.ROLE_GUARDIAN,
+			}, true
+		}
+		return commenter{
+			MemberID: principal.UserID,
+			ReaderID: principal.UserID,
+			Name:     principal.Name,
+			Role:     auth.ROLE_MEMBER,
+		}, true
+	}
+
+	role, err := e.reviewerRole(c.Request.Context(), principal, memberID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return commenter{}, false
+	}
+
+	if role == "" {
+		c.JSON(403, gin.H{
+			"message": ErrNotRecordReviewer,
+		})
+		return commenter{}, false
+	}
+
+	return commenter{
+		MemberID: memberID,
+		ReaderID: principal.UserID,
+		Name:     principal.Name,
+		Role:     role,
+		Staff:    true,
+	}, true
+
+}
+
+// checks that the member has the record being commented on
+func (e *env) commentEntityExists(ctx context.Context, memberID string, entityType string, entityID string) error {
+	var err error
+	switch entityType {
+	case db.COMMENT_ENTITY_PROJECT:
+		_, err = e.db.GetProjectByID(ctx, memberID, entityID)
+	case db.COMMENT_ENTITY_ANIMAL:
+		_, err = e.db.GetAnimalByID(ctx, memberID, entityID)
+	case db.COMMENT_ENTITY_EXPENSE:
+		_, err = e.db.GetExpenseByID(ctx, memberID, entityID)
+	case db.COMMENT_ENTITY_SECTION:
+		_, err = e.db.GetSectionSummaryByID(ctx, memberID, entityID)
+	}
+	return err
+}
+
+func (cm commenter) canSee(thread db.Comment) bool {
+	return cm.Staff || thread.Visibility != db.COMMENT_VISIBILITY_STAFF
+}
+
+// groups the comments into the threads the commenter can see, each with the number of comments others have left
+// since the commenter last read it
+func (cm commenter) threads(comments []db.Comment, reads []db.CommentRead) []CommentThread {
+
+	readAt := map[string]utils.Timestamp{}
+	for _, read := range reads {
+		timestamp, err := utils.StringToTimestamp(read.ReadAt)
+		if err == nil {
+			readAt[read.ThreadID] = timestamp
+		}
+	}
+
+	threads := []CommentThread{}
+	byThread := map[string]int{}
+
+	for _, comment := range comments {
+		if comment.IsThread() && cm.canSee(comment) {
+			byThread[comment.ID] = len(threads)
+			threads = append(threads, CommentThread{
+				Thread:  comment,
+				Replies: []db.Comment{},
+			})
+		}
+	}
+
+	for _, comment := range comments {
+
+		i, ok := byThread[comment.ThreadID]
+		if !ok {
+			continue
+		}
+
+		if !comment.IsThread() {
+			threads[i].Replies = append(threads[i].Replies, comment)
+		}
+
+		if comment.AuthorID == cm.ReaderID {
+			continue
+		}
+
+		created, err := utils.StringToTimestamp(comment.Created)
+		last, read := readAt[comment.ThreadID]
+		if !read || (err == nil && last.Before(created)) {
+			threads[i].Unread++
+		}
+
+	}
+
+	return threads
+
+}
+
+// reads the thread the route's comment starts, which the commenter must be able to see. the response is written when
+// it can't be read
+func (e *env) routeCommentThread(c *gin.Context, cm commenter) (db.Comment, bool) {
+
+	thread, err := e.db.GetCommentByID(c.Request.Context(), cm.MemberID, c.Param("commentID"))
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return db.Comment{}, false
+	}
+
+	if !thread.IsThread() || !cm.canSee(thread) {
+		c.JSON(404, gin.H{
+			"message": ErrNotFound,
+		})
+		return db.Comment{}, false
+	}
+
+	return thread, true
+
+}
+
+func (e *env) markCommentThreadRead(ctx context.Context, cm commenter, threadID string) (db.CommentRead, error) {
+
+	timestamp := utils.TimeNow()
+
+	return e.db.UpsertCommentRead(ctx, db.CommentRead{
+		ID:       cm.MemberID + "_" + threadID,
+		UserID:   cm.ReaderID,
+		MemberID: cm.MemberID,
+		ThreadID: threadID,
+		ReadAt:   timestamp.String(),
+		GenericDatabaseInfo: db.GenericDatabaseInfo{
+			Created: timestamp.String(),
+			Updated: timestamp.String(),
+		},
+	})
+
+}
+
+// GetComments godoc
+// @Summary Get comment threads on a member's records
+// @Description Returns the comment threads on one of a member's records, or on all of them when no record is given,
+// @Description with the number of comments in each that the signed-in user hasn't read. Under /member/{memberID}
+// @Description the threads are on that member's records, for the people overseeing them, who also see staff-only
+// @Description threads. Otherwise they are on the signed-in member's own records.
+// @Tags Comments
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param entity_type query string false "Record type: project, animal, expense or section"
+// @Param entity_id query string false "Record ID"
+// @Success 200 {object} api.GetCommentThreadsOutput
+// @Failure 400
+// @Failure 401
+// @Failure 403
+// @Router /comments [get]
+// @Router /member/{memberID}/comments [get]
+func (e *env) getComments(c *gin.Context) {
+
+	cm, ok := e.routeCommenter(c)
+	if !ok {
+		return
+	}
+
+	entityType := c.Query("entity_type")
+	entityID := c.Query("entity_id")
+	if (entityType != "" || entityID != "") && (!slices.Contains(db.COMMENT_ENTITY_TYPES, entityType) || entityID == "") {
+		c.JSON(400, gin.H{
+			"message": ErrBadCommentEntity,
+		})
+		return
+	}
+
+	comments, err := e.db.GetCommentsByUser(c.Request.Context(), cm.MemberID, entityType, entityID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	reads, err := e.db.GetCommentReadsByUser(c.Request.Context(), cm.ReaderID, cm.MemberID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(200, GetCommentThreadsOutput{
+		Threads: cm.threads(comments, reads),
+	})
+
+}
+
+// GetUnreadComments godoc
+// @Summary Count unread comments on a member's records
+// @Description Returns how many comments the signed-in user hasn't read on a member's records, in total and for
+// @Description each record with unread comments
+// @Tags Comments
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} api.GetUnreadCommentsOutput
+// @Failure 401
+// @Failure 403
+// @Router /comments/unread [get]
+// @Router /member/{memberID}/comments/unread [get]
+func (e *env) getUnreadComments(c *gin.Context) {
+
+	cm, ok := e.routeCommenter(c)
+	if !ok {
+		return
+	}
+
+	comments, err := e.db.GetCommentsByUser(c.Request.Context(), cm.MemberID, "", "")
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	reads, err := e.db.GetCommentReadsByUser(c.Request.Context(), cm.ReaderID, cm.MemberID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	output := GetUnreadCommentsOutput{
+		Entities: []UnreadComments{},
+	}
+
+	for _, thread := range cm.threads(comments, reads) {
+
+		if thread.Unread == 0 {
+			continue
+		}
+		output.Unread += thread.Unread
+
+		i := slices.IndexFunc(output.Entities, func(entity UnreadComments) bool {
+			return entity.EntityType == thread.Thread.EntityType && entity.EntityID == thread.Thread.EntityID
+		})
+		if i < 0 {
+			output.Entities = append(output.Entities, UnreadComments{
+				EntityType: thread.Thread.EntityType,
+				EntityID:   thread.Thread.EntityID,
+			})
+			i = len(output.Entities) - 1
+		}
+		output.Entities[i].Unread += thread.Unread
+
+	}
+
+	c.JSON(200, output)
+
+}
+
+// AddComment godoc
+// @Summary Comment on a member's record
+// @Description Starts a comment thread on one of a member's projects, animals, expenses or resume section entries,
+// @Description or replies to a thread when thread_id is given. Threads are visible to everyone who can see the
+// @Description member's records unless they are started as staff-only, which only the people overseeing the
+// @Description member's records can do. Writing a comment marks its thread as read.
+// @Tags Comments
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param AddCommentInput body api.AddCommentInput true "Comment information"
+// @Success 201 {object} api.GetCommentOutput
+// @Failure 400
+// @Failure 401
+// @Failure 403
+// @Failure 404
+// @Router /comments [post]
+// @Router /member/{memberID}/comments [post]
+func (e *env) addComment(c *gin.Context) {
+
+	cm, ok := e.routeCommenter(c)
+	if !ok {
+		return
+	}
+
+	var input AddCommentInput
+	err := c.BindJSON(&input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrBadRequest,
+		})
+		return
+	}
+
+	err = e.validator.Struct(input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrMissingFields,
+		})
+		return
+	}
+
+	g := guid.New()
+	timestamp := utils.TimeNow()
+
+	comment := db.Comment{
+		ID:         g.String(),
+		UserID:     cm.MemberID,
+		ThreadID:   g.String(),
+		AuthorID:   cm.ReaderID,
+		AuthorName: cm.Name,
+		AuthorRole: string(cm.Role),
+		Body:       input.Body,
+		Visibility: ternary(input.Visibility, db.COMMENT_VISIBILITY_ALL),
+		GenericDatabaseInfo: db.GenericDatabaseInfo{
+			Created: timestamp.String(),
+			Updated: timestamp.String(),
+		},
+	}
+
+	if input.ThreadID != "" {
+
+		thread, err := e.db.GetCommentByID(c.Request.Context(), cm.MemberID, input.ThreadID)
+		if err != nil {
+			response := InterpretCosmosError(err)
+			c.JSON(response.Code, gin.H{
+				"message": response.Message,
+			})
+			return
+		}
+
+		if !thread.IsThread() || !cm.canSee(thread) {
+			c.JSON(404, gin.H{
+				"message": ErrNotFound,
+			})
+			return
+		}
+
+		comment.ThreadID = thread.ID
+		comment.EntityType = thread.EntityType
+		comment.EntityID = thread.EntityID
+		comment.Visibility = thread.Visibility
+
+	} else {
+
+		if !slices.Contains(db.COMMENT_ENTITY_TYPES, input.EntityType) || input.EntityID == "" {
+			c.JSON(400, gin.H{
+				"message": ErrBadCommentEntity,
+			})
+			return
+		}
+
+		if comment.Visibility == db.COMMENT_VISIBILITY_STAFF && !cm.Staff {
+			c.JSON(403, gin.H{
+				"message": ErrStaffComment,
+			})
+			return
+		}
+
+		err = e.commentEntityExists(c.Request.Context(), cm.MemberID, input.EntityType, input.EntityID)
+		if err != nil {
+			response := InterpretCosmosError(err)
+			c.JSON(response.Code, gin.H{
+				"message": response.Message,
+			})
+			return
+		}
+
+		comment.EntityType = input.EntityType
+		comment.EntityID = input.EntityID
+
+	}
+
+	var output GetCommentOutput
+
+	output.Comment, err = e.db.UpsertComment(c.Request.Context(), comment)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	_, err = e.markCommentThreadRead(c.Request.Context(), cm, comment.ThreadID)
+	if err != nil {
+		e.logger.Warnf("Failed to mark comment thread %s as read: %v", comment.ThreadID, err)
+	}
+
+	c.JSON(201, output)
+
+}
+
+// resolves or reopens the thread in the route
+func (e *env) setCommentThreadResolved(c *gin.Context, resolved bool) {
+
+	cm, ok := e.routeCommenter(c)
+	if !ok {
+		return
+	}
+
+	thread, ok := e.routeCommentThread(c, cm)
+	if !ok {
+		return
+	}
+
+	timestamp := utils.TimeNow()
+
+	thread.Resolved = resolved
+	thread.ResolvedBy = ""
+	thread.ResolvedAt = ""
+	if resolved {
+		thread.ResolvedBy = cm.ReaderID
+		thread.ResolvedAt = timestamp.String()
+	}
+	thread.Updated = timestamp.String()
+
+	var output GetCommentOutput
+	var err error
+
+	output.Comment, err = e.db.UpsertComment(c.Request.Context(), thread)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(200, output)
+
+}
+
+// ResolveComment godoc
+// @Summary Resolve a comment thread
+// @Description Marks a comment thread as resolved. Anyone who can see the thread can resolve it.
+// @Tags Comments
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param commentID path string true "ID of the thread's first comment"
+// @Success 200 {object} api.GetCommentOutput
+// @Failure 401
+// @Failure 403
+// @Failure 404
+// @Router /comment/{commentID}/resolve [post]
+// @Router /member/{memberID}/comment/{commentID}/resolve [post]
+func (e *env) resolveComment(c *gin.Context) {
+	e.setCommentThreadResolved(c, true)
+}
+
+// UnresolveComment godoc
+// @Summary Reopen a comment thread
+// @Description Marks a resolved comment thread as unresolved. Anyone who can see the thread can reopen it.
+// @Tags Comments
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param commentID path string true "ID of the thread's first comment"
+// @Success 200 {object} api.GetCommentOutput
+// @Failure 401
+// @Failure 403
+// @Failure 404
+// @Router /comment/{commentID}/unresolve [post]
+// @Router /member/{memberID}/comment/{commentID}/unresolve [post]
+func (e *env) unresolveComment(c *gin.Context) {
+	e.setCommentThreadResolved(c, false)
+}
+
+// ReadComment godoc
+// @Summary Mark a comment thread as read
+// @Description Marks every comment in a thread as read by the signed-in user, which clears it from their unread
+// @Description counts
+// @Tags Comments
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param commentID path string true "ID of the thread's first comment"
+// @Success 200 {object} api.GetCommentReadOutput
+// @Failure 401
+// @Failure 403
+// @Failure 404
+// @Router /comment/{commentID}/read [post]
+// @Router /member/{memberID}/comment/{commentID}/read [post]
+func (e *env) readComment(c *gin.Context) {
+
+	cm, ok := e.routeCommenter(c)
+	if !ok {
+		return
+	}
+
+	thread, ok := e.routeCommentThread(c, cm)
+	if !ok {
+		return
+	}
+
+	var output GetCommentReadOutput
+	var err error
+
+	output.Read, err = e.markCommentThreadRead(c.Request.Context(), cm, thread.ID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(200, output)
+
+}
@@ -1,8 +1,10 @@
 package api
 
 import (
+	"4h-recordbook-backend/internal/auth"
 	"4h-recordbook-backend/internal/utils"
 	"4h-recordbook-backend/pkg/db"
+	"math"
 	"strconv"
 
 	"github.com/beevik/guid"
@@ -21,6 +23,7 @@ type GetDailyFeedOutput struct {
 type UpsertDailyFeedInput struct {
 	FeedDate       string   `json:"feed_date" validate:"required"`
 	FeedAmount     *float64 `json:"feed_amount" validate:"required"`
+	FeedAmountUnit string   `json:"feed_amount_unit"`
 	AnimalID       string   `json:"animal_id" validate:"required"`
 	FeedID         string   `json:"feed_id" validate:"required"`
 	FeedPurchaseID string   `json:"feed_purchase_id" validate:"required"`
@@ -29,6 +32,38 @@ type UpsertDailyFeedInput struct {
 
 type UpsertDailyFeedOutput GetDailyFeedOutput
 
+const (
+	DAILY_FEED_BATCH_MODE_ROWS  = "rows"
+	DAILY_FEED_BATCH_MODE_SPLIT = "split"
+)
+
+type SplitDailyFeedInput struct {
+	FeedDate       string   `json:"feed_date" validate:"required"`
+	TotalAmount    *float64 `json:"total_amount" validate:"required"`
+	TotalUnit      string   `json:"total_unit"`
+	AnimalIDs      []string `json:"animal_ids" validate:"required,min=1,dive,required"`
+	FeedID         string   `json:"feed_id" validate:"required"`
+	FeedPurchaseID string   `json:"feed_purchase_id" validate:"required"`
+	ProjectID      string   `json:"project_id" validate:"required"`
+}
+
+type AddDailyFeedBatchInput struct {
+	Mode       string                 `json:"mode" validate:"omitempty,oneof=rows split"`
+	DailyFeeds []UpsertDailyFeedInput `json:"daily_feeds"`
+	Split      *SplitDailyFeedInput   `json:"split"`
+}
+
+type DailyFeedBatchResult struct {
+	Index     int           `json:"index"`
+	Status    int           `json:"status"`
+	Message   string        `json:"message,omitempty"`
+	DailyFeed *db.DailyFeed `json:"daily_feed,omitempty"`
+}
+
+type AddDailyFeedBatchOutput struct {
+	Results []DailyFeedBatchResult `json:"results"`
+}
+
 // GetDailyFeeds godoc
 // @Summary Get daily feeds by project and animal
 // @Description Gets all of a user's daily feeds for a given project and animal
@@ -47,7 +82,7 @@ type UpsertDailyFeedOutput GetDailyFeedOutput
 // @Router /project/{projectID}/animal/{animalID}/daily-feed [get]
 func (e *env) getDailyFeeds(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -66,7 +101,7 @@ func (e *env) getDailyFeeds(c *gin.Context) {
 		SortByNewest: c.GetBool(CONTEXT_KEY_SORT_BY_NEWEST),
 	}
 
-	output.DailyFeeds, err = e.db.GetDailyFeedsByProjectAndAnimal(c.Request.Context(), claims.ID, projectID, animalID, paginationOptions)
+	output.DailyFeeds, err = e.db.GetDailyFeedsByProjectAndAnimal(c.Request.Context(), principal.UserID, projectID, animalID, paginationOptions)
 	if err != nil {
 		e.logger.Info(err)
 		response := InterpretCosmosError(err)
@@ -91,6 +126,8 @@ func (e *env) getDailyFeeds(c *gin.Context) {
 		output.Next = utils.BuildNextUrl(nextUrlInput)
 	}
 
+	output.DailyFeeds = localizeDailyFeeds(output.DailyFeeds, e.preferredMassUnit(c.Request.Context(), principal.UserID))
+
 	c.JSON(200, output)
 
 }
@@ -109,7 +146,7 @@ func (e *env) getDailyFeeds(c *gin.Context) {
 // @Router /daily-feed/{dailyFeedID} [get]
 func (e *env) getDailyFeed(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -121,7 +158,7 @@ func (e *env) getDailyFeed(c *gin.Context) {
 
 	var output GetDailyFeedOutput
 
-	output.DailyFeed, err = e.db.GetDailyFeedByID(c.Request.Context(), claims.ID, dailyFeedID)
+	output.DailyFeed, err = e.db.GetDailyFeedByID(c.Request.Context(), principal.UserID, dailyFeedID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -130,6 +167,8 @@ func (e *env) getDailyFeed(c *gin.Context) {
 		return
 	}
 
+	output.DailyFeed = localizeDailyFeed(output.DailyFeed, e.preferredMassUnit(c.Request.Context(), principal.UserID))
+
 	c.JSON(200, output)
 
 }
@@ -137,6 +176,7 @@ func (e *env) getDailyFeed(c *gin.Context) {
 // AddDailyFeed godoc
 // @Summary Add a daily feed
 // @Description Adds a daily feed to a user's personal records
+// @Description feed_amount_unit is lb, oz, kg or g (default the user's preferred unit), or the custom unit of the feed purchase such as a bag or flake.
 // @Tags Daily Feed
 // @Accept json
 // @Produce json
@@ -145,10 +185,11 @@ func (e *env) getDailyFeed(c *gin.Context) {
 // @Success 201 {object} api.UpsertDailyFeedOutput
 // @Failure 400
 // @Failure 401
+// @Failure 404
 // @Router /daily-feed [post]
 func (e *env) addDailyFeed(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -173,7 +214,9 @@ func (e *env) addDailyFeed(c *gin.Context) {
 		return
 	}
 
-	feedDate, err := utils.StringToTimestamp(input.FeedDate)
+	location := e.userLocation(c.Request.Context(), principal.UserID)
+
+	feedDate, err := utils.StringToDate(input.FeedDate, location)
 	if err != nil {
 		c.JSON(400, gin.H{
 			"message": ErrBadDate,
@@ -181,18 +224,31 @@ func (e *env) addDailyFeed(c *gin.Context) {
 		return
 	}
 
+	if !e.checkProjectRecordPage(c, principal.UserID, input.ProjectID, db.RECORD_PAGE_DAILY_FEEDS) {
+		return
+	}
+
+	unit := e.preferredMassUnit(c.Request.Context(), principal.UserID)
+
+	feedAmount, err := e.feedAmountToPounds(c.Request.Context(), principal.UserID, input.FeedPurchaseID, *input.FeedAmount, input.FeedAmountUnit, unit, map[string]db.FeedPurchase{})
+	if err != nil {
+		respondUnitError(c, err)
+		return
+	}
+
 	g := guid.New()
 	timestamp := utils.TimeNow()
 
 	dailyFeed := db.DailyFeed{
 		ID:             g.String(),
 		FeedDate:       feedDate.String(),
-		FeedAmount:     *input.FeedAmount,
+		FeedAmount:     feedAmount,
+		FeedAmountUnit: utils.CANONICAL_MASS_UNIT,
 		AnimalID:       input.AnimalID,
 		FeedID:         input.FeedID,
 		FeedPurchaseID: input.FeedPurchaseID,
 		ProjectID:      input.ProjectID,
-		UserID:         claims.ID,
+		UserID:         principal.UserID,
 		GenericDatabaseInfo: db.GenericDatabaseInfo{
 			Created: timestamp.String(),
 			Updated: timestamp.String(),
@@ -210,10 +266,227 @@ func (e *env) addDailyFeed(c *gin.Context) {
 		return
 	}
 
+	output.DailyFeed = localizeDailyFeed(output.DailyFeed, unit)
+
 	c.JSON(201, output)
 
 }
 
+// AddDailyFeedBatch godoc
+// @Summary Add many daily feeds at once
+// @Description Adds a batch of daily feeds to a user's personal records. In "rows" mode (default) each entry of daily_feeds is added as given.
+// @Description In "split" mode the total amount is divided evenly across the given animals for one date.
+// @Description Every row is validated before anything is written, and the rows are written in a single transaction.
+// @Tags Daily Feed
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param AddDailyFeedBatchInput body api.AddDailyFeedBatchInput true "Daily Feed batch information"
+// @Success 201 {object} api.AddDailyFeedBatchOutput
+// @Failure 400 {object} api.AddDailyFeedBatchOutput
+// @Failure 401
+// @Failure 404
+// @Router /daily-feed/batch [post]
+func (e *env) addDailyFeedBatch(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	var input AddDailyFeedBatchInput
+	err = c.BindJSON(&input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrBadRequest,
+		})
+		return
+	}
+
+	err = e.validator.Struct(input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrBadBatchMode,
+		})
+		return
+	}
+
+	rows := input.DailyFeeds
+	if input.Mode == DAILY_FEED_BATCH_MODE_SPLIT {
+		if input.Split == nil {
+			c.JSON(400, gin.H{
+				"message": ErrMissingFields,
+			})
+			return
+		}
+		err = e.validator.Struct(input.Split)
+		if err != nil {
+			c.JSON(400, gin.H{
+				"message": ErrMissingFields,
+			})
+			return
+		}
+		rows = splitDailyFeed(*input.Split)
+	}
+
+	if len(rows) == 0 || len(rows) > db.MAX_BATCH_SIZE {
+		c.JSON(400, gin.H{
+			"message": ErrBatchSize,
+		})
+		return
+	}
+
+	timestamp := utils.TimeNow()
+	unit := e.preferredMassUnit(c.Request.Context(), principal.UserID)
+	location := e.userLocation(c.Request.Context(), principal.UserID)
+	purchases := make(map[string]db.FeedPurchase)
+
+	var output AddDailyFeedBatchOutput
+	dailyFeeds := []db.DailyFeed{}
+	valid := true
+
+	for i, row := range rows {
+
+		result := DailyFeedBatchResult{
+			Index: i,
+		}
+
+		err = e.validator.Struct(row)
+		if err != nil {
+			result.Status = 400
+			result.Message = ErrMissingFields
+			output.Results = append(output.Results, result)
+			valid = false
+			continue
+		}
+
+		feedDate, err := utils.StringToDate(row.FeedDate, location)
+		if err != nil {
+			result.Status = 400
+			result.Message = ErrBadDate
+			output.Results = append(output.Results, result)
+			valid = false
+			continue
+		}
+
+		feedAmount, err := e.feedAmountToPounds(c.Request.Context(), principal.UserID, row.FeedPurchaseID, *row.FeedAmount, row.FeedAmountUnit, unit, purchases)
+		if err != nil {
+			result.Status, result.Message = unitErrorResponse(err)
+			output.Results = append(output.Results, result)
+			valid = false
+			continue
+		}
+
+		g := guid.New()
+
+		dailyFeed := db.DailyFeed{
+			ID:             g.String(),
+			FeedDate:       feedDate.String(),
+			FeedAmount:     feedAmount,
+			FeedAmountUnit: utils.CANONICAL_MASS_UNIT,
+			AnimalID:       row.AnimalID,
+			FeedID:         row.FeedID,
+			FeedPurchaseID: row.FeedPurchaseID,
+			ProjectID:      row.ProjectID,
+			UserID:         principal.UserID,
+			GenericDatabaseInfo: db.GenericDatabaseInfo{
+				Created: timestamp.String(),
+				Updated: timestamp.String(),
+			},
+		}
+
+		dailyFeeds = append(dailyFeeds, dailyFeed)
+		output.Results = append(output.Results, result)
+
+	}
+
+	if !valid {
+		// rows that passed validation were not written because the batch is all or nothing
+		for i := range output.Results {
+			if output.Results[i].Status == 0 {
+				output.Results[i].Status = 424
+				output.Results[i].Message = ErrBatchRowNotWritten
+			}
+		}
+		c.JSON(400, output)
+		return
+	}
+
+	checkedProjects := make(map[string]bool)
+	for _, dailyFeed := range dailyFeeds {
+		if checkedProjects[dailyFeed.ProjectID] {
+			continue
+		}
+		if !e.checkProjectRecordPage(c, principal.UserID, dailyFeed.ProjectID, db.RECORD_PAGE_DAILY_FEEDS) {
+			return
+		}
+		checkedProjects[dailyFeed.ProjectID] = true
+	}
+
+	statusCodes, err := e.db.UpsertDailyFeedBatch(c.Request.Context(), principal.UserID, dailyFeeds)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	committed := true
+	for i, statusCode := range statusCodes {
+		dailyFeeds[i] = localizeDailyFeed(dailyFeeds[i], unit)
+		output.Results[i].DailyFeed = &dailyFeeds[i]
+		output.Results[i].Status = statusCode
+		if statusCode >= 300 {
+			committed = false
+			output.Results[i].DailyFeed = nil
+			output.Results[i].Message = ternary(HTTPResponseCodeMap[statusCode], "unexpected error")
+		}
+	}
+
+	if !committed {
+		c.JSON(400, output)
+		return
+	}
+
+	c.JSON(201, output)
+
+}
+
+// divides the total amount evenly between the animals, with any rounding remainder going to the last animal
+func splitDailyFeed(split SplitDailyFeedInput) []UpsertDailyFeedInput {
+
+	rows := []UpsertDailyFeedInput{}
+
+	count := float64(len(split.AnimalIDs))
+	share := math.Floor(*split.TotalAmount/count*100) / 100
+	remaining := *split.TotalAmount
+
+	for i, animalID := range split.AnimalIDs {
+		amount := share
+		if i == len(split.AnimalIDs)-1 {
+			amount = math.Round(remaining*100) / 100
+		}
+		remaining -= amount
+
+		rows = append(rows, UpsertDailyFeedInput{
+			FeedDate:       split.FeedDate,
+			FeedAmount:     &amount,
+			FeedAmountUnit: split.TotalUnit,
+			AnimalID:       animalID,
+			FeedID:         split.FeedID,
+			FeedPurchaseID: split.FeedPurchaseID,
+			ProjectID:      split.ProjectID,
+		})
+	}
+
+	return rows
+
+}
+
 // UpdateDailyFeed godoc
 // @Summary Update a daily feed
 // @Description Updates a user's daily feed information
@@ -230,7 +503,7 @@ func (e *env) addDailyFeed(c *gin.Context) {
 // @Router /daily-feed/{dailyFeedID} [put]
 func (e *env) updateDailyFeed(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -255,7 +528,9 @@ func (e *env) updateDailyFeed(c *gin.Context) {
 		return
 	}
 
-	feedDate, err := utils.StringToTimestamp(input.FeedDate)
+	location := e.userLocation(c.Request.Context(), principal.UserID)
+
+	feedDate, err := utils.StringToDate(input.FeedDate, location)
 	if err != nil {
 		c.JSON(400, gin.H{
 			"message": ErrBadDate,
@@ -265,7 +540,7 @@ func (e *env) updateDailyFeed(c *gin.Context) {
 
 	dailyFeedID := c.Param("dailyFeedID")
 
-	dailyFeed, err := e.db.GetDailyFeedByID(c.Request.Context(), claims.ID, dailyFeedID)
+	dailyFeed, err := e.db.GetDailyFeedByID(c.Request.Context(), principal.UserID, dailyFeedID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -274,17 +549,26 @@ func (e *env) updateDailyFeed(c *gin.Context) {
 		return
 	}
 
+	unit := e.preferredMassUnit(c.Request.Context(), principal.UserID)
+
+	feedAmount, err := e.feedAmountToPounds(c.Request.Context(), principal.UserID, dailyFeed.FeedPurchaseID, *input.FeedAmount, input.FeedAmountUnit, unit, map[string]db.FeedPurchase{})
+	if err != nil {
+		respondUnitError(c, err)
+		return
+	}
+
 	timestamp := utils.TimeNow()
 
 	updatedDailyFeed := db.DailyFeed{
 		ID:             dailyFeed.ID,
 		FeedDate:       feedDate.String(),
-		FeedAmount:     *input.FeedAmount,
+		FeedAmount:     feedAmount,
+		FeedAmountUnit: utils.CANONICAL_MASS_UNIT,
 		AnimalID:       dailyFeed.AnimalID,
 		FeedID:         dailyFeed.FeedID,
 		FeedPurchaseID: dailyFeed.FeedPurchaseID,
 		ProjectID:      dailyFeed.ProjectID,
-		UserID:         claims.ID,
+		UserID:         principal.UserID,
 		GenericDatabaseInfo: db.GenericDatabaseInfo{
 			Created: dailyFeed.Created,
 			Updated: timestamp.String(),
@@ -302,6 +586,8 @@ func (e *env) updateDailyFeed(c *gin.Context) {
 		return
 	}
 
+	output.DailyFeed = localizeDailyFeed(output.DailyFeed, unit)
+
 	c.JSON(200, output)
 
 }
@@ -320,7 +606,7 @@ func (e *env) updateDailyFeed(c *gin.Context) {
 // @Router /daily-feed/{dailyFeedID} [delete]
 func (e *env) deleteDailyFeed(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -330,7 +616,7 @@ func (e *env) deleteDailyFeed(c *gin.Context) {
 
 	dailyFeedID := c.Param("dailyFeedID")
 
-	response, err := e.db.RemoveDailyFeed(c.Request.Context(), claims.ID, dailyFeedID)
+	response, err := e.db.RemoveDailyFeed(c.Request.Context(), principal.UserID, dailyFeedID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -0,0 +1,315 @@
+package api
+
+import (
+	"4h-recordbook-backend/internal/auth"
+	"4h-recordbook-backend/internal/utils"
+	"4h-recordbook-backend/pkg/db"
+	"context"
+	"math"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	RESUME_SECTION_COUNT = 14
+
+	// members' partitions are read in parallel, but no more than this many at once
+	DASHBOARD_CONCURRENCY = 8
+)
+
+type DashboardProject struct {
+	ID                        string `json:"id"`
+	Name                      string `json:"name"`
+	Type                      string `json:"type"`
+	Kind                      string `json:"kind"`
+	Updated                   string `json:"updated"`
+	DailyFeeds                int    `json:"daily_feeds"`
+	MissingFeedLogs           bool   `json:"missing_feed_logs"`
+	Expenses                  int    `json:"expenses"`
+	Supplies                  int    `json:"supplies"`
+	FinancialSummaryAvailable bool   `json:"financial_summary_available"`
+}
+
+type DashboardMember struct {
+	MembershipID          string             `json:"membership_id"`
+	UserID                string             `json:"user_id"`
+	Name                  string             `json:"name"`
+	Started               bool               `json:"started"`
+	ProjectCount          int                `json:"project_count"`
+	LastUpdated           string             `json:"last_updated"`
+	Projects              []DashboardProject `json:"projects"`
+	ResumeSections        []int              `json:"resume_sections"`
+	MissingResumeSections []int              `json:"missing_resume_sections"`
+	ResumeCompleteness    float64            `json:"resume_completeness"`
+}
+
+type GetClubDashboardOutput struct {
+	ClubID  string            `json:"club_id"`
+	Year    string            `json:"year"`
+	Members []DashboardMember `json:"members"`
+	Next    string            `json:"next"`
+}
+
+// the slice of a list that makes up a page
+func pageBounds(paginationOptions db.PaginationOptions, length int) (int, int) {
+	start := min(paginationOptions.Page*paginationOptions.PerPage, length)
+	end := min(start+paginationOptions.PerPage, length)
+	return start, end
+}
+
+func laterTimestamp(current string, candidate string) string {
+	c, err := utils.StringToTimestamp(candidate)
+	if err != nil {
+		return current
+	}
+	if l, err := utils.StringToTimestamp(current); err == nil && !l.Before(c) {
+		return current
+	}
+	return candidate
+}
+
+// one member's progress on their record book for the year. only projects of the given type are included when the
+// type isn't empty
+func (e *env) dashboardMember(ctx context.Context, membership db.Membership, year string, projectType string) (DashboardMember, error) {
+
+	member := DashboardMember{
+		MembershipID:          membership.ID,
+		UserID:                membership.UserID,
+		Name:                  membership.UserName,
+		Projects:              []DashboardProject{},
+		ResumeSections:        []int{},
+		MissingResumeSections: []int{},
+	}
+
+	projects, err := e.db.GetProjectsByYear(ctx, membership.UserID, year, db.PaginationOptions{
+		Page:    0,
+		PerPage: e.config.MaxPageSize,
+	})
+	if err != nil {
+		return member, err
+	}
+
+	for _, project := range projects {
+
+		if projectType != "" && !strings.EqualFold(project.Type, projectType) {
+			continue
+		}
+
+		counts, err := e.db.GetProjectRecordCounts(ctx, membership.UserID, project.ID)
+		if err != nil {
+			return member, err
+		}
+
+		member.Projects = append(member.Projects, DashboardProject{
+			ID:                        project.ID,
+			Name:                      project.Name,
+			Type:                      project.Type,
+			Kind:                      project.CurrentKind(),
+			Updated:                   project.Updated,
+			DailyFeeds:                counts[db.RECORD_PAGE_DAILY_FEEDS],
+			MissingFeedLogs:           project.HasRecordPage(db.RECORD_PAGE_DAILY_FEEDS) && counts[db.RECORD_PAGE_DAILY_FEEDS] == 0,
+			Expenses:                  counts[db.RECORD_PAGE_EXPENSES],
+			Supplies:                  counts[db.RECORD_PAGE_SUPPLIES],
+			FinancialSummaryAvailable: project.HasRecordPage(db.RECORD_PAGE_EXPENSES) && counts[db.RECORD_PAGE_EXPENSES]+counts[db.RECORD_PAGE_SUPPLIES] > 0,
+		})
+
+		member.LastUpdated = laterTimestamp(member.LastUpdated, project.Updated)
+
+	}
+
+	member.ProjectCount = len(member.Projects)
+
+	sections, err := e.db.GetSectionSummariesByYear(ctx, membership.UserID, year)
+	if err != nil {
+		return member, err
+	}
+
+	completed := make(map[int]bool)
+	for _, section := range sections {
+		completed[section.Section] = true
+		member.LastUpdated = laterTimestamp(member.LastUpdated, section.Updated)
+	}
+
+	for section := 1; section <= RESUME_SECTION_COUNT; section++ {
+		if completed[section] {
+			member.ResumeSections = append(member.ResumeSections, section)
+		} else {
+			member.MissingResumeSections = append(member.MissingResumeSections, section)
+		}
+	}
+
+	member.ResumeCompleteness = math.Round(float64(len(member.ResumeSections))/RESUME_SECTION_COUNT*100) / 100
+	member.Started = len(projects) > 0 || len(sections) > 0
+
+	return member, nil
+
+}
+
+// GetClubDashboard godoc
+// @Summary Get a club's record book dashboard
+// @Description Returns each approved member's progress on their record book for a program year, the current one by
+// @Description default: their projects with feed log and expense counts, when they last updated anything, and which
+// @Description resume sections they haven't started. Members are sorted by name. When a project type is given,
+// @Description only projects of that type are included, and only members with one of them are listed.
+// @Description Available to the club's leaders, the agents for its county and admins.
+// @Tags Clubs
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param clubID path string true "Club ID"
+// @Param year query string false "Program year"
+// @Param project_type query string false "Project type"
+// @Param page query int false "Page number, default 0"
+// @Param per_page query int false "Max number of members to return. Can be [1-200], default 100"
+// @Success 200 {object} api.GetClubDashboardOutput
+// @Failure 400
+// @Failure 401
+// @Failure 403
+// @Failure 404
+// @Router /club/{clubID}/dashboard [get]
+func (e *env) getClubDashboard(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	location := e.userLocation(c.Request.Context(), principal.UserID)
+	year := ternary(c.Query("year"), e.programYear.Current(location))
+	if !e.programYear.IsValid(year) {
+		c.JSON(400, gin.H{
+			"message": ErrBadProgramYear,
+		})
+		return
+	}
+
+	projectType := c.Query("project_type")
+
+	club, ok := e.routeClub(c, principal, false, year)
+	if !ok {
+		return
+	}
+
+	roster, err := e.db.GetMembershipsByClub(c.Request.Context(), club.ID, year)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	memberships := []db.Membership{}
+	for _, membership := range roster {
+		if membership.Role == db.MEMBERSHIP_ROLE_MEMBER && membership.Status == db.MEMBERSHIP_STATUS_APPROVED {
+			memberships = append(memberships, membership)
+		}
+	}
+
+	sort.SliceStable(memberships, func(i, j int) bool {
+		return strings.ToLower(memberships[i].UserName) < strings.ToLower(memberships[j].UserName)
+	})
+
+	paginationOptions := db.PaginationOptions{
+		Page:    c.GetInt(CONTEXT_KEY_PAGE),
+		PerPage: c.GetInt(CONTEXT_KEY_PER_PAGE),
+	}
+
+	// with a project type only members with a project of that type are listed. counting them is a single query per
+	// member, so they are filtered before paging and only the requested page's dashboards are read
+	if projectType != "" {
+
+		counts := make([]int, len(memberships))
+
+		group, ctx := errgroup.WithContext(c.Request.Context())
+		group.SetLimit(DASHBOARD_CONCURRENCY)
+
+		for i, membership := range memberships {
+			group.Go(func() error {
+				count, err := e.db.CountProjectsByYearAndType(ctx, membership.UserID, year, projectType)
+				counts[i] = count
+				return err
+			})
+		}
+
+		err = group.Wait()
+		if err != nil {
+			response := InterpretCosmosError(err)
+			c.JSON(response.Code, gin.H{
+				"message": response.Message,
+			})
+			return
+		}
+
+		filtered := []db.Membership{}
+		for i, membership := range memberships {
+			if counts[i] > 0 {
+				filtered = append(filtered, membership)
+			}
+		}
+		memberships = filtered
+
+	}
+
+	total := len(memberships)
+
+	start, end := pageBounds(paginationOptions, len(memberships))
+	memberships = memberships[start:end]
+
+	members := make([]DashboardMember, len(memberships))
+
+	group, ctx := errgroup.WithContext(c.Request.Context())
+	group.SetLimit(DASHBOARD_CONCURRENCY)
+
+	for i, membership := range memberships {
+		group.Go(func() error {
+			member, err := e.dashboardMember(ctx, membership, year, projectType)
+			members[i] = member
+			return err
+		})
+	}
+
+	err = group.Wait()
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	output := GetClubDashboardOutput{
+		ClubID:  club.ID,
+		Year:    year,
+		Members: members,
+	}
+
+	if _, end := pageBounds(paginationOptions, total); end < total {
+
+		queryParamsMap := make(map[string]string)
+		queryParamsMap[CONTEXT_KEY_PAGE] = strconv.Itoa(paginationOptions.Page + 1)
+		queryParamsMap[CONTEXT_KEY_PER_PAGE] = strconv.Itoa(paginationOptions.PerPage)
+		queryParamsMap["year"] = year
+		if projectType != "" {
+			queryParamsMap["project_type"] = url.QueryEscape(projectType)
+		}
+
+		nextUrlInput := utils.NextUrlInput{
+			Context:     c,
+			QueryParams: queryParamsMap,
+		}
+
+		output.Next = utils.BuildNextUrl(nextUrlInput)
+	}
+
+	c.JSON(200, output)
+
+}
@@ -0,0 +1,24 @@
+package api
+
+import (
+	"context"
+	"time"
+)
+
+// calendar dates sent as timestamps are read in the user's time zone, falling back to the configured zone when the user
+// hasn't set one or can't be read
+func (e *env) userLocation(ctx context.Context, userID string) *time.Location {
+
+	user, err := e.requestUser(ctx, userID)
+	if err != nil || user.TimeZone == "" {
+		return e.timeZone
+	}
+
+	location, err := time.LoadLocation(user.TimeZone)
+	if err != nil {
+		return e.timeZone
+	}
+
+	return location
+
+}
@@ -19,22 +19,77 @@ const (
 	//400
 	ErrBadRequest           = "bad request"
 	ErrMissingFields        = "one or more required fields is missing"
-	ErrBadDate              = "the date(s) provided do not conform to the RFC3339 format."
+	ErrBadDate              = "dates must be formatted YYYY-MM-DD, or as RFC3339 timestamps which are read in the user's time zone"
 	ErrInvalidSectionNumber = "section number must be in the range [1-14] inclusive"
 	ErrQueryMustBeInt       = "query param must be an integer value"
 	ErrQueryMustBeBool      = "query param must be a bool value (1, t, T, TRUE, true, True, 0, f, F, FALSE, false, False)"
+	ErrBadBatchMode         = "batch mode must be one of: rows, split"
+	ErrBatchSize            = "batch must contain between 1 and 100 rows"
+	ErrBatchRowNotWritten   = "row was not written because another row in the batch failed"
+	ErrBadAnimalStatus      = "animal status must be one of: active, sold, retained, deceased, transferred"
+	ErrBadDueDate           = "expected due date is missing or malformed and cannot be estimated for this species"
+	ErrTooManyOffspring     = "number of offspring cannot be more than the number born alive"
+	ErrBreedingDamMismatch  = "breeding does not belong to the given dam"
+	ErrRecordPageNotAllowed = "this kind of project does not keep this kind of record"
+	ErrBadExpenseCategory   = "category must be one of the configured expense categories"
+	ErrBadProgramYear       = "year must be a program year such as 2025-2026, or 2025 when program years follow the calendar year"
+	ErrBadUnitSystem        = "unit system must be one of: imperial, metric"
+	ErrBadUnit              = "unit must be one of: lb, oz, kg, g, or the custom unit of the feed purchase"
+	ErrMissingUnitFactor    = "a custom unit needs a unit factor giving the weight of one unit"
+	ErrBadTimeZone          = "time zone must be an IANA time zone name such as America/Los_Angeles"
+	ErrBadRole              = "role must be one of: member, guardian, leader, agent, judge, admin"
+	ErrMissingRoleCounty    = "agents and judges must be assigned the county they work in"
+	ErrOwnGuardianInvite    = "members cannot accept their own guardian invites"
+	ErrGuardianActing       = "guardian invites must be accepted from the guardian's own account"
+	ErrMissingClubCounty    = "a club needs the county it belongs to"
+	ErrBadMembershipRole    = "role must be one of: member, leader"
+	ErrMissingClubFields    = "club name, club leader and number in club are required when you aren't in a club for that year"
+	ErrBadRubricCriteria    = "rubric criteria need distinct IDs, a name and maximum points above zero"
+	ErrClubNotInCounty      = "club does not belong to this county"
+	ErrOwnScoresheet        = "members cannot judge their own record books"
+	ErrRubricYear           = "rubric is for a different program year than the record book"
+	ErrNotCountyJudge       = "judge has not been assigned to this county as a judge"
+	ErrBadScores            = "scores must give points for each of the rubric's criteria, from zero up to its maximum"
+	ErrBadCommentEntity     = "comments need an entity_type of project, animal, expense or section, and the record's entity_id"
 
-	//401
-	ErrNoToken  = "no authentication token provided"
-	ErrBadToken = "bad token"
+	//403
+	ErrMemberJoin            = "only members can join a club as a member"
+	ErrLeaderJoin            = "only leaders can join a club as a leader"
+	ErrNotClubLeader         = "only the club's leaders can do this"
+	ErrNotClubOverseer       = "only the club's leaders, the agents for its county and admins can see this club"
+	ErrNotClubJudge          = "only the agents for the club's county and admins can judge its record books"
+	ErrNotCountyManager      = "only the agents for the county and admins can run its judging"
+	ErrNotScoresheetJudge    = "only the judge assigned to this scoresheet can see or score it"
+	ErrNotRecordReviewer     = "only the member's club leaders, the agents for their county, their judges and admins can comment on their records"
+	ErrStaffComment          = "only the people overseeing a member's records can start staff-only threads"
+	ErrGuardianEmailMismatch = "guardian invites can only be accepted from an account with the verified email address they were sent to"
+	ErrGuardiansActing       = "a member's guardians can only be managed from the member's own account"
 
 	//404
-	ErrNotFound = "item not found"
+	ErrNotFound               = "item not found"
+	ErrGuardianInviteNotFound = "no pending guardian invite matches that code"
+	ErrClubCodeNotFound       = "no club has that join code"
 
 	//409
-	ErrBookmarkConflict     = "bookmark with that link already exists"
-	ErrEventSectionConflict = "event already has this section"
-	ErrUserExists = "User already has an account"
+	ErrBookmarkConflict         = "bookmark with that link already exists"
+	ErrEventSectionConflict     = "event already has this section"
+	ErrAnimalStatusTransition   = "animal cannot move from its current status to the requested status"
+	ErrAnimalIdentifierConflict = "another animal of this species already has that identifier"
+	ErrUserExists               = "User already has an account"
+	ErrProjectRolledOver        = "project has already been rolled over into the next program year"
+	ErrRoleConflict             = "user already has that role"
+	ErrGuardianLinkConflict     = "you are already a guardian of that member"
+	ErrMembershipConflict       = "you have already joined that club for this program year"
+	ErrSubmissionTransition     = "record book cannot move from its current status to the requested status"
+	ErrRecordsLocked            = "records for this program year are locked because the record book has been submitted"
+	ErrSubmissionNotSubmitted   = "record book has not been submitted to the county"
+	ErrScoresheetConflict       = "judge has already been assigned to this record book"
+	ErrRubricMismatch           = "record book is already being scored against a different rubric"
+	ErrRubricInUse              = "rubric cannot be changed once judges have been assigned to score against it"
+	ErrScoresheetClosed         = "scores can only be changed while the record book is submitted and its results are unpublished"
+	ErrUnscoredScoresheets      = "every assigned judge must score their record books before results are published"
+	ErrNothingToPublish         = "no judges have been assigned to record books in this county for that year"
+	ErrProjectKindHasRecords    = "project cannot change to a kind that does not keep the records it already has"
 )
 
 type HTTPResponseCode struct {
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"4h-recordbook-backend/internal/auth"
 	"4h-recordbook-backend/internal/utils"
 	"4h-recordbook-backend/pkg/db"
 	"strconv"
@@ -46,7 +47,7 @@ type UpsertEventOutput struct {
 // @Router /event [get]
 func (e *env) getEvents(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -62,7 +63,7 @@ func (e *env) getEvents(c *gin.Context) {
 		SortByNewest: c.GetBool(CONTEXT_KEY_SORT_BY_NEWEST),
 	}
 
-	output.Events, err = e.db.GetEventsByUser(c.Request.Context(), claims.ID, paginationOptions)
+	output.Events, err = e.db.GetEventsByUser(c.Request.Context(), principal.UserID, paginationOptions)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -104,7 +105,7 @@ func (e *env) getEvents(c *gin.Context) {
 // @Router /event [post]
 func (e *env) addEvent(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -129,7 +130,9 @@ func (e *env) addEvent(c *gin.Context) {
 		return
 	}
 
-	startDate, err := utils.StringToTimestamp(input.StartDate)
+	location := e.userLocation(c.Request.Context(), principal.UserID)
+
+	startDate, err := utils.StringToDate(input.StartDate, location)
 	if err != nil {
 		c.JSON(400, gin.H{
 			"message": ErrBadDate,
@@ -137,7 +140,7 @@ func (e *env) addEvent(c *gin.Context) {
 		return
 	}
 
-	endDate, err := utils.StringToTimestamp(input.EndDate)
+	endDate, err := utils.StringToDate(input.EndDate, location)
 	if err != nil {
 		c.JSON(400, gin.H{
 			"message": ErrBadDate,
@@ -155,7 +158,7 @@ func (e *env) addEvent(c *gin.Context) {
 		EndDate:     endDate.String(),
 		Location:    input.Location,
 		Description: input.Description,
-		UserID:      claims.ID,
+		UserID:      principal.UserID,
 		GenericDatabaseInfo: db.GenericDatabaseInfo{
 			Created: timestamp.String(),
 			Updated: timestamp.String(),
@@ -193,7 +196,7 @@ func (e *env) addEvent(c *gin.Context) {
 // @Router /event/{eventID} [put]
 func (e *env) updateEvent(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -218,7 +221,9 @@ func (e *env) updateEvent(c *gin.Context) {
 		return
 	}
 
-	startDate, err := utils.StringToTimestamp(input.StartDate)
+	location := e.userLocation(c.Request.Context(), principal.UserID)
+
+	startDate, err := utils.StringToDate(input.StartDate, location)
 	if err != nil {
 		c.JSON(400, gin.H{
 			"message": ErrBadDate,
@@ -226,7 +231,7 @@ func (e *env) updateEvent(c *gin.Context) {
 		return
 	}
 
-	endDate, err := utils.StringToTimestamp(input.EndDate)
+	endDate, err := utils.StringToDate(input.EndDate, location)
 	if err != nil {
 		c.JSON(400, gin.H{
 			"message": ErrBadDate,
@@ -236,7 +241,7 @@ func (e *env) updateEvent(c *gin.Context) {
 
 	eventID := c.Param("eventID")
 
-	event, err := e.db.GetEventByID(c.Request.Context(), claims.ID, eventID)
+	event, err := e.db.GetEventByID(c.Request.Context(), principal.UserID, eventID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -254,7 +259,7 @@ func (e *env) updateEvent(c *gin.Context) {
 		EndDate:     endDate.String(),
 		Location:    input.Location,
 		Description: input.Description,
-		UserID:      claims.ID,
+		UserID:      principal.UserID,
 		GenericDatabaseInfo: db.GenericDatabaseInfo{
 			Created: event.Created,
 			Updated: timestamp.String(),
@@ -290,7 +295,7 @@ func (e *env) updateEvent(c *gin.Context) {
 // @Router /event/{eventID} [delete]
 func (e *env) deleteEvent(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -300,7 +305,7 @@ func (e *env) deleteEvent(c *gin.Context) {
 
 	eventID := c.Param("eventID")
 
-	response, err := e.db.RemoveEvent(c.Request.Context(), claims.ID, eventID)
+	response, err := e.db.RemoveEvent(c.Request.Context(), principal.UserID, eventID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -345,7 +350,7 @@ type UpsertEventSectionOutput struct {
 // @Router /event/{eventID} [get]
 func (e *env) getEventWithSections(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -357,7 +362,7 @@ func (e *env) getEventWithSections(c *gin.Context) {
 
 	var output GetEventWithSectionsOutput
 
-	output.Event, err = e.db.GetEventByID(c.Request.Context(), claims.ID, eventID)
+	output.Event, err = e.db.GetEventByID(c.Request.Context(), principal.UserID, eventID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -366,7 +371,7 @@ func (e *env) getEventWithSections(c *gin.Context) {
 		return
 	}
 
-	sections, err := e.db.GetEventSectionsByEvent(c.Request.Context(), claims.ID, eventID)
+	sections, err := e.db.GetEventSectionsByEvent(c.Request.Context(), principal.UserID, eventID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -378,72 +383,72 @@ func (e *env) getEventWithSections(c *gin.Context) {
 	for _, section := range sections {
 		switch section.SectionNumber {
 		case 1:
-			sectionInterface, err := e.db.GetSection1ByID(c.Request.Context(), claims.ID, section.SectionID)
+			sectionInterface, err := e.db.GetSection1ByID(c.Request.Context(), principal.UserID, section.SectionID)
 			if err == nil {
 				output.Sections = append(output.Sections, sectionInterface)
 			}
 		case 2:
-			sectionInterface, err := e.db.GetSection2ByID(c.Request.Context(), claims.ID, section.SectionID)
+			sectionInterface, err := e.db.GetSection2ByID(c.Request.Context(), principal.UserID, section.SectionID)
 			if err == nil {
 				output.Sections = append(output.Sections, sectionInterface)
 			}
 		case 3:
-			sectionInterface, err := e.db.GetSection3ByID(c.Request.Context(), claims.ID, section.SectionID)
+			sectionInterface, err := e.db.GetSection3ByID(c.Request.Context(), principal.UserID, section.SectionID)
 			if err == nil {
 				output.Sections = append(output.Sections, sectionInterface)
 			}
 		case 4:
-			sectionInterface, err := e.db.GetSection4ByID(c.Request.Context(), claims.ID, section.SectionID)
+			sectionInterface, err := e.db.GetSection4ByID(c.Request.Context(), principal.UserID, section.SectionID)
 			if err == nil {
 				output.Sections = append(output.Sections, sectionInterface)
 			}
 		case 5:
-			sectionInterface, err := e.db.GetSection5ByID(c.Request.Context(), claims.ID, section.SectionID)
+			sectionInterface, err := e.db.GetSection5ByID(c.Request.Context(), principal.UserID, section.SectionID)
 			if err == nil {
 				output.Sections = append(output.Sections, sectionInterface)
 			}
 		case 6:
-			sectionInterface, err := e.db.GetSection6ByID(c.Request.Context(), claims.ID, section.SectionID)
+			sectionInterface, err := e.db.GetSection6ByID(c.Request.Context(), principal.UserID, section.SectionID)
 			if err == nil {
 				output.Sections = append(output.Sections, sectionInterface)
 			}
 		case 7:
-			sectionInterface, err := e.db.GetSection7ByID(c.Request.Context(), claims.ID, section.SectionID)
+			sectionInterface, err := e.db.GetSection7ByID(c.Request.Context(), principal.UserID, section.SectionID)
 			if err == nil {
 				output.Sections = append(output.Sections, sectionInterface)
 			}
 		case 8:
-			sectionInterface, err := e.db.GetSection8ByID(c.Request.Context(), claims.ID, section.SectionID)
+			sectionInterface, err := e.db.GetSection8ByID(c.Request.Context(), principal.UserID, section.SectionID)
 			if err == nil {
 				output.Sections = append(output.Sections, sectionInterface)
 			}
 		case 9:
-			sectionInterface, err := e.db.GetSection9ByID(c.Request.Context(), claims.ID, section.SectionID)
+			sectionInterface, err := e.db.GetSection9ByID(c.Request.Context(), principal.UserID, section.SectionID)
 			if err == nil {
 				output.Sections = append(output.Sections, sectionInterface)
 			}
 		case 10:
-			sectionInterface, err := e.db.GetSection10ByID(c.Request.Context(), claims.ID, section.SectionID)
+			sectionInterface, err := e.db.GetSection10ByID(c.Request.Context(), principal.UserID, section.SectionID)
 			if err == nil {
 				output.Sections = append(output.Sections, sectionInterface)
 			}
 		case 11:
-			sectionInterface, err := e.db.GetSection11ByID(c.Request.Context(), claims.ID, section.SectionID)
+			sectionInterface, err := e.db.GetSection11ByID(c.Request.Context(), principal.UserID, section.SectionID)
 			if err == nil {
 				output.Sections = append(output.Sections, sectionInterface)
 			}
 		case 12:
-			sectionInterface, err := e.db.GetSection12ByID(c.Request.Context(), claims.ID, section.SectionID)
+			sectionInterface, err := e.db.GetSection12ByID(c.Request.Context(), principal.UserID, section.SectionID)
 			if err == nil {
 				output.Sections = append(output.Sections, sectionInterface)
 			}
 		case 13:
-			sectionInterface, err := e.db.GetSection13ByID(c.Request.Context(), claims.ID, section.SectionID)
+			sectionInterface, err := e.db.GetSection13ByID(c.Request.Context(), principal.UserID, section.SectionID)
 			if err == nil {
 				output.Sections = append(output.Sections, sectionInterface)
 			}
 		case 14:
-			sectionInterface, err := e.db.GetSection14ByID(c.Request.Context(), claims.ID, section.SectionID)
+			sectionInterface, err := e.db.GetSection14ByID(c.Request.Context(), principal.UserID, section.SectionID)
 			if err == nil {
 				output.Sections = append(output.Sections, sectionInterface)
 			}
@@ -470,7 +475,7 @@ func (e *env) getEventWithSections(c *gin.Context) {
 // @Router /event/{eventID} [post]
 func (e *env) addEventSection(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -498,7 +503,7 @@ func (e *env) addEventSection(c *gin.Context) {
 	eventID := c.Param("eventID")
 
 	//verify event exists
-	event, err := e.db.GetEventByID(c.Request.Context(), claims.ID, eventID)
+	event, err := e.db.GetEventByID(c.Request.Context(), principal.UserID, eventID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -517,7 +522,7 @@ func (e *env) addEventSection(c *gin.Context) {
 
 	switch *input.SectionNumber {
 	case 1:
-		_, err := e.db.GetSection1ByID(c.Request.Context(), claims.ID, input.SectionID)
+		_, err := e.db.GetSection1ByID(c.Request.Context(), principal.UserID, input.SectionID)
 		if err != nil {
 			response := InterpretCosmosError(err)
 			c.JSON(response.Code, gin.H{
@@ -526,7 +531,7 @@ func (e *env) addEventSection(c *gin.Context) {
 			return
 		}
 	case 2:
-		_, err := e.db.GetSection2ByID(c.Request.Context(), claims.ID, input.SectionID)
+		_, err := e.db.GetSection2ByID(c.Request.Context(), principal.UserID, input.SectionID)
 		if err != nil {
 			response := InterpretCosmosError(err)
 			c.JSON(response.Code, gin.H{
@@ -535,7 +540,7 @@ func (e *env) addEventSection(c *gin.Context) {
 			return
 		}
 	case 3:
-		_, err := e.db.GetSection3ByID(c.Request.Context(), claims.ID, input.SectionID)
+		_, err := e.db.GetSection3ByID(c.Request.Context(), principal.UserID, input.SectionID)
 		if err != nil {
 			response := InterpretCosmosError(err)
 			c.JSON(response.Code, gin.H{
@@ -544,7 +549,7 @@ func (e *env) addEventSection(c *gin.Context) {
 			return
 		}
 	case 4:
-		_, err := e.db.GetSection4ByID(c.Request.Context(), claims.ID, input.SectionID)
+		_, err := e.db.GetSection4ByID(c.Request.Context(), principal.UserID, input.SectionID)
 		if err != nil {
 			response := InterpretCosmosError(err)
 			c.JSON(response.Code, gin.H{
@@ -553,7 +558,7 @@ func (e *env) addEventSection(c *gin.Context) {
 			return
 		}
 	case 5:
-		_, err := e.db.GetSection5ByID(c.Request.Context(), claims.ID, input.SectionID)
+		_, err := e.db.GetSection5ByID(c.Request.Context(), principal.UserID, input.SectionID)
 		if err != nil {
 			response := InterpretCosmosError(err)
 			c.JSON(response.Code, gin.H{
@@ -562,7 +567,7 @@ func (e *env) addEventSection(c *gin.Context) {
 			return
 		}
 	case 6:
-		_, err := e.db.GetSection6ByID(c.Request.Context(), claims.ID, input.SectionID)
+		_, err := e.db.GetSection6ByID(c.Request.Context(), principal.UserID, input.SectionID)
 		if err != nil {
 			response := InterpretCosmosError(err)
 			c.JSON(response.Code, gin.H{
@@ -571,7 +576,7 @@ func (e *env) addEventSection(c *gin.Context) {
 			return
 		}
 	case 7:
-		_, err := e.db.GetSection7ByID(c.Request.Context(), claims.ID, input.SectionID)
+		_, err := e.db.GetSection7ByID(c.Request.Context(), principal.UserID, input.SectionID)
 		if err != nil {
 			response := InterpretCosmosError(err)
 			c.JSON(response.Code, gin.H{
@@ -580,7 +585,7 @@ func (e *env) addEventSection(c *gin.Context) {
 			return
 		}
 	case 8:
-		_, err := e.db.GetSection8ByID(c.Request.Context(), claims.ID, input.SectionID)
+		_, err := e.db.GetSection8ByID(c.Request.Context(), principal.UserID, input.SectionID)
 		if err != nil {
 			response := InterpretCosmosError(err)
 			c.JSON(response.Code, gin.H{
@@ -589,7 +594,7 @@ func (e *env) addEventSection(c *gin.Context) {
 			return
 		}
 	case 9:
-		_, err := e.db.GetSection9ByID(c.Request.Context(), claims.ID, input.SectionID)
+		_, err := e.db.GetSection9ByID(c.Request.Context(), principal.UserID, input.SectionID)
 		if err != nil {
 			response := InterpretCosmosError(err)
 			c.JSON(response.Code, gin.H{
@@ -598,7 +603,7 @@ func (e *env) addEventSection(c *gin.Context) {
 			return
 		}
 	case 10:
-		_, err := e.db.GetSection10ByID(c.Request.Context(), claims.ID, input.SectionID)
+		_, err := e.db.GetSection10ByID(c.Request.Context(), principal.UserID, input.SectionID)
 		if err != nil {
 			response := InterpretCosmosError(err)
 			c.JSON(response.Code, gin.H{
@@ -607,7 +612,7 @@ func (e *env) addEventSection(c *gin.Context) {
 			return
 		}
 	case 11:
-		_, err := e.db.GetSection11ByID(c.Request.Context(), claims.ID, input.SectionID)
+		_, err := e.db.GetSection11ByID(c.Request.Context(), principal.UserID, input.SectionID)
 		if err != nil {
 			response := InterpretCosmosError(err)
 			c.JSON(response.Code, gin.H{
@@ -616,7 +621,7 @@ func (e *env) addEventSection(c *gin.Context) {
 			return
 		}
 	case 12:
-		_, err := e.db.GetSection12ByID(c.Request.Context(), claims.ID, input.SectionID)
+		_, err := e.db.GetSection12ByID(c.Request.Context(), principal.UserID, input.SectionID)
 		if err != nil {
 			response := InterpretCosmosError(err)
 			c.JSON(response.Code, gin.H{
@@ -625,7 +630,7 @@ func (e *env) addEventSection(c *gin.Context) {
 			return
 		}
 	case 13:
-		_, err := e.db.GetSection13ByID(c.Request.Context(), claims.ID, input.SectionID)
+		_, err := e.db.GetSection13ByID(c.Request.Context(), principal.UserID, input.SectionID)
 		if err != nil {
 			response := InterpretCosmosError(err)
 			c.JSON(response.Code, gin.H{
@@ -634,7 +639,7 @@ func (e *env) addEventSection(c *gin.Context) {
 			return
 		}
 	case 14:
-		_, err := e.db.GetSection14ByID(c.Request.Context(), claims.ID, input.SectionID)
+		_, err := e.db.GetSection14ByID(c.Request.Context(), principal.UserID, input.SectionID)
 		if err != nil {
 			response := InterpretCosmosError(err)
 			c.JSON(response.Code, gin.H{
@@ -650,7 +655,7 @@ func (e *env) addEventSection(c *gin.Context) {
 	}
 
 	//verify eventSection doesn't already exist
-	existingEventSection, err := e.db.GetEventSectionByIDs(c.Request.Context(), claims.ID, eventID, input.SectionID)
+	existingEventSection, err := e.db.GetEventSectionByIDs(c.Request.Context(), principal.UserID, eventID, input.SectionID)
 	if existingEventSection != (db.EventSection{}) {
 		c.JSON(409, gin.H{
 			"message": ErrEventSectionConflict,
@@ -672,7 +677,7 @@ func (e *env) addEventSection(c *gin.Context) {
 
 	eventSection := db.EventSection{
 		ID:            g.String(),
-		UserID:        claims.ID,
+		UserID:        principal.UserID,
 		EventID:       event.ID,
 		SectionNumber: *input.SectionNumber,
 		SectionID:     input.SectionID,
@@ -712,7 +717,7 @@ func (e *env) addEventSection(c *gin.Context) {
 // @Router /event/{eventID}/{sectionID} [delete]
 func (e *env) deleteEventSection(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -723,7 +728,7 @@ func (e *env) deleteEventSection(c *gin.Context) {
 	eventID := c.Param("eventID")
 	sectionID := c.Param("sectionID")
 
-	eventSection, err := e.db.GetEventSectionByIDs(c.Request.Context(), claims.ID, eventID, sectionID)
+	eventSection, err := e.db.GetEventSectionByIDs(c.Request.Context(), principal.UserID, eventID, sectionID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -738,7 +743,7 @@ func (e *env) deleteEventSection(c *gin.Context) {
 		return
 	}
 
-	response, err := e.db.RemoveEventSection(c.Request.Context(), claims.ID, eventSection.ID)
+	response, err := e.db.RemoveEventSection(c.Request.Context(), principal.UserID, eventSection.ID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
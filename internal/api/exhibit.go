@@ -0,0 +1,357 @@
+package api
+
+import (
+	"4h-recordbook-backend/internal/auth"
+	"4h-recordbook-backend/internal/utils"
+	"4h-recordbook-backend/pkg/db"
+	"strconv"
+
+	"github.com/beevik/guid"
+	"github.com/gin-gonic/gin"
+)
+
+type GetExhibitsOutput struct {
+	Exhibits []db.Exhibit `json:"exhibits"`
+	Next     string       `json:"next"`
+}
+
+type GetExhibitOutput struct {
+	Exhibit db.Exhibit `json:"exhibit"`
+}
+
+type UpsertExhibitInput struct {
+	Name        string `json:"name" validate:"required"`
+	Description string `json:"description" validate:"required"`
+	ShowName    string `json:"show_name"`
+	Date        string `json:"date" validate:"required"`
+	Division    string `json:"division"`
+	Placing     string `json:"placing"`
+	Award       string `json:"award"`
+	ProjectID   string `json:"project_id" validate:"required"`
+}
+
+type UpsertExhibitOutput GetExhibitOutput
+
+// GetExhibits godoc
+// @Summary Get exhibits by project
+// @Description Gets all of a user's exhibits given a project ID
+// @Tags Exhibit
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param projectID path string true "Project ID"
+// @Param page query int false "Page number, default 0"
+// @Param per_page query int false "Max number of items to return. Can be [1-200], default 100"
+// @Param sort_by_newest query bool false "Sort results by most recently added, default false"
+// @Success 200 {object} api.GetExhibitsOutput
+// @Failure 400
+// @Failure 401
+// @Router /project/{projectID}/exhibit [get]
+func (e *env) getExhibits(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	projectID := c.Param("projectID")
+
+	var output GetExhibitsOutput
+
+	paginationOptions := db.PaginationOptions{
+		Page:         c.GetInt(CONTEXT_KEY_PAGE),
+		PerPage:      c.GetInt(CONTEXT_KEY_PER_PAGE),
+		SortByNewest: c.GetBool(CONTEXT_KEY_SORT_BY_NEWEST),
+	}
+
+	output.Exhibits, err = e.db.GetExhibitsByProject(c.Request.Context(), principal.UserID, projectID, paginationOptions)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	if len(output.Exhibits) == paginationOptions.PerPage {
+
+		queryParamsMap := make(map[string]string)
+		queryParamsMap[CONTEXT_KEY_PAGE] = strconv.Itoa(paginationOptions.Page + 1)
+		queryParamsMap[CONTEXT_KEY_PER_PAGE] = strconv.Itoa(paginationOptions.PerPage)
+		queryParamsMap[CONTEXT_KEY_SORT_BY_NEWEST] = strconv.FormatBool(paginationOptions.SortByNewest)
+
+		nextUrlInput := utils.NextUrlInput{
+			Context:     c,
+			QueryParams: queryParamsMap,
+		}
+
+		output.Next = utils.BuildNextUrl(nextUrlInput)
+	}
+
+	c.JSON(200, output)
+
+}
+
+// GetExhibit godoc
+// @Summary Get an exhibit
+// @Description Get a user's exhibit by ID
+// @Tags Exhibit
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param exhibitID path string true "Exhibit ID"
+// @Success 200 {object} api.GetExhibitOutput
+// @Failure 401
+// @Failure 404
+// @Router /exhibit/{exhibitID} [get]
+func (e *env) getExhibit(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	exhibitID := c.Param("exhibitID")
+
+	var output GetExhibitOutput
+
+	output.Exhibit, err = e.db.GetExhibitByID(c.Request.Context(), principal.UserID, exhibitID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(200, output)
+
+}
+
+// AddExhibit godoc
+// @Summary Adds an exhibit
+// @Description Adds an exhibit to a user's personal records
+// @Tags Exhibit
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param UpsertExhibitInput body api.UpsertExhibitInput true "Exhibit information"
+// @Success 201 {object} api.UpsertExhibitOutput
+// @Failure 400
+// @Failure 401
+// @Failure 404
+// @Router /exhibit [post]
+func (e *env) addExhibit(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	var input UpsertExhibitInput
+	err = c.BindJSON(&input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrBadRequest,
+		})
+		return
+	}
+
+	err = e.validator.Struct(input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrMissingFields,
+		})
+		return
+	}
+
+	location := e.userLocation(c.Request.Context(), principal.UserID)
+
+	date, err := utils.StringToDate(input.Date, location)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrBadDate,
+		})
+		return
+	}
+
+	if !e.checkProjectRecordPage(c, principal.UserID, input.ProjectID, db.RECORD_PAGE_EXHIBITS) {
+		return
+	}
+
+	g := guid.New()
+	timestamp := utils.TimeNow()
+
+	exhibit := db.Exhibit{
+		ID:          g.String(),
+		Name:        input.Name,
+		Description: input.Description,
+		ShowName:    input.ShowName,
+		Date:        date.String(),
+		Division:    input.Division,
+		Placing:     input.Placing,
+		Award:       input.Award,
+		ProjectID:   input.ProjectID,
+		UserID:      principal.UserID,
+		GenericDatabaseInfo: db.GenericDatabaseInfo{
+			Created: timestamp.String(),
+			Updated: timestamp.String(),
+		},
+	}
+
+	var output UpsertExhibitOutput
+
+	output.Exhibit, err = e.db.UpsertExhibit(c.Request.Context(), exhibit)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(201, output)
+
+}
+
+// UpdateExhibit godoc
+// @Summary Update an exhibit
+// @Description Updates a user's exhibit information
+// @Tags Exhibit
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param exhibitID path string true "Exhibit ID"
+// @Param UpsertExhibitInput body api.UpsertExhibitInput true "Exhibit information"
+// @Success 200 {object} api.UpsertExhibitOutput
+// @Failure 400
+// @Failure 401
+// @Failure 404
+// @Router /exhibit/{exhibitID} [put]
+func (e *env) updateExhibit(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	var input UpsertExhibitInput
+	err = c.BindJSON(&input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrBadRequest,
+		})
+		return
+	}
+
+	err = e.validator.Struct(input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrMissingFields,
+		})
+		return
+	}
+
+	location := e.userLocation(c.Request.Context(), principal.UserID)
+
+	date, err := utils.StringToDate(input.Date, location)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrBadDate,
+		})
+		return
+	}
+
+	exhibitID := c.Param("exhibitID")
+
+	exhibit, err := e.db.GetExhibitByID(c.Request.Context(), principal.UserID, exhibitID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	timestamp := utils.TimeNow()
+
+	updatedExhibit := db.Exhibit{
+		ID:          exhibit.ID,
+		Name:        input.Name,
+		Description: input.Description,
+		ShowName:    input.ShowName,
+		Date:        date.String(),
+		Division:    input.Division,
+		Placing:     input.Placing,
+		Award:       input.Award,
+		ProjectID:   exhibit.ProjectID,
+		UserID:      principal.UserID,
+		GenericDatabaseInfo: db.GenericDatabaseInfo{
+			Created: exhibit.Created,
+			Updated: timestamp.String(),
+		},
+	}
+
+	var output UpsertExhibitOutput
+
+	output.Exhibit, err = e.db.UpsertExhibit(c.Request.Context(), updatedExhibit)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(200, output)
+
+}
+
+// DeleteExhibit godoc
+// @Summary Removes an exhibit
+// @Description Deletes a user's exhibit given the exhibit ID
+// @Tags Exhibit
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param exhibitID path string true "Exhibit ID"
+// @Success 204
+// @Failure 401
+// @Failure 404
+// @Router /exhibit/{exhibitID} [delete]
+func (e *env) deleteExhibit(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	exhibitID := c.Param("exhibitID")
+
+	response, err := e.db.RemoveExhibit(c.Request.Context(), principal.UserID, exhibitID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(204, response)
+
+}
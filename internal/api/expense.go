@@ -1,9 +1,15 @@
 package api
 
 import (
+	"4h-recordbook-backend/internal/auth"
+	"4h-recordbook-backend/internal/config"
 	"4h-recordbook-backend/internal/utils"
 	"4h-recordbook-backend/pkg/db"
+	"slices"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/beevik/guid"
 	"github.com/gin-gonic/gin"
@@ -19,15 +25,40 @@ type GetExpenseOutput struct {
 }
 
 type UpsertExpenseInput struct {
-	Date      string   `json:"date" validate:"required"`
-	Items     string   `json:"items" validate:"required"`
-	Quantity  *float64 `json:"quantity" validate:"required"`
-	Cost      *float64 `json:"cost" validate:"required"`
-	ProjectID string   `json:"project_id" validate:"required"`
+	Date          string       `json:"date" validate:"required"`
+	Items         string       `json:"items" validate:"required"`
+	Quantity      *float64     `json:"quantity" validate:"required"`
+	Cost          *utils.Money `json:"cost" validate:"required"`
+	Category      string       `json:"category"`
+	OtherCategory string       `json:"other_category"`
+	ProjectID     string       `json:"project_id" validate:"required"`
 }
 
 type UpsertExpenseOutput GetExpenseOutput
 
+type GetExpenseCategoriesOutput struct {
+	Categories      []string `json:"categories"`
+	OtherCategories []string `json:"other_categories"`
+}
+
+type ExpenseCategoryTotal struct {
+	Category      string      `json:"category"`
+	OtherCategory string      `json:"other_category"`
+	Total         utils.Money `json:"total"`
+}
+
+type ExpenseMonthTotal struct {
+	Month string      `json:"month"`
+	Total utils.Money `json:"total"`
+}
+
+type GetExpenseSummaryOutput struct {
+	Categories []ExpenseCategoryTotal `json:"categories"`
+	Months     []ExpenseMonthTotal    `json:"months"`
+	Undated    utils.Money            `json:"undated"`
+	Total      utils.Money            `json:"total"`
+}
+
 // GetExpenses godoc
 // @Summary Get expenses by project
 // @Description Gets all of a user's expenses given a project ID
@@ -45,7 +76,7 @@ type UpsertExpenseOutput GetExpenseOutput
 // @Router /project/{projectID}/expense [get]
 func (e *env) getExpenses(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -63,7 +94,7 @@ func (e *env) getExpenses(c *gin.Context) {
 		SortByNewest: c.GetBool(CONTEXT_KEY_SORT_BY_NEWEST),
 	}
 
-	output.Expenses, err = e.db.GetExpensesByProject(c.Request.Context(), claims.ID, projectID, paginationOptions)
+	output.Expenses, err = e.db.GetExpensesByProject(c.Request.Context(), principal.UserID, projectID, paginationOptions)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -105,7 +136,7 @@ func (e *env) getExpenses(c *gin.Context) {
 // @Router /expense/{expenseID} [get]
 func (e *env) getExpense(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -117,7 +148,7 @@ func (e *env) getExpense(c *gin.Context) {
 
 	var output GetExpenseOutput
 
-	output.Expense, err = e.db.GetExpenseByID(c.Request.Context(), claims.ID, expenseID)
+	output.Expense, err = e.db.GetExpenseByID(c.Request.Context(), principal.UserID, expenseID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -144,7 +175,7 @@ func (e *env) getExpense(c *gin.Context) {
 // @Router /expense [post]
 func (e *env) addExpense(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -169,7 +200,17 @@ func (e *env) addExpense(c *gin.Context) {
 		return
 	}
 
-	date, err := utils.StringToTimestamp(input.Date)
+	category, otherCategory, ok := e.expenseCategory(input.Category, input.OtherCategory)
+	if !ok {
+		c.JSON(400, gin.H{
+			"message": ErrBadExpenseCategory,
+		})
+		return
+	}
+
+	location := e.userLocation(c.Request.Context(), principal.UserID)
+
+	date, err := utils.StringToDate(input.Date, location)
 	if err != nil {
 		c.JSON(400, gin.H{
 			"message": ErrBadDate,
@@ -181,13 +222,15 @@ func (e *env) addExpense(c *gin.Context) {
 	timestamp := utils.TimeNow()
 
 	expense := db.Expense{
-		ID:        g.String(),
-		Date:      date.String(),
-		Items:     input.Items,
-		Quantity:  *input.Quantity,
-		Cost:      *input.Cost,
-		ProjectID: input.ProjectID,
-		UserID:    claims.ID,
+		ID:            g.String(),
+		Date:          date.String(),
+		Items:         input.Items,
+		Quantity:      *input.Quantity,
+		Cost:          *input.Cost,
+		Category:      category,
+		OtherCategory: otherCategory,
+		ProjectID:     input.ProjectID,
+		UserID:        principal.UserID,
 		GenericDatabaseInfo: db.GenericDatabaseInfo{
 			Created: timestamp.String(),
 			Updated: timestamp.String(),
@@ -225,7 +268,7 @@ func (e *env) addExpense(c *gin.Context) {
 // @Router /expense/{expenseID} [put]
 func (e *env) updateExpense(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -250,7 +293,9 @@ func (e *env) updateExpense(c *gin.Context) {
 		return
 	}
 
-	date, err := utils.StringToTimestamp(input.Date)
+	location := e.userLocation(c.Request.Context(), principal.UserID)
+
+	date, err := utils.StringToDate(input.Date, location)
 	if err != nil {
 		c.JSON(400, gin.H{
 			"message": ErrBadDate,
@@ -260,7 +305,7 @@ func (e *env) updateExpense(c *gin.Context) {
 
 	expenseID := c.Param("expenseID")
 
-	expense, err := e.db.GetExpenseByID(c.Request.Context(), claims.ID, expenseID)
+	expense, err := e.db.GetExpenseByID(c.Request.Context(), principal.UserID, expenseID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -269,16 +314,26 @@ func (e *env) updateExpense(c *gin.Context) {
 		return
 	}
 
+	category, otherCategory, ok := e.expenseCategory(ternary(input.Category, expense.Category), ternary(input.OtherCategory, expense.OtherCategory))
+	if !ok {
+		c.JSON(400, gin.H{
+			"message": ErrBadExpenseCategory,
+		})
+		return
+	}
+
 	timestamp := utils.TimeNow()
 
 	updatedExpense := db.Expense{
-		ID:        expense.ID,
-		Date:      date.String(),
-		Items:     input.Items,
-		Quantity:  *input.Quantity,
-		Cost:      *input.Cost,
-		ProjectID: expense.ProjectID,
-		UserID:    claims.ID,
+		ID:            expense.ID,
+		Date:          date.String(),
+		Items:         input.Items,
+		Quantity:      *input.Quantity,
+		Cost:          *input.Cost,
+		Category:      category,
+		OtherCategory: otherCategory,
+		ProjectID:     expense.ProjectID,
+		UserID:        principal.UserID,
 		GenericDatabaseInfo: db.GenericDatabaseInfo{
 			Created: expense.Created,
 			Updated: timestamp.String(),
@@ -314,7 +369,7 @@ func (e *env) updateExpense(c *gin.Context) {
 // @Router /expense/{expenseID} [delete]
 func (e *env) deleteExpense(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -324,7 +379,7 @@ func (e *env) deleteExpense(c *gin.Context) {
 
 	expenseID := c.Param("expenseID")
 
-	response, err := e.db.RemoveExpense(c.Request.Context(), claims.ID, expenseID)
+	response, err := e.db.RemoveExpense(c.Request.Context(), principal.UserID, expenseID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -336,3 +391,190 @@ func (e *env) deleteExpense(c *gin.Context) {
 	c.JSON(204, response)
 
 }
+
+// GetExpenseCategories godoc
+// @Summary Get expense categories
+// @Description Lists the categories that expenses and supplies can be filed under, along with the labels the user has given to the "other" category before
+// @Tags Expense
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} api.GetExpenseCategoriesOutput
+// @Failure 401
+// @Router /expense-categories [get]
+func (e *env) getExpenseCategories(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	output := GetExpenseCategoriesOutput{
+		Categories: e.config.ExpenseCategories,
+	}
+
+	output.OtherCategories, err = e.db.GetOtherExpenseCategoriesByUser(c.Request.Context(), principal.UserID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(200, output)
+
+}
+
+// GetExpenseSummary godoc
+// @Summary Get an expense summary
+// @Description Totals a project's expenses by category and by month. Expenses saved before categories existed are counted as other.
+// @Description Feed purchases are counted under feed along with any expenses filed there. Anything without a readable date is totalled as undated instead of under a month
+// @Tags Expense
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param projectID path string true "Project ID"
+// @Success 200 {object} api.GetExpenseSummaryOutput
+// @Failure 401
+// @Router /project/{projectID}/expense/summary [get]
+func (e *env) getExpenseSummary(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	projectID := c.Param("projectID")
+
+	expenses, err := e.db.GetAllExpensesByProject(c.Request.Context(), principal.UserID, projectID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	feedPurchases, err := e.db.GetAllFeedPurchasesByProject(c.Request.Context(), principal.UserID, projectID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(200, expenseSummary(expenses, feedPurchases, e.config.ExpenseCategories, e.userLocation(c.Request.Context(), principal.UserID)))
+
+}
+
+// files an expense or supply under one of the configured categories. anything without a category is filed under other,
+// and only other keeps the member's own label
+func (e *env) expenseCategory(category string, otherCategory string) (string, string, bool) {
+
+	category = ternary(category, config.EXPENSE_CATEGORY_OTHER)
+
+	if !slices.Contains(e.config.ExpenseCategories, category) {
+		return "", "", false
+	}
+
+	if category != config.EXPENSE_CATEGORY_OTHER {
+		return category, "", true
+	}
+
+	return category, strings.TrimSpace(otherCategory), true
+
+}
+
+// feed purchases are counted under feed on the date they were bought. categories are listed in configured order, with
+// each of the user's other labels after the plain other category, and months are listed oldest first. anything without
+// a readable date is totalled as undated, so the months and undated add up to the total
+func expenseSummary(expenses []db.Expense, feedPurchases []db.FeedPurchase, categories []string, location *time.Location) GetExpenseSummaryOutput {
+
+	categoryTotals := make(map[ExpenseCategoryTotal]utils.Money)
+	monthTotals := make(map[string]utils.Money)
+
+	output := GetExpenseSummaryOutput{
+		Categories: []ExpenseCategoryTotal{},
+		Months:     []ExpenseMonthTotal{},
+	}
+
+	for _, expense := range expenses {
+
+		key := ExpenseCategoryTotal{
+			Category:      ternary(expense.Category, config.EXPENSE_CATEGORY_OTHER),
+			OtherCategory: expense.OtherCategory,
+		}
+		categoryTotals[key] += expense.Cost
+
+		date, err := utils.StringToDate(expense.Date, location)
+		if err == nil {
+			monthTotals[date.Time().Format("2006-01")] += expense.Cost
+		} else {
+			output.Undated += expense.Cost
+		}
+
+		output.Total += expense.Cost
+
+	}
+
+	for _, feedPurchase := range feedPurchases {
+
+		categoryTotals[ExpenseCategoryTotal{Category: config.EXPENSE_CATEGORY_FEED}] += feedPurchase.TotalCost
+
+		date, err := utils.StringToDate(feedPurchase.DatePurchased, location)
+		if err == nil {
+			monthTotals[date.Time().Format("2006-01")] += feedPurchase.TotalCost
+		} else {
+			output.Undated += feedPurchase.TotalCost
+		}
+
+		output.Total += feedPurchase.TotalCost
+
+	}
+
+	for key, total := range categoryTotals {
+		key.Total = total
+		output.Categories = append(output.Categories, key)
+	}
+
+	order := func(category string) int {
+		index := slices.Index(categories, category)
+		if index == -1 {
+			return len(categories)
+		}
+		return index
+	}
+
+	sort.Slice(output.Categories, func(i, j int) bool {
+		a, b := output.Categories[i], output.Categories[j]
+		if order(a.Category) != order(b.Category) {
+			return order(a.Category) < order(b.Category)
+		}
+		if a.Category != b.Category {
+			return a.Category < b.Category
+		}
+		return a.OtherCategory < b.OtherCategory
+	})
+
+	for month, total := range monthTotals {
+		output.Months = append(output.Months, ExpenseMonthTotal{
+			Month: month,
+			Total: total,
+		})
+	}
+
+	sort.Slice(output.Months, func(i, j int) bool {
+		return output.Months[i].Month < output.Months[j].Month
+	})
+
+	return output
+
+}
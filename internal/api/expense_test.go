@@ -0,0 +1,105 @@
+package api
+
+import (
+	"4h-recordbook-backend/internal/config"
+	"4h-recordbook-backend/pkg/db"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestExpenseSummary(t *testing.T) {
+
+	categories := []string{config.EXPENSE_CATEGORY_FEED, "vet_health", config.EXPENSE_CATEGORY_OTHER}
+
+	tests := []struct {
+		name          string
+		expenses      []db.Expense
+		feedPurchases []db.FeedPurchase
+		categories    []ExpenseCategoryTotal
+		months        []ExpenseMonthTotal
+		undated       int64
+		total         int64
+	}{
+		{
+			name:       "nothing recorded",
+			categories: []ExpenseCategoryTotal{},
+			months:     []ExpenseMonthTotal{},
+		},
+		{
+			name: "categories in configured order with other labels last",
+			expenses: []db.Expense{
+				{Category: config.EXPENSE_CATEGORY_OTHER, OtherCategory: "show fees", Cost: 500, Date: "2025-11-02"},
+				{Category: "vet_health", Cost: 2000, Date: "2025-10-15"},
+				{Cost: 100, Date: "2025-10-20"},
+				{Category: "vet_health", Cost: 1000, Date: "2025-11-30"},
+			},
+			categories: []ExpenseCategoryTotal{
+				{Category: "vet_health", Total: 3000},
+				{Category: config.EXPENSE_CATEGORY_OTHER, Total: 100},
+				{Category: config.EXPENSE_CATEGORY_OTHER, OtherCategory: "show fees", Total: 500},
+			},
+			months: []ExpenseMonthTotal{
+				{Month: "2025-10", Total: 2100},
+				{Month: "2025-11", Total: 1500},
+			},
+			total: 3600,
+		},
+		{
+			name: "feed purchases counted under feed",
+			expenses: []db.Expense{
+				{Category: config.EXPENSE_CATEGORY_FEED, Cost: 1200, Date: "2025-12-01"},
+			},
+			feedPurchases: []db.FeedPurchase{
+				{TotalCost: 3000, DatePurchased: "2025-12-10"},
+				{TotalCost: 2500, DatePurchased: "2026-01-05"},
+			},
+			categories: []ExpenseCategoryTotal{
+				{Category: config.EXPENSE_CATEGORY_FEED, Total: 6700},
+			},
+			months: []ExpenseMonthTotal{
+				{Month: "2025-12", Total: 4200},
+				{Month: "2026-01", Total: 2500},
+			},
+			total: 6700,
+		},
+		{
+			name: "unreadable dates counted as undated",
+			expenses: []db.Expense{
+				{Category: "vet_health", Cost: 2000, Date: "2025-10-15"},
+				{Category: "vet_health", Cost: 700, Date: "last fall"},
+			},
+			feedPurchases: []db.FeedPurchase{
+				{TotalCost: 3000},
+			},
+			categories: []ExpenseCategoryTotal{
+				{Category: config.EXPENSE_CATEGORY_FEED, Total: 3000},
+				{Category: "vet_health", Total: 2700},
+			},
+			months: []ExpenseMonthTotal{
+				{Month: "2025-10", Total: 2000},
+			},
+			undated: 3700,
+			total:   5700,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := expenseSummary(test.expenses, test.feedPurchases, categories, time.UTC)
+			if !reflect.DeepEqual(output.Categories, test.categories) {
+				t.Errorf("categories = %+v, want %+v", output.Categories, test.categories)
+			}
+			if !reflect.DeepEqual(output.Months, test.months) {
+				t.Errorf("months = %+v, want %+v", output.Months, test.months)
+			}
+			if int64(output.Undated) != test.undated {
+				t.Errorf("undated = %d, want %d", output.Undated, test.undated)
+			}
+			if int64(output.Total) != test.total {
+				t.Errorf("total = %d, want %d", output.Total, test.total)
+			}
+		})
+	}
+
+}
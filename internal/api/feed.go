@@ -1,6 +1,7 @@
 package api
 
 import (
+	"4h-recordbook-backend/internal/auth"
 	"4h-recordbook-backend/internal/utils"
 	"4h-recordbook-backend/pkg/db"
 	"strconv"
@@ -42,7 +43,7 @@ type UpsertFeedOutput GetFeedOutput
 // @Router /project/{projectID}/feed [get]
 func (e *env) getFeeds(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -60,7 +61,7 @@ func (e *env) getFeeds(c *gin.Context) {
 		SortByNewest: c.GetBool(CONTEXT_KEY_SORT_BY_NEWEST),
 	}
 
-	output.Feeds, err = e.db.GetFeedsByProject(c.Request.Context(), claims.ID, projectID, paginationOptions)
+	output.Feeds, err = e.db.GetFeedsByProject(c.Request.Context(), principal.UserID, projectID, paginationOptions)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -102,7 +103,7 @@ func (e *env) getFeeds(c *gin.Context) {
 // @Router /feed/{feedID} [get]
 func (e *env) getFeed(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -114,7 +115,7 @@ func (e *env) getFeed(c *gin.Context) {
 
 	var output GetFeedOutput
 
-	output.Feed, err = e.db.GetFeedByID(c.Request.Context(), claims.ID, feedID)
+	output.Feed, err = e.db.GetFeedByID(c.Request.Context(), principal.UserID, feedID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -138,10 +139,11 @@ func (e *env) getFeed(c *gin.Context) {
 // @Success 201 {object} api.UpsertFeedOutput
 // @Failure 400
 // @Failure 401
+// @Failure 404
 // @Router /feed [post]
 func (e *env) addFeed(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -166,6 +168,10 @@ func (e *env) addFeed(c *gin.Context) {
 		return
 	}
 
+	if !e.checkProjectRecordPage(c, principal.UserID, input.ProjectID, db.RECORD_PAGE_FEEDS) {
+		return
+	}
+
 	g := guid.New()
 	timestamp := utils.TimeNow()
 
@@ -173,7 +179,7 @@ func (e *env) addFeed(c *gin.Context) {
 		ID:        g.String(),
 		Name:      input.Name,
 		ProjectID: input.ProjectID,
-		UserID:    claims.ID,
+		UserID:    principal.UserID,
 		GenericDatabaseInfo: db.GenericDatabaseInfo{
 			Created: timestamp.String(),
 			Updated: timestamp.String(),
@@ -211,7 +217,7 @@ func (e *env) addFeed(c *gin.Context) {
 // @Router /feed/{feedID} [put]
 func (e *env) updateFeed(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -238,7 +244,7 @@ func (e *env) updateFeed(c *gin.Context) {
 
 	feedID := c.Param("feedID")
 
-	feed, err := e.db.GetFeedByID(c.Request.Context(), claims.ID, feedID)
+	feed, err := e.db.GetFeedByID(c.Request.Context(), principal.UserID, feedID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -253,7 +259,7 @@ func (e *env) updateFeed(c *gin.Context) {
 		ID:        feed.ID,
 		Name:      input.Name,
 		ProjectID: feed.ProjectID,
-		UserID:    claims.ID,
+		UserID:    principal.UserID,
 		GenericDatabaseInfo: db.GenericDatabaseInfo{
 			Created: feed.Created,
 			Updated: timestamp.String(),
@@ -289,7 +295,7 @@ func (e *env) updateFeed(c *gin.Context) {
 // @Router /feed/{feedID} [delete]
 func (e *env) deleteFeed(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -299,7 +305,7 @@ func (e *env) deleteFeed(c *gin.Context) {
 
 	feedID := c.Param("feedID")
 
-	response, err := e.db.RemoveFeed(c.Request.Context(), claims.ID, feedID)
+	response, err := e.db.RemoveFeed(c.Request.Context(), principal.UserID, feedID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
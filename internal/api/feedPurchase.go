@@ -1,9 +1,12 @@
 package api
 
 import (
+	"4h-recordbook-backend/internal/auth"
 	"4h-recordbook-backend/internal/utils"
 	"4h-recordbook-backend/pkg/db"
+	"errors"
 	"strconv"
+	"strings"
 
 	"github.com/beevik/guid"
 	"github.com/gin-gonic/gin"
@@ -19,15 +22,20 @@ type GetFeedPurchaseOutput struct {
 }
 
 type UpsertFeedPurchaseInput struct {
-	DatePurchased   string   `json:"date_purchased" validate:"required"`
-	AmountPurchased *float64 `json:"amount_purchased" validate:"required"`
-	TotalCost       *float64 `json:"total_cost" validate:"required"`
-	FeedID          string   `json:"feed_id" validate:"required"`
-	ProjectID       string   `json:"project_id" validate:"required"`
+	DatePurchased   string       `json:"date_purchased" validate:"required"`
+	AmountPurchased *float64     `json:"amount_purchased" validate:"required"`
+	AmountUnit      string       `json:"amount_unit"`
+	UnitFactor      *float64     `json:"unit_factor" validate:"omitempty,gt=0"`
+	UnitFactorUnit  string       `json:"unit_factor_unit"`
+	TotalCost       *utils.Money `json:"total_cost" validate:"required"`
+	FeedID          string       `json:"feed_id" validate:"required"`
+	ProjectID       string       `json:"project_id" validate:"required"`
 }
 
 type UpsertFeedPurchaseOutput GetFeedPurchaseOutput
 
+var errMissingUnitFactor = errors.New("missing unit factor")
+
 // GetFeedPurchases godoc
 // @Summary Get feed purchases by project
 // @Description Gets all of a user's feed purchases given a project ID
@@ -45,7 +53,7 @@ type UpsertFeedPurchaseOutput GetFeedPurchaseOutput
 // @Router /project/{projectID}/feed-purchase [get]
 func (e *env) getFeedPurchases(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -63,7 +71,7 @@ func (e *env) getFeedPurchases(c *gin.Context) {
 		SortByNewest: c.GetBool(CONTEXT_KEY_SORT_BY_NEWEST),
 	}
 
-	output.FeedPurchases, err = e.db.GetFeedPurchasesByProject(c.Request.Context(), claims.ID, projectID, paginationOptions)
+	output.FeedPurchases, err = e.db.GetFeedPurchasesByProject(c.Request.Context(), principal.UserID, projectID, paginationOptions)
 	if err != nil {
 		e.logger.Info(err)
 		response := InterpretCosmosError(err)
@@ -88,6 +96,8 @@ func (e *env) getFeedPurchases(c *gin.Context) {
 		output.Next = utils.BuildNextUrl(nextUrlInput)
 	}
 
+	output.FeedPurchases = localizeFeedPurchases(output.FeedPurchases, e.preferredMassUnit(c.Request.Context(), principal.UserID))
+
 	c.JSON(200, output)
 
 }
@@ -106,7 +116,7 @@ func (e *env) getFeedPurchases(c *gin.Context) {
 // @Router /feed-purchase/{feedPurchaseID} [get]
 func (e *env) getFeedPurchase(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -118,7 +128,7 @@ func (e *env) getFeedPurchase(c *gin.Context) {
 
 	var output GetFeedPurchaseOutput
 
-	output.FeedPurchase, err = e.db.GetFeedPurchaseByID(c.Request.Context(), claims.ID, feedPurchaseID)
+	output.FeedPurchase, err = e.db.GetFeedPurchaseByID(c.Request.Context(), principal.UserID, feedPurchaseID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -127,6 +137,8 @@ func (e *env) getFeedPurchase(c *gin.Context) {
 		return
 	}
 
+	output.FeedPurchase = localizeFeedPurchase(output.FeedPurchase, e.preferredMassUnit(c.Request.Context(), principal.UserID))
+
 	c.JSON(200, output)
 
 }
@@ -134,6 +146,8 @@ func (e *env) getFeedPurchase(c *gin.Context) {
 // AddFeedPurchase godoc
 // @Summary Add a feed purchase
 // @Description Adds a feed purchase to a user's personal records
+// @Description The amount is in amount_unit (lb, oz, kg or g, default the user's preferred unit), or in a custom unit such as "50-lb bag"
+// @Description given with unit_factor, the weight of one unit in unit_factor_unit.
 // @Tags Feed Purchase
 // @Accept json
 // @Produce json
@@ -142,10 +156,11 @@ func (e *env) getFeedPurchase(c *gin.Context) {
 // @Success 201 {object} api.UpsertFeedPurchaseOutput
 // @Failure 400
 // @Failure 401
+// @Failure 404
 // @Router /feed-purchase [post]
 func (e *env) addFeedPurchase(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -170,7 +185,9 @@ func (e *env) addFeedPurchase(c *gin.Context) {
 		return
 	}
 
-	datePurchased, err := utils.StringToTimestamp(input.DatePurchased)
+	location := e.userLocation(c.Request.Context(), principal.UserID)
+
+	datePurchased, err := utils.StringToDate(input.DatePurchased, location)
 	if err != nil {
 		c.JSON(400, gin.H{
 			"message": ErrBadDate,
@@ -178,23 +195,34 @@ func (e *env) addFeedPurchase(c *gin.Context) {
 		return
 	}
 
+	if !e.checkProjectRecordPage(c, principal.UserID, input.ProjectID, db.RECORD_PAGE_FEED_PURCHASES) {
+		return
+	}
+
+	unit := e.preferredMassUnit(c.Request.Context(), principal.UserID)
+
 	g := guid.New()
 	timestamp := utils.TimeNow()
 
 	feedPurchase := db.FeedPurchase{
-		ID:              g.String(),
-		DatePurchased:   datePurchased.String(),
-		AmountPurchased: *input.AmountPurchased,
-		TotalCost:       *input.TotalCost,
-		FeedID:          input.FeedID,
-		ProjectID:       input.ProjectID,
-		UserID:          claims.ID,
+		ID:            g.String(),
+		DatePurchased: datePurchased.String(),
+		TotalCost:     *input.TotalCost,
+		FeedID:        input.FeedID,
+		ProjectID:     input.ProjectID,
+		UserID:        principal.UserID,
 		GenericDatabaseInfo: db.GenericDatabaseInfo{
 			Created: timestamp.String(),
 			Updated: timestamp.String(),
 		},
 	}
 
+	err = setFeedPurchaseAmount(&feedPurchase, input, unit)
+	if err != nil {
+		respondFeedPurchaseAmountError(c, err)
+		return
+	}
+
 	var output UpsertFeedPurchaseOutput
 
 	output.FeedPurchase, err = e.db.UpsertFeedPurchase(c.Request.Context(), feedPurchase)
@@ -206,6 +234,8 @@ func (e *env) addFeedPurchase(c *gin.Context) {
 		return
 	}
 
+	output.FeedPurchase = localizeFeedPurchase(output.FeedPurchase, unit)
+
 	c.JSON(201, output)
 
 }
@@ -213,6 +243,8 @@ func (e *env) addFeedPurchase(c *gin.Context) {
 // UpdateFeedPurchase godoc
 // @Summary Update a feed purchase
 // @Description Updates a user's feed purchase information
+// @Description The amount is in amount_unit (lb, oz, kg or g, default the user's preferred unit), or in a custom unit such as "50-lb bag"
+// @Description given with unit_factor, the weight of one unit in unit_factor_unit.
 // @Tags Feed Purchase
 // @Accept json
 // @Produce json
@@ -226,7 +258,7 @@ func (e *env) addFeedPurchase(c *gin.Context) {
 // @Router /feed-purchase/{feedPurchaseID} [put]
 func (e *env) updateFeedPurchase(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -251,7 +283,9 @@ func (e *env) updateFeedPurchase(c *gin.Context) {
 		return
 	}
 
-	datePurchased, err := utils.StringToTimestamp(input.DatePurchased)
+	location := e.userLocation(c.Request.Context(), principal.UserID)
+
+	datePurchased, err := utils.StringToDate(input.DatePurchased, location)
 	if err != nil {
 		c.JSON(400, gin.H{
 			"message": ErrBadDate,
@@ -261,7 +295,7 @@ func (e *env) updateFeedPurchase(c *gin.Context) {
 
 	feedPurchaseID := c.Param("feedPurchaseID")
 
-	feedPurchase, err := e.db.GetFeedPurchaseByID(c.Request.Context(), claims.ID, feedPurchaseID)
+	feedPurchase, err := e.db.GetFeedPurchaseByID(c.Request.Context(), principal.UserID, feedPurchaseID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -270,22 +304,28 @@ func (e *env) updateFeedPurchase(c *gin.Context) {
 		return
 	}
 
+	unit := e.preferredMassUnit(c.Request.Context(), principal.UserID)
 	timestamp := utils.TimeNow()
 
 	updatedFeedPurchase := db.FeedPurchase{
-		ID:              feedPurchase.ID,
-		DatePurchased:   datePurchased.String(),
-		AmountPurchased: *input.AmountPurchased,
-		TotalCost:       *input.TotalCost,
-		FeedID:          feedPurchase.FeedID,
-		ProjectID:       feedPurchase.ProjectID,
-		UserID:          claims.ID,
+		ID:            feedPurchase.ID,
+		DatePurchased: datePurchased.String(),
+		TotalCost:     *input.TotalCost,
+		FeedID:        feedPurchase.FeedID,
+		ProjectID:     feedPurchase.ProjectID,
+		UserID:        principal.UserID,
 		GenericDatabaseInfo: db.GenericDatabaseInfo{
 			Created: feedPurchase.Created,
 			Updated: timestamp.String(),
 		},
 	}
 
+	err = setFeedPurchaseAmount(&updatedFeedPurchase, input, unit)
+	if err != nil {
+		respondFeedPurchaseAmountError(c, err)
+		return
+	}
+
 	var output UpsertFeedPurchaseOutput
 
 	output.FeedPurchase, err = e.db.UpsertFeedPurchase(c.Request.Context(), updatedFeedPurchase)
@@ -297,10 +337,59 @@ func (e *env) updateFeedPurchase(c *gin.Context) {
 		return
 	}
 
+	output.FeedPurchase = localizeFeedPurchase(output.FeedPurchase, unit)
+
 	c.JSON(200, output)
 
 }
 
+// stores the amount in pounds. purchases in a custom unit keep the quantity and unit as entered along with the weight of one unit
+func setFeedPurchaseAmount(feedPurchase *db.FeedPurchase, input UpsertFeedPurchaseInput, preferred string) error {
+
+	amountUnit := strings.TrimSpace(input.AmountUnit)
+
+	feedPurchase.AmountUnit = utils.CANONICAL_MASS_UNIT
+	feedPurchase.PurchaseUnit = ""
+	feedPurchase.PurchaseQuantity = 0
+	feedPurchase.PurchaseUnitFactor = 0
+
+	if amountUnit == "" || utils.IsMassUnit(amountUnit) {
+		pounds, err := massToPounds(*input.AmountPurchased, amountUnit, preferred)
+		if err != nil {
+			return err
+		}
+		feedPurchase.AmountPurchased = pounds
+		return nil
+	}
+
+	if input.UnitFactor == nil {
+		return errMissingUnitFactor
+	}
+
+	factor, err := massToPounds(*input.UnitFactor, input.UnitFactorUnit, preferred)
+	if err != nil {
+		return err
+	}
+
+	feedPurchase.PurchaseUnit = amountUnit
+	feedPurchase.PurchaseQuantity = *input.AmountPurchased
+	feedPurchase.PurchaseUnitFactor = factor
+	feedPurchase.AmountPurchased, err = utils.ToCanonicalMass(*input.AmountPurchased*factor, utils.CANONICAL_MASS_UNIT)
+
+	return err
+
+}
+
+func respondFeedPurchaseAmountError(c *gin.Context, err error) {
+	if errors.Is(err, errMissingUnitFactor) {
+		c.JSON(400, gin.H{
+			"message": ErrMissingUnitFactor,
+		})
+		return
+	}
+	respondUnitError(c, err)
+}
+
 // DeleteFeedPurchase godoc
 // @Summary Removes a feed purchase
 // @Description Deletes a user's feed purchase given the feed purchase ID
@@ -315,7 +404,7 @@ func (e *env) updateFeedPurchase(c *gin.Context) {
 // @Router /feed-purchase/{feedPurchaseID} [delete]
 func (e *env) deleteFeedPurchase(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -325,7 +414,7 @@ func (e *env) deleteFeedPurchase(c *gin.Context) {
 
 	feedPurchaseID := c.Param("feedPurchaseID")
 
-	response, err := e.db.RemoveFeedPurchase(c.Request.Context(), claims.ID, feedPurchaseID)
+	response, err := e.db.RemoveFeedPurchase(c.Request.Context(), principal.UserID, feedPurchaseID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -0,0 +1,541 @@
+package api
+
+import (
+	"4h-recordbook-backend/internal/auth"
+	"4h-recordbook-backend/internal/utils"
+	"4h-recordbook-backend/pkg/db"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+
+	"github.com/beevik/guid"
+	"github.com/gin-gonic/gin"
+)
+
+const GUARDIAN_INVITE_CODE_BYTES = 16
+
+type GetGuardianLinksOutput struct {
+	GuardianLinks []db.GuardianLink `json:"guardian_links"`
+}
+
+type GetGuardianLinkOutput struct {
+	GuardianLink db.GuardianLink `json:"guardian_link"`
+}
+
+type InviteGuardianInput struct {
+	GuardianEmail string `json:"guardian_email" validate:"required,email"`
+}
+
+type InviteGuardianOutput GetGuardianLinkOutput
+
+type AcceptGuardianInviteInput struct {
+	MemberID string `json:"member_id" validate:"required"`
+	Code     string `json:"code" validate:"required"`
+}
+
+type AcceptGuardianInviteOutput GetGuardianLinkOutput
+
+func guardianInviteCode() (string, error) {
+	code := make([]byte, GUARDIAN_INVITE_CODE_BYTES)
+	_, err := rand.Read(code)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(code), nil
+}
+
+// removes both copies of a link. the other side's copy may never have been written, when the invite wasn't accepted
+func (e *env) removeGuardianLink(ctx context.Context, link db.GuardianLink) error {
+
+	for _, userID := range []string{link.MemberID, link.GuardianID} {
+		if userID == "" {
+			continue
+		}
+		_, err := e.db.RemoveGuardianLink(ctx, userID, link.ID)
+		if err != nil && InterpretCosmosError(err).Code != 404 {
+			return err
+		}
+	}
+
+	return nil
+
+}
+
+// GetGuardians godoc
+// @Summary Get a member's guardians
+// @Description Returns the guardians linked to the member and the invites they haven't accepted yet. Only the
+// @Description member can see them, not a guardian acting for them
+// @Tags Guardians
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} api.GetGuardianLinksOutput
+// @Failure 401
+// @Failure 403
+// @Router /guardians [get]
+func (e *env) getGuardians(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	// a guardian acting for the member must not see or change who else can act for them
+	if principal.IsActing() {
+		c.JSON(403, gin.H{
+			"message": ErrGuardiansActing,
+		})
+		return
+	}
+
+	links, err := e.db.GetGuardianLinksByUser(c.Request.Context(), principal.UserID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	output := GetGuardianLinksOutput{
+		GuardianLinks: []db.GuardianLink{},
+	}
+
+	for _, link := range links {
+		if link.MemberID == principal.UserID {
+			output.GuardianLinks = append(output.GuardianLinks, link)
+		}
+	}
+
+	c.JSON(200, output)
+
+}
+
+// InviteGuardian godoc
+// @Summary Invites a guardian
+// @Description Creates an invite for a parent or guardian to manage the member's records.
+// @Description The guardian accepts it by sending the member's ID and the returned code to /guardians/accept.
+// @Description Only the member can invite guardians, not a guardian acting for them.
+// @Tags Guardians
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param InviteGuardianInput body api.InviteGuardianInput true "Guardian information"
+// @Success 201 {object} api.InviteGuardianOutput
+// @Failure 400
+// @Failure 401
+// @Failure 403
+// @Router /guardians [post]
+func (e *env) inviteGuardian(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	// a guardian acting for the member must not see or change who else can act for them
+	if principal.IsActing() {
+		c.JSON(403, gin.H{
+			"message": ErrGuardiansActing,
+		})
+		return
+	}
+
+	var input InviteGuardianInput
+	err = c.BindJSON(&input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrBadRequest,
+		})
+		return
+	}
+
+	err = e.validator.Struct(input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrMissingFields,
+		})
+		return
+	}
+
+	member, err := e.db.GetUser(c.Request.Context(), principal.UserID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	code, err := guardianInviteCode()
+	if err != nil {
+		c.JSON(500, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	g := guid.New()
+	timestamp := utils.TimeNow()
+
+	link := db.GuardianLink{
+		ID:            g.String(),
+		UserID:        principal.UserID,
+		MemberID:      principal.UserID,
+		MemberName:    member.FirstName,
+		GuardianEmail: input.GuardianEmail,
+		Status:        db.GUARDIAN_LINK_STATUS_PENDING,
+		Code:          code,
+		GenericDatabaseInfo: db.GenericDatabaseInfo{
+			Created: timestamp.String(),
+			Updated: timestamp.String(),
+		},
+	}
+
+	var output InviteGuardianOutput
+
+	output.GuardianLink, err = e.db.UpsertGuardianLink(c.Request.Context(), link)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(201, output)
+
+}
+
+// AcceptGuardianInvite godoc
+// @Summary Accepts a guardian invite
+// @Description Links the signed-in user to the member who invited them as a guardian, and makes them a guardian.
+// @Description The user's verified email must be the address the invite was sent to.
+// @Description Once linked, they can act for the member by sending the member's ID in the X-Acting-As header.
+// @Tags Guardians
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param AcceptGuardianInviteInput body api.AcceptGuardianInviteInput true "Invite information"
+// @Success 200 {object} api.AcceptGuardianInviteOutput
+// @Failure 400
+// @Failure 401
+// @Failure 403
+// @Failure 404
+// @Router /guardians/accept [post]
+func (e *env) acceptGuardianInvite(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if principal.IsActing() {
+		c.JSON(400, gin.H{
+			"message": ErrGuardianActing,
+		})
+		return
+	}
+
+	var input AcceptGuardianInviteInput
+	err = c.BindJSON(&input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrBadRequest,
+		})
+		return
+	}
+
+	err = e.validator.Struct(input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrMissingFields,
+		})
+		return
+	}
+
+	if input.MemberID == principal.UserID {
+		c.JSON(400, gin.H{
+			"message": ErrOwnGuardianInvite,
+		})
+		return
+	}
+
+	links, err := e.db.GetGuardianLinksByUser(c.Request.Context(), input.MemberID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	var link db.GuardianLink
+	for _, l := range links {
+		if l.Status == db.GUARDIAN_LINK_STATUS_PENDING && subtle.ConstantTimeCompare([]byte(l.Code), []byte(input.Code)) == 1 {
+			link = l
+			break
+		}
+	}
+
+	if link.ID == "" {
+		c.JSON(404, gin.H{
+			"message": ErrGuardianInviteNotFound,
+		})
+		return
+	}
+
+	// the code alone isn't enough, since members may share it. the invite is only for the address it was sent to
+	if !principal.EmailVerified || !strings.EqualFold(strings.TrimSpace(principal.Email), strings.TrimSpace(link.GuardianEmail)) {
+		c.JSON(403, gin.H{
+			"message": ErrGuardianEmailMismatch,
+		})
+		return
+	}
+
+	existing, err := e.db.GetAcceptedGuardianLink(c.Request.Context(), input.MemberID, principal.UserID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	if existing.ID != "" {
+		c.JSON(409, gin.H{
+			"message": ErrGuardianLinkConflict,
+		})
+		return
+	}
+
+	timestamp := utils.TimeNow()
+
+	link.GuardianID = principal.UserID
+	link.GuardianName = principal.Name
+	link.Status = db.GUARDIAN_LINK_STATUS_ACCEPTED
+	link.Code = ""
+	link.AcceptedAt = timestamp.String()
+	link.Updated = timestamp.String()
+
+	// the guardian's copy goes first, so that access is only granted once both copies exist
+	guardianCopy := link
+	guardianCopy.UserID = principal.UserID
+
+	_, err = e.db.UpsertGuardianLink(c.Request.Context(), guardianCopy)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	var output AcceptGuardianInviteOutput
+
+	output.GuardianLink, err = e.db.UpsertGuardianLink(c.Request.Context(), link)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	if !principal.HasRole(auth.ROLE_GUARDIAN) {
+		assignment := db.RoleAssignment{
+			ID:        guid.New().String(),
+			UserID:    principal.UserID,
+			Role:      string(auth.ROLE_GUARDIAN),
+			GrantedBy: link.MemberID,
+			GenericDatabaseInfo: db.GenericDatabaseInfo{
+				Created: timestamp.String(),
+				Updated: timestamp.String(),
+			},
+		}
+
+		_, err = e.db.UpsertRoleAssignment(c.Request.Context(), assignment)
+		if err != nil {
+			response := InterpretCosmosError(err)
+			c.JSON(response.Code, gin.H{
+				"message": response.Message,
+			})
+			return
+		}
+	}
+
+	c.JSON(200, output)
+
+}
+
+// DeleteGuardian godoc
+// @Summary Removes a guardian
+// @Description Unlinks a guardian from the member, or withdraws an invite they haven't accepted. Only the member
+// @Description can remove guardians, not a guardian acting for them
+// @Tags Guardians
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param guardianLinkID path string true "Guardian link ID"
+// @Success 204
+// @Failure 401
+// @Failure 403
+// @Failure 404
+// @Router /guardians/{guardianLinkID} [delete]
+func (e *env) deleteGuardian(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	// a guardian acting for the member must not see or change who else can act for them
+	if principal.IsActing() {
+		c.JSON(403, gin.H{
+			"message": ErrGuardiansActing,
+		})
+		return
+	}
+
+	guardianLinkID := c.Param("guardianLinkID")
+
+	link, err := e.db.GetGuardianLinkByID(c.Request.Context(), principal.UserID, guardianLinkID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	if link.MemberID != principal.UserID {
+		c.JSON(404, gin.H{
+			"message": ErrNotFound,
+		})
+		return
+	}
+
+	err = e.removeGuardianLink(c.Request.Context(), link)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(204, nil)
+
+}
+
+// GetWards godoc
+// @Summary Get a guardian's linked members
+// @Description Returns the members the signed-in guardian is linked to and can act for
+// @Tags Guardians
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} api.GetGuardianLinksOutput
+// @Failure 401
+// @Failure 403
+// @Router /wards [get]
+func (e *env) getWards(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	links, err := e.db.GetGuardianLinksByUser(c.Request.Context(), principal.UserID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	output := GetGuardianLinksOutput{
+		GuardianLinks: []db.GuardianLink{},
+	}
+
+	for _, link := range links {
+		if link.GuardianID == principal.UserID {
+			output.GuardianLinks = append(output.GuardianLinks, link)
+		}
+	}
+
+	c.JSON(200, output)
+
+}
+
+// DeleteWard godoc
+// @Summary Unlinks a guardian from a member
+// @Description Removes the signed-in guardian's link to a member, after which they can no longer act for them
+// @Tags Guardians
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param guardianLinkID path string true "Guardian link ID"
+// @Success 204
+// @Failure 401
+// @Failure 403
+// @Failure 404
+// @Router /wards/{guardianLinkID} [delete]
+func (e *env) deleteWard(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	guardianLinkID := c.Param("guardianLinkID")
+
+	link, err := e.db.GetGuardianLinkByID(c.Request.Context(), principal.UserID, guardianLinkID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	if link.GuardianID != principal.UserID {
+		c.JSON(404, gin.H{
+			"message": ErrNotFound,
+		})
+		return
+	}
+
+	err = e.removeGuardianLink(c.Request.Context(), link)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(204, nil)
+
+}
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"4h-recordbook-backend/internal/auth"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestGuardianRoutesRejectActingGuardians(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	// no database, so the test fails if a handler gets as far as reading or writing a link
+	e := &env{}
+
+	router := gin.New()
+	records := router.Group("", func(c *gin.Context) {
+		auth.SetPrincipal(c, auth.Principal{
+			UserID:    "member",
+			ActorID:   "guardian",
+			ActorName: "Guardian",
+			Roles:     []auth.Role{auth.ROLE_MEMBER},
+		})
+	})
+	records.GET("/guardians", e.getGuardians)
+	records.POST("/guardians", e.inviteGuardian)
+	records.DELETE("/guardians/:guardianLinkID", e.deleteGuardian)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+	}{
+		{"list guardians and invites", "GET", "/guardians", ""},
+		{"invite another guardian", "POST", "/guardians", `{"guardian_email":"someone@example.com"}`},
+		{"remove another guardian", "DELETE", "/guardians/link", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(test.method, test.path, strings.NewReader(test.body)))
+			if recorder.Code != 403 {
+				t.Errorf("got %d, want 403", recorder.Code)
+			}
+		})
+	}
+
+}
@@ -0,0 +1,496 @@
+package api
+
+import (
+	"4h-recordbook-backend/internal/auth"
+	"4h-recordbook-backend/internal/utils"
+	"4h-recordbook-backend/pkg/db"
+	"strconv"
+
+	"github.com/beevik/guid"
+	"github.com/gin-gonic/gin"
+)
+
+type GetHealthRecordsOutput struct {
+	HealthRecords []db.HealthRecord `json:"health_records"`
+	Next          string            `json:"next"`
+}
+
+type GetHealthRecordOutput struct {
+	HealthRecord db.HealthRecord `json:"health_record"`
+}
+
+type UpsertHealthRecordInput struct {
+	RecordType       string   `json:"record_type" validate:"required,oneof=vaccination deworming medication treatment"`
+	Product          string   `json:"product" validate:"required"`
+	Dosage           *float64 `json:"dosage" validate:"required"`
+	DosageUnit       string   `json:"dosage_unit" validate:"required"`
+	Route            string   `json:"route" validate:"required"`
+	AdministeredBy   string   `json:"administered_by" validate:"required"`
+	DateAdministered string   `json:"date_administered" validate:"required"`
+	WithdrawalDays   *int     `json:"withdrawal_days" validate:"required,min=0"`
+	Notes            string   `json:"notes"`
+	AnimalID         string   `json:"animal_id" validate:"required"`
+}
+
+type UpsertHealthRecordOutput GetHealthRecordOutput
+
+type AnimalWithdrawal struct {
+	Animal            db.Animal         `json:"animal"`
+	CheckDate         string            `json:"check_date"`
+	WithdrawalEndDate string            `json:"withdrawal_end_date"`
+	HealthRecords     []db.HealthRecord `json:"health_records"`
+}
+
+type GetWithdrawalsOutput struct {
+	Animals []AnimalWithdrawal `json:"animals"`
+}
+
+// GetHealthRecords godoc
+// @Summary Get health records by animal
+// @Description Gets all of a user's health records for a given animal
+// @Tags Health Record
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param animalID path string true "Animal ID"
+// @Param page query int false "Page number, default 0"
+// @Param per_page query int false "Max number of items to return. Can be [1-200], default 100"
+// @Param sort_by_newest query bool false "Sort results by most recently added, default false"
+// @Success 200 {object} api.GetHealthRecordsOutput
+// @Failure 400
+// @Failure 401
+// @Router /animal/{animalID}/health-record [get]
+func (e *env) getHealthRecords(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	animalID := c.Param("animalID")
+
+	var output GetHealthRecordsOutput
+
+	paginationOptions := db.PaginationOptions{
+		Page:         c.GetInt(CONTEXT_KEY_PAGE),
+		PerPage:      c.GetInt(CONTEXT_KEY_PER_PAGE),
+		SortByNewest: c.GetBool(CONTEXT_KEY_SORT_BY_NEWEST),
+	}
+
+	output.HealthRecords, err = e.db.GetHealthRecordsByAnimal(c.Request.Context(), principal.UserID, animalID, paginationOptions)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	if len(output.HealthRecords) == paginationOptions.PerPage {
+
+		queryParamsMap := make(map[string]string)
+		queryParamsMap[CONTEXT_KEY_PAGE] = strconv.Itoa(paginationOptions.Page + 1)
+		queryParamsMap[CONTEXT_KEY_PER_PAGE] = strconv.Itoa(paginationOptions.PerPage)
+		queryParamsMap[CONTEXT_KEY_SORT_BY_NEWEST] = strconv.FormatBool(paginationOptions.SortByNewest)
+
+		nextUrlInput := utils.NextUrlInput{
+			Context:     c,
+			QueryParams: queryParamsMap,
+		}
+
+		output.Next = utils.BuildNextUrl(nextUrlInput)
+	}
+
+	c.JSON(200, output)
+
+}
+
+// GetHealthRecord godoc
+// @Summary Get a health record
+// @Description Get a user's health record by ID
+// @Tags Health Record
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param healthRecordID path string true "Health Record ID"
+// @Success 200 {object} api.GetHealthRecordOutput
+// @Failure 401
+// @Failure 404
+// @Router /health-record/{healthRecordID} [get]
+func (e *env) getHealthRecord(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	healthRecordID := c.Param("healthRecordID")
+
+	var output GetHealthRecordOutput
+
+	output.HealthRecord, err = e.db.GetHealthRecordByID(c.Request.Context(), principal.UserID, healthRecordID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(200, output)
+
+}
+
+// AddHealthRecord godoc
+// @Summary Add a health record
+// @Description Adds a vaccination, deworming, medication or treatment record to one of a user's animals
+// @Tags Health Record
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param UpsertHealthRecordInput body api.UpsertHealthRecordInput true "Health Record information"
+// @Success 201 {object} api.UpsertHealthRecordOutput
+// @Failure 400
+// @Failure 401
+// @Failure 404
+// @Router /health-record [post]
+func (e *env) addHealthRecord(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	var input UpsertHealthRecordInput
+	err = c.BindJSON(&input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrBadRequest,
+		})
+		return
+	}
+
+	err = e.validator.Struct(input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrMissingFields,
+		})
+		return
+	}
+
+	location := e.userLocation(c.Request.Context(), principal.UserID)
+
+	dateAdministered, err := utils.StringToDate(input.DateAdministered, location)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrBadDate,
+		})
+		return
+	}
+
+	animal, err := e.db.GetAnimalByID(c.Request.Context(), principal.UserID, input.AnimalID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	g := guid.New()
+	timestamp := utils.TimeNow()
+
+	healthRecord := db.HealthRecord{
+		ID:                g.String(),
+		RecordType:        input.RecordType,
+		Product:           input.Product,
+		Dosage:            *input.Dosage,
+		DosageUnit:        input.DosageUnit,
+		Route:             input.Route,
+		AdministeredBy:    input.AdministeredBy,
+		DateAdministered:  dateAdministered.String(),
+		WithdrawalDays:    *input.WithdrawalDays,
+		WithdrawalEndDate: dateAdministered.AddDays(*input.WithdrawalDays).String(),
+		Notes:             input.Notes,
+		AnimalID:          animal.ID,
+		ProjectID:         animal.ProjectID,
+		UserID:            principal.UserID,
+		GenericDatabaseInfo: db.GenericDatabaseInfo{
+			Created: timestamp.String(),
+			Updated: timestamp.String(),
+		},
+	}
+
+	var output UpsertHealthRecordOutput
+
+	output.HealthRecord, err = e.db.UpsertHealthRecord(c.Request.Context(), healthRecord)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(201, output)
+
+}
+
+// UpdateHealthRecord godoc
+// @Summary Update a health record
+// @Description Updates a user's health record information
+// @Tags Health Record
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param healthRecordID path string true "Health Record ID"
+// @Param UpsertHealthRecordInput body api.UpsertHealthRecordInput true "Health Record information"
+// @Success 200 {object} api.UpsertHealthRecordOutput
+// @Failure 400
+// @Failure 401
+// @Failure 404
+// @Router /health-record/{healthRecordID} [put]
+func (e *env) updateHealthRecord(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	var input UpsertHealthRecordInput
+	err = c.BindJSON(&input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrBadRequest,
+		})
+		return
+	}
+
+	err = e.validator.Struct(input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrMissingFields,
+		})
+		return
+	}
+
+	location := e.userLocation(c.Request.Context(), principal.UserID)
+
+	dateAdministered, err := utils.StringToDate(input.DateAdministered, location)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrBadDate,
+		})
+		return
+	}
+
+	healthRecordID := c.Param("healthRecordID")
+
+	healthRecord, err := e.db.GetHealthRecordByID(c.Request.Context(), principal.UserID, healthRecordID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	timestamp := utils.TimeNow()
+
+	updatedHealthRecord := db.HealthRecord{
+		ID:                healthRecord.ID,
+		RecordType:        input.RecordType,
+		Product:           input.Product,
+		Dosage:            *input.Dosage,
+		DosageUnit:        input.DosageUnit,
+		Route:             input.Route,
+		AdministeredBy:    input.AdministeredBy,
+		DateAdministered:  dateAdministered.String(),
+		WithdrawalDays:    *input.WithdrawalDays,
+		WithdrawalEndDate: dateAdministered.AddDays(*input.WithdrawalDays).String(),
+		Notes:             input.Notes,
+		AnimalID:          healthRecord.AnimalID,
+		ProjectID:         healthRecord.ProjectID,
+		UserID:            principal.UserID,
+		GenericDatabaseInfo: db.GenericDatabaseInfo{
+			Created: healthRecord.Created,
+			Updated: timestamp.String(),
+		},
+	}
+
+	var output UpsertHealthRecordOutput
+
+	output.HealthRecord, err = e.db.UpsertHealthRecord(c.Request.Context(), updatedHealthRecord)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(200, output)
+
+}
+
+// DeleteHealthRecord godoc
+// @Summary Removes a health record
+// @Description Deletes a user's health record given the health record ID
+// @Tags Health Record
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param healthRecordID path string true "Health Record ID"
+// @Success 204
+// @Failure 401
+// @Failure 404
+// @Router /health-record/{healthRecordID} [delete]
+func (e *env) deleteHealthRecord(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	healthRecordID := c.Param("healthRecordID")
+
+	response, err := e.db.RemoveHealthRecord(c.Request.Context(), principal.UserID, healthRecordID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(204, response)
+
+}
+
+// GetWithdrawals godoc
+// @Summary Get animals inside a drug withdrawal period
+// @Description Flags every animal in a project that is still inside a withdrawal period on the check date.
+// @Description The check date is the date query param if given, otherwise the animal's end date, otherwise now.
+// @Tags Health Record
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param projectID path string true "Project ID"
+// @Param date query string false "Date to check against, YYYY-MM-DD"
+// @Success 200 {object} api.GetWithdrawalsOutput
+// @Failure 400
+// @Failure 401
+// @Router /project/{projectID}/withdrawal [get]
+func (e *env) getWithdrawals(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	projectID := c.Param("projectID")
+
+	location := e.userLocation(c.Request.Context(), principal.UserID)
+
+	var checkDate *utils.Date
+	if dateStr, ok := c.GetQuery("date"); ok {
+		date, err := utils.StringToDate(dateStr, location)
+		if err != nil {
+			c.JSON(400, gin.H{
+				"message": ErrBadDate,
+			})
+			return
+		}
+		checkDate = &date
+	}
+
+	healthRecords, err := e.db.GetWithdrawalHealthRecordsByProject(c.Request.Context(), principal.UserID, projectID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	healthRecordsByAnimal := make(map[string][]db.HealthRecord)
+	animalIDs := []string{}
+	for _, healthRecord := range healthRecords {
+		if _, ok := healthRecordsByAnimal[healthRecord.AnimalID]; !ok {
+			animalIDs = append(animalIDs, healthRecord.AnimalID)
+		}
+		healthRecordsByAnimal[healthRecord.AnimalID] = append(healthRecordsByAnimal[healthRecord.AnimalID], healthRecord)
+	}
+
+	output := GetWithdrawalsOutput{
+		Animals: []AnimalWithdrawal{},
+	}
+
+	unit := e.preferredMassUnit(c.Request.Context(), principal.UserID)
+
+	for _, animalID := range animalIDs {
+
+		animal, err := e.db.GetAnimalByID(c.Request.Context(), principal.UserID, animalID)
+		if err != nil {
+			response := InterpretCosmosError(err)
+			c.JSON(response.Code, gin.H{
+				"message": response.Message,
+			})
+			return
+		}
+
+		animalCheckDate := utils.Today(location)
+		if checkDate != nil {
+			animalCheckDate = *checkDate
+		} else if endDate, err := utils.StringToDate(animal.EndDate, location); err == nil {
+			animalCheckDate = endDate
+		}
+
+		withdrawal := AnimalWithdrawal{
+			Animal:        localizeAnimal(animal, unit),
+			CheckDate:     animalCheckDate.String(),
+			HealthRecords: []db.HealthRecord{},
+		}
+
+		var latestEnd utils.Date
+		for _, healthRecord := range healthRecordsByAnimal[animalID] {
+			withdrawalEnd, err := utils.StringToDate(healthRecord.WithdrawalEndDate, location)
+			if err != nil {
+				continue
+			}
+			if animalCheckDate.Before(withdrawalEnd) {
+				withdrawal.HealthRecords = append(withdrawal.HealthRecords, healthRecord)
+				if latestEnd.Before(withdrawalEnd) {
+					latestEnd = withdrawalEnd
+				}
+			}
+		}
+
+		if len(withdrawal.HealthRecords) > 0 {
+			withdrawal.WithdrawalEndDate = latestEnd.String()
+			output.Animals = append(output.Animals, withdrawal)
+		}
+
+	}
+
+	c.JSON(200, output)
+
+}
@@ -2,21 +2,21 @@ package api
 
 import (
 	_ "4h-recordbook-backend/internal/api/docs"
+	"4h-recordbook-backend/internal/auth"
 	"4h-recordbook-backend/internal/config"
 	"4h-recordbook-backend/internal/middleware"
+	"4h-recordbook-backend/internal/utils"
 	"4h-recordbook-backend/pkg/db"
 	"4h-recordbook-backend/pkg/upc"
 	"context"
 	"errors"
 	"net/http"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
-	"github.com/golang-jwt/jwt"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"go.uber.org/zap"
@@ -33,11 +33,17 @@ const (
 	CONTEXT_KEY_PAGE           = "page"
 	CONTEXT_KEY_PER_PAGE       = "per_page"
 	CONTEXT_KEY_SORT_BY_NEWEST = "sort_by_newest"
+
+	VALIDATOR_TAG_PROGRAM_YEAR = "program_year"
+
+	AUTH_PROVIDER_AUTH0 = "auth0"
+	AUTH_PROVIDER_DEV   = "dev"
 )
 
 type Api interface {
 	RunLocal() error
 	RunAzureFunctions(string) error
+	Handler() http.Handler
 }
 
 type env struct {
@@ -47,6 +53,9 @@ type env struct {
 	db        db.Db               `validate:"required"`
 	upc       upc.Upc             `validate:"required"`
 	api       *gin.Engine         `validate:"required"`
+
+	programYear utils.ProgramYear
+	timeZone    *time.Location
 }
 
 func ternary(s1 string, s2 string) string {
@@ -56,61 +65,55 @@ func ternary(s1 string, s2 string) string {
 	return s1
 }
 
-type UserInfo struct {
-	ID        string `json:"id"`
-	FirstName string `json:"first_name"`
-}
-
-type CustomClaims struct {
-	jwt.StandardClaims
-	UserInfo
+func programYearValidation(programYear utils.ProgramYear) validator.Func {
+	return func(fl validator.FieldLevel) bool {
+		return programYear.IsValid(fl.Field().String())
+	}
 }
 
-func decodeJWT(c *gin.Context) (*CustomClaims, error) {
-
-	var claims *CustomClaims
-
-	auth := c.Request.Header.Get("Authorization")
-	if auth == "" {
-		return claims, errors.New(ErrNoToken)
+// picks the message for a failed validation, singling out malformed program years
+func validationMessage(err error) string {
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		for _, fieldError := range validationErrors {
+			if fieldError.Tag() == VALIDATOR_TAG_PROGRAM_YEAR {
+				return ErrBadProgramYear
+			}
+		}
 	}
+	return ErrMissingFields
+}
 
-	splitToken := strings.Split(auth, "Bearer ")
-	if len(splitToken) == 1 {
-		return claims, errors.New(ErrBadToken)
-	}
-	auth = splitToken[1]
+// every issuer whose tokens the API accepts. each provider validates its own tokens, and handlers only ever see the
+// resulting principal
+func authProviders(logger *zap.SugaredLogger, cfg *config.Config, devIssuer *auth.DevIssuer) ([]auth.Provider, error) {
 
-	token, err := jwt.ParseWithClaims(auth, &CustomClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte("AccessToken"), nil
-	})
+	providers := []auth.Provider{}
 
-	if err != nil {
-		return claims, errors.New(ErrBadToken)
+	if cfg.Auth0.Domain != "" {
+		auth0, err := auth.NewOIDCProvider(logger, auth.OIDCProviderOptions{
+			Name:        AUTH_PROVIDER_AUTH0,
+			Issuer:      "https://" + cfg.Auth0.Domain + "/",
+			Audience:    cfg.Auth0.Audience,
+			UserInfoURL: cfg.Auth0.UserinfoURL,
+			UserInfoTTL: time.Duration(cfg.Auth0.UserinfoTTLSeconds) * time.Second,
+		})
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, auth0)
 	}
 
-	if claims, ok := token.Claims.(*CustomClaims); ok && token.Valid {
-		return claims, nil
+	// the dev issuer checks its own tokens in process, so it works whatever address the API is reached at
+	if devIssuer != nil {
+		providers = append(providers, devIssuer)
 	}
 
-	return claims, errors.New(ErrBadToken)
-
-}
-
-func generateJWT(userid string, firstName string) (string, error) {
-
-	token := jwt.New(jwt.SigningMethodHS256)
-	token.Claims = CustomClaims{
-		jwt.StandardClaims{
-			ExpiresAt: time.Now().Add(time.Minute * 60).Unix(),
-		},
-		UserInfo{
-			userid,
-			firstName,
-		},
+	if len(providers) == 0 {
+		return nil, errors.New("no authentication providers are configured")
 	}
 
-	return token.SignedString([]byte("AccessToken"))
+	return providers, nil
 
 }
 
@@ -122,6 +125,11 @@ func (e *env) RunAzureFunctions(port string) error {
 	return http.ListenAndServe(":"+port, e.api)
 }
 
+// the router itself, for serving the API from an httptest server
+func (e *env) Handler() http.Handler {
+	return e.api
+}
+
 func PaginationMiddleware(defaultSortByNewest bool) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		pageStr := c.DefaultQuery(CONTEXT_KEY_PAGE, strconv.Itoa(PAGE_DEFAULT))
@@ -170,19 +178,60 @@ func New(logger *zap.SugaredLogger, cfg *config.Config, dbInstance db.Db, upcIns
 
 	validator := validator.New()
 
+	programYear := utils.NewProgramYear(cfg.ProgramYear.StartMonth, cfg.ProgramYear.StartDay)
+
+	err := validator.RegisterValidation(VALIDATOR_TAG_PROGRAM_YEAR, programYearValidation(programYear))
+	if err != nil {
+		return nil, err
+	}
+
+	timeZone, err := time.LoadLocation(cfg.TimeZone)
+	if err != nil {
+		return nil, err
+	}
+
+	var devIssuer *auth.DevIssuer
+	if cfg.DevIssuer.Enabled {
+		logger.Warnf("Accepting tokens from the dev issuer at %s", cfg.DevIssuer.Issuer)
+		devUsers := []auth.DevUser{}
+		for _, user := range cfg.DevIssuer.Users {
+			roles := []auth.Role{}
+			for _, role := range user.Roles {
+				roles = append(roles, auth.Role(role))
+			}
+			devUsers = append(devUsers, auth.DevUser{Name: user.Name, Subject: user.Subject, Email: user.Email, Roles: roles})
+		}
+		devIssuer, err = auth.NewDevIssuer(logger, auth.DevIssuerOptions{
+			Name:     AUTH_PROVIDER_DEV,
+			Issuer:   cfg.DevIssuer.Issuer,
+			Audience: cfg.DevIssuer.Audience,
+			Users:    devUsers,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	providers, err := authProviders(logger, cfg, devIssuer)
+	if err != nil {
+		return nil, err
+	}
+
 	e := &env{
-		validator: validator,
-		logger:    logger,
-		config:    cfg,
-		db:        dbInstance,
-		upc:       upcInstance,
+		validator:   validator,
+		logger:      logger,
+		config:      cfg,
+		db:          dbInstance,
+		upc:         upcInstance,
+		programYear: programYear,
+		timeZone:    timeZone,
 	}
 
 	router := gin.Default()
 
 	router.Use(cors.New(cors.Config{
 		AllowCredentials: true,
-		AllowHeaders:     []string{"Authorization", "Content-Type"},
+		AllowHeaders:     []string{"Authorization", "Content-Type", middleware.HEADER_ACTING_AS},
 		AllowAllOrigins:  true,
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE"},
 	}))
@@ -197,157 +246,313 @@ func New(logger *zap.SugaredLogger, cfg *config.Config, dbInstance db.Db, upcIns
 		})
 	})
 
-	router.POST("/signup", e.signup)
-	router.POST("/signin", e.signin)
+	if devIssuer != nil {
+		devIssuer.Register(router)
+	}
 
 	/*Require Authentication for all later calls.*/
-	router.Use(middleware.EnsureValidToken(), middleware.GetToken());
+	router.Use(auth.Middleware(providers...))
 
 	router.POST("/register", e.register)
 
 	/*Require a valid user*/
 	router.Use(middleware.GetUser(e.db));
 
+	/*Resolve the user's roles*/
+	router.Use(middleware.GetRoles(e.db))
+
+	/*Let guardians act for linked members, and audit both identities*/
+	router.Use(middleware.ActingAs(e.db))
+	router.Use(middleware.Audit(e.db, logger))
+
 	router.GET("/auth", func(c *gin.Context){
-		c.String(http.StatusOK, "Authorized successfully, welcome, " + c.GetString("user_name") + "!")
+		principal, _ := auth.FromContext(c)
+		c.String(http.StatusOK, "Authorized successfully, welcome, " + principal.Name + "!")
 	})
 
-	router.GET("/user", e.getUserProfile)
-	router.PUT("/user", e.updateUserProfile)
-	
-
-	router.GET("/bookmarks", PaginationMiddleware(false), e.getUserBookmarks)
-	router.GET("/bookmarks/:link", e.getBookmarkByLink)
-	router.POST("/bookmarks", e.addUserBookmark)
-	router.DELETE("/bookmarks/:bookmarkID", e.deleteUserBookmark)
-
-	router.GET("/projects", PaginationMiddleware(true), e.getCurrentProjects)
-	router.GET("/project", PaginationMiddleware(true), e.getProjects)
-	router.GET("/project/:projectID", e.getProject)
-	router.POST("/project", e.addProject)
-	router.PUT("/project/:projectID", e.updateProject)
-	router.DELETE("/project/:projectID", e.deleteProject)
-
-	router.GET("/resume", e.getResume)
-
-	router.GET("/section1", PaginationMiddleware(false), e.getSection1s)
-	router.GET("/section1/:sectionID", e.getSection1)
-	router.POST("/section1", e.addSection1)
-	router.PUT("/section1/:sectionID", e.updateSection1)
-
-	router.GET("/section2", PaginationMiddleware(false), e.getSection2s)
-	router.GET("/section2/:sectionID", e.getSection2)
-	router.POST("/section2", e.addSection2)
-	router.PUT("/section2/:sectionID", e.updateSection2)
-
-	router.GET("/section3", PaginationMiddleware(false), e.getSection3s)
-	router.GET("/section3/:sectionID", e.getSection3)
-	router.POST("/section3", e.addSection3)
-	router.PUT("/section3/:sectionID", e.updateSection3)
-
-	router.GET("/section4", PaginationMiddleware(false), e.getSection4s)
-	router.GET("/section4/:sectionID", e.getSection4)
-	router.POST("/section4", e.addSection4)
-	router.PUT("/section4/:sectionID", e.updateSection4)
-
-	router.GET("/section5", PaginationMiddleware(false), e.getSection5s)
-	router.GET("/section5/:sectionID", e.getSection5)
-	router.POST("/section5", e.addSection5)
-	router.PUT("/section5/:sectionID", e.updateSection5)
-
-	router.GET("/section6", PaginationMiddleware(false), e.getSection6s)
-	router.GET("/section6/:sectionID", e.getSection6)
-	router.POST("/section6", e.addSection6)
-	router.PUT("/section6/:sectionID", e.updateSection6)
-
-	router.GET("/section7", PaginationMiddleware(false), e.getSection7s)
-	router.GET("/section7/:sectionID", e.getSection7)
-	router.POST("/section7", e.addSection7)
-	router.PUT("/section7/:sectionID", e.updateSection7)
-
-	router.GET("/section8", PaginationMiddleware(false), e.getSection8s)
-	router.GET("/section8/:sectionID", e.getSection8)
-	router.POST("/section8", e.addSection8)
-	router.PUT("/section8/:sectionID", e.updateSection8)
-
-	router.GET("/section9", PaginationMiddleware(false), e.getSection9s)
-	router.GET("/section9/:sectionID", e.getSection9)
-	router.POST("/section9", e.addSection9)
-	router.PUT("/section9/:sectionID", e.updateSection9)
-
-	router.GET("/section10", PaginationMiddleware(false), e.getSection10s)
-	router.GET("/section10/:sectionID", e.getSection10)
-	router.POST("/section10", e.addSection10)
-	router.PUT("/section10/:sectionID", e.updateSection10)
-
-	router.GET("/section11", PaginationMiddleware(false), e.getSection11s)
-	router.GET("/section11/:sectionID", e.getSection11)
-	router.POST("/section11", e.addSection11)
-	router.PUT("/section11/:sectionID", e.updateSection11)
-
-	router.GET("/section12", PaginationMiddleware(false), e.getSection12s)
-	router.GET("/section12/:sectionID", e.getSection12)
-	router.POST("/section12", e.addSection12)
-	router.PUT("/section12/:sectionID", e.updateSection12)
-
-	router.GET("/section13", PaginationMiddleware(false), e.getSection13s)
-	router.GET("/section13/:sectionID", e.getSection13)
-	router.POST("/section13", e.addSection13)
-	router.PUT("/section13/:sectionID", e.updateSection13)
-
-	router.GET("/section14", PaginationMiddleware(false), e.getSection14s)
-	router.GET("/section14/:sectionID", e.getSection14)
-	router.POST("/section14", e.addSection14)
-	router.PUT("/section14/:sectionID", e.updateSection14)
-
-	router.DELETE("/section/:sectionID", e.deleteSection)
-
-	router.GET("/event", PaginationMiddleware(false), e.getEvents)
-	router.POST("/event", e.addEvent)
-	router.PUT("/event/:eventID", e.updateEvent)
-	router.DELETE("/event/:eventID", e.deleteEvent)
-	router.GET("/event/:eventID", e.getEventWithSections)
-	router.POST("/event/:eventID", e.addEventSection)
-	router.DELETE("event/:eventID/:sectionID", e.deleteEventSection)
-
-	router.GET("/project/:projectID/animal", PaginationMiddleware(false), e.getAnimals)
-	router.GET("/animal/:animalID", e.getAnimal)
-	router.POST("/animal", e.addAnimal)
-	router.PUT("/animal/:animalID", e.updateAnimal)
-	router.PUT("/rate-of-gain/:animalID", e.updateRateOfGain)
-	router.DELETE("/animal/:animalID", e.deleteAnimal)
-
-	router.GET("/project/:projectID/feed", PaginationMiddleware(false), e.getFeeds)
-	router.GET("/feed/:feedID", e.getFeed)
-	router.POST("/feed", e.addFeed)
-	router.PUT("/feed/:feedID", e.updateFeed)
-	router.DELETE("/feed/:feedID", e.deleteFeed)
-
-	router.GET("/project/:projectID/feed-purchase", PaginationMiddleware(false), e.getFeedPurchases)
-	router.GET("/feed-purchase/:feedPurchaseID", e.getFeedPurchase)
-	router.POST("/feed-purchase", e.addFeedPurchase)
-	router.PUT("/feed-purchase/:feedPurchaseID", e.updateFeedPurchase)
-	router.DELETE("/feed-purchase/:feedPurchaseID", e.deleteFeedPurchase)
-
-	router.GET("/project/:projectID/animal/:animalID/daily-feed", PaginationMiddleware(false), e.getDailyFeeds)
-	router.GET("/daily-feed/:dailyFeedID", e.getDailyFeed)
-	router.POST("/daily-feed", e.addDailyFeed)
-	router.PUT("/daily-feed/:dailyFeedID", e.updateDailyFeed)
-	router.DELETE("/daily-feed/:dailyFeedID", e.deleteDailyFeed)
-
-	router.GET("/project/:projectID/expense", PaginationMiddleware(false), e.getExpenses)
-	router.GET("/expense/:expenseID", e.getExpense)
-	router.POST("/expense", e.addExpense)
-	router.PUT("/expense/:expenseID", e.updateExpense)
-	router.DELETE("/expense/:expenseID", e.deleteExpense)
-
-	router.GET("/project/:projectID/supply", PaginationMiddleware(false), e.getSupplies)
-	router.GET("/supply/:supplyID", e.getSupply)
-	router.POST("/supply", e.addSupply)
-	router.PUT("/supply/:supplyID", e.updateSupply)
-	router.DELETE("/supply/:supplyID", e.deleteSupply)
-
-	router.GET("/upc/:code", e.getUpcProduct)
+	router.GET("/roles", e.getRoles)
+	router.POST("/guardians/accept", e.acceptGuardianInvite)
+
+	/*Each group declares the permissions its routes need, see auth.ROLE_PERMISSIONS*/
+	profile := router.Group("", auth.RequirePermissions(auth.PERMISSION_PROFILE_MANAGE))
+
+	profile.GET("/user", e.getUserProfile)
+	profile.PUT("/user", e.updateUserProfile)
+
+	profile.GET("/bookmarks", PaginationMiddleware(false), e.getUserBookmarks)
+	profile.GET("/bookmarks/:link", e.getBookmarkByLink)
+	profile.POST("/bookmarks", e.addUserBookmark)
+	profile.DELETE("/bookmarks/:bookmarkID", e.deleteUserBookmark)
+
+	profile.GET("/memberships", e.getMemberships)
+	profile.DELETE("/memberships/:membershipID", e.leaveClub)
+	profile.POST("/clubs/join", e.joinClub)
+
+	reference := router.Group("", auth.RequirePermissions(auth.PERMISSION_REFERENCE_READ))
+
+	reference.GET("/project-kinds", e.getProjectKinds)
+	reference.GET("/expense-categories", e.getExpenseCategories)
+	reference.GET("/upc/:code", e.getUpcProduct)
+
+	router.POST("/clubs", auth.RequirePermissions(auth.PERMISSION_CLUB_MANAGE), e.addClub)
+
+	clubs := router.Group("/club/:clubID", auth.RequireReadWrite(auth.PERMISSION_CLUB_READ, auth.PERMISSION_CLUB_MANAGE))
+
+	clubs.GET("", e.getClub)
+	clubs.GET("/members", e.getClubMembers)
+	clubs.GET("/dashboard", PaginationMiddleware(false), e.getClubDashboard)
+	clubs.POST("/members/:membershipID/approve", e.approveClubMember)
+	clubs.DELETE("/members/:membershipID", e.removeClubMember)
+	clubs.POST("/code", e.resetClubCode)
+	clubs.GET("/submissions", e.getClubSubmissions)
+	clubs.POST("/members/:membershipID/submission/approve", e.approveSubmission)
+	clubs.POST("/members/:membershipID/submission/submit", e.submitSubmission)
+	clubs.POST("/members/:membershipID/submission/reopen", e.reopenSubmission)
+
+	router.POST("/club/:clubID/members/:membershipID/submission/judge", auth.RequirePermissions(auth.PERMISSION_JUDGE), e.judgeSubmission)
+
+	county := router.Group("/county/:countyName", auth.RequirePermissions(auth.PERMISSION_JUDGING_MANAGE))
+
+	county.GET("/rubrics", e.getRubrics)
+	county.POST("/rubrics", e.addRubric)
+	county.GET("/rubric/:rubricID", e.getRubric)
+	county.PUT("/rubric/:rubricID", e.updateRubric)
+	county.DELETE("/rubric/:rubricID", e.deleteRubric)
+	county.GET("/scoresheets", e.getCountyScoresheets)
+	county.POST("/scoresheets", e.assignScoresheet)
+	county.DELETE("/scoresheet/:scoresheetID", e.deleteScoresheet)
+	county.GET("/standings", e.getStandings)
+	county.POST("/results/publish", e.publishResults)
+
+	judging := router.Group("/judging", auth.RequirePermissions(auth.PERMISSION_JUDGE))
+
+	judging.GET("/scoresheets", e.getJudgeScoresheets)
+	judging.GET("/scoresheets/:countyName/:scoresheetID", e.getJudgeScoresheet)
+	judging.GET("/scoresheets/:countyName/:scoresheetID/resume", e.getJudgeScoresheetResume)
+	judging.PUT("/scoresheets/:countyName/:scoresheetID", e.scoreScoresheet)
+
+	/*Comments on another member's records, from the people overseeing them*/
+	review := router.Group("/member/:memberID", auth.RequirePermissions(auth.PERMISSION_RECORDS_REVIEW))
+
+	review.GET("/comments", e.getComments)
+	review.GET("/comments/unread", e.getUnreadComments)
+	review.POST("/comments", e.addComment)
+	review.POST("/comment/:commentID/resolve", e.resolveComment)
+	review.POST("/comment/:commentID/unresolve", e.unresolveComment)
+	review.POST("/comment/:commentID/read", e.readComment)
+
+	wards := router.Group("/wards", auth.RequirePermissions(auth.PERMISSION_LINKED_ACT))
+
+	wards.GET("", e.getWards)
+	wards.DELETE("/:guardianLinkID", e.deleteWard)
+
+	roles := router.Group("/user/:userID/roles", auth.RequirePermissions(auth.PERMISSION_ROLES_MANAGE))
+
+	roles.GET("", e.getUserRoleAssignments)
+	roles.POST("", e.addUserRoleAssignment)
+	roles.DELETE("/:roleAssignmentID", e.deleteUserRoleAssignment)
+
+	/*Members' own record books. Reads need records:read and everything else also needs records:write, and changes
+	to a year whose record book has been submitted are rejected*/
+	records := router.Group("", auth.RequireReadWrite(auth.PERMISSION_RECORDS_READ, auth.PERMISSION_RECORDS_WRITE), e.RecordLockMiddleware())
+
+	records.GET("/guardians", e.getGuardians)
+	records.POST("/guardians", e.inviteGuardian)
+	records.DELETE("/guardians/:guardianLinkID", e.deleteGuardian)
+	records.GET("/audit", PaginationMiddleware(true), e.getAuditEntries)
+
+	records.GET("/submissions", e.getSubmissions)
+	records.GET("/submission/:year", e.getSubmission)
+	records.POST("/submission/:year/sign", e.signSubmission)
+	records.GET("/results", e.getResults)
+
+	records.GET("/comments", e.getComments)
+	records.GET("/comments/unread", e.getUnreadComments)
+	records.POST("/comments", e.addComment)
+	records.POST("/comment/:commentID/resolve", e.resolveComment)
+	records.POST("/comment/:commentID/unresolve", e.unresolveComment)
+	records.POST("/comment/:commentID/read", e.readComment)
+
+	records.GET("/projects", PaginationMiddleware(true), e.getCurrentProjects)
+	records.GET("/project", PaginationMiddleware(true), e.getProjects)
+	records.GET("/project/:projectID", e.getProject)
+	records.POST("/project", e.addProject)
+	records.PUT("/project/:projectID", e.updateProject)
+	records.DELETE("/project/:projectID", e.deleteProject)
+	records.POST("/project/:projectID/rollover", e.rolloverProject)
+	records.GET("/project/:projectID/lineage", e.getProjectLineage)
+
+	records.GET("/resume", e.getResume)
+
+	records.GET("/section1", PaginationMiddleware(false), e.getSection1s)
+	records.GET("/section1/:sectionID", e.getSection1)
+	records.POST("/section1", e.addSection1)
+	records.PUT("/section1/:sectionID", e.updateSection1)
+
+	records.GET("/section2", PaginationMiddleware(false), e.getSection2s)
+	records.GET("/section2/:sectionID", e.getSection2)
+	records.POST("/section2", e.addSection2)
+	records.PUT("/section2/:sectionID", e.updateSection2)
+
+	records.GET("/section3", PaginationMiddleware(false), e.getSection3s)
+	records.GET("/section3/:sectionID", e.getSection3)
+	records.POST("/section3", e.addSection3)
+	records.PUT("/section3/:sectionID", e.updateSection3)
+
+	records.GET("/section4", PaginationMiddleware(false), e.getSection4s)
+	records.GET("/section4/:sectionID", e.getSection4)
+	records.POST("/section4", e.addSection4)
+	records.PUT("/section4/:sectionID", e.updateSection4)
+
+	records.GET("/section5", PaginationMiddleware(false), e.getSection5s)
+	records.GET("/section5/:sectionID", e.getSection5)
+	records.POST("/section5", e.addSection5)
+	records.PUT("/section5/:sectionID", e.updateSection5)
+
+	records.GET("/section6", PaginationMiddleware(false), e.getSection6s)
+	records.GET("/section6/:sectionID", e.getSection6)
+	records.POST("/section6", e.addSection6)
+	records.PUT("/section6/:sectionID", e.updateSection6)
+
+	records.GET("/section7", PaginationMiddleware(false), e.getSection7s)
+	records.GET("/section7/:sectionID", e.getSection7)
+	records.POST("/section7", e.addSection7)
+	records.PUT("/section7/:sectionID", e.updateSection7)
+
+	records.GET("/section8", PaginationMiddleware(false), e.getSection8s)
+	records.GET("/section8/:sectionID", e.getSection8)
+	records.POST("/section8", e.addSection8)
+	records.PUT("/section8/:sectionID", e.updateSection8)
+
+	records.GET("/section9", PaginationMiddleware(false), e.getSection9s)
+	records.GET("/section9/:sectionID", e.getSection9)
+	records.POST("/section9", e.addSection9)
+	records.PUT("/section9/:sectionID", e.updateSection9)
+
+	records.GET("/section10", PaginationMiddleware(false), e.getSection10s)
+	records.GET("/section10/:sectionID", e.getSection10)
+	records.POST("/section10", e.addSection10)
+	records.PUT("/section10/:sectionID", e.updateSection10)
+
+	records.GET("/section11", PaginationMiddleware(false), e.getSection11s)
+	records.GET("/section11/:sectionID", e.getSection11)
+	records.POST("/section11", e.addSection11)
+	records.PUT("/section11/:sectionID", e.updateSection11)
+
+	records.GET("/section12", PaginationMiddleware(false), e.getSection12s)
+	records.GET("/section12/:sectionID", e.getSection12)
+	records.POST("/section12", e.addSection12)
+	records.PUT("/section12/:sectionID", e.updateSection12)
+
+	records.GET("/section13", PaginationMiddleware(false), e.getSection13s)
+	records.GET("/section13/:sectionID", e.getSection13)
+	records.POST("/section13", e.addSection13)
+	records.PUT("/section13/:sectionID", e.updateSection13)
+
+	records.GET("/section14", PaginationMiddleware(false), e.getSection14s)
+	records.GET("/section14/:sectionID", e.getSection14)
+	records.POST("/section14", e.addSection14)
+	records.PUT("/section14/:sectionID", e.updateSection14)
+
+	records.DELETE("/section/:sectionID", e.deleteSection)
+
+	records.GET("/event", PaginationMiddleware(false), e.getEvents)
+	records.POST("/event", e.addEvent)
+	records.PUT("/event/:eventID", e.updateEvent)
+	records.DELETE("/event/:eventID", e.deleteEvent)
+	records.GET("/event/:eventID", e.getEventWithSections)
+	records.POST("/event/:eventID", e.addEventSection)
+	records.DELETE("event/:eventID/:sectionID", e.deleteEventSection)
+
+	records.GET("/project/:projectID/animal", PaginationMiddleware(false), e.getAnimals)
+	records.GET("/animal/lookup", e.lookupAnimal)
+	records.GET("/animal/:animalID", e.getAnimal)
+	records.POST("/animal", e.addAnimal)
+	records.PUT("/animal/:animalID", e.updateAnimal)
+	records.PUT("/rate-of-gain/:animalID", e.updateRateOfGain)
+	records.PUT("/animal/:animalID/status", e.updateAnimalStatus)
+	records.DELETE("/animal/:animalID", e.deleteAnimal)
+
+	records.GET("/project/:projectID/feed", PaginationMiddleware(false), e.getFeeds)
+	records.GET("/feed/:feedID", e.getFeed)
+	records.POST("/feed", e.addFeed)
+	records.PUT("/feed/:feedID", e.updateFeed)
+	records.DELETE("/feed/:feedID", e.deleteFeed)
+
+	records.GET("/project/:projectID/feed-purchase", PaginationMiddleware(false), e.getFeedPurchases)
+	records.GET("/feed-purchase/:feedPurchaseID", e.getFeedPurchase)
+	records.POST("/feed-purchase", e.addFeedPurchase)
+	records.PUT("/feed-purchase/:feedPurchaseID", e.updateFeedPurchase)
+	records.DELETE("/feed-purchase/:feedPurchaseID", e.deleteFeedPurchase)
+
+	records.GET("/project/:projectID/animal/:animalID/daily-feed", PaginationMiddleware(false), e.getDailyFeeds)
+	records.GET("/daily-feed/:dailyFeedID", e.getDailyFeed)
+	records.POST("/daily-feed", e.addDailyFeed)
+	records.POST("/daily-feed/batch", e.addDailyFeedBatch)
+	records.PUT("/daily-feed/:dailyFeedID", e.updateDailyFeed)
+	records.DELETE("/daily-feed/:dailyFeedID", e.deleteDailyFeed)
+
+	records.GET("/project/:projectID/expense", PaginationMiddleware(false), e.getExpenses)
+	records.GET("/project/:projectID/expense/summary", e.getExpenseSummary)
+	records.GET("/expense/:expenseID", e.getExpense)
+	records.POST("/expense", e.addExpense)
+	records.PUT("/expense/:expenseID", e.updateExpense)
+	records.DELETE("/expense/:expenseID", e.deleteExpense)
+
+	records.GET("/project/:projectID/supply", PaginationMiddleware(false), e.getSupplies)
+	records.GET("/supply/:supplyID", e.getSupply)
+	records.GET("/supply/:supplyID/schedule", e.getSupplySchedule)
+	records.POST("/supply", e.addSupply)
+	records.PUT("/supply/:supplyID", e.updateSupply)
+	records.DELETE("/supply/:supplyID", e.deleteSupply)
+
+	records.GET("/animal/:animalID/health-record", PaginationMiddleware(false), e.getHealthRecords)
+	records.GET("/health-record/:healthRecordID", e.getHealthRecord)
+	records.POST("/health-record", e.addHealthRecord)
+	records.PUT("/health-record/:healthRecordID", e.updateHealthRecord)
+	records.DELETE("/health-record/:healthRecordID", e.deleteHealthRecord)
+	records.GET("/project/:projectID/withdrawal", e.getWithdrawals)
+
+	records.GET("/animal/:animalID/breeding", PaginationMiddleware(false), e.getBreedings)
+	records.GET("/breeding/:breedingID", e.getBreeding)
+	records.POST("/breeding", e.addBreeding)
+	records.PUT("/breeding/:breedingID", e.updateBreeding)
+	records.DELETE("/breeding/:breedingID", e.deleteBreeding)
+
+	records.GET("/animal/:animalID/birth", PaginationMiddleware(false), e.getBirths)
+	records.GET("/birth/:birthID", e.getBirth)
+	records.POST("/birth", e.addBirth)
+	records.PUT("/birth/:birthID", e.updateBirth)
+	records.DELETE("/birth/:birthID", e.deleteBirth)
+
+	records.GET("/project/:projectID/activity-log", PaginationMiddleware(false), e.getActivityLogs)
+	records.GET("/activity-log/:activityLogID", e.getActivityLog)
+	records.POST("/activity-log", e.addActivityLog)
+	records.PUT("/activity-log/:activityLogID", e.updateActivityLog)
+	records.DELETE("/activity-log/:activityLogID", e.deleteActivityLog)
+
+	records.GET("/project/:projectID/exhibit", PaginationMiddleware(false), e.getExhibits)
+	records.GET("/exhibit/:exhibitID", e.getExhibit)
+	records.POST("/exhibit", e.addExhibit)
+	records.PUT("/exhibit/:exhibitID", e.updateExhibit)
+	records.DELETE("/exhibit/:exhibitID", e.deleteExhibit)
+
+	records.GET("/project/:projectID/goal", PaginationMiddleware(false), e.getProjectGoals)
+	records.GET("/goal/:projectGoalID", e.getProjectGoal)
+	records.POST("/goal", e.addProjectGoal)
+	records.PUT("/goal/:projectGoalID", e.updateProjectGoal)
+	records.DELETE("/goal/:projectGoalID", e.deleteProjectGoal)
+
+	records.GET("/project/:projectID/budget-item", PaginationMiddleware(false), e.getProjectBudgetItems)
+	records.GET("/budget-item/:projectBudgetItemID", e.getProjectBudgetItem)
+	records.POST("/budget-item", e.addProjectBudgetItem)
+	records.PUT("/budget-item/:projectBudgetItemID", e.updateProjectBudgetItem)
+	records.DELETE("/budget-item/:projectBudgetItemID", e.deleteProjectBudgetItem)
+	records.GET("/project/:projectID/budget-report", e.getProjectBudgetReport)
+
+	records.GET("/project/:projectID/reflection", e.getProjectReflection)
+	records.PUT("/project/:projectID/reflection", e.upsertProjectReflection)
+	records.DELETE("/project/:projectID/reflection", e.deleteProjectReflection)
 
 	e.api = router
 
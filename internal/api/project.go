@@ -1,14 +1,19 @@
 package api
 
 import (
+	"4h-recordbook-backend/internal/auth"
 	"4h-recordbook-backend/internal/utils"
 	"4h-recordbook-backend/pkg/db"
+	"context"
 	"strconv"
+	"time"
 
 	"github.com/beevik/guid"
 	"github.com/gin-gonic/gin"
 )
 
+const ROLLOVER_CLEANUP_TIMEOUT = 5 * time.Second
+
 type GetProjectsOutput struct {
 	Projects []db.Project `json:"projects"`
 	Next     string       `json:"next"`
@@ -18,33 +23,66 @@ type GetProjectOutput struct {
 	Project db.Project `json:"project"`
 }
 
+type GetProjectWithGoalsOutput struct {
+	Project        db.Project `json:"project"`
+	GoalCompletion float64    `json:"goal_completion"`
+}
+
 type UpsertProjectInput struct {
-	Year        string `json:"year" validate:"required"`
+	Year        string `json:"year" validate:"required,program_year"`
 	Name        string `json:"name" validate:"required"`
 	Description string `json:"description" validate:"required"`
 	Type        string `json:"type" validate:"required"`
+	Kind        string `json:"kind" validate:"omitempty,oneof=livestock still_life science_engineering"`
 	StartDate   string `json:"start_date" validate:"required"`
 	EndDate     string `json:"end_date" validate:"required"`
 }
 
 type UpsertProjectOutput GetProjectOutput
 
+type RolloverProjectInput struct {
+	Name                string `json:"name"`
+	Description         string `json:"description"`
+	StartDate           string `json:"start_date"`
+	EndDate             string `json:"end_date"`
+	CopyFeeds           bool   `json:"copy_feeds"`
+	CopyRetainedAnimals bool   `json:"copy_retained_animals"`
+	CopySupplies        bool   `json:"copy_supplies"`
+}
+
+type RolloverProjectOutput struct {
+	Project  db.Project  `json:"project"`
+	Feeds    []db.Feed   `json:"feeds"`
+	Animals  []db.Animal `json:"animals"`
+	Supplies []db.Supply `json:"supplies"`
+}
+
+type GetProjectLineageOutput struct {
+	Projects []db.Project `json:"projects"`
+}
+
+type GetProjectKindsOutput struct {
+	ProjectKinds []db.ProjectKind `json:"project_kinds"`
+}
+
 // GetCurrentProjects godoc
-// @Summary Gets projects of the current year
-// @Description Gets all of a user's projects that take place in the last 12 months
+// @Summary Gets projects of a program year
+// @Description Gets all of a user's projects in the given program year, or in the current program year when none is given
 // @Tags Project
 // @Accept json
 // @Produce json
 // @Security ApiKeyAuth
+// @Param year query string false "Program year, e.g. 2025-2026. Defaults to the current program year"
 // @Param page query int false "Page number, default 0"
 // @Param per_page query int false "Max number of items to return. Can be [1-200], default 100"
 // @Param sort_by_newest query bool false "Sort results by most recently added, default true"
 // @Success 200 {object} api.GetProjectsOutput
+// @Failure 400
 // @Failure 401
 // @Router /projects [get]
 func (e *env) getCurrentProjects(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -52,6 +90,14 @@ func (e *env) getCurrentProjects(c *gin.Context) {
 		return
 	}
 
+	year := ternary(c.Query("year"), e.programYear.Current(e.userLocation(c.Request.Context(), principal.UserID)))
+	if !e.programYear.IsValid(year) {
+		c.JSON(400, gin.H{
+			"message": ErrBadProgramYear,
+		})
+		return
+	}
+
 	var output GetProjectsOutput
 
 	paginationOptions := db.PaginationOptions{
@@ -60,7 +106,7 @@ func (e *env) getCurrentProjects(c *gin.Context) {
 		SortByNewest: c.GetBool(CONTEXT_KEY_SORT_BY_NEWEST),
 	}
 
-	output.Projects, err = e.db.GetCurrentProjects(c.Request.Context(), claims.ID, paginationOptions)
+	output.Projects, err = e.db.GetProjectsByYear(c.Request.Context(), principal.UserID, year, paginationOptions)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -75,6 +121,7 @@ func (e *env) getCurrentProjects(c *gin.Context) {
 		queryParamsMap[CONTEXT_KEY_PAGE] = strconv.Itoa(paginationOptions.Page + 1)
 		queryParamsMap[CONTEXT_KEY_PER_PAGE] = strconv.Itoa(paginationOptions.PerPage)
 		queryParamsMap[CONTEXT_KEY_SORT_BY_NEWEST] = strconv.FormatBool(paginationOptions.SortByNewest)
+		queryParamsMap["year"] = year
 
 		nextUrlInput := utils.NextUrlInput{
 			Context:     c,
@@ -103,7 +150,7 @@ func (e *env) getCurrentProjects(c *gin.Context) {
 // @Router /project [get]
 func (e *env) getProjects(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -119,7 +166,7 @@ func (e *env) getProjects(c *gin.Context) {
 		SortByNewest: c.GetBool(CONTEXT_KEY_SORT_BY_NEWEST),
 	}
 
-	output.Projects, err = e.db.GetProjectsByUser(c.Request.Context(), claims.ID, paginationOptions)
+	output.Projects, err = e.db.GetProjectsByUser(c.Request.Context(), principal.UserID, paginationOptions)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -149,19 +196,19 @@ func (e *env) getProjects(c *gin.Context) {
 
 // GetProject godoc
 // @Summary Get a project
-// @Description Get a user's project by ID
+// @Description Get a user's project by ID, along with the percentage of its goals that are completed
 // @Tags Project
 // @Accept json
 // @Produce json
 // @Security ApiKeyAuth
 // @Param projectID path string true "Project ID"
-// @Success 200 {object} api.GetProjectOutput
+// @Success 200 {object} api.GetProjectWithGoalsOutput
 // @Failure 401
 // @Failure 404
 // @Router /project/{projectID} [get]
 func (e *env) getProject(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -171,9 +218,9 @@ func (e *env) getProject(c *gin.Context) {
 
 	projectID := c.Param("projectID")
 
-	var output GetProjectOutput
+	var output GetProjectWithGoalsOutput
 
-	output.Project, err = e.db.GetProjectByID(c.Request.Context(), claims.ID, projectID)
+	output.Project, err = e.db.GetProjectByID(c.Request.Context(), principal.UserID, projectID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -182,6 +229,17 @@ func (e *env) getProject(c *gin.Context) {
 		return
 	}
 
+	goals, err := e.db.GetAllProjectGoalsByProject(c.Request.Context(), principal.UserID, projectID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	output.GoalCompletion = goalCompletion(goals)
+
 	c.JSON(200, output)
 
 }
@@ -200,7 +258,7 @@ func (e *env) getProject(c *gin.Context) {
 // @Router /project [post]
 func (e *env) addProject(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -220,7 +278,7 @@ func (e *env) addProject(c *gin.Context) {
 	err = e.validator.Struct(input)
 	if err != nil {
 		c.JSON(400, gin.H{
-			"message": ErrMissingFields,
+			"message": validationMessage(err),
 		})
 		return
 	}
@@ -228,7 +286,9 @@ func (e *env) addProject(c *gin.Context) {
 	g := guid.New()
 	timestamp := utils.TimeNow()
 
-	startDate, err := utils.StringToTimestamp(input.StartDate)
+	location := e.userLocation(c.Request.Context(), principal.UserID)
+
+	startDate, err := utils.StringToDate(input.StartDate, location)
 	if err != nil {
 		c.JSON(400, gin.H{
 			"message": ErrBadDate,
@@ -236,7 +296,7 @@ func (e *env) addProject(c *gin.Context) {
 		return
 	}
 
-	endDate, err := utils.StringToTimestamp(input.EndDate)
+	endDate, err := utils.StringToDate(input.EndDate, location)
 	if err != nil {
 		c.JSON(400, gin.H{
 			"message": ErrBadDate,
@@ -250,9 +310,10 @@ func (e *env) addProject(c *gin.Context) {
 		Name:        input.Name,
 		Description: input.Description,
 		Type:        input.Type,
+		Kind:        ternary(input.Kind, db.PROJECT_KIND_LIVESTOCK),
 		StartDate:   startDate.String(),
 		EndDate:     endDate.String(),
-		UserID:      claims.ID,
+		UserID:      principal.UserID,
 		GenericDatabaseInfo: db.GenericDatabaseInfo{
 			Created: timestamp.String(),
 			Updated: timestamp.String(),
@@ -276,7 +337,8 @@ func (e *env) addProject(c *gin.Context) {
 
 // UpdateProject godoc
 // @Summary Update a project
-// @Description Updates a user's project information
+// @Description Updates a user's project information. A project can't change to a kind that has no page for records
+// @Description it already has, e.g. to still life while it has animals or feeds
 // @Tags Project
 // @Accept json
 // @Produce json
@@ -287,10 +349,11 @@ func (e *env) addProject(c *gin.Context) {
 // @Failure 400
 // @Failure 401
 // @Failure 404
+// @Failure 409
 // @Router /project/{projectID} [put]
 func (e *env) updateProject(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -310,12 +373,14 @@ func (e *env) updateProject(c *gin.Context) {
 	err = e.validator.Struct(input)
 	if err != nil {
 		c.JSON(400, gin.H{
-			"message": ErrMissingFields,
+			"message": validationMessage(err),
 		})
 		return
 	}
 
-	startDate, err := utils.StringToTimestamp(input.StartDate)
+	location := e.userLocation(c.Request.Context(), principal.UserID)
+
+	startDate, err := utils.StringToDate(input.StartDate, location)
 	if err != nil {
 		c.JSON(400, gin.H{
 			"message": ErrBadDate,
@@ -323,7 +388,7 @@ func (e *env) updateProject(c *gin.Context) {
 		return
 	}
 
-	endDate, err := utils.StringToTimestamp(input.EndDate)
+	endDate, err := utils.StringToDate(input.EndDate, location)
 	if err != nil {
 		c.JSON(400, gin.H{
 			"message": ErrBadDate,
@@ -333,7 +398,7 @@ func (e *env) updateProject(c *gin.Context) {
 
 	projectID := c.Param("projectID")
 
-	project, err := e.db.GetProjectByID(c.Request.Context(), claims.ID, projectID)
+	project, err := e.db.GetProjectByID(c.Request.Context(), principal.UserID, projectID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -342,17 +407,38 @@ func (e *env) updateProject(c *gin.Context) {
 		return
 	}
 
+	kind := ternary(input.Kind, project.Kind)
+
+	if kind != project.CurrentKind() {
+		stranded, err := e.strandsRecords(c.Request.Context(), principal.UserID, project, kind)
+		if err != nil {
+			response := InterpretCosmosError(err)
+			c.JSON(response.Code, gin.H{
+				"message": response.Message,
+			})
+			return
+		}
+		if stranded {
+			c.JSON(409, gin.H{
+				"message": ErrProjectKindHasRecords,
+			})
+			return
+		}
+	}
+
 	timestamp := utils.TimeNow()
 
 	updatedProject := db.Project{
-		ID:          project.ID,
-		Year:        input.Year,
-		Name:        input.Name,
-		Description: input.Description,
-		Type:        input.Type,
-		StartDate:   startDate.String(),
-		EndDate:     endDate.String(),
-		UserID:      claims.ID,
+		ID:                project.ID,
+		Year:              input.Year,
+		Name:              input.Name,
+		Description:       input.Description,
+		Type:              input.Type,
+		Kind:              kind,
+		StartDate:         startDate.String(),
+		EndDate:           endDate.String(),
+		PreviousProjectID: project.PreviousProjectID,
+		UserID:            principal.UserID,
 		GenericDatabaseInfo: db.GenericDatabaseInfo{
 			Created: project.Created,
 			Updated: timestamp.String(),
@@ -388,7 +474,7 @@ func (e *env) updateProject(c *gin.Context) {
 // @Router /project/{projectID} [delete]
 func (e *env) deleteProject(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -398,7 +484,7 @@ func (e *env) deleteProject(c *gin.Context) {
 
 	projectID := c.Param("projectID")
 
-	response, err := e.db.RemoveProject(c.Request.Context(), claims.ID, projectID)
+	response, err := e.db.RemoveProject(c.Request.Context(), principal.UserID, projectID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -410,3 +496,562 @@ func (e *env) deleteProject(c *gin.Context) {
 	c.JSON(204, response)
 
 }
+
+// RolloverProject godoc
+// @Summary Roll a project over into the next program year
+// @Description Creates a copy of a project for the following program year and links it to the original. Feeds, retained animals and supplies can be copied along with it, with each animal's end weight becoming its beginning weight and each supply's end value becoming its start value. Dates default to one year after the original project's, and must be given if the original's are not well formed
+// @Tags Project
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param projectID path string true "Project ID"
+// @Param RolloverProjectInput body api.RolloverProjectInput true "Overrides for the new project and which records to copy"
+// @Success 201 {object} api.RolloverProjectOutput
+// @Failure 400
+// @Failure 401
+// @Failure 404
+// @Failure 409
+// @Router /project/{projectID}/rollover [post]
+func (e *env) rolloverProject(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	var input RolloverProjectInput
+	err = c.BindJSON(&input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrBadRequest,
+		})
+		return
+	}
+
+	projectID := c.Param("projectID")
+
+	project, err := e.db.GetProjectByID(c.Request.Context(), principal.UserID, projectID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	if (input.CopyFeeds && !project.HasRecordPage(db.RECORD_PAGE_FEEDS)) || (input.CopyRetainedAnimals && !project.HasRecordPage(db.RECORD_PAGE_ANIMALS)) || (input.CopySupplies && !project.HasRecordPage(db.RECORD_PAGE_SUPPLIES)) {
+		c.JSON(400, gin.H{
+			"message": ErrRecordPageNotAllowed,
+		})
+		return
+	}
+
+	successors, err := e.db.GetProjectsByPreviousProject(c.Request.Context(), principal.UserID, projectID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+	if len(successors) > 0 {
+		c.JSON(409, gin.H{
+			"message": ErrProjectRolledOver,
+		})
+		return
+	}
+
+	location := e.userLocation(c.Request.Context(), principal.UserID)
+
+	startDate, err := rolloverDate(input.StartDate, project.StartDate, location)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrBadDate,
+		})
+		return
+	}
+
+	endDate, err := rolloverDate(input.EndDate, project.EndDate, location)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrBadDate,
+		})
+		return
+	}
+
+	// projects saved before program years were validated may not have a well formed year
+	year, err := e.programYear.Next(project.Year)
+	if err != nil {
+		year = e.programYear.Of(startDate.Time())
+	}
+
+	timestamp := utils.TimeNow()
+	g := guid.New()
+
+	var output RolloverProjectOutput
+
+	output.Project = db.Project{
+		ID:                g.String(),
+		Year:              year,
+		Name:              ternary(input.Name, project.Name),
+		Description:       ternary(input.Description, project.Description),
+		Type:              project.Type,
+		Kind:              project.CurrentKind(),
+		StartDate:         startDate.String(),
+		EndDate:           endDate.String(),
+		PreviousProjectID: project.ID,
+		UserID:            principal.UserID,
+		GenericDatabaseInfo: db.GenericDatabaseInfo{
+			Created: timestamp.String(),
+			Updated: timestamp.String(),
+		},
+	}
+
+	output.Feeds = []db.Feed{}
+
+	if input.CopyFeeds {
+
+		feeds, err := e.db.GetAllFeedsByProject(c.Request.Context(), principal.UserID, projectID)
+		if err != nil {
+			response := InterpretCosmosError(err)
+			c.JSON(response.Code, gin.H{
+				"message": response.Message,
+			})
+			return
+		}
+
+		for _, feed := range feeds {
+			output.Feeds = append(output.Feeds, db.Feed{
+				ID:        guid.New().String(),
+				Name:      feed.Name,
+				ProjectID: output.Project.ID,
+				UserID:    principal.UserID,
+				GenericDatabaseInfo: db.GenericDatabaseInfo{
+					Created: timestamp.String(),
+					Updated: timestamp.String(),
+				},
+			})
+		}
+
+	}
+
+	output.Animals = []db.Animal{}
+
+	if input.CopyRetainedAnimals {
+
+		animals, err := e.db.GetAllAnimalsByProject(c.Request.Context(), principal.UserID, projectID)
+		if err != nil {
+			response := InterpretCosmosError(err)
+			c.JSON(response.Code, gin.H{
+				"message": response.Message,
+			})
+			return
+		}
+
+		for _, animal := range animals {
+
+			if animal.CurrentStatus() != db.ANIMAL_STATUS_RETAINED {
+				continue
+			}
+
+			output.Animals = append(output.Animals, db.Animal{
+				ID:              guid.New().String(),
+				Name:            animal.Name,
+				Species:         animal.Species,
+				BirthDate:       animal.BirthDate,
+				PurchaseDate:    animal.PurchaseDate,
+				SireBreed:       animal.SireBreed,
+				DamBreed:        animal.DamBreed,
+				AnimalCost:      animal.AnimalCost,
+				SalePrice:       0,
+				YieldGrade:      "",
+				QualityGrade:    "",
+				BeginningWeight: animal.EndWeight,
+				BeginningDate:   animal.EndDate,
+				EndWeight:       0,
+				WeightUnit:      utils.CANONICAL_MASS_UNIT,
+				EndDate:         "",
+				Status:          db.ANIMAL_STATUS_ACTIVE,
+				Identifiers:     animal.Identifiers,
+				DamID:           animal.DamID,
+				SireID:          animal.SireID,
+				BirthID:         animal.BirthID,
+				OriginAnimalID:  animal.OriginID(),
+				ProjectID:       output.Project.ID,
+				UserID:          principal.UserID,
+				GenericDatabaseInfo: db.GenericDatabaseInfo{
+					Created: timestamp.String(),
+					Updated: timestamp.String(),
+				},
+			})
+
+		}
+
+	}
+
+	output.Supplies = []db.Supply{}
+
+	if input.CopySupplies {
+
+		supplies, err := e.db.GetAllSuppliesByProject(c.Request.Context(), principal.UserID, projectID)
+		if err != nil {
+			response := InterpretCosmosError(err)
+			c.JSON(response.Code, gin.H{
+				"message": response.Message,
+			})
+			return
+		}
+
+		for _, supply := range supplies {
+
+			carriedOver := supply
+			carriedOver.ID = guid.New().String()
+			carriedOver.StartValue = supply.EndValue
+			carriedOver.ProjectID = output.Project.ID
+			carriedOver.GenericDatabaseInfo = db.GenericDatabaseInfo{
+				Created: timestamp.String(),
+				Updated: timestamp.String(),
+			}
+
+			if carriedOver.Depreciates() {
+				err := setSupplyValues(&carriedOver, output.Project, location)
+				if err != nil {
+					c.JSON(500, gin.H{
+						"message": err.Error(),
+					})
+					return
+				}
+			}
+
+			output.Supplies = append(output.Supplies, carriedOver)
+
+		}
+
+	}
+
+	// copies are written before the project so that nothing is linked to the original until the rollover is saved. if a
+	// write fails, the copies already written are removed so the rollover can be retried
+	for start := 0; start < len(output.Feeds); start += db.MAX_BATCH_SIZE {
+
+		statusCodes, err := e.db.UpsertFeedBatch(c.Request.Context(), principal.UserID, output.Feeds[start:min(start+db.MAX_BATCH_SIZE, len(output.Feeds))])
+		if err != nil {
+			e.removeRolloverCopies(c.Request.Context(), principal.UserID, output.Feeds[:start], nil, nil)
+			response := InterpretCosmosError(err)
+			c.JSON(response.Code, gin.H{
+				"message": response.Message,
+			})
+			return
+		}
+
+		if statusCode := batchFailure(statusCodes); statusCode != 0 {
+			e.removeRolloverCopies(c.Request.Context(), principal.UserID, output.Feeds[:start], nil, nil)
+			c.JSON(statusCode, gin.H{
+				"message": ternary(HTTPResponseCodeMap[statusCode], "unexpected error"),
+			})
+			return
+		}
+
+	}
+
+	for start := 0; start < len(output.Animals); start += db.MAX_BATCH_SIZE {
+
+		statusCodes, err := e.db.UpsertAnimalBatch(c.Request.Context(), principal.UserID, output.Animals[start:min(start+db.MAX_BATCH_SIZE, len(output.Animals))])
+		if err != nil {
+			e.removeRolloverCopies(c.Request.Context(), principal.UserID, output.Feeds, output.Animals[:start], nil)
+			response := InterpretCosmosError(err)
+			c.JSON(response.Code, gin.H{
+				"message": response.Message,
+			})
+			return
+		}
+
+		if statusCode := batchFailure(statusCodes); statusCode != 0 {
+			e.removeRolloverCopies(c.Request.Context(), principal.UserID, output.Feeds, output.Animals[:start], nil)
+			c.JSON(statusCode, gin.H{
+				"message": ternary(HTTPResponseCodeMap[statusCode], "unexpected error"),
+			})
+			return
+		}
+
+	}
+
+	for start := 0; start < len(output.Supplies); start += db.MAX_BATCH_SIZE {
+
+		statusCodes, err := e.db.UpsertSupplyBatch(c.Request.Context(), principal.UserID, output.Supplies[start:min(start+db.MAX_BATCH_SIZE, len(output.Supplies))])
+		if err != nil {
+			e.removeRolloverCopies(c.Request.Context(), principal.UserID, output.Feeds, output.Animals, output.Supplies[:start])
+			response := InterpretCosmosError(err)
+			c.JSON(response.Code, gin.H{
+				"message": response.Message,
+			})
+			return
+		}
+
+		if statusCode := batchFailure(statusCodes); statusCode != 0 {
+			e.removeRolloverCopies(c.Request.Context(), principal.UserID, output.Feeds, output.Animals, output.Supplies[:start])
+			c.JSON(statusCode, gin.H{
+				"message": ternary(HTTPResponseCodeMap[statusCode], "unexpected error"),
+			})
+			return
+		}
+
+	}
+
+	output.Project, err = e.db.UpsertProject(c.Request.Context(), output.Project)
+	if err != nil {
+		e.removeRolloverCopies(c.Request.Context(), principal.UserID, output.Feeds, output.Animals, output.Supplies)
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	output.Animals = localizeAnimals(output.Animals, e.preferredMassUnit(c.Request.Context(), principal.UserID))
+
+	c.JSON(201, output)
+
+}
+
+// the given date, or a year after the original project's. an original date that doesn't parse is only an error when
+// no date is given in its place
+func rolloverDate(date string, previousDate string, location *time.Location) (utils.Date, error) {
+
+	if date != "" {
+		return utils.StringToDate(date, location)
+	}
+
+	previous, err := utils.StringToDate(previousDate, location)
+	if err != nil {
+		return utils.Date{}, err
+	}
+
+	return previous.AddYears(1), nil
+
+}
+
+// removes the copies written for a rollover that failed to save. the request may have timed out, so they are removed
+// with a context of their own
+func (e *env) removeRolloverCopies(ctx context.Context, userID string, feeds []db.Feed, animals []db.Animal, supplies []db.Supply) {
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), ROLLOVER_CLEANUP_TIMEOUT)
+	defer cancel()
+
+	for _, feed := range feeds {
+		_, err := e.db.RemoveFeed(ctx, userID, feed.ID)
+		if err != nil {
+			e.logger.Errorf("Failed to remove feed %s of a rollover that failed to save: %v", feed.ID, err)
+		}
+	}
+
+	for _, animal := range animals {
+		_, err := e.db.RemoveAnimal(ctx, userID, animal.ID)
+		if err != nil {
+			e.logger.Errorf("Failed to remove animal %s of a rollover that failed to save: %v", animal.ID, err)
+		}
+	}
+
+	for _, supply := range supplies {
+		_, err := e.db.RemoveSupply(ctx, userID, supply.ID)
+		if err != nil {
+			e.logger.Errorf("Failed to remove supply %s of a rollover that failed to save: %v", supply.ID, err)
+		}
+	}
+
+}
+
+// GetProjectLineage godoc
+// @Summary Get a project's lineage
+// @Description Gets every project in the chain of rollovers that the given project belongs to, oldest first
+// @Tags Project
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param projectID path string true "Project ID"
+// @Success 200 {object} api.GetProjectLineageOutput
+// @Failure 401
+// @Failure 404
+// @Router /project/{projectID}/lineage [get]
+func (e *env) getProjectLineage(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	projectID := c.Param("projectID")
+
+	project, err := e.db.GetProjectByID(c.Request.Context(), principal.UserID, projectID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	var output GetProjectLineageOutput
+
+	output.Projects, err = e.projectLineage(c.Request.Context(), principal.UserID, project)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(200, output)
+
+}
+
+// follows previous_project_id back to the first project and successors forward to the latest. a deleted project ends
+// the chain on that side
+func (e *env) projectLineage(ctx context.Context, userID string, project db.Project) ([]db.Project, error) {
+
+	seen := map[string]bool{project.ID: true}
+	earlier := []db.Project{}
+
+	previousID := project.PreviousProjectID
+	for previousID != "" && !seen[previousID] {
+
+		previous, err := e.db.GetProjectByID(ctx, userID, previousID)
+		if err != nil {
+			if InterpretCosmosError(err).Code == 404 {
+				break
+			}
+			return []db.Project{}, err
+		}
+
+		seen[previous.ID] = true
+		earlier = append(earlier, previous)
+		previousID = previous.PreviousProjectID
+
+	}
+
+	lineage := []db.Project{}
+	for i := len(earlier) - 1; i >= 0; i-- {
+		lineage = append(lineage, earlier[i])
+	}
+	lineage = append(lineage, project)
+
+	current := project
+	for {
+
+		successors, err := e.db.GetProjectsByPreviousProject(ctx, userID, current.ID)
+		if err != nil {
+			return []db.Project{}, err
+		}
+
+		if len(successors) == 0 || seen[successors[0].ID] {
+			break
+		}
+
+		current = successors[0]
+		seen[current.ID] = true
+		lineage = append(lineage, current)
+
+	}
+
+	return lineage, nil
+
+}
+
+// the first status code in a batch response that isn't a success or a failed dependency, or 0 if the batch was written
+func batchFailure(statusCodes []int) int {
+	for _, statusCode := range statusCodes {
+		if statusCode >= 300 && statusCode != 424 {
+			return statusCode
+		}
+	}
+	return 0
+}
+
+// GetProjectKinds godoc
+// @Summary Get project kinds
+// @Description Lists every kind of project and the record pages that make up its record book
+// @Tags Project
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} api.GetProjectKindsOutput
+// @Failure 401
+// @Router /project-kinds [get]
+func (e *env) getProjectKinds(c *gin.Context) {
+
+	_, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	output := GetProjectKindsOutput{
+		ProjectKinds: db.ProjectKinds,
+	}
+
+	c.JSON(200, output)
+
+}
+
+// whether changing the project to the given kind would leave records on it that the kind has no page for, e.g. animals
+// on a project changed to still life
+func (e *env) strandsRecords(ctx context.Context, userID string, project db.Project, kind string) (bool, error) {
+
+	changed := db.Project{Kind: kind}
+
+	if !changed.HasRecordPage(db.RECORD_PAGE_ANIMALS) {
+		animals, err := e.db.GetProjectDependentAnimals(ctx, userID, project.ID)
+		if err != nil || len(animals) > 0 {
+			return true, err
+		}
+	}
+
+	if !changed.HasRecordPage(db.RECORD_PAGE_FEEDS) {
+		feeds, err := e.db.GetProjectDependentFeeds(ctx, userID, project.ID)
+		if err != nil || len(feeds) > 0 {
+			return true, err
+		}
+	}
+
+	if !changed.HasRecordPage(db.RECORD_PAGE_FEED_PURCHASES) {
+		feedPurchases, err := e.db.GetAllFeedPurchasesByProject(ctx, userID, project.ID)
+		if err != nil || len(feedPurchases) > 0 {
+			return true, err
+		}
+	}
+
+	return false, nil
+
+}
+
+// writes an error response and returns false if the project doesn't exist or its kind doesn't keep the given record page,
+// e.g. animals on a still life project
+func (e *env) checkProjectRecordPage(c *gin.Context, userID string, projectID string, recordPage string) bool {
+
+	project, err := e.db.GetProjectByID(c.Request.Context(), userID, projectID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return false
+	}
+
+	if !project.HasRecordPage(recordPage) {
+		c.JSON(400, gin.H{
+			"message": ErrRecordPageNotAllowed,
+		})
+		return false
+	}
+
+	return true
+
+}
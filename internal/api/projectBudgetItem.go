@@ -0,0 +1,537 @@
+package api
+
+import (
+	"4h-recordbook-backend/internal/auth"
+	"4h-recordbook-backend/internal/utils"
+	"4h-recordbook-backend/pkg/db"
+	"slices"
+	"strconv"
+
+	"github.com/beevik/guid"
+	"github.com/gin-gonic/gin"
+)
+
+type GetProjectBudgetItemsOutput struct {
+	ProjectBudgetItems []db.ProjectBudgetItem `json:"project_budget_items"`
+	Next               string                 `json:"next"`
+}
+
+type GetProjectBudgetItemOutput struct {
+	ProjectBudgetItem db.ProjectBudgetItem `json:"project_budget_item"`
+}
+
+type UpsertProjectBudgetItemInput struct {
+	Category        string       `json:"category" validate:"required,oneof=animal_purchase feed expenses income"`
+	Description     string       `json:"description"`
+	PlannedAmount   *utils.Money `json:"planned_amount" validate:"required"`
+	PlannedQuantity *float64     `json:"planned_quantity" validate:"required"`
+	QuantityUnit    string       `json:"quantity_unit"`
+	ProjectID       string       `json:"project_id" validate:"required"`
+}
+
+type UpsertProjectBudgetItemOutput GetProjectBudgetItemOutput
+
+// variances are actual minus planned
+type BudgetCategoryReport struct {
+	Category         string      `json:"category"`
+	PlannedAmount    utils.Money `json:"planned_amount"`
+	ActualAmount     utils.Money `json:"actual_amount"`
+	AmountVariance   utils.Money `json:"amount_variance"`
+	PlannedQuantity  float64     `json:"planned_quantity"`
+	ActualQuantity   float64     `json:"actual_quantity"`
+	QuantityVariance float64     `json:"quantity_variance"`
+	QuantityUnit     string      `json:"quantity_unit"`
+}
+
+type GetProjectBudgetReportOutput struct {
+	Categories      []BudgetCategoryReport `json:"categories"`
+	PlannedExpenses utils.Money            `json:"planned_expenses"`
+	ActualExpenses  utils.Money            `json:"actual_expenses"`
+	PlannedIncome   utils.Money            `json:"planned_income"`
+	ActualIncome    utils.Money            `json:"actual_income"`
+	PlannedNet      utils.Money            `json:"planned_net"`
+	ActualNet       utils.Money            `json:"actual_net"`
+	NetVariance     utils.Money            `json:"net_variance"`
+}
+
+// GetProjectBudgetItems godoc
+// @Summary Get budget items by project
+// @Description Gets all of a user's budget items given a project ID
+// @Tags Project Budget
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param projectID path string true "Project ID"
+// @Param page query int false "Page number, default 0"
+// @Param per_page query int false "Max number of items to return. Can be [1-200], default 100"
+// @Param sort_by_newest query bool false "Sort results by most recently added, default false"
+// @Success 200 {object} api.GetProjectBudgetItemsOutput
+// @Failure 400
+// @Failure 401
+// @Router /project/{projectID}/budget-item [get]
+func (e *env) getProjectBudgetItems(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	projectID := c.Param("projectID")
+
+	var output GetProjectBudgetItemsOutput
+
+	paginationOptions := db.PaginationOptions{
+		Page:         c.GetInt(CONTEXT_KEY_PAGE),
+		PerPage:      c.GetInt(CONTEXT_KEY_PER_PAGE),
+		SortByNewest: c.GetBool(CONTEXT_KEY_SORT_BY_NEWEST),
+	}
+
+	output.ProjectBudgetItems, err = e.db.GetProjectBudgetItemsByProject(c.Request.Context(), principal.UserID, projectID, paginationOptions)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	output.ProjectBudgetItems = localizeProjectBudgetItems(output.ProjectBudgetItems, e.preferredMassUnit(c.Request.Context(), principal.UserID))
+
+	if len(output.ProjectBudgetItems) == paginationOptions.PerPage {
+
+		queryParamsMap := make(map[string]string)
+		queryParamsMap[CONTEXT_KEY_PAGE] = strconv.Itoa(paginationOptions.Page + 1)
+		queryParamsMap[CONTEXT_KEY_PER_PAGE] = strconv.Itoa(paginationOptions.PerPage)
+		queryParamsMap[CONTEXT_KEY_SORT_BY_NEWEST] = strconv.FormatBool(paginationOptions.SortByNewest)
+
+		nextUrlInput := utils.NextUrlInput{
+			Context:     c,
+			QueryParams: queryParamsMap,
+		}
+
+		output.Next = utils.BuildNextUrl(nextUrlInput)
+	}
+
+	c.JSON(200, output)
+
+}
+
+// GetProjectBudgetItem godoc
+// @Summary Get a budget item
+// @Description Get a user's budget item by ID
+// @Tags Project Budget
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param projectBudgetItemID path string true "Budget Item ID"
+// @Success 200 {object} api.GetProjectBudgetItemOutput
+// @Failure 401
+// @Failure 404
+// @Router /budget-item/{projectBudgetItemID} [get]
+func (e *env) getProjectBudgetItem(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	projectBudgetItemID := c.Param("projectBudgetItemID")
+
+	var output GetProjectBudgetItemOutput
+
+	output.ProjectBudgetItem, err = e.db.GetProjectBudgetItemByID(c.Request.Context(), principal.UserID, projectBudgetItemID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	output.ProjectBudgetItem = localizeProjectBudgetItem(output.ProjectBudgetItem, e.preferredMassUnit(c.Request.Context(), principal.UserID))
+
+	c.JSON(200, output)
+
+}
+
+// AddProjectBudgetItem godoc
+// @Summary Adds a budget item
+// @Description Adds a budget item to a user's personal records. A feed item's planned quantity is a weight in quantity_unit (lb, oz, kg or g), defaulting to the user's preferred unit, and is returned in the preferred unit
+// @Tags Project Budget
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param UpsertProjectBudgetItemInput body api.UpsertProjectBudgetItemInput true "Budget item information"
+// @Success 201 {object} api.UpsertProjectBudgetItemOutput
+// @Failure 400
+// @Failure 401
+// @Failure 404
+// @Router /budget-item [post]
+func (e *env) addProjectBudgetItem(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	var input UpsertProjectBudgetItemInput
+	err = c.BindJSON(&input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrBadRequest,
+		})
+		return
+	}
+
+	err = e.validator.Struct(input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrMissingFields,
+		})
+		return
+	}
+
+	if !e.checkProjectRecordPage(c, principal.UserID, input.ProjectID, db.RECORD_PAGE_BUDGET) {
+		return
+	}
+
+	unit := e.preferredMassUnit(c.Request.Context(), principal.UserID)
+
+	plannedQuantity, quantityUnit, err := budgetQuantity(input, unit)
+	if err != nil {
+		respondUnitError(c, err)
+		return
+	}
+
+	g := guid.New()
+	timestamp := utils.TimeNow()
+
+	projectBudgetItem := db.ProjectBudgetItem{
+		ID:              g.String(),
+		Category:        input.Category,
+		Description:     input.Description,
+		PlannedAmount:   *input.PlannedAmount,
+		PlannedQuantity: plannedQuantity,
+		QuantityUnit:    quantityUnit,
+		ProjectID:       input.ProjectID,
+		UserID:          principal.UserID,
+		GenericDatabaseInfo: db.GenericDatabaseInfo{
+			Created: timestamp.String(),
+			Updated: timestamp.String(),
+		},
+	}
+
+	var output UpsertProjectBudgetItemOutput
+
+	output.ProjectBudgetItem, err = e.db.UpsertProjectBudgetItem(c.Request.Context(), projectBudgetItem)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	output.ProjectBudgetItem = localizeProjectBudgetItem(output.ProjectBudgetItem, unit)
+
+	c.JSON(201, output)
+
+}
+
+// UpdateProjectBudgetItem godoc
+// @Summary Update a budget item
+// @Description Updates a user's budget item information. A feed item's planned quantity is a weight in quantity_unit (lb, oz, kg or g), defaulting to the user's preferred unit, and is returned in the preferred unit
+// @Tags Project Budget
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param projectBudgetItemID path string true "Budget Item ID"
+// @Param UpsertProjectBudgetItemInput body api.UpsertProjectBudgetItemInput true "Budget item information"
+// @Success 200 {object} api.UpsertProjectBudgetItemOutput
+// @Failure 400
+// @Failure 401
+// @Failure 404
+// @Router /budget-item/{projectBudgetItemID} [put]
+func (e *env) updateProjectBudgetItem(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	var input UpsertProjectBudgetItemInput
+	err = c.BindJSON(&input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrBadRequest,
+		})
+		return
+	}
+
+	err = e.validator.Struct(input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrMissingFields,
+		})
+		return
+	}
+
+	projectBudgetItemID := c.Param("projectBudgetItemID")
+
+	projectBudgetItem, err := e.db.GetProjectBudgetItemByID(c.Request.Context(), principal.UserID, projectBudgetItemID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	unit := e.preferredMassUnit(c.Request.Context(), principal.UserID)
+
+	plannedQuantity, quantityUnit, err := budgetQuantity(input, unit)
+	if err != nil {
+		respondUnitError(c, err)
+		return
+	}
+
+	timestamp := utils.TimeNow()
+
+	updatedProjectBudgetItem := db.ProjectBudgetItem{
+		ID:              projectBudgetItem.ID,
+		Category:        input.Category,
+		Description:     input.Description,
+		PlannedAmount:   *input.PlannedAmount,
+		PlannedQuantity: plannedQuantity,
+		QuantityUnit:    quantityUnit,
+		ProjectID:       projectBudgetItem.ProjectID,
+		UserID:          principal.UserID,
+		GenericDatabaseInfo: db.GenericDatabaseInfo{
+			Created: projectBudgetItem.Created,
+			Updated: timestamp.String(),
+		},
+	}
+
+	var output UpsertProjectBudgetItemOutput
+
+	output.ProjectBudgetItem, err = e.db.UpsertProjectBudgetItem(c.Request.Context(), updatedProjectBudgetItem)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	output.ProjectBudgetItem = localizeProjectBudgetItem(output.ProjectBudgetItem, unit)
+
+	c.JSON(200, output)
+
+}
+
+// DeleteProjectBudgetItem godoc
+// @Summary Removes a budget item
+// @Description Deletes a user's budget item given the budget item ID
+// @Tags Project Budget
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param projectBudgetItemID path string true "Budget Item ID"
+// @Success 204
+// @Failure 401
+// @Failure 404
+// @Router /budget-item/{projectBudgetItemID} [delete]
+func (e *env) deleteProjectBudgetItem(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	projectBudgetItemID := c.Param("projectBudgetItemID")
+
+	response, err := e.db.RemoveProjectBudgetItem(c.Request.Context(), principal.UserID, projectBudgetItemID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(204, response)
+
+}
+
+// GetProjectBudgetReport godoc
+// @Summary Get a project's budget versus actuals
+// @Description Compares a project's planned budget to the amounts actually recorded in each category. Animal purchase and income actuals come from animal costs and sale prices, feed from feed purchases and expenses filed under feed, and expenses from every other expense. Expenses count toward amounts only, since their quantities are in unrelated units, so the expenses category has no actual quantity. Feed quantities are reported in the user's preferred unit. Variances are actual minus planned
+// @Tags Project Budget
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param projectID path string true "Project ID"
+// @Success 200 {object} api.GetProjectBudgetReportOutput
+// @Failure 401
+// @Router /project/{projectID}/budget-report [get]
+func (e *env) getProjectBudgetReport(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	projectID := c.Param("projectID")
+
+	budgetItems, err := e.db.GetAllProjectBudgetItemsByProject(c.Request.Context(), principal.UserID, projectID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	expenses, err := e.db.GetAllExpensesByProject(c.Request.Context(), principal.UserID, projectID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	feedPurchases, err := e.db.GetAllFeedPurchasesByProject(c.Request.Context(), principal.UserID, projectID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	animals, err := e.db.GetAllAnimalsByProject(c.Request.Context(), principal.UserID, projectID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(200, budgetReport(budgetItems, expenses, feedPurchases, animals, e.preferredMassUnit(c.Request.Context(), principal.UserID)))
+
+}
+
+// the planned quantity to store. feed is planned by weight and kept in pounds like feed purchases, and everything else
+// is a count
+func budgetQuantity(input UpsertProjectBudgetItemInput, unit string) (float64, string, error) {
+
+	if input.Category != db.BUDGET_CATEGORY_FEED {
+		return *input.PlannedQuantity, "", nil
+	}
+
+	pounds, err := massToPounds(*input.PlannedQuantity, input.QuantityUnit, unit)
+	if err != nil {
+		return 0, "", err
+	}
+
+	return pounds, utils.CANONICAL_MASS_UNIT, nil
+
+}
+
+// feed quantities are compared in pounds and reported in the given unit
+func budgetReport(budgetItems []db.ProjectBudgetItem, expenses []db.Expense, feedPurchases []db.FeedPurchase, animals []db.Animal, unit string) GetProjectBudgetReportOutput {
+
+	categories := make(map[string]*BudgetCategoryReport)
+	for _, category := range db.BudgetCategories {
+		categories[category] = &BudgetCategoryReport{Category: category}
+	}
+
+	for _, item := range budgetItems {
+		if report, ok := categories[item.Category]; ok {
+			report.PlannedAmount += item.PlannedAmount
+			report.PlannedQuantity += item.PlannedQuantity
+		}
+	}
+
+	// expenses filed under a budget category count toward it, as they do on the expense summary. their quantities are
+	// in whatever units each expense was bought in, so they aren't added up
+	for _, expense := range expenses {
+		category := db.BUDGET_CATEGORY_EXPENSES
+		if expense.Category != db.BUDGET_CATEGORY_INCOME && slices.Contains(db.BudgetCategories, expense.Category) {
+			category = expense.Category
+		}
+		categories[category].ActualAmount += expense.Cost
+	}
+
+	for _, feedPurchase := range feedPurchases {
+		categories[db.BUDGET_CATEGORY_FEED].ActualAmount += feedPurchase.TotalCost
+		categories[db.BUDGET_CATEGORY_FEED].ActualQuantity += feedPurchase.AmountPurchased
+	}
+
+	for _, animal := range animals {
+		categories[db.BUDGET_CATEGORY_ANIMAL_PURCHASE].ActualAmount += animal.AnimalCost
+		categories[db.BUDGET_CATEGORY_ANIMAL_PURCHASE].ActualQuantity++
+		if animal.SalePrice > 0 {
+			categories[db.BUDGET_CATEGORY_INCOME].ActualAmount += animal.SalePrice
+			categories[db.BUDGET_CATEGORY_INCOME].ActualQuantity++
+		}
+	}
+
+	output := GetProjectBudgetReportOutput{
+		Categories: []BudgetCategoryReport{},
+	}
+
+	for _, category := range db.BudgetCategories {
+
+		report := categories[category]
+		report.AmountVariance = report.ActualAmount - report.PlannedAmount
+		if category != db.BUDGET_CATEGORY_EXPENSES {
+			report.QuantityVariance = report.ActualQuantity - report.PlannedQuantity
+		}
+
+		if category == db.BUDGET_CATEGORY_FEED {
+			report.PlannedQuantity = utils.FromCanonicalMass(report.PlannedQuantity, unit)
+			report.ActualQuantity = utils.FromCanonicalMass(report.ActualQuantity, unit)
+			report.QuantityVariance = utils.FromCanonicalMass(report.QuantityVariance, unit)
+			report.QuantityUnit = unit
+		}
+
+		if category == db.BUDGET_CATEGORY_INCOME {
+			output.PlannedIncome += report.PlannedAmount
+			output.ActualIncome += report.ActualAmount
+		} else {
+			output.PlannedExpenses += report.PlannedAmount
+			output.ActualExpenses += report.ActualAmount
+		}
+
+		output.Categories = append(output.Categories, *report)
+
+	}
+
+	output.PlannedNet = output.PlannedIncome - output.PlannedExpenses
+	output.ActualNet = output.ActualIncome - output.ActualExpenses
+	output.NetVariance = output.ActualNet - output.PlannedNet
+
+	return output
+
+}
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"4h-recordbook-backend/internal/utils"
+	"4h-recordbook-backend/pkg/db"
+	"math"
+	"testing"
+)
+
+func TestBudgetReportFilesExpensesByCategory(t *testing.T) {
+
+	budgetItems := []db.ProjectBudgetItem{
+		{Category: db.BUDGET_CATEGORY_FEED, PlannedAmount: 5000, PlannedQuantity: 100},
+		{Category: db.BUDGET_CATEGORY_EXPENSES, PlannedAmount: 3000, PlannedQuantity: 4},
+	}
+
+	expenses := []db.Expense{
+		{Category: db.BUDGET_CATEGORY_FEED, Cost: 1200, Quantity: 3},
+		{Category: "vet_health", Cost: 2000, Quantity: 2},
+		{Cost: 500, Quantity: 1},
+		{Category: db.BUDGET_CATEGORY_INCOME, Cost: 100, Quantity: 1},
+	}
+
+	feedPurchases := []db.FeedPurchase{
+		{TotalCost: 3000, AmountPurchased: 50},
+	}
+
+	report := budgetReport(budgetItems, expenses, feedPurchases, nil, utils.CANONICAL_MASS_UNIT)
+
+	byCategory := map[string]BudgetCategoryReport{}
+	for _, category := range report.Categories {
+		byCategory[category.Category] = category
+	}
+
+	tests := []struct {
+		category         string
+		actualAmount     int64
+		actualQuantity   float64
+		quantityVariance float64
+	}{
+		{db.BUDGET_CATEGORY_FEED, 4200, 50, -50},
+		{db.BUDGET_CATEGORY_EXPENSES, 2600, 0, 0},
+		{db.BUDGET_CATEGORY_INCOME, 0, 0, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.category, func(t *testing.T) {
+			got := byCategory[test.category]
+			if int64(got.ActualAmount) != test.actualAmount || got.ActualQuantity != test.actualQuantity || got.QuantityVariance != test.quantityVariance {
+				t.Errorf("got amount %d, quantity %v, quantity variance %v, want %d, %v, %v", got.ActualAmount, got.ActualQuantity, got.QuantityVariance, test.actualAmount, test.actualQuantity, test.quantityVariance)
+			}
+		})
+	}
+
+	if int64(report.ActualExpenses) != 6800 {
+		t.Errorf("actual expenses = %d, want 6800", report.ActualExpenses)
+	}
+
+}
+
+func TestBudgetReportLocalizesFeedQuantities(t *testing.T) {
+
+	// 110.231 lb planned against 220.462 lb bought
+	budgetItems := []db.ProjectBudgetItem{
+		{Category: db.BUDGET_CATEGORY_FEED, PlannedQuantity: 110.231131092, QuantityUnit: utils.CANONICAL_MASS_UNIT},
+	}
+
+	feedPurchases := []db.FeedPurchase{
+		{AmountPurchased: 220.46226218},
+	}
+
+	tests := []struct {
+		unit            string
+		plannedQuantity float64
+		actualQuantity  float64
+	}{
+		{utils.UNIT_POUND, 110.231131092, 220.46226218},
+		{utils.UNIT_KILOGRAM, 50, 100},
+	}
+
+	for _, test := range tests {
+		t.Run(test.unit, func(t *testing.T) {
+			report := budgetReport(budgetItems, nil, feedPurchases, nil, test.unit)
+			for _, got := range report.Categories {
+				if got.Category != db.BUDGET_CATEGORY_FEED {
+					continue
+				}
+				if got.QuantityUnit != test.unit || math.Abs(got.PlannedQuantity-test.plannedQuantity) > 0.01 || math.Abs(got.ActualQuantity-test.actualQuantity) > 0.01 {
+					t.Errorf("got %v planned, %v actual %s, want %v, %v %s", got.PlannedQuantity, got.ActualQuantity, got.QuantityUnit, test.plannedQuantity, test.actualQuantity, test.unit)
+				}
+			}
+		})
+	}
+
+}
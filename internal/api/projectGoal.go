@@ -0,0 +1,407 @@
+package api
+
+import (
+	"4h-recordbook-backend/internal/auth"
+	"4h-recordbook-backend/internal/utils"
+	"4h-recordbook-backend/pkg/db"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/beevik/guid"
+	"github.com/gin-gonic/gin"
+)
+
+type GetProjectGoalsOutput struct {
+	ProjectGoals []db.ProjectGoal `json:"project_goals"`
+	Next         string           `json:"next"`
+}
+
+type GetProjectGoalOutput struct {
+	ProjectGoal db.ProjectGoal `json:"project_goal"`
+}
+
+type UpsertProjectGoalInput struct {
+	Goal           string `json:"goal" validate:"required"`
+	Target         string `json:"target" validate:"required"`
+	Status         string `json:"status" validate:"required,oneof=not_started in_progress completed abandoned"`
+	TargetDate     string `json:"target_date"`
+	CompletionDate string `json:"completion_date"`
+	ProjectID      string `json:"project_id" validate:"required"`
+}
+
+type UpsertProjectGoalOutput GetProjectGoalOutput
+
+// GetProjectGoals godoc
+// @Summary Get project goals by project
+// @Description Gets all of a user's project goals given a project ID
+// @Tags Project Goal
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param projectID path string true "Project ID"
+// @Param page query int false "Page number, default 0"
+// @Param per_page query int false "Max number of items to return. Can be [1-200], default 100"
+// @Param sort_by_newest query bool false "Sort results by most recently added, default false"
+// @Success 200 {object} api.GetProjectGoalsOutput
+// @Failure 400
+// @Failure 401
+// @Router /project/{projectID}/goal [get]
+func (e *env) getProjectGoals(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	projectID := c.Param("projectID")
+
+	var output GetProjectGoalsOutput
+
+	paginationOptions := db.PaginationOptions{
+		Page:         c.GetInt(CONTEXT_KEY_PAGE),
+		PerPage:      c.GetInt(CONTEXT_KEY_PER_PAGE),
+		SortByNewest: c.GetBool(CONTEXT_KEY_SORT_BY_NEWEST),
+	}
+
+	output.ProjectGoals, err = e.db.GetProjectGoalsByProject(c.Request.Context(), principal.UserID, projectID, paginationOptions)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	if len(output.ProjectGoals) == paginationOptions.PerPage {
+
+		queryParamsMap := make(map[string]string)
+		queryParamsMap[CONTEXT_KEY_PAGE] = strconv.Itoa(paginationOptions.Page + 1)
+		queryParamsMap[CONTEXT_KEY_PER_PAGE] = strconv.Itoa(paginationOptions.PerPage)
+		queryParamsMap[CONTEXT_KEY_SORT_BY_NEWEST] = strconv.FormatBool(paginationOptions.SortByNewest)
+
+		nextUrlInput := utils.NextUrlInput{
+			Context:     c,
+			QueryParams: queryParamsMap,
+		}
+
+		output.Next = utils.BuildNextUrl(nextUrlInput)
+	}
+
+	c.JSON(200, output)
+
+}
+
+// GetProjectGoal godoc
+// @Summary Get a project goal
+// @Description Get a user's project goal by ID
+// @Tags Project Goal
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param projectGoalID path string true "Project Goal ID"
+// @Success 200 {object} api.GetProjectGoalOutput
+// @Failure 401
+// @Failure 404
+// @Router /goal/{projectGoalID} [get]
+func (e *env) getProjectGoal(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	projectGoalID := c.Param("projectGoalID")
+
+	var output GetProjectGoalOutput
+
+	output.ProjectGoal, err = e.db.GetProjectGoalByID(c.Request.Context(), principal.UserID, projectGoalID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(200, output)
+
+}
+
+// AddProjectGoal godoc
+// @Summary Adds a project goal
+// @Description Adds a project goal to a user's personal records
+// @Tags Project Goal
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param UpsertProjectGoalInput body api.UpsertProjectGoalInput true "Project Goal information"
+// @Success 201 {object} api.UpsertProjectGoalOutput
+// @Failure 400
+// @Failure 401
+// @Failure 404
+// @Router /goal [post]
+func (e *env) addProjectGoal(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	var input UpsertProjectGoalInput
+	err = c.BindJSON(&input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrBadRequest,
+		})
+		return
+	}
+
+	err = e.validator.Struct(input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrMissingFields,
+		})
+		return
+	}
+
+	location := e.userLocation(c.Request.Context(), principal.UserID)
+
+	targetDate, completionDate, ok := projectGoalDates(input, "", location)
+	if !ok {
+		c.JSON(400, gin.H{
+			"message": ErrBadDate,
+		})
+		return
+	}
+
+	if !e.checkProjectRecordPage(c, principal.UserID, input.ProjectID, db.RECORD_PAGE_GOALS) {
+		return
+	}
+
+	g := guid.New()
+	timestamp := utils.TimeNow()
+
+	projectGoal := db.ProjectGoal{
+		ID:             g.String(),
+		Goal:           input.Goal,
+		Target:         input.Target,
+		Status:         input.Status,
+		TargetDate:     targetDate,
+		CompletionDate: completionDate,
+		ProjectID:      input.ProjectID,
+		UserID:         principal.UserID,
+		GenericDatabaseInfo: db.GenericDatabaseInfo{
+			Created: timestamp.String(),
+			Updated: timestamp.String(),
+		},
+	}
+
+	var output UpsertProjectGoalOutput
+
+	output.ProjectGoal, err = e.db.UpsertProjectGoal(c.Request.Context(), projectGoal)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(201, output)
+
+}
+
+// UpdateProjectGoal godoc
+// @Summary Update a project goal
+// @Description Updates a user's project goal information
+// @Tags Project Goal
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param projectGoalID path string true "Project Goal ID"
+// @Param UpsertProjectGoalInput body api.UpsertProjectGoalInput true "Project Goal information"
+// @Success 200 {object} api.UpsertProjectGoalOutput
+// @Failure 400
+// @Failure 401
+// @Failure 404
+// @Router /goal/{projectGoalID} [put]
+func (e *env) updateProjectGoal(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	var input UpsertProjectGoalInput
+	err = c.BindJSON(&input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrBadRequest,
+		})
+		return
+	}
+
+	err = e.validator.Struct(input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrMissingFields,
+		})
+		return
+	}
+
+	projectGoalID := c.Param("projectGoalID")
+
+	projectGoal, err := e.db.GetProjectGoalByID(c.Request.Context(), principal.UserID, projectGoalID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	location := e.userLocation(c.Request.Context(), principal.UserID)
+
+	targetDate, completionDate, ok := projectGoalDates(input, projectGoal.CompletionDate, location)
+	if !ok {
+		c.JSON(400, gin.H{
+			"message": ErrBadDate,
+		})
+		return
+	}
+
+	timestamp := utils.TimeNow()
+
+	updatedProjectGoal := db.ProjectGoal{
+		ID:             projectGoal.ID,
+		Goal:           input.Goal,
+		Target:         input.Target,
+		Status:         input.Status,
+		TargetDate:     targetDate,
+		CompletionDate: completionDate,
+		ProjectID:      projectGoal.ProjectID,
+		UserID:         principal.UserID,
+		GenericDatabaseInfo: db.GenericDatabaseInfo{
+			Created: projectGoal.Created,
+			Updated: timestamp.String(),
+		},
+	}
+
+	var output UpsertProjectGoalOutput
+
+	output.ProjectGoal, err = e.db.UpsertProjectGoal(c.Request.Context(), updatedProjectGoal)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(200, output)
+
+}
+
+// DeleteProjectGoal godoc
+// @Summary Removes a project goal
+// @Description Deletes a user's project goal given the project goal ID
+// @Tags Project Goal
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param projectGoalID path string true "Project Goal ID"
+// @Success 204
+// @Failure 401
+// @Failure 404
+// @Router /goal/{projectGoalID} [delete]
+func (e *env) deleteProjectGoal(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	projectGoalID := c.Param("projectGoalID")
+
+	response, err := e.db.RemoveProjectGoal(c.Request.Context(), principal.UserID, projectGoalID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(204, response)
+
+}
+
+// a completed goal without a completion date keeps its previous completion date or is treated as completed now, and goals
+// that aren't completed have no completion date
+func projectGoalDates(input UpsertProjectGoalInput, previousCompletionDate string, location *time.Location) (string, string, bool) {
+
+	targetDate := ""
+	if input.TargetDate != "" {
+		date, err := utils.StringToDate(input.TargetDate, location)
+		if err != nil {
+			return "", "", false
+		}
+		targetDate = date.String()
+	}
+
+	if input.Status != db.GOAL_STATUS_COMPLETED {
+		return targetDate, "", true
+	}
+
+	if input.CompletionDate == "" {
+		return targetDate, ternary(previousCompletionDate, utils.Today(location).String()), true
+	}
+
+	completionDate, err := utils.StringToDate(input.CompletionDate, location)
+	if err != nil {
+		return "", "", false
+	}
+
+	return targetDate, completionDate.String(), true
+
+}
+
+// percentage of a project's goals that are completed, ignoring abandoned goals
+func goalCompletion(goals []db.ProjectGoal) float64 {
+
+	total := 0
+	completed := 0
+
+	for _, goal := range goals {
+		switch goal.Status {
+		case db.GOAL_STATUS_ABANDONED:
+			continue
+		case db.GOAL_STATUS_COMPLETED:
+			completed++
+		}
+		total++
+	}
+
+	if total == 0 {
+		return 0
+	}
+
+	return math.Round(float64(completed)/float64(total)*1000) / 10
+
+}
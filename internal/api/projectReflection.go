@@ -0,0 +1,185 @@
+package api
+
+import (
+	"4h-recordbook-backend/internal/auth"
+	"4h-recordbook-backend/internal/utils"
+	"4h-recordbook-backend/pkg/db"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GetProjectReflectionOutput struct {
+	ProjectReflection db.ProjectReflection `json:"project_reflection"`
+}
+
+type UpsertProjectReflectionInput struct {
+	WhatILearned  string `json:"what_i_learned" validate:"required"`
+	Challenges    string `json:"challenges"`
+	NextYearPlans string `json:"next_year_plans"`
+}
+
+type UpsertProjectReflectionOutput GetProjectReflectionOutput
+
+// GetProjectReflection godoc
+// @Summary Get a project reflection
+// @Description Get the year-end reflection for one of a user's projects
+// @Tags Project Reflection
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param projectID path string true "Project ID"
+// @Success 200 {object} api.GetProjectReflectionOutput
+// @Failure 401
+// @Failure 404
+// @Router /project/{projectID}/reflection [get]
+func (e *env) getProjectReflection(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	projectID := c.Param("projectID")
+
+	var output GetProjectReflectionOutput
+
+	output.ProjectReflection, err = e.db.GetProjectReflection(c.Request.Context(), principal.UserID, projectID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(200, output)
+
+}
+
+// UpsertProjectReflection godoc
+// @Summary Add or update a project reflection
+// @Description Saves the year-end reflection for one of a user's projects, creating it if it doesn't exist yet
+// @Tags Project Reflection
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param projectID path string true "Project ID"
+// @Param UpsertProjectReflectionInput body api.UpsertProjectReflectionInput true "Project reflection information"
+// @Success 200 {object} api.UpsertProjectReflectionOutput
+// @Failure 400
+// @Failure 401
+// @Failure 404
+// @Router /project/{projectID}/reflection [put]
+func (e *env) upsertProjectReflection(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	var input UpsertProjectReflectionInput
+	err = c.BindJSON(&input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrBadRequest,
+		})
+		return
+	}
+
+	err = e.validator.Struct(input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrMissingFields,
+		})
+		return
+	}
+
+	projectID := c.Param("projectID")
+
+	if !e.checkProjectRecordPage(c, principal.UserID, projectID, db.RECORD_PAGE_REFLECTION) {
+		return
+	}
+
+	timestamp := utils.TimeNow()
+	created := timestamp.String()
+
+	projectReflection, err := e.db.GetProjectReflection(c.Request.Context(), principal.UserID, projectID)
+	if err == nil {
+		created = projectReflection.Created
+	} else if response := InterpretCosmosError(err); response.Code != 404 {
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	updatedProjectReflection := db.ProjectReflection{
+		ID:            projectID,
+		WhatILearned:  input.WhatILearned,
+		Challenges:    input.Challenges,
+		NextYearPlans: input.NextYearPlans,
+		ProjectID:     projectID,
+		UserID:        principal.UserID,
+		GenericDatabaseInfo: db.GenericDatabaseInfo{
+			Created: created,
+			Updated: timestamp.String(),
+		},
+	}
+
+	var output UpsertProjectReflectionOutput
+
+	output.ProjectReflection, err = e.db.UpsertProjectReflection(c.Request.Context(), updatedProjectReflection)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(200, output)
+
+}
+
+// DeleteProjectReflection godoc
+// @Summary Removes a project reflection
+// @Description Deletes the year-end reflection for one of a user's projects
+// @Tags Project Reflection
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param projectID path string true "Project ID"
+// @Success 204
+// @Failure 401
+// @Failure 404
+// @Router /project/{projectID}/reflection [delete]
+func (e *env) deleteProjectReflection(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	projectID := c.Param("projectID")
+
+	response, err := e.db.RemoveProjectReflection(c.Request.Context(), principal.UserID, projectID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(204, response)
+
+}
@@ -0,0 +1,204 @@
+package api
+
+import (
+	"4h-recordbook-backend/internal/auth"
+	"4h-recordbook-backend/internal/utils"
+	"4h-recordbook-backend/pkg/db"
+	"context"
+	"errors"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// a project whose records are given by its ID: "animals", "feeds", "feed-purchases" or "empty"
+type projectRecordsDb struct {
+	db.Db
+}
+
+func (projectRecordsDb) GetProjectDependentAnimals(ctx context.Context, userID string, projectID string) ([]db.Identifiable, error) {
+	if projectID == "animals" {
+		return []db.Identifiable{db.Animal{ID: "animal"}}, nil
+	}
+	return []db.Identifiable{}, nil
+}
+
+func (projectRecordsDb) GetProjectDependentFeeds(ctx context.Context, userID string, projectID string) ([]db.Identifiable, error) {
+	if projectID == "feeds" {
+		return []db.Identifiable{db.Feed{ID: "feed"}}, nil
+	}
+	return []db.Identifiable{}, nil
+}
+
+func (projectRecordsDb) GetAllFeedPurchasesByProject(ctx context.Context, userID string, projectID string) ([]db.FeedPurchase, error) {
+	if projectID == "feed-purchases" {
+		return []db.FeedPurchase{{ID: "feed-purchase"}}, nil
+	}
+	return []db.FeedPurchase{}, nil
+}
+
+func TestStrandsRecords(t *testing.T) {
+
+	e := &env{db: projectRecordsDb{}}
+
+	tests := []struct {
+		name      string
+		projectID string
+		kind      string
+		want      bool
+	}{
+		{"animals to still life", "animals", db.PROJECT_KIND_STILL_LIFE, true},
+		{"feeds to science and engineering", "feeds", db.PROJECT_KIND_SCIENCE_ENGINEERING, true},
+		{"feed purchases to still life", "feed-purchases", db.PROJECT_KIND_STILL_LIFE, true},
+		{"animals to livestock", "animals", db.PROJECT_KIND_LIVESTOCK, false},
+		{"no livestock records to still life", "empty", db.PROJECT_KIND_STILL_LIFE, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			project := db.Project{ID: test.projectID, Kind: db.PROJECT_KIND_LIVESTOCK}
+			got, err := e.strandsRecords(context.Background(), "member", project, test.kind)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != test.want {
+				t.Errorf("strandsRecords(%s, %s) = %v, want %v", test.projectID, test.kind, got, test.want)
+			}
+		})
+	}
+
+}
+
+// a livestock project with a feed, a retained animal and a supply, whose supply copies fail to save
+type failedRolloverDb struct {
+	db.Db
+	removed []string
+	saved   bool
+}
+
+func (*failedRolloverDb) GetProjectByID(ctx context.Context, userID string, projectID string) (db.Project, error) {
+	return db.Project{ID: projectID, Year: "2024-2025", StartDate: "2024-10-01", EndDate: "2025-09-30", UserID: userID}, nil
+}
+
+func (*failedRolloverDb) GetProjectsByPreviousProject(ctx context.Context, userID string, projectID string) ([]db.Project, error) {
+	return []db.Project{}, nil
+}
+
+func (*failedRolloverDb) GetUser(ctx context.Context, userID string) (db.User, error) {
+	return db.User{ID: userID}, nil
+}
+
+func (*failedRolloverDb) GetAllFeedsByProject(ctx context.Context, userID string, projectID string) ([]db.Feed, error) {
+	return []db.Feed{{ID: "feed", Name: "Grower"}}, nil
+}
+
+func (*failedRolloverDb) GetAllAnimalsByProject(ctx context.Context, userID string, projectID string) ([]db.Animal, error) {
+	return []db.Animal{{ID: "retained", Status: db.ANIMAL_STATUS_RETAINED}, {ID: "sold", Status: db.ANIMAL_STATUS_SOLD}}, nil
+}
+
+func (*failedRolloverDb) GetAllSuppliesByProject(ctx context.Context, userID string, projectID string) ([]db.Supply, error) {
+	return []db.Supply{{ID: "supply"}}, nil
+}
+
+func (*failedRolloverDb) UpsertFeedBatch(ctx context.Context, userID string, feeds []db.Feed) ([]int, error) {
+	return make([]int, len(feeds)), nil
+}
+
+func (*failedRolloverDb) UpsertAnimalBatch(ctx context.Context, userID string, animals []db.Animal) ([]int, error) {
+	return make([]int, len(animals)), nil
+}
+
+func (*failedRolloverDb) UpsertSupplyBatch(ctx context.Context, userID string, supplies []db.Supply) ([]int, error) {
+	return nil, errors.New("batch failed")
+}
+
+func (d *failedRolloverDb) UpsertProject(ctx context.Context, project db.Project) (db.Project, error) {
+	d.saved = true
+	return project, nil
+}
+
+func (d *failedRolloverDb) RemoveFeed(ctx context.Context, userID string, feedID string) (interface{}, error) {
+	d.removed = append(d.removed, "feed")
+	return nil, nil
+}
+
+func (d *failedRolloverDb) RemoveAnimal(ctx context.Context, userID string, animalID string) (interface{}, error) {
+	d.removed = append(d.removed, "animal")
+	return nil, nil
+}
+
+func (d *failedRolloverDb) RemoveSupply(ctx context.Context, userID string, supplyID string) (interface{}, error) {
+	d.removed = append(d.removed, "supply")
+	return nil, nil
+}
+
+func TestRolloverRemovesCopiesWhenItFails(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	store := &failedRolloverDb{}
+	e := &env{
+		db:          store,
+		logger:      zap.NewNop().Sugar(),
+		timeZone:    time.UTC,
+		programYear: utils.NewProgramYear(10, 1),
+	}
+
+	router := gin.New()
+	router.POST("/project/:projectID/rollover", func(c *gin.Context) {
+		auth.SetPrincipal(c, auth.Principal{UserID: "member", Roles: []auth.Role{auth.ROLE_MEMBER}})
+	}, e.rolloverProject)
+
+	body := `{"copy_feeds":true,"copy_retained_animals":true,"copy_supplies":true}`
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest("POST", "/project/steer/rollover", strings.NewReader(body)))
+
+	if recorder.Code != 500 {
+		t.Errorf("got %d, want 500", recorder.Code)
+	}
+
+	if store.saved {
+		t.Errorf("the project was saved after its supplies failed to")
+	}
+
+	// the supply batch failed as a whole, so only the feed and the retained animal were written
+	if !slices.Equal(store.removed, []string{"feed", "animal"}) {
+		t.Errorf("removed %v, want [feed animal]", store.removed)
+	}
+
+}
+
+func TestRolloverDate(t *testing.T) {
+
+	tests := []struct {
+		name         string
+		date         string
+		previousDate string
+		want         string
+		wantErr      bool
+	}{
+		{"a year after the original", "", "2024-10-01", "2025-10-01", false},
+		{"given date", "2025-11-15", "2024-10-01", "2025-11-15", false},
+		{"given date over a malformed original", "2025-11-15", "October 1st", "2025-11-15", false},
+		{"malformed original", "", "October 1st", "", true},
+		{"malformed given date", "11/15/2025", "2024-10-01", "", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := rolloverDate(test.date, test.previousDate, time.UTC)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error %v", err, test.wantErr)
+			}
+			if err == nil && got.String() != test.want {
+				t.Errorf("got %s, want %s", got.String(), test.want)
+			}
+		})
+	}
+
+}
@@ -0,0 +1,526 @@
+package api
+
+import (
+	"4h-recordbook-backend/internal/auth"
+	"4h-recordbook-backend/internal/config"
+	"4h-recordbook-backend/internal/utils"
+	"4h-recordbook-backend/pkg/db"
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/sync/errgroup"
+)
+
+// members' record books are read and their results written in parallel, but no more than this many at once
+const PUBLISH_CONCURRENCY = 8
+
+var errSubmissionNotSubmitted = errors.New(ErrSubmissionNotSubmitted)
+
+// a member's standing in their age division, from the average of their judges' totals as a percentage of their
+// rubric's maximum. members are only placed once every judge assigned to them has scored their record book
+type Standing struct {
+	MemberID   string  `json:"member_id"`
+	MemberName string  `json:"member_name"`
+	ClubName   string  `json:"club_name"`
+	RubricID   string  `json:"rubric_id"`
+	Judges     int     `json:"judges"`
+	Scored     int     `json:"scored"`
+	Total      float64 `json:"total"`
+	MaxTotal   float64 `json:"max_total"`
+	Percent    float64 `json:"percent"`
+	Placing    int     `json:"placing"`
+}
+
+type DivisionStandings struct {
+	Division  string     `json:"division"`
+	Standings []Standing `json:"standings"`
+}
+
+type GetStandingsOutput struct {
+	Year      string              `json:"year"`
+	Divisions []DivisionStandings `json:"divisions"`
+}
+
+type PublishResultsInput struct {
+	Year string `json:"year" validate:"required,program_year"`
+}
+
+type PublishResultsOutput struct {
+	Results []db.Result `json:"results"`
+}
+
+type GetResultsOutput struct {
+	Results []db.Result `json:"results"`
+}
+
+func roundPoints(points float64) float64 {
+	return math.Round(points*100) / 100
+}
+
+// the configured age divisions in order, then the open division
+func (e *env) divisionOrder(division string) int {
+	i := slices.IndexFunc(e.config.AgeDivisions, func(ageDivision config.AgeDivision) bool {
+		return ageDivision.Name == division
+	})
+	if i < 0 {
+		return len(e.config.AgeDivisions)
+	}
+	return i
+}
+
+// totals and placings for every member judged in the county in the program year, by age division. members are ranked
+// on their percentage rather than their total, since members of a division may be scored against rubrics with
+// different maximums. each division is ordered by placing, with members who are still being scored last. tied
+// percentages share a placing
+func (e *env) standings(scoresheets []db.Scoresheet, rubrics map[string]db.Rubric) []DivisionStandings {
+
+	byMember := map[string]*Standing{}
+	divisions := map[string][]*Standing{}
+	sums := map[string]float64{}
+
+	for _, scoresheet := range scoresheets {
+
+		standing, ok := byMember[scoresheet.MemberID]
+		if !ok {
+			standing = &Standing{
+				MemberID:   scoresheet.MemberID,
+				MemberName: scoresheet.MemberName,
+				ClubName:   scoresheet.ClubName,
+				RubricID:   scoresheet.RubricID,
+				MaxTotal:   rubrics[scoresheet.RubricID].MaxTotal(),
+			}
+			byMember[scoresheet.MemberID] = standing
+			divisions[scoresheet.Division] = append(divisions[scoresheet.Division], standing)
+		}
+
+		standing.Judges++
+		if scoresheet.Status == db.SCORESHEET_STATUS_SCORED {
+			standing.Scored++
+			sums[scoresheet.MemberID] += scoresheet.Total
+		}
+
+	}
+
+	output := []DivisionStandings{}
+
+	for division, members := range divisions {
+
+		for _, standing := range members {
+			if standing.Scored > 0 {
+				standing.Total = roundPoints(sums[standing.MemberID] / float64(standing.Scored))
+			}
+			if standing.MaxTotal > 0 {
+				standing.Percent = roundPoints(standing.Total / standing.MaxTotal * 100)
+			}
+		}
+
+		slices.SortStableFunc(members, func(a *Standing, b *Standing) int {
+			aPlaced, bPlaced := a.Scored == a.Judges, b.Scored == b.Judges
+			if aPlaced != bPlaced {
+				if aPlaced {
+					return -1
+				}
+				return 1
+			}
+			if a.Percent != b.Percent {
+				if a.Percent > b.Percent {
+					return -1
+				}
+				return 1
+			}
+			return strings.Compare(a.MemberName, b.MemberName)
+		})
+
+		standings := []Standing{}
+		for i, standing := range members {
+			if standing.Scored == standing.Judges {
+				standing.Placing = i + 1
+				if i > 0 && members[i-1].Placing > 0 && members[i-1].Percent == standing.Percent {
+					standing.Placing = members[i-1].Placing
+				}
+			}
+			standings = append(standings, *standing)
+		}
+
+		output = append(output, DivisionStandings{
+			Division:  division,
+			Standings: standings,
+		})
+
+	}
+
+	slices.SortFunc(output, func(a DivisionStandings, b DivisionStandings) int {
+		return e.divisionOrder(a.Division) - e.divisionOrder(b.Division)
+	})
+
+	return output
+
+}
+
+// the county's scoresheets for the program year and the rubrics they are scored against
+func (e *env) countyScoresheets(ctx context.Context, countyName string, year string) ([]db.Scoresheet, map[string]db.Rubric, error) {
+
+	scoresheets, err := e.db.GetScoresheetsByCounty(ctx, countyName, year)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rubrics := map[string]db.Rubric{}
+	for _, scoresheet := range scoresheets {
+		if _, ok := rubrics[scoresheet.RubricID]; ok {
+			continue
+		}
+		rubric, err := e.db.GetRubricByID(ctx, countyName, scoresheet.RubricID)
+		if err != nil {
+			return nil, nil, err
+		}
+		rubrics[rubric.ID] = rubric
+	}
+
+	return scoresheets, rubrics, nil
+
+}
+
+// the member's published result from their judges' scoresheets. points for each criterion are averaged over the
+// judges, and every comment is kept
+func memberResult(standing Standing, entries int, division string, rubric db.Rubric, scoresheets []db.Scoresheet) db.Result {
+
+	result := db.Result{
+		UserID:     standing.MemberID,
+		RubricID:   rubric.ID,
+		RubricName: rubric.Name,
+		Division:   division,
+		Total:      standing.Total,
+		MaxTotal:   standing.MaxTotal,
+		Percent:    standing.Percent,
+		Placing:    standing.Placing,
+		Entries:    entries,
+		Scores:     []db.ResultScore{},
+		Comments:   []string{},
+	}
+
+	for _, criterion := range rubric.Criteria {
+
+		score := db.ResultScore{
+			CriterionID: criterion.ID,
+			Name:        criterion.Name,
+			MaxPoints:   criterion.MaxPoints,
+			Comments:    []string{},
+		}
+
+		sum, judges := 0.0, 0
+		for _, scoresheet := range scoresheets {
+			for _, criterionScore := range scoresheet.Scores {
+				if criterionScore.CriterionID != criterion.ID {
+					continue
+				}
+				sum += criterionScore.Points
+				judges++
+				if criterionScore.Comment != "" {
+					score.Comments = append(score.Comments, criterionScore.Comment)
+				}
+			}
+		}
+		if judges > 0 {
+			score.Points = roundPoints(sum / float64(judges))
+		}
+
+		result.Scores = append(result.Scores, score)
+
+	}
+
+	for _, scoresheet := range scoresheets {
+		if scoresheet.Comment != "" {
+			result.Comments = append(result.Comments, scoresheet.Comment)
+		}
+	}
+
+	return result
+
+}
+
+// GetStandings godoc
+// @Summary Get a county's standings
+// @Description Returns each judged member's total, averaged over their judges, and their placing in their age
+// @Description division for a program year, the current one by default. Members are ranked on their total as a
+// @Description percentage of their rubric's maximum, and tied percentages share a placing. Members are placed once
+// @Description every judge assigned to them has scored their record book. Available to the agents for the county
+// @Description and admins.
+// @Tags Judging
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param countyName path string true "County name"
+// @Param year query string false "Program year"
+// @Success 200 {object} api.GetStandingsOutput
+// @Failure 400
+// @Failure 401
+// @Failure 403
+// @Router /county/{countyName}/standings [get]
+func (e *env) getStandings(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	location := e.userLocation(c.Request.Context(), principal.UserID)
+	year := ternary(c.Query("year"), e.programYear.Current(location))
+	if !e.programYear.IsValid(year) {
+		c.JSON(400, gin.H{
+			"message": ErrBadProgramYear,
+		})
+		return
+	}
+
+	countyName, ok := e.routeCounty(c, principal)
+	if !ok {
+		return
+	}
+
+	scoresheets, rubrics, err := e.countyScoresheets(c.Request.Context(), countyName, year)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(200, GetStandingsOutput{
+		Year:      year,
+		Divisions: e.standings(scoresheets, rubrics),
+	})
+
+}
+
+// PublishResults godoc
+// @Summary Publish a county's results
+// @Description Publishes each judged member's scores, comments and placing in their age division for a program
+// @Description year, which members then see with GET /results, and marks their record books as judged. Every
+// @Description scoresheet must have been scored first. Publishing again replaces the published results and leaves
+// @Description record books that are already judged alone, so a publish that fails or times out partway through is
+// @Description finished by publishing again. Available to the agents for the county and admins.
+// @Tags Judging
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param countyName path string true "County name"
+// @Param PublishResultsInput body api.PublishResultsInput true "Program year"
+// @Success 200 {object} api.PublishResultsOutput
+// @Failure 400
+// @Failure 401
+// @Failure 403
+// @Failure 409
+// @Router /county/{countyName}/results/publish [post]
+func (e *env) publishResults(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	var input PublishResultsInput
+	err = c.BindJSON(&input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrBadRequest,
+		})
+		return
+	}
+
+	err = e.validator.Struct(input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": validationMessage(err),
+		})
+		return
+	}
+
+	countyName, ok := e.routeCounty(c, principal)
+	if !ok {
+		return
+	}
+
+	scoresheets, rubrics, err := e.countyScoresheets(c.Request.Context(), countyName, input.Year)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	if len(scoresheets) == 0 {
+		c.JSON(409, gin.H{
+			"message": ErrNothingToPublish,
+		})
+		return
+	}
+
+	if slices.ContainsFunc(scoresheets, func(scoresheet db.Scoresheet) bool {
+		return scoresheet.Status != db.SCORESHEET_STATUS_SCORED
+	}) {
+		c.JSON(409, gin.H{
+			"message": ErrUnscoredScoresheets,
+		})
+		return
+	}
+
+	divisions := e.standings(scoresheets, rubrics)
+
+	type entry struct {
+		division string
+		entries  int
+		standing Standing
+	}
+
+	entries := []entry{}
+	for _, division := range divisions {
+		for _, standing := range division.Standings {
+			entries = append(entries, entry{division.Division, len(division.Standings), standing})
+		}
+	}
+
+	// every record book is read before anything is written, so that a record book reopened since its judges were
+	// assigned stops the whole county from being published rather than part of it
+	submissions := make([]db.Submission, len(entries))
+
+	group, ctx := errgroup.WithContext(c.Request.Context())
+	group.SetLimit(PUBLISH_CONCURRENCY)
+
+	for i, entry := range entries {
+		group.Go(func() error {
+			submission, err := e.submissionForYear(ctx, entry.standing.MemberID, input.Year)
+			if err != nil {
+				return err
+			}
+			if !submission.IsLocked() {
+				return errSubmissionNotSubmitted
+			}
+			submissions[i] = submission
+			return nil
+		})
+	}
+
+	err = group.Wait()
+	if errors.Is(err, errSubmissionNotSubmitted) {
+		c.JSON(409, gin.H{
+			"message": ErrSubmissionNotSubmitted,
+		})
+		return
+	}
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	actorRole := auth.ROLE_AGENT
+	if principal.HasRole(auth.ROLE_ADMIN) {
+		actorRole = auth.ROLE_ADMIN
+	}
+
+	timestamp := utils.TimeNow()
+
+	output := PublishResultsOutput{
+		Results: make([]db.Result, len(entries)),
+	}
+
+	// each member's result and record book are in their own partition, so members are published independently. the
+	// result is written before the record book is judged, which lets publishing again pick up where a failed publish
+	// stopped
+	group, ctx = errgroup.WithContext(c.Request.Context())
+	group.SetLimit(PUBLISH_CONCURRENCY)
+
+	for i, entry := range entries {
+		group.Go(func() error {
+
+			memberScoresheets := slices.DeleteFunc(slices.Clone(scoresheets), func(scoresheet db.Scoresheet) bool {
+				return scoresheet.MemberID != entry.standing.MemberID
+			})
+
+			result := memberResult(entry.standing, entry.entries, entry.division, rubrics[entry.standing.RubricID], memberScoresheets)
+			result.ID = input.Year
+			result.Year = input.Year
+			result.CountyName = countyName
+			result.PublishedBy = principal.UserID
+			result.PublishedAt = timestamp.String()
+			result.Created = timestamp.String()
+			result.Updated = timestamp.String()
+
+			result, err := e.db.UpsertResult(ctx, result)
+			if err != nil {
+				return err
+			}
+
+			if submissions[i].Status == db.SUBMISSION_STATUS_SUBMITTED {
+				_, err = e.moveSubmission(ctx, submissions[i], db.SUBMISSION_STATUS_JUDGED, principal.UserID, principal.Name, actorRole)
+				if err != nil {
+					return err
+				}
+			}
+
+			output.Results[i] = result
+			return nil
+
+		})
+	}
+
+	err = group.Wait()
+	if err != nil {
+		respondSubmissionError(c, err)
+		return
+	}
+
+	c.JSON(200, output)
+
+}
+
+// GetResults godoc
+// @Summary Get a member's judging results
+// @Description Returns the scores, judges' comments and placings the county has published for the member's record
+// @Description books, by year
+// @Tags Judging
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} api.GetResultsOutput
+// @Failure 401
+// @Router /results [get]
+func (e *env) getResults(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	var output GetResultsOutput
+
+	output.Results, err = e.db.GetResultsByUser(c.Request.Context(), principal.UserID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(200, output)
+
+}
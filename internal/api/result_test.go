@@ -0,0 +1,89 @@
+package api
+
+import (
+	"4h-recordbook-backend/internal/config"
+	"4h-recordbook-backend/pkg/db"
+	"testing"
+)
+
+func scored(memberID string, division string, rubricID string, total float64) db.Scoresheet {
+	return db.Scoresheet{
+		MemberID:   memberID,
+		MemberName: memberID,
+		Division:   division,
+		RubricID:   rubricID,
+		Status:     db.SCORESHEET_STATUS_SCORED,
+		Total:      total,
+	}
+}
+
+func TestStandings(t *testing.T) {
+
+	e := &env{config: &config.Config{AgeDivisions: []config.AgeDivision{
+		{Name: "Junior", MinAge: 8, MaxAge: 10},
+		{Name: "Senior", MinAge: 14, MaxAge: 19},
+	}}}
+
+	rubrics := map[string]db.Rubric{
+		"fifty":   {ID: "fifty", Criteria: []db.RubricCriterion{{ID: "a", MaxPoints: 50}}},
+		"hundred": {ID: "hundred", Criteria: []db.RubricCriterion{{ID: "a", MaxPoints: 100}}},
+	}
+
+	unscored := scored("dana", "Junior", "hundred", 0)
+	unscored.Status = db.SCORESHEET_STATUS_ASSIGNED
+
+	scoresheets := []db.Scoresheet{
+		// ana averages 90 of 100, ben 45 of 50 and cal 80 of 100
+		scored("ana", "Junior", "hundred", 88),
+		scored("ana", "Junior", "hundred", 92),
+		scored("ben", "Junior", "fifty", 45),
+		scored("cal", "Junior", "hundred", 80),
+		// dana has one judge still to score
+		scored("dana", "Junior", "hundred", 99),
+		unscored,
+		scored("eve", "Senior", "hundred", 70),
+		scored("finn", "Open", "hundred", 60),
+	}
+
+	divisions := e.standings(scoresheets, rubrics)
+
+	wantDivisions := []string{"Junior", "Senior", "Open"}
+	if len(divisions) != len(wantDivisions) {
+		t.Fatalf("got %d divisions, want %d", len(divisions), len(wantDivisions))
+	}
+	for i, division := range divisions {
+		if division.Division != wantDivisions[i] {
+			t.Errorf("division %d = %s, want %s", i, division.Division, wantDivisions[i])
+		}
+	}
+
+	tests := []struct {
+		member  string
+		total   float64
+		percent float64
+		placing int
+	}{
+		{"ana", 90, 90, 1},
+		{"ben", 45, 90, 1},
+		{"cal", 80, 80, 3},
+		{"dana", 99, 99, 0},
+	}
+
+	junior := divisions[0].Standings
+	if len(junior) != len(tests) {
+		t.Fatalf("got %d junior standings, want %d", len(junior), len(tests))
+	}
+
+	for i, test := range tests {
+		t.Run(test.member, func(t *testing.T) {
+			standing := junior[i]
+			if standing.MemberID != test.member {
+				t.Fatalf("standing %d is %s, want %s", i, standing.MemberID, test.member)
+			}
+			if standing.Total != test.total || standing.Percent != test.percent || standing.Placing != test.placing {
+				t.Errorf("got total %v, percent %v, placing %d, want %v, %v, %d", standing.Total, standing.Percent, standing.Placing, test.total, test.percent, test.placing)
+			}
+		})
+	}
+
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"4h-recordbook-backend/internal/auth"
 	"4h-recordbook-backend/internal/utils"
 	"4h-recordbook-backend/pkg/db"
 	"strconv"
@@ -19,17 +20,19 @@ type GetResumeOutput struct {
 
 // GetResume godoc
 // @Summary Gets full resume
-// @Description Gets all of a user's entries for every resume section
+// @Description Gets all of a user's entries for every resume section, optionally limited to a single program year
 // @Tags Resume
 // @Accept json
 // @Produce json
 // @Security ApiKeyAuth
+// @Param year query string false "Program year, e.g. 2025-2026. Defaults to every year"
 // @Success 200 {object} api.GetResumeOutput
+// @Failure 400
 // @Failure 401
 // @Router /resume [get]
 func (e *env) getResume(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -37,9 +40,17 @@ func (e *env) getResume(c *gin.Context) {
 		return
 	}
 
+	year := c.Query("year")
+	if year != "" && !e.programYear.IsValid(year) {
+		c.JSON(400, gin.H{
+			"message": ErrBadProgramYear,
+		})
+		return
+	}
+
 	var output GetResumeOutput
 
-	output.Resume, err = e.db.GetResume(c.Request.Context(), claims.ID)
+	output.Resume, err = e.db.GetResume(c.Request.Context(), principal.UserID, year)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -67,11 +78,11 @@ type GetSection1Output struct {
 
 type UpsertSection1Input struct {
 	Nickname         string `json:"nickname" validate:"required"`
-	Year             string `json:"year" validate:"required"`
+	Year             string `json:"year" validate:"required,program_year"`
 	Grade            *int   `json:"grade" validate:"required"`
-	ClubName         string `json:"club_name" validate:"required"`
-	NumInClub        *int   `json:"num_in_club" validate:"required"`
-	ClubLeader       string `json:"club_leader" validate:"required"`
+	ClubName         string `json:"club_name"`
+	NumInClub        *int   `json:"num_in_club"`
+	ClubLeader       string `json:"club_leader"`
 	MeetingsHeld     *int   `json:"meetings_held" validate:"required"`
 	MeetingsAttended *int   `json:"meetings_attended" validate:"required"`
 }
@@ -94,7 +105,7 @@ type UpsertSection1Output GetSection1Output
 // @Router /section1 [get]
 func (e *env) getSection1s(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -110,7 +121,7 @@ func (e *env) getSection1s(c *gin.Context) {
 		SortByNewest: c.GetBool(CONTEXT_KEY_SORT_BY_NEWEST),
 	}
 
-	output.Sections, err = e.db.GetSection1sByUser(c.Request.Context(), claims.ID, paginationOptions)
+	output.Sections, err = e.db.GetSection1sByUser(c.Request.Context(), principal.UserID, paginationOptions)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -151,7 +162,7 @@ func (e *env) getSection1s(c *gin.Context) {
 // @Router /section1/{sectionID} [get]
 func (e *env) getSection1(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -163,7 +174,7 @@ func (e *env) getSection1(c *gin.Context) {
 
 	var output GetSection1Output
 
-	output.Section, err = e.db.GetSection1ByID(c.Request.Context(), claims.ID, sectionID)
+	output.Section, err = e.db.GetSection1ByID(c.Request.Context(), principal.UserID, sectionID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -178,7 +189,8 @@ func (e *env) getSection1(c *gin.Context) {
 
 // AddSection1 godoc
 // @Summary Add a Section 1 entry, return added Section 1 entry
-// @Description Adds a Section 1 entry to a user's personal records
+// @Description Adds a Section 1 entry to a user's personal records.
+// @Description The club fields are filled in from the club the user was an approved member of that year, if any.
 // @Tags Resume Section 01
 // @Accept json
 // @Produce json
@@ -190,7 +202,7 @@ func (e *env) getSection1(c *gin.Context) {
 // @Router /section1 [post]
 func (e *env) addSection1(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -210,7 +222,7 @@ func (e *env) addSection1(c *gin.Context) {
 	err = e.validator.Struct(input)
 	if err != nil {
 		c.JSON(400, gin.H{
-			"message": ErrMissingFields,
+			"message": validationMessage(err),
 		})
 		return
 	}
@@ -223,14 +235,13 @@ func (e *env) addSection1(c *gin.Context) {
 		Nickname:         input.Nickname,
 		Grade:            *input.Grade,
 		ClubName:         input.ClubName,
-		NumInClub:        *input.NumInClub,
 		ClubLeader:       input.ClubLeader,
 		MeetingsHeld:     *input.MeetingsHeld,
 		MeetingsAttended: *input.MeetingsAttended,
 		GenericSectionInfo: db.GenericSectionInfo{
 			Section: 1,
 			Year:    input.Year,
-			UserID:  claims.ID,
+			UserID:  principal.UserID,
 			GenericDatabaseInfo: db.GenericDatabaseInfo{
 				Created: timestamp.String(),
 				Updated: timestamp.String(),
@@ -238,6 +249,26 @@ func (e *env) addSection1(c *gin.Context) {
 		},
 	}
 
+	if input.NumInClub != nil {
+		section.NumInClub = *input.NumInClub
+	}
+
+	inClub, err := e.setSection1Club(c.Request.Context(), &section)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	if !inClub && (input.ClubName == "" || input.ClubLeader == "" || input.NumInClub == nil) {
+		c.JSON(400, gin.H{
+			"message": ErrMissingClubFields,
+		})
+		return
+	}
+
 	var output UpsertSection1Output
 
 	output.Section, err = e.db.UpsertSection1(c.Request.Context(), section)
@@ -254,7 +285,8 @@ func (e *env) addSection1(c *gin.Context) {
 
 // UpdateSection1 godoc
 // @Summary Updates a Section 1 entry
-// @Description Updates a user's Section 1 entry information
+// @Description Updates a user's Section 1 entry information.
+// @Description The club fields are filled in from the club the user was an approved member of that year, if any.
 // @Tags Resume Section 01
 // @Accept json
 // @Produce json
@@ -267,7 +299,7 @@ func (e *env) addSection1(c *gin.Context) {
 // @Router /section1/{sectionID} [put]
 func (e *env) updateSection1(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -289,12 +321,12 @@ func (e *env) updateSection1(c *gin.Context) {
 	err = e.validator.Struct(input)
 	if err != nil {
 		c.JSON(400, gin.H{
-			"message": ErrMissingFields,
+			"message": validationMessage(err),
 		})
 		return
 	}
 
-	existingSection, err := e.db.GetSection1ByID(c.Request.Context(), claims.ID, sectionID)
+	existingSection, err := e.db.GetSection1ByID(c.Request.Context(), principal.UserID, sectionID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -310,14 +342,13 @@ func (e *env) updateSection1(c *gin.Context) {
 		Nickname:         input.Nickname,
 		Grade:            *input.Grade,
 		ClubName:         input.ClubName,
-		NumInClub:        *input.NumInClub,
 		ClubLeader:       input.ClubLeader,
 		MeetingsHeld:     *input.MeetingsHeld,
 		MeetingsAttended: *input.MeetingsAttended,
 		GenericSectionInfo: db.GenericSectionInfo{
 			Section: 1,
 			Year:    input.Year,
-			UserID:  claims.ID,
+			UserID:  principal.UserID,
 			GenericDatabaseInfo: db.GenericDatabaseInfo{
 				Created: existingSection.Created,
 				Updated: timestamp.String(),
@@ -325,6 +356,26 @@ func (e *env) updateSection1(c *gin.Context) {
 		},
 	}
 
+	if input.NumInClub != nil {
+		updatedSection.NumInClub = *input.NumInClub
+	}
+
+	inClub, err := e.setSection1Club(c.Request.Context(), &updatedSection)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	if !inClub && (input.ClubName == "" || input.ClubLeader == "" || input.NumInClub == nil) {
+		c.JSON(400, gin.H{
+			"message": ErrMissingClubFields,
+		})
+		return
+	}
+
 	var output UpsertSection1Output
 
 	output.Section, err = e.db.UpsertSection1(c.Request.Context(), updatedSection)
@@ -353,7 +404,7 @@ type GetSection2Output struct {
 }
 
 type UpsertSection2Input struct {
-	Year         string `json:"year" validate:"required"`
+	Year         string `json:"year" validate:"required,program_year"`
 	ProjectName  string `json:"project_name" validate:"required"`
 	ProjectScope string `json:"project_scope" validate:"required"`
 }
@@ -375,7 +426,7 @@ type UpsertSection2Output GetSection2Output
 // @Router /section2 [get]
 func (e *env) getSection2s(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -391,7 +442,7 @@ func (e *env) getSection2s(c *gin.Context) {
 		SortByNewest: c.GetBool(CONTEXT_KEY_SORT_BY_NEWEST),
 	}
 
-	output.Sections, err = e.db.GetSection2sByUser(c.Request.Context(), claims.ID, paginationOptions)
+	output.Sections, err = e.db.GetSection2sByUser(c.Request.Context(), principal.UserID, paginationOptions)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -433,7 +484,7 @@ func (e *env) getSection2s(c *gin.Context) {
 // @Router /section2/{sectionID} [get]
 func (e *env) getSection2(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -445,7 +496,7 @@ func (e *env) getSection2(c *gin.Context) {
 
 	var output GetSection2Output
 
-	output.Section, err = e.db.GetSection2ByID(c.Request.Context(), claims.ID, sectionID)
+	output.Section, err = e.db.GetSection2ByID(c.Request.Context(), principal.UserID, sectionID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -472,7 +523,7 @@ func (e *env) getSection2(c *gin.Context) {
 // @Router /section2 [post]
 func (e *env) addSection2(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -492,7 +543,7 @@ func (e *env) addSection2(c *gin.Context) {
 	err = e.validator.Struct(input)
 	if err != nil {
 		c.JSON(400, gin.H{
-			"message": ErrMissingFields,
+			"message": validationMessage(err),
 		})
 		return
 	}
@@ -507,7 +558,7 @@ func (e *env) addSection2(c *gin.Context) {
 		GenericSectionInfo: db.GenericSectionInfo{
 			Section: 2,
 			Year:    input.Year,
-			UserID:  claims.ID,
+			UserID:  principal.UserID,
 			GenericDatabaseInfo: db.GenericDatabaseInfo{
 				Created: timestamp.String(),
 				Updated: timestamp.String(),
@@ -545,7 +596,7 @@ func (e *env) addSection2(c *gin.Context) {
 // @Router /section2/{sectionID} [put]
 func (e *env) updateSection2(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -567,12 +618,12 @@ func (e *env) updateSection2(c *gin.Context) {
 	err = e.validator.Struct(input)
 	if err != nil {
 		c.JSON(400, gin.H{
-			"message": ErrMissingFields,
+			"message": validationMessage(err),
 		})
 		return
 	}
 
-	existingSection, err := e.db.GetSection2ByID(c.Request.Context(), claims.ID, sectionID)
+	existingSection, err := e.db.GetSection2ByID(c.Request.Context(), principal.UserID, sectionID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -590,7 +641,7 @@ func (e *env) updateSection2(c *gin.Context) {
 		GenericSectionInfo: db.GenericSectionInfo{
 			Section: 2,
 			Year:    input.Year,
-			UserID:  claims.ID,
+			UserID:  principal.UserID,
 			GenericDatabaseInfo: db.GenericDatabaseInfo{
 				Created: existingSection.Created,
 				Updated: timestamp.String(),
@@ -628,7 +679,7 @@ type GetSection3Output struct {
 
 type UpsertSection3Input struct {
 	Nickname      string `json:"nickname" validate:"required"`
-	Year          string `json:"year" validate:"required"`
+	Year          string `json:"year" validate:"required,program_year"`
 	ActivityKind  string `json:"activity_kind" validate:"required"`
 	ThingsLearned string `json:"things_learned" validate:"required"`
 	Level         string `json:"level" validate:"required"`
@@ -651,7 +702,7 @@ type UpsertSection3Output GetSection3Output
 // @Router /section3 [get]
 func (e *env) getSection3s(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -667,7 +718,7 @@ func (e *env) getSection3s(c *gin.Context) {
 		SortByNewest: c.GetBool(CONTEXT_KEY_SORT_BY_NEWEST),
 	}
 
-	output.Sections, err = e.db.GetSection3sByUser(c.Request.Context(), claims.ID, paginationOptions)
+	output.Sections, err = e.db.GetSection3sByUser(c.Request.Context(), principal.UserID, paginationOptions)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -709,7 +760,7 @@ func (e *env) getSection3s(c *gin.Context) {
 // @Router /section3/{sectionID} [get]
 func (e *env) getSection3(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -721,7 +772,7 @@ func (e *env) getSection3(c *gin.Context) {
 
 	var output GetSection3Output
 
-	output.Section, err = e.db.GetSection3ByID(c.Request.Context(), claims.ID, sectionID)
+	output.Section, err = e.db.GetSection3ByID(c.Request.Context(), principal.UserID, sectionID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -748,7 +799,7 @@ func (e *env) getSection3(c *gin.Context) {
 // @Router /section3 [post]
 func (e *env) addSection3(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -768,7 +819,7 @@ func (e *env) addSection3(c *gin.Context) {
 	err = e.validator.Struct(input)
 	if err != nil {
 		c.JSON(400, gin.H{
-			"message": ErrMissingFields,
+			"message": validationMessage(err),
 		})
 		return
 	}
@@ -785,7 +836,7 @@ func (e *env) addSection3(c *gin.Context) {
 		GenericSectionInfo: db.GenericSectionInfo{
 			Section: 3,
 			Year:    input.Year,
-			UserID:  claims.ID,
+			UserID:  principal.UserID,
 			GenericDatabaseInfo: db.GenericDatabaseInfo{
 				Created: timestamp.String(),
 				Updated: timestamp.String(),
@@ -823,7 +874,7 @@ func (e *env) addSection3(c *gin.Context) {
 // @Router /section3/{sectionID} [put]
 func (e *env) updateSection3(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -845,12 +896,12 @@ func (e *env) updateSection3(c *gin.Context) {
 	err = e.validator.Struct(input)
 	if err != nil {
 		c.JSON(400, gin.H{
-			"message": ErrMissingFields,
+			"message": validationMessage(err),
 		})
 		return
 	}
 
-	existingSection, err := e.db.GetSection3ByID(c.Request.Context(), claims.ID, sectionID)
+	existingSection, err := e.db.GetSection3ByID(c.Request.Context(), principal.UserID, sectionID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -870,7 +921,7 @@ func (e *env) updateSection3(c *gin.Context) {
 		GenericSectionInfo: db.GenericSectionInfo{
 			Section: 3,
 			Year:    input.Year,
-			UserID:  claims.ID,
+			UserID:  principal.UserID,
 			GenericDatabaseInfo: db.GenericDatabaseInfo{
 				Created: existingSection.Created,
 				Updated: timestamp.String(),
@@ -908,7 +959,7 @@ type GetSection4Output struct {
 
 type UpsertSection4Input struct {
 	Nickname     string `json:"nickname" validate:"required"`
-	Year         string `json:"year" validate:"required"`
+	Year         string `json:"year" validate:"required,program_year"`
 	ActivityKind string `json:"activity_kind" validate:"required"`
 	Scope        string `json:"scope" validate:"required"`
 	Level        string `json:"level" validate:"required"`
@@ -931,7 +982,7 @@ type UpsertSection4Output GetSection4Output
 // @Router /section4 [get]
 func (e *env) getSection4s(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -947,7 +998,7 @@ func (e *env) getSection4s(c *gin.Context) {
 		SortByNewest: c.GetBool(CONTEXT_KEY_SORT_BY_NEWEST),
 	}
 
-	output.Sections, err = e.db.GetSection4sByUser(c.Request.Context(), claims.ID, paginationOptions)
+	output.Sections, err = e.db.GetSection4sByUser(c.Request.Context(), principal.UserID, paginationOptions)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -989,7 +1040,7 @@ func (e *env) getSection4s(c *gin.Context) {
 // @Router /section4/{sectionID} [get]
 func (e *env) getSection4(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -1001,7 +1052,7 @@ func (e *env) getSection4(c *gin.Context) {
 
 	var output GetSection4Output
 
-	output.Section, err = e.db.GetSection4ByID(c.Request.Context(), claims.ID, sectionID)
+	output.Section, err = e.db.GetSection4ByID(c.Request.Context(), principal.UserID, sectionID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -1028,7 +1079,7 @@ func (e *env) getSection4(c *gin.Context) {
 // @Router /section4 [post]
 func (e *env) addSection4(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -1048,7 +1099,7 @@ func (e *env) addSection4(c *gin.Context) {
 	err = e.validator.Struct(input)
 	if err != nil {
 		c.JSON(400, gin.H{
-			"message": ErrMissingFields,
+			"message": validationMessage(err),
 		})
 		return
 	}
@@ -1065,7 +1116,7 @@ func (e *env) addSection4(c *gin.Context) {
 		GenericSectionInfo: db.GenericSectionInfo{
 			Section: 4,
 			Year:    input.Year,
-			UserID:  claims.ID,
+			UserID:  principal.UserID,
 			GenericDatabaseInfo: db.GenericDatabaseInfo{
 				Created: timestamp.String(),
 				Updated: timestamp.String(),
@@ -1103,7 +1154,7 @@ func (e *env) addSection4(c *gin.Context) {
 // @Router /section4/{sectionID} [put]
 func (e *env) updateSection4(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -1125,12 +1176,12 @@ func (e *env) updateSection4(c *gin.Context) {
 	err = e.validator.Struct(input)
 	if err != nil {
 		c.JSON(400, gin.H{
-			"message": ErrMissingFields,
+			"message": validationMessage(err),
 		})
 		return
 	}
 
-	existingSection, err := e.db.GetSection4ByID(c.Request.Context(), claims.ID, sectionID)
+	existingSection, err := e.db.GetSection4ByID(c.Request.Context(), principal.UserID, sectionID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -1150,7 +1201,7 @@ func (e *env) updateSection4(c *gin.Context) {
 		GenericSectionInfo: db.GenericSectionInfo{
 			Section: 4,
 			Year:    input.Year,
-			UserID:  claims.ID,
+			UserID:  principal.UserID,
 			GenericDatabaseInfo: db.GenericDatabaseInfo{
 				Created: existingSection.Created,
 				Updated: timestamp.String(),
@@ -1188,7 +1239,7 @@ type GetSection5Output struct {
 
 type UpsertSection5Input struct {
 	Nickname         string `json:"nickname" validate:"required"`
-	Year             string `json:"year" validate:"required"`
+	Year             string `json:"year" validate:"required,program_year"`
 	LeadershipRole   string `json:"leadership_role" validate:"required"`
 	HoursSpent       *int   `json:"hours_spent" validate:"required"`
 	NumPeopleReached *int   `json:"num_people_reached" validate:"required"`
@@ -1211,7 +1262,7 @@ type UpsertSection5Output GetSection5Output
 // @Router /section5 [get]
 func (e *env) getSection5s(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -1227,7 +1278,7 @@ func (e *env) getSection5s(c *gin.Context) {
 		SortByNewest: c.GetBool(CONTEXT_KEY_SORT_BY_NEWEST),
 	}
 
-	output.Sections, err = e.db.GetSection5sByUser(c.Request.Context(), claims.ID, paginationOptions)
+	output.Sections, err = e.db.GetSection5sByUser(c.Request.Context(), principal.UserID, paginationOptions)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -1269,7 +1320,7 @@ func (e *env) getSection5s(c *gin.Context) {
 // @Router /section5/{sectionID} [get]
 func (e *env) getSection5(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -1281,7 +1332,7 @@ func (e *env) getSection5(c *gin.Context) {
 
 	var output GetSection5Output
 
-	output.Section, err = e.db.GetSection5ByID(c.Request.Context(), claims.ID, sectionID)
+	output.Section, err = e.db.GetSection5ByID(c.Request.Context(), principal.UserID, sectionID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -1308,7 +1359,7 @@ func (e *env) getSection5(c *gin.Context) {
 // @Router /section5 [post]
 func (e *env) addSection5(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -1328,7 +1379,7 @@ func (e *env) addSection5(c *gin.Context) {
 	err = e.validator.Struct(input)
 	if err != nil {
 		c.JSON(400, gin.H{
-			"message": ErrMissingFields,
+			"message": validationMessage(err),
 		})
 		return
 	}
@@ -1345,7 +1396,7 @@ func (e *env) addSection5(c *gin.Context) {
 		GenericSectionInfo: db.GenericSectionInfo{
 			Section: 5,
 			Year:    input.Year,
-			UserID:  claims.ID,
+			UserID:  principal.UserID,
 			GenericDatabaseInfo: db.GenericDatabaseInfo{
 				Created: timestamp.String(),
 				Updated: timestamp.String(),
@@ -1383,7 +1434,7 @@ func (e *env) addSection5(c *gin.Context) {
 // @Router /section5/{sectionID} [put]
 func (e *env) updateSection5(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -1405,12 +1456,12 @@ func (e *env) updateSection5(c *gin.Context) {
 	err = e.validator.Struct(input)
 	if err != nil {
 		c.JSON(400, gin.H{
-			"message": ErrMissingFields,
+			"message": validationMessage(err),
 		})
 		return
 	}
 
-	existingSection, err := e.db.GetSection5ByID(c.Request.Context(), claims.ID, sectionID)
+	existingSection, err := e.db.GetSection5ByID(c.Request.Context(), principal.UserID, sectionID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -1430,7 +1481,7 @@ func (e *env) updateSection5(c *gin.Context) {
 		GenericSectionInfo: db.GenericSectionInfo{
 			Section: 5,
 			Year:    input.Year,
-			UserID:  claims.ID,
+			UserID:  principal.UserID,
 			GenericDatabaseInfo: db.GenericDatabaseInfo{
 				Created: existingSection.Created,
 				Updated: timestamp.String(),
@@ -1468,7 +1519,7 @@ type GetSection6Output struct {
 
 type UpsertSection6Input struct {
 	Nickname         string `json:"nickname" validate:"required"`
-	Year             string `json:"year" validate:"required"`
+	Year             string `json:"year" validate:"required,program_year"`
 	OrganizationName string `json:"organization_name" validate:"required"`
 	LeadershipRole   string `json:"leadership_role" validate:"required"`
 	HoursSpent       *int   `json:"hours_spent" validate:"required"`
@@ -1492,7 +1543,7 @@ type UpsertSection6Output GetSection6Output
 // @Router /section6 [get]
 func (e *env) getSection6s(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -1508,7 +1559,7 @@ func (e *env) getSection6s(c *gin.Context) {
 		SortByNewest: c.GetBool(CONTEXT_KEY_SORT_BY_NEWEST),
 	}
 
-	output.Sections, err = e.db.GetSection6sByUser(c.Request.Context(), claims.ID, paginationOptions)
+	output.Sections, err = e.db.GetSection6sByUser(c.Request.Context(), principal.UserID, paginationOptions)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -1550,7 +1601,7 @@ func (e *env) getSection6s(c *gin.Context) {
 // @Router /section6/{sectionID} [get]
 func (e *env) getSection6(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -1562,7 +1613,7 @@ func (e *env) getSection6(c *gin.Context) {
 
 	var output GetSection6Output
 
-	output.Section, err = e.db.GetSection6ByID(c.Request.Context(), claims.ID, sectionID)
+	output.Section, err = e.db.GetSection6ByID(c.Request.Context(), principal.UserID, sectionID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -1589,7 +1640,7 @@ func (e *env) getSection6(c *gin.Context) {
 // @Router /section6 [post]
 func (e *env) addSection6(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -1609,7 +1660,7 @@ func (e *env) addSection6(c *gin.Context) {
 	err = e.validator.Struct(input)
 	if err != nil {
 		c.JSON(400, gin.H{
-			"message": ErrMissingFields,
+			"message": validationMessage(err),
 		})
 		return
 	}
@@ -1627,7 +1678,7 @@ func (e *env) addSection6(c *gin.Context) {
 		GenericSectionInfo: db.GenericSectionInfo{
 			Section: 6,
 			Year:    input.Year,
-			UserID:  claims.ID,
+			UserID:  principal.UserID,
 			GenericDatabaseInfo: db.GenericDatabaseInfo{
 				Created: timestamp.String(),
 				Updated: timestamp.String(),
@@ -1665,7 +1716,7 @@ func (e *env) addSection6(c *gin.Context) {
 // @Router /section6/{sectionID} [put]
 func (e *env) updateSection6(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -1687,12 +1738,12 @@ func (e *env) updateSection6(c *gin.Context) {
 	err = e.validator.Struct(input)
 	if err != nil {
 		c.JSON(400, gin.H{
-			"message": ErrMissingFields,
+			"message": validationMessage(err),
 		})
 		return
 	}
 
-	existingSection, err := e.db.GetSection6ByID(c.Request.Context(), claims.ID, sectionID)
+	existingSection, err := e.db.GetSection6ByID(c.Request.Context(), principal.UserID, sectionID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -1713,7 +1764,7 @@ func (e *env) updateSection6(c *gin.Context) {
 		GenericSectionInfo: db.GenericSectionInfo{
 			Section: 6,
 			Year:    input.Year,
-			UserID:  claims.ID,
+			UserID:  principal.UserID,
 			GenericDatabaseInfo: db.GenericDatabaseInfo{
 				Created: existingSection.Created,
 				Updated: timestamp.String(),
@@ -1751,7 +1802,7 @@ type GetSection7Output struct {
 
 type UpsertSection7Input struct {
 	Nickname             string `json:"nickname" validate:"required"`
-	Year                 string `json:"year" validate:"required"`
+	Year                 string `json:"year" validate:"required,program_year"`
 	ClubMemberActivities string `json:"club_member_activities" validate:"required"`
 	HoursSpent           *int   `json:"hours_spent" validate:"required"`
 	NumPeopleReached     *int   `json:"num_people_reached" validate:"required"`
@@ -1774,7 +1825,7 @@ type UpsertSection7Output GetSection7Output
 // @Router /section7 [get]
 func (e *env) getSection7s(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -1790,7 +1841,7 @@ func (e *env) getSection7s(c *gin.Context) {
 		SortByNewest: c.GetBool(CONTEXT_KEY_SORT_BY_NEWEST),
 	}
 
-	output.Sections, err = e.db.GetSection7sByUser(c.Request.Context(), claims.ID, paginationOptions)
+	output.Sections, err = e.db.GetSection7sByUser(c.Request.Context(), principal.UserID, paginationOptions)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -1832,7 +1883,7 @@ func (e *env) getSection7s(c *gin.Context) {
 // @Router /section7/{sectionID} [get]
 func (e *env) getSection7(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -1844,7 +1895,7 @@ func (e *env) getSection7(c *gin.Context) {
 
 	var output GetSection7Output
 
-	output.Section, err = e.db.GetSection7ByID(c.Request.Context(), claims.ID, sectionID)
+	output.Section, err = e.db.GetSection7ByID(c.Request.Context(), principal.UserID, sectionID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -1871,7 +1922,7 @@ func (e *env) getSection7(c *gin.Context) {
 // @Router /section7 [post]
 func (e *env) addSection7(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -1891,7 +1942,7 @@ func (e *env) addSection7(c *gin.Context) {
 	err = e.validator.Struct(input)
 	if err != nil {
 		c.JSON(400, gin.H{
-			"message": ErrMissingFields,
+			"message": validationMessage(err),
 		})
 		return
 	}
@@ -1908,7 +1959,7 @@ func (e *env) addSection7(c *gin.Context) {
 		GenericSectionInfo: db.GenericSectionInfo{
 			Section: 7,
 			Year:    input.Year,
-			UserID:  claims.ID,
+			UserID:  principal.UserID,
 			GenericDatabaseInfo: db.GenericDatabaseInfo{
 				Created: timestamp.String(),
 				Updated: timestamp.String(),
@@ -1946,7 +1997,7 @@ func (e *env) addSection7(c *gin.Context) {
 // @Router /section7/{sectionID} [put]
 func (e *env) updateSection7(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -1968,12 +2019,12 @@ func (e *env) updateSection7(c *gin.Context) {
 	err = e.validator.Struct(input)
 	if err != nil {
 		c.JSON(400, gin.H{
-			"message": ErrMissingFields,
+			"message": validationMessage(err),
 		})
 		return
 	}
 
-	existingSection, err := e.db.GetSection7ByID(c.Request.Context(), claims.ID, sectionID)
+	existingSection, err := e.db.GetSection7ByID(c.Request.Context(), principal.UserID, sectionID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -1993,7 +2044,7 @@ func (e *env) updateSection7(c *gin.Context) {
 		GenericSectionInfo: db.GenericSectionInfo{
 			Section: 7,
 			Year:    input.Year,
-			UserID:  claims.ID,
+			UserID:  principal.UserID,
 			GenericDatabaseInfo: db.GenericDatabaseInfo{
 				Created: existingSection.Created,
 				Updated: timestamp.String(),
@@ -2031,7 +2082,7 @@ type GetSection8Output struct {
 
 type UpsertSection8Input struct {
 	Nickname                  string `json:"nickname" validate:"required"`
-	Year                      string `json:"year" validate:"required"`
+	Year                      string `json:"year" validate:"required,program_year"`
 	IndividualGroupActivities string `json:"individual_group_activities" validate:"required"`
 	HoursSpent                *int   `json:"hours_spent" validate:"required"`
 	NumPeopleReached          *int   `json:"num_people_reached" validate:"required"`
@@ -2054,7 +2105,7 @@ type UpsertSection8Output GetSection8Output
 // @Router /section8 [get]
 func (e *env) getSection8s(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -2070,7 +2121,7 @@ func (e *env) getSection8s(c *gin.Context) {
 		SortByNewest: c.GetBool(CONTEXT_KEY_SORT_BY_NEWEST),
 	}
 
-	output.Sections, err = e.db.GetSection8sByUser(c.Request.Context(), claims.ID, paginationOptions)
+	output.Sections, err = e.db.GetSection8sByUser(c.Request.Context(), principal.UserID, paginationOptions)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -2112,7 +2163,7 @@ func (e *env) getSection8s(c *gin.Context) {
 // @Router /section8/{sectionID} [get]
 func (e *env) getSection8(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -2124,7 +2175,7 @@ func (e *env) getSection8(c *gin.Context) {
 
 	var output GetSection8Output
 
-	output.Section, err = e.db.GetSection8ByID(c.Request.Context(), claims.ID, sectionID)
+	output.Section, err = e.db.GetSection8ByID(c.Request.Context(), principal.UserID, sectionID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -2151,7 +2202,7 @@ func (e *env) getSection8(c *gin.Context) {
 // @Router /section8 [post]
 func (e *env) addSection8(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -2171,7 +2222,7 @@ func (e *env) addSection8(c *gin.Context) {
 	err = e.validator.Struct(input)
 	if err != nil {
 		c.JSON(400, gin.H{
-			"message": ErrMissingFields,
+			"message": validationMessage(err),
 		})
 		return
 	}
@@ -2188,7 +2239,7 @@ func (e *env) addSection8(c *gin.Context) {
 		GenericSectionInfo: db.GenericSectionInfo{
 			Section: 8,
 			Year:    input.Year,
-			UserID:  claims.ID,
+			UserID:  principal.UserID,
 			GenericDatabaseInfo: db.GenericDatabaseInfo{
 				Created: timestamp.String(),
 				Updated: timestamp.String(),
@@ -2226,7 +2277,7 @@ func (e *env) addSection8(c *gin.Context) {
 // @Router /section8/{sectionID} [put]
 func (e *env) updateSection8(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -2248,12 +2299,12 @@ func (e *env) updateSection8(c *gin.Context) {
 	err = e.validator.Struct(input)
 	if err != nil {
 		c.JSON(400, gin.H{
-			"message": ErrMissingFields,
+			"message": validationMessage(err),
 		})
 		return
 	}
 
-	existingSection, err := e.db.GetSection8ByID(c.Request.Context(), claims.ID, sectionID)
+	existingSection, err := e.db.GetSection8ByID(c.Request.Context(), principal.UserID, sectionID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -2273,7 +2324,7 @@ func (e *env) updateSection8(c *gin.Context) {
 		GenericSectionInfo: db.GenericSectionInfo{
 			Section: 8,
 			Year:    input.Year,
-			UserID:  claims.ID,
+			UserID:  principal.UserID,
 			GenericDatabaseInfo: db.GenericDatabaseInfo{
 				Created: existingSection.Created,
 				Updated: timestamp.String(),
@@ -2311,7 +2362,7 @@ type GetSection9Output struct {
 
 type UpsertSection9Input struct {
 	Nickname          string `json:"nickname" validate:"required"`
-	Year              string `json:"year" validate:"required"`
+	Year              string `json:"year" validate:"required,program_year"`
 	CommunicationType string `json:"communication_type" validate:"required"`
 	Topic             string `json:"topic" validate:"required"`
 	TimesGiven        *int   `json:"times_given" validate:"required"`
@@ -2336,7 +2387,7 @@ type UpsertSection9Output GetSection9Output
 // @Router /section9 [get]
 func (e *env) getSection9s(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -2352,7 +2403,7 @@ func (e *env) getSection9s(c *gin.Context) {
 		SortByNewest: c.GetBool(CONTEXT_KEY_SORT_BY_NEWEST),
 	}
 
-	output.Sections, err = e.db.GetSection9sByUser(c.Request.Context(), claims.ID, paginationOptions)
+	output.Sections, err = e.db.GetSection9sByUser(c.Request.Context(), principal.UserID, paginationOptions)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -2394,7 +2445,7 @@ func (e *env) getSection9s(c *gin.Context) {
 // @Router /section9/{sectionID} [get]
 func (e *env) getSection9(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -2406,7 +2457,7 @@ func (e *env) getSection9(c *gin.Context) {
 
 	var output GetSection9Output
 
-	output.Section, err = e.db.GetSection9ByID(c.Request.Context(), claims.ID, sectionID)
+	output.Section, err = e.db.GetSection9ByID(c.Request.Context(), principal.UserID, sectionID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -2433,7 +2484,7 @@ func (e *env) getSection9(c *gin.Context) {
 // @Router /section9 [post]
 func (e *env) addSection9(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -2453,7 +2504,7 @@ func (e *env) addSection9(c *gin.Context) {
 	err = e.validator.Struct(input)
 	if err != nil {
 		c.JSON(400, gin.H{
-			"message": ErrMissingFields,
+			"message": validationMessage(err),
 		})
 		return
 	}
@@ -2472,7 +2523,7 @@ func (e *env) addSection9(c *gin.Context) {
 		GenericSectionInfo: db.GenericSectionInfo{
 			Section: 9,
 			Year:    input.Year,
-			UserID:  claims.ID,
+			UserID:  principal.UserID,
 			GenericDatabaseInfo: db.GenericDatabaseInfo{
 				Created: timestamp.String(),
 				Updated: timestamp.String(),
@@ -2510,7 +2561,7 @@ func (e *env) addSection9(c *gin.Context) {
 // @Router /section9/{sectionID} [put]
 func (e *env) updateSection9(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -2532,12 +2583,12 @@ func (e *env) updateSection9(c *gin.Context) {
 	err = e.validator.Struct(input)
 	if err != nil {
 		c.JSON(400, gin.H{
-			"message": ErrMissingFields,
+			"message": validationMessage(err),
 		})
 		return
 	}
 
-	existingSection, err := e.db.GetSection9ByID(c.Request.Context(), claims.ID, sectionID)
+	existingSection, err := e.db.GetSection9ByID(c.Request.Context(), principal.UserID, sectionID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -2559,7 +2610,7 @@ func (e *env) updateSection9(c *gin.Context) {
 		GenericSectionInfo: db.GenericSectionInfo{
 			Section: 9,
 			Year:    input.Year,
-			UserID:  claims.ID,
+			UserID:  principal.UserID,
 			GenericDatabaseInfo: db.GenericDatabaseInfo{
 				Created: existingSection.Created,
 				Updated: timestamp.String(),
@@ -2597,7 +2648,7 @@ type GetSection10Output struct {
 
 type UpsertSection10Input struct {
 	Nickname          string `json:"nickname" validate:"required"`
-	Year              string `json:"year" validate:"required"`
+	Year              string `json:"year" validate:"required,program_year"`
 	CommunicationType string `json:"communication_type" validate:"required"`
 	Topic             string `json:"topic" validate:"required"`
 	TimesGiven        *int   `json:"times_given" validate:"required"`
@@ -2622,7 +2673,7 @@ type UpsertSection10Output GetSection10Output
 // @Router /section10 [get]
 func (e *env) getSection10s(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -2638,7 +2689,7 @@ func (e *env) getSection10s(c *gin.Context) {
 		SortByNewest: c.GetBool(CONTEXT_KEY_SORT_BY_NEWEST),
 	}
 
-	output.Sections, err = e.db.GetSection10sByUser(c.Request.Context(), claims.ID, paginationOptions)
+	output.Sections, err = e.db.GetSection10sByUser(c.Request.Context(), principal.UserID, paginationOptions)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -2680,7 +2731,7 @@ func (e *env) getSection10s(c *gin.Context) {
 // @Router /section10/{sectionID} [get]
 func (e *env) getSection10(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -2692,7 +2743,7 @@ func (e *env) getSection10(c *gin.Context) {
 
 	var output GetSection10Output
 
-	output.Section, err = e.db.GetSection10ByID(c.Request.Context(), claims.ID, sectionID)
+	output.Section, err = e.db.GetSection10ByID(c.Request.Context(), principal.UserID, sectionID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -2719,7 +2770,7 @@ func (e *env) getSection10(c *gin.Context) {
 // @Router /section10 [post]
 func (e *env) addSection10(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -2739,7 +2790,7 @@ func (e *env) addSection10(c *gin.Context) {
 	err = e.validator.Struct(input)
 	if err != nil {
 		c.JSON(400, gin.H{
-			"message": ErrMissingFields,
+			"message": validationMessage(err),
 		})
 		return
 	}
@@ -2758,7 +2809,7 @@ func (e *env) addSection10(c *gin.Context) {
 		GenericSectionInfo: db.GenericSectionInfo{
 			Section: 10,
 			Year:    input.Year,
-			UserID:  claims.ID,
+			UserID:  principal.UserID,
 			GenericDatabaseInfo: db.GenericDatabaseInfo{
 				Created: timestamp.String(),
 				Updated: timestamp.String(),
@@ -2796,7 +2847,7 @@ func (e *env) addSection10(c *gin.Context) {
 // @Router /section10/{sectionID} [put]
 func (e *env) updateSection10(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -2818,12 +2869,12 @@ func (e *env) updateSection10(c *gin.Context) {
 	err = e.validator.Struct(input)
 	if err != nil {
 		c.JSON(400, gin.H{
-			"message": ErrMissingFields,
+			"message": validationMessage(err),
 		})
 		return
 	}
 
-	existingSection, err := e.db.GetSection10ByID(c.Request.Context(), claims.ID, sectionID)
+	existingSection, err := e.db.GetSection10ByID(c.Request.Context(), principal.UserID, sectionID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -2845,7 +2896,7 @@ func (e *env) updateSection10(c *gin.Context) {
 		GenericSectionInfo: db.GenericSectionInfo{
 			Section: 10,
 			Year:    input.Year,
-			UserID:  claims.ID,
+			UserID:  principal.UserID,
 			GenericDatabaseInfo: db.GenericDatabaseInfo{
 				Created: existingSection.Created,
 				Updated: timestamp.String(),
@@ -2883,7 +2934,7 @@ type GetSection11Output struct {
 
 type UpsertSection11Input struct {
 	Nickname           string `json:"nickname" validate:"required"`
-	Year               string `json:"year" validate:"required"`
+	Year               string `json:"year" validate:"required,program_year"`
 	EventAndLevel      string `json:"event_and_level" validate:"required"`
 	ExhibitsOrDivision string `json:"exhibits_or_division" validate:"required"`
 	RibbonOrPlacings   string `json:"ribbon_or_placings" validate:"required"`
@@ -2906,7 +2957,7 @@ type UpsertSection11Output GetSection11Output
 // @Router /section11 [get]
 func (e *env) getSection11s(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -2922,7 +2973,7 @@ func (e *env) getSection11s(c *gin.Context) {
 		SortByNewest: c.GetBool(CONTEXT_KEY_SORT_BY_NEWEST),
 	}
 
-	output.Sections, err = e.db.GetSection11sByUser(c.Request.Context(), claims.ID, paginationOptions)
+	output.Sections, err = e.db.GetSection11sByUser(c.Request.Context(), principal.UserID, paginationOptions)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -2964,7 +3015,7 @@ func (e *env) getSection11s(c *gin.Context) {
 // @Router /section11/{sectionID} [get]
 func (e *env) getSection11(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -2976,7 +3027,7 @@ func (e *env) getSection11(c *gin.Context) {
 
 	var output GetSection11Output
 
-	output.Section, err = e.db.GetSection11ByID(c.Request.Context(), claims.ID, sectionID)
+	output.Section, err = e.db.GetSection11ByID(c.Request.Context(), principal.UserID, sectionID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -3003,7 +3054,7 @@ func (e *env) getSection11(c *gin.Context) {
 // @Router /section11 [post]
 func (e *env) addSection11(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -3023,7 +3074,7 @@ func (e *env) addSection11(c *gin.Context) {
 	err = e.validator.Struct(input)
 	if err != nil {
 		c.JSON(400, gin.H{
-			"message": ErrMissingFields,
+			"message": validationMessage(err),
 		})
 		return
 	}
@@ -3040,7 +3091,7 @@ func (e *env) addSection11(c *gin.Context) {
 		GenericSectionInfo: db.GenericSectionInfo{
 			Section: 11,
 			Year:    input.Year,
-			UserID:  claims.ID,
+			UserID:  principal.UserID,
 			GenericDatabaseInfo: db.GenericDatabaseInfo{
 				Created: timestamp.String(),
 				Updated: timestamp.String(),
@@ -3078,7 +3129,7 @@ func (e *env) addSection11(c *gin.Context) {
 // @Router /section11/{sectionID} [put]
 func (e *env) updateSection11(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -3100,12 +3151,12 @@ func (e *env) updateSection11(c *gin.Context) {
 	err = e.validator.Struct(input)
 	if err != nil {
 		c.JSON(400, gin.H{
-			"message": ErrMissingFields,
+			"message": validationMessage(err),
 		})
 		return
 	}
 
-	existingSection, err := e.db.GetSection11ByID(c.Request.Context(), claims.ID, sectionID)
+	existingSection, err := e.db.GetSection11ByID(c.Request.Context(), principal.UserID, sectionID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -3125,7 +3176,7 @@ func (e *env) updateSection11(c *gin.Context) {
 		GenericSectionInfo: db.GenericSectionInfo{
 			Section: 11,
 			Year:    input.Year,
-			UserID:  claims.ID,
+			UserID:  principal.UserID,
 			GenericDatabaseInfo: db.GenericDatabaseInfo{
 				Created: existingSection.Created,
 				Updated: timestamp.String(),
@@ -3163,7 +3214,7 @@ type GetSection12Output struct {
 
 type UpsertSection12Input struct {
 	Nickname            string `json:"nickname" validate:"required"`
-	Year                string `json:"year" validate:"required"`
+	Year                string `json:"year" validate:"required,program_year"`
 	ContestOrEvent      string `json:"contest_or_event" validate:"required"`
 	RecognitionReceived string `json:"recognition_received" validate:"required"`
 	Level               string `json:"level" validate:"required"`
@@ -3186,7 +3237,7 @@ type UpsertSection12Output GetSection12Output
 // @Router /section12 [get]
 func (e *env) getSection12s(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -3202,7 +3253,7 @@ func (e *env) getSection12s(c *gin.Context) {
 		SortByNewest: c.GetBool(CONTEXT_KEY_SORT_BY_NEWEST),
 	}
 
-	output.Sections, err = e.db.GetSection12sByUser(c.Request.Context(), claims.ID, paginationOptions)
+	output.Sections, err = e.db.GetSection12sByUser(c.Request.Context(), principal.UserID, paginationOptions)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -3244,7 +3295,7 @@ func (e *env) getSection12s(c *gin.Context) {
 // @Router /section12/{sectionID} [get]
 func (e *env) getSection12(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -3256,7 +3307,7 @@ func (e *env) getSection12(c *gin.Context) {
 
 	var output GetSection12Output
 
-	output.Section, err = e.db.GetSection12ByID(c.Request.Context(), claims.ID, sectionID)
+	output.Section, err = e.db.GetSection12ByID(c.Request.Context(), principal.UserID, sectionID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -3283,7 +3334,7 @@ func (e *env) getSection12(c *gin.Context) {
 // @Router /section12 [post]
 func (e *env) addSection12(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -3303,7 +3354,7 @@ func (e *env) addSection12(c *gin.Context) {
 	err = e.validator.Struct(input)
 	if err != nil {
 		c.JSON(400, gin.H{
-			"message": ErrMissingFields,
+			"message": validationMessage(err),
 		})
 		return
 	}
@@ -3320,7 +3371,7 @@ func (e *env) addSection12(c *gin.Context) {
 		GenericSectionInfo: db.GenericSectionInfo{
 			Section: 12,
 			Year:    input.Year,
-			UserID:  claims.ID,
+			UserID:  principal.UserID,
 			GenericDatabaseInfo: db.GenericDatabaseInfo{
 				Created: timestamp.String(),
 				Updated: timestamp.String(),
@@ -3358,7 +3409,7 @@ func (e *env) addSection12(c *gin.Context) {
 // @Router /section12/{sectionID} [put]
 func (e *env) updateSection12(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -3380,12 +3431,12 @@ func (e *env) updateSection12(c *gin.Context) {
 	err = e.validator.Struct(input)
 	if err != nil {
 		c.JSON(400, gin.H{
-			"message": ErrMissingFields,
+			"message": validationMessage(err),
 		})
 		return
 	}
 
-	existingSection, err := e.db.GetSection12ByID(c.Request.Context(), claims.ID, sectionID)
+	existingSection, err := e.db.GetSection12ByID(c.Request.Context(), principal.UserID, sectionID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -3405,7 +3456,7 @@ func (e *env) updateSection12(c *gin.Context) {
 		GenericSectionInfo: db.GenericSectionInfo{
 			Section: 12,
 			Year:    input.Year,
-			UserID:  claims.ID,
+			UserID:  principal.UserID,
 			GenericDatabaseInfo: db.GenericDatabaseInfo{
 				Created: existingSection.Created,
 				Updated: timestamp.String(),
@@ -3443,7 +3494,7 @@ type GetSection13Output struct {
 
 type UpsertSection13Input struct {
 	Nickname        string `json:"nickname" validate:"required"`
-	Year            string `json:"year" validate:"required"`
+	Year            string `json:"year" validate:"required,program_year"`
 	RecognitionType string `json:"recognition_type" validate:"required"`
 }
 
@@ -3464,7 +3515,7 @@ type UpsertSection13Output GetSection13Output
 // @Router /section13 [get]
 func (e *env) getSection13s(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -3480,7 +3531,7 @@ func (e *env) getSection13s(c *gin.Context) {
 		SortByNewest: c.GetBool(CONTEXT_KEY_SORT_BY_NEWEST),
 	}
 
-	output.Sections, err = e.db.GetSection13sByUser(c.Request.Context(), claims.ID, paginationOptions)
+	output.Sections, err = e.db.GetSection13sByUser(c.Request.Context(), principal.UserID, paginationOptions)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -3522,7 +3573,7 @@ func (e *env) getSection13s(c *gin.Context) {
 // @Router /section13/{sectionID} [get]
 func (e *env) getSection13(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -3534,7 +3585,7 @@ func (e *env) getSection13(c *gin.Context) {
 
 	var output GetSection13Output
 
-	output.Section, err = e.db.GetSection13ByID(c.Request.Context(), claims.ID, sectionID)
+	output.Section, err = e.db.GetSection13ByID(c.Request.Context(), principal.UserID, sectionID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -3561,7 +3612,7 @@ func (e *env) getSection13(c *gin.Context) {
 // @Router /section13 [post]
 func (e *env) addSection13(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -3581,7 +3632,7 @@ func (e *env) addSection13(c *gin.Context) {
 	err = e.validator.Struct(input)
 	if err != nil {
 		c.JSON(400, gin.H{
-			"message": ErrMissingFields,
+			"message": validationMessage(err),
 		})
 		return
 	}
@@ -3596,7 +3647,7 @@ func (e *env) addSection13(c *gin.Context) {
 		GenericSectionInfo: db.GenericSectionInfo{
 			Section: 13,
 			Year:    input.Year,
-			UserID:  claims.ID,
+			UserID:  principal.UserID,
 			GenericDatabaseInfo: db.GenericDatabaseInfo{
 				Created: timestamp.String(),
 				Updated: timestamp.String(),
@@ -3634,7 +3685,7 @@ func (e *env) addSection13(c *gin.Context) {
 // @Router /section13/{sectionID} [put]
 func (e *env) updateSection13(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -3656,12 +3707,12 @@ func (e *env) updateSection13(c *gin.Context) {
 	err = e.validator.Struct(input)
 	if err != nil {
 		c.JSON(400, gin.H{
-			"message": ErrMissingFields,
+			"message": validationMessage(err),
 		})
 		return
 	}
 
-	existingSection, err := e.db.GetSection13ByID(c.Request.Context(), claims.ID, sectionID)
+	existingSection, err := e.db.GetSection13ByID(c.Request.Context(), principal.UserID, sectionID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -3679,7 +3730,7 @@ func (e *env) updateSection13(c *gin.Context) {
 		GenericSectionInfo: db.GenericSectionInfo{
 			Section: 13,
 			Year:    input.Year,
-			UserID:  claims.ID,
+			UserID:  principal.UserID,
 			GenericDatabaseInfo: db.GenericDatabaseInfo{
 				Created: existingSection.Created,
 				Updated: timestamp.String(),
@@ -3716,7 +3767,7 @@ type GetSection14Output struct {
 
 type UpsertSection14Input struct {
 	Nickname        string `json:"nickname" validate:"required"`
-	Year            string `json:"year" validate:"required"`
+	Year            string `json:"year" validate:"required,program_year"`
 	RecognitionType string `json:"recognition_type" validate:"required"`
 }
 
@@ -3737,7 +3788,7 @@ type UpsertSection14Output GetSection14Output
 // @Router /section14 [get]
 func (e *env) getSection14s(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -3753,7 +3804,7 @@ func (e *env) getSection14s(c *gin.Context) {
 		SortByNewest: c.GetBool(CONTEXT_KEY_SORT_BY_NEWEST),
 	}
 
-	output.Sections, err = e.db.GetSection14sByUser(c.Request.Context(), claims.ID, paginationOptions)
+	output.Sections, err = e.db.GetSection14sByUser(c.Request.Context(), principal.UserID, paginationOptions)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -3795,7 +3846,7 @@ func (e *env) getSection14s(c *gin.Context) {
 // @Router /section14/{sectionID} [get]
 func (e *env) getSection14(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -3807,7 +3858,7 @@ func (e *env) getSection14(c *gin.Context) {
 
 	var output GetSection14Output
 
-	output.Section, err = e.db.GetSection14ByID(c.Request.Context(), claims.ID, sectionID)
+	output.Section, err = e.db.GetSection14ByID(c.Request.Context(), principal.UserID, sectionID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -3834,7 +3885,7 @@ func (e *env) getSection14(c *gin.Context) {
 // @Router /section14 [post]
 func (e *env) addSection14(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -3854,7 +3905,7 @@ func (e *env) addSection14(c *gin.Context) {
 	err = e.validator.Struct(input)
 	if err != nil {
 		c.JSON(400, gin.H{
-			"message": ErrMissingFields,
+			"message": validationMessage(err),
 		})
 		return
 	}
@@ -3869,7 +3920,7 @@ func (e *env) addSection14(c *gin.Context) {
 		GenericSectionInfo: db.GenericSectionInfo{
 			Section: 14,
 			Year:    input.Year,
-			UserID:  claims.ID,
+			UserID:  principal.UserID,
 			GenericDatabaseInfo: db.GenericDatabaseInfo{
 				Created: timestamp.String(),
 				Updated: timestamp.String(),
@@ -3907,7 +3958,7 @@ func (e *env) addSection14(c *gin.Context) {
 // @Router /section14/{sectionID} [put]
 func (e *env) updateSection14(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -3929,12 +3980,12 @@ func (e *env) updateSection14(c *gin.Context) {
 	err = e.validator.Struct(input)
 	if err != nil {
 		c.JSON(400, gin.H{
-			"message": ErrMissingFields,
+			"message": validationMessage(err),
 		})
 		return
 	}
 
-	existingSection, err := e.db.GetSection14ByID(c.Request.Context(), claims.ID, sectionID)
+	existingSection, err := e.db.GetSection14ByID(c.Request.Context(), principal.UserID, sectionID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -3952,7 +4003,7 @@ func (e *env) updateSection14(c *gin.Context) {
 		GenericSectionInfo: db.GenericSectionInfo{
 			Section: 14,
 			Year:    input.Year,
-			UserID:  claims.ID,
+			UserID:  principal.UserID,
 			GenericDatabaseInfo: db.GenericDatabaseInfo{
 				Created: existingSection.Created,
 				Updated: timestamp.String(),
@@ -3993,7 +4044,7 @@ func (e *env) updateSection14(c *gin.Context) {
 // @Router /section/{sectionID} [delete]
 func (e *env) deleteSection(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -4003,7 +4054,7 @@ func (e *env) deleteSection(c *gin.Context) {
 
 	sectionID := c.Param("sectionID")
 
-	response, err := e.db.RemoveSection(c.Request.Context(), claims.ID, sectionID)
+	response, err := e.db.RemoveSection(c.Request.Context(), principal.UserID, sectionID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
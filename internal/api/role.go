@@ -0,0 +1,267 @@
+package api
+
+import (
+	"4h-recordbook-backend/internal/auth"
+	"4h-recordbook-backend/internal/utils"
+	"4h-recordbook-backend/pkg/db"
+	"context"
+
+	"github.com/beevik/guid"
+	"github.com/gin-gonic/gin"
+)
+
+type GetRolesOutput struct {
+	Roles       []auth.Role       `json:"roles"`
+	Permissions []auth.Permission `json:"permissions"`
+}
+
+type GetRoleAssignmentsOutput struct {
+	RoleAssignments []db.RoleAssignment `json:"role_assignments"`
+}
+
+type AddRoleAssignmentInput struct {
+	Role       string `json:"role" validate:"required"`
+	CountyName string `json:"county_name"`
+}
+
+type AddRoleAssignmentOutput struct {
+	RoleAssignment db.RoleAssignment `json:"role_assignment"`
+}
+
+// GetRoles godoc
+// @Summary Get the signed-in user's roles
+// @Description Returns the roles the user holds, from their token scopes and role assignments, and the permissions
+// @Description those roles grant. Users without any assigned role are members.
+// @Tags Roles
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} api.GetRolesOutput
+// @Failure 401
+// @Router /roles [get]
+func (e *env) getRoles(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(200, GetRolesOutput{
+		Roles:       principal.Roles,
+		Permissions: principal.Permissions(),
+	})
+
+}
+
+// GetUserRoleAssignments godoc
+// @Summary Get a user's role assignments
+// @Description Returns the roles that have been assigned to a user. Requires the roles:manage permission.
+// @Tags Roles
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param userID path string true "User ID"
+// @Success 200 {object} api.GetRoleAssignmentsOutput
+// @Failure 401
+// @Failure 403
+// @Router /user/{userID}/roles [get]
+func (e *env) getUserRoleAssignments(c *gin.Context) {
+
+	userID := c.Param("userID")
+
+	var output GetRoleAssignmentsOutput
+	var err error
+
+	output.RoleAssignments, err = e.db.GetRoleAssignmentsByUser(c.Request.Context(), userID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(200, output)
+
+}
+
+// AddUserRoleAssignment godoc
+// @Summary Assigns a role to a user
+// @Description Grants a user one of the roles: member, guardian, leader, agent, judge, admin.
+// @Description Agents and judges must be given the county they work in. Requires the roles:manage permission.
+// @Tags Roles
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param userID path string true "User ID"
+// @Param AddRoleAssignmentInput body api.AddRoleAssignmentInput true "Role information"
+// @Success 201 {object} api.AddRoleAssignmentOutput
+// @Failure 400
+// @Failure 401
+// @Failure 403
+// @Failure 404
+// @Failure 409
+// @Router /user/{userID}/roles [post]
+func (e *env) addUserRoleAssignment(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	var input AddRoleAssignmentInput
+	err = c.BindJSON(&input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrBadRequest,
+		})
+		return
+	}
+
+	err = e.validator.Struct(input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrMissingFields,
+		})
+		return
+	}
+
+	if !auth.IsRole(input.Role) {
+		c.JSON(400, gin.H{
+			"message": ErrBadRole,
+		})
+		return
+	}
+
+	if (auth.Role(input.Role) == auth.ROLE_AGENT || auth.Role(input.Role) == auth.ROLE_JUDGE) && input.CountyName == "" {
+		c.JSON(400, gin.H{
+			"message": ErrMissingRoleCounty,
+		})
+		return
+	}
+
+	userID := c.Param("userID")
+
+	_, err = e.db.GetUser(c.Request.Context(), userID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	existing, err := e.db.GetRoleAssignmentsByUser(c.Request.Context(), userID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	for _, assignment := range existing {
+		if assignment.Role == input.Role && assignment.CountyName == input.CountyName {
+			c.JSON(409, gin.H{
+				"message": ErrRoleConflict,
+			})
+			return
+		}
+	}
+
+	g := guid.New()
+	timestamp := utils.TimeNow()
+
+	assignment := db.RoleAssignment{
+		ID:         g.String(),
+		UserID:     userID,
+		Role:       input.Role,
+		CountyName: input.CountyName,
+		GrantedBy:  principal.UserID,
+		GenericDatabaseInfo: db.GenericDatabaseInfo{
+			Created: timestamp.String(),
+			Updated: timestamp.String(),
+		},
+	}
+
+	var output AddRoleAssignmentOutput
+
+	output.RoleAssignment, err = e.db.UpsertRoleAssignment(c.Request.Context(), assignment)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(201, output)
+
+}
+
+// DeleteUserRoleAssignment godoc
+// @Summary Removes a role from a user
+// @Description Deletes one of a user's role assignments. Requires the roles:manage permission.
+// @Tags Roles
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param userID path string true "User ID"
+// @Param roleAssignmentID path string true "Role assignment ID"
+// @Success 204
+// @Failure 401
+// @Failure 403
+// @Failure 404
+// @Router /user/{userID}/roles/{roleAssignmentID} [delete]
+func (e *env) deleteUserRoleAssignment(c *gin.Context) {
+
+	userID := c.Param("userID")
+	roleAssignmentID := c.Param("roleAssignmentID")
+
+	response, err := e.db.RemoveRoleAssignment(c.Request.Context(), userID, roleAssignmentID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(204, response)
+
+}
+
+// the counties the user has been assigned to as an agent
+func (e *env) agentCounties(ctx context.Context, userID string) ([]string, error) {
+	return e.roleCounties(ctx, userID, auth.ROLE_AGENT)
+}
+
+// the counties the user has been assigned to as a judge
+func (e *env) judgeCounties(ctx context.Context, userID string) ([]string, error) {
+	return e.roleCounties(ctx, userID, auth.ROLE_JUDGE)
+}
+
+// the counties the user has been assigned to in the role
+func (e *env) roleCounties(ctx context.Context, userID string, role auth.Role) ([]string, error) {
+
+	assignments, err := e.db.GetRoleAssignmentsByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	counties := []string{}
+	for _, assignment := range assignments {
+		if auth.Role(assignment.Role) == role && assignment.CountyName != "" {
+			counties = append(counties, assignment.CountyName)
+		}
+	}
+
+	return counties, nil
+
+}
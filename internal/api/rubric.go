@@ -0,0 +1,504 @@
+package api
+
+import (
+	"4h-recordbook-backend/internal/auth"
+	"4h-recordbook-backend/internal/utils"
+	"4h-recordbook-backend/pkg/db"
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/beevik/guid"
+	"github.com/gin-gonic/gin"
+)
+
+// the criteria a rubric scores when it is created without any
+var DEFAULT_RUBRIC_CRITERIA = []db.RubricCriterion{
+	{ID: "completeness", Name: "Completeness", Description: "Every section of the record book is filled in", MaxPoints: 25},
+	{ID: "financials", Name: "Financials", Description: "Expenses, income and inventory are complete and add up", MaxPoints: 25},
+	{ID: "story", Name: "Story", Description: "The project story tells what the member did and learned", MaxPoints: 25},
+	{ID: "resume", Name: "Resume", Description: "The resume shows the member's growth in 4-H", MaxPoints: 25},
+}
+
+type GetRubricsOutput struct {
+	Rubrics []db.Rubric `json:"rubrics"`
+}
+
+type GetRubricOutput struct {
+	Rubric db.Rubric `json:"rubric"`
+}
+
+type RubricCriterionInput struct {
+	ID          string  `json:"id"`
+	Name        string  `json:"name" validate:"required"`
+	Description string  `json:"description"`
+	MaxPoints   float64 `json:"max_points" validate:"gt=0"`
+}
+
+type UpsertRubricInput struct {
+	Name     string                 `json:"name" validate:"required"`
+	Year     string                 `json:"year" validate:"required,program_year"`
+	Criteria []RubricCriterionInput `json:"criteria" validate:"dive"`
+}
+
+type UpsertRubricOutput GetRubricOutput
+
+// whether the principal runs judging in the county, as an agent for it or an admin
+func (e *env) managesCounty(ctx context.Context, principal auth.Principal, countyName string) (bool, error) {
+
+	if principal.HasRole(auth.ROLE_ADMIN) {
+		return true, nil
+	}
+
+	counties, err := e.agentCounties(ctx, principal.UserID)
+	if err != nil {
+		return false, err
+	}
+
+	return slices.ContainsFunc(counties, func(county string) bool {
+		return strings.EqualFold(county, countyName)
+	}), nil
+
+}
+
+// the county in the route, once the principal is known to run judging in it. the response is written when they
+// don't
+func (e *env) routeCounty(c *gin.Context, principal auth.Principal) (string, bool) {
+
+	countyName := c.Param("countyName")
+
+	manages, err := e.managesCounty(c.Request.Context(), principal, countyName)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return "", false
+	}
+
+	if !manages {
+		c.JSON(403, gin.H{
+			"message": ErrNotCountyManager,
+		})
+		return "", false
+	}
+
+	return countyName, true
+
+}
+
+// the rubric's criteria from the input, or the default criteria when there are none. criteria without an ID are
+// given one
+func rubricCriteria(input []RubricCriterionInput) ([]db.RubricCriterion, bool) {
+
+	if len(input) == 0 {
+		return slices.Clone(DEFAULT_RUBRIC_CRITERIA), true
+	}
+
+	criteria := []db.RubricCriterion{}
+	ids := []string{}
+
+	for _, criterion := range input {
+
+		id := criterion.ID
+		if id == "" {
+			id = guid.New().String()
+		}
+
+		if slices.Contains(ids, id) {
+			return nil, false
+		}
+		ids = append(ids, id)
+
+		criteria = append(criteria, db.RubricCriterion{
+			ID:          id,
+			Name:        criterion.Name,
+			Description: criterion.Description,
+			MaxPoints:   criterion.MaxPoints,
+		})
+
+	}
+
+	return criteria, true
+
+}
+
+// whether any judge has been assigned to score against the rubric
+func (e *env) rubricInUse(ctx context.Context, rubric db.Rubric) (bool, error) {
+
+	scoresheets, err := e.db.GetScoresheetsByCounty(ctx, rubric.CountyName, rubric.Year)
+	if err != nil {
+		return false, err
+	}
+
+	return slices.ContainsFunc(scoresheets, func(scoresheet db.Scoresheet) bool {
+		return scoresheet.RubricID == rubric.ID
+	}), nil
+
+}
+
+// GetRubrics godoc
+// @Summary Get a county's rubrics
+// @Description Returns the rubrics the county scores record books against, for one program year or all of them.
+// @Description Available to the agents for the county and admins.
+// @Tags Judging
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param countyName path string true "County name"
+// @Param year query string false "Program year"
+// @Success 200 {object} api.GetRubricsOutput
+// @Failure 400
+// @Failure 401
+// @Failure 403
+// @Router /county/{countyName}/rubrics [get]
+func (e *env) getRubrics(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	year := c.Query("year")
+	if year != "" && !e.programYear.IsValid(year) {
+		c.JSON(400, gin.H{
+			"message": ErrBadProgramYear,
+		})
+		return
+	}
+
+	countyName, ok := e.routeCounty(c, principal)
+	if !ok {
+		return
+	}
+
+	var output GetRubricsOutput
+
+	output.Rubrics, err = e.db.GetRubricsByCounty(c.Request.Context(), countyName, year)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(200, output)
+
+}
+
+// GetRubric godoc
+// @Summary Get a rubric
+// @Description Returns one of the county's rubrics. Available to the agents for the county and admins.
+// @Tags Judging
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param countyName path string true "County name"
+// @Param rubricID path string true "Rubric ID"
+// @Success 200 {object} api.GetRubricOutput
+// @Failure 401
+// @Failure 403
+// @Failure 404
+// @Router /county/{countyName}/rubric/{rubricID} [get]
+func (e *env) getRubric(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	countyName, ok := e.routeCounty(c, principal)
+	if !ok {
+		return
+	}
+
+	var output GetRubricOutput
+
+	output.Rubric, err = e.db.GetRubricByID(c.Request.Context(), countyName, c.Param("rubricID"))
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(200, output)
+
+}
+
+// AddRubric godoc
+// @Summary Add a rubric
+// @Description Adds a rubric for scoring the county's record books in a program year. Each criterion is scored out
+// @Description of its maximum points. Rubrics without criteria score completeness, financials, story and resume out
+// @Description of 25 points each. Available to the agents for the county and admins.
+// @Tags Judging
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param countyName path string true "County name"
+// @Param UpsertRubricInput body api.UpsertRubricInput true "Rubric information"
+// @Success 201 {object} api.UpsertRubricOutput
+// @Failure 400
+// @Failure 401
+// @Failure 403
+// @Router /county/{countyName}/rubrics [post]
+func (e *env) addRubric(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	var input UpsertRubricInput
+	err = c.BindJSON(&input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrBadRequest,
+		})
+		return
+	}
+
+	err = e.validator.Struct(input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": validationMessage(err),
+		})
+		return
+	}
+
+	criteria, ok := rubricCriteria(input.Criteria)
+	if !ok {
+		c.JSON(400, gin.H{
+			"message": ErrBadRubricCriteria,
+		})
+		return
+	}
+
+	countyName, ok := e.routeCounty(c, principal)
+	if !ok {
+		return
+	}
+
+	g := guid.New()
+	timestamp := utils.TimeNow()
+
+	rubric := db.Rubric{
+		ID:         g.String(),
+		CountyName: countyName,
+		Year:       input.Year,
+		Name:       input.Name,
+		Criteria:   criteria,
+		CreatedBy:  principal.UserID,
+		GenericDatabaseInfo: db.GenericDatabaseInfo{
+			Created: timestamp.String(),
+			Updated: timestamp.String(),
+		},
+	}
+
+	var output UpsertRubricOutput
+
+	output.Rubric, err = e.db.UpsertRubric(c.Request.Context(), rubric)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(201, output)
+
+}
+
+// UpdateRubric godoc
+// @Summary Update a rubric
+// @Description Replaces one of the county's rubrics. Rubrics can't be changed once judges have been assigned to
+// @Description score against them. Available to the agents for the county and admins.
+// @Tags Judging
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param countyName path string true "County name"
+// @Param rubricID path string true "Rubric ID"
+// @Param UpsertRubricInput body api.UpsertRubricInput true "Rubric information"
+// @Success 200 {object} api.UpsertRubricOutput
+// @Failure 400
+// @Failure 401
+// @Failure 403
+// @Failure 404
+// @Failure 409
+// @Router /county/{countyName}/rubric/{rubricID} [put]
+func (e *env) updateRubric(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	var input UpsertRubricInput
+	err = c.BindJSON(&input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrBadRequest,
+		})
+		return
+	}
+
+	err = e.validator.Struct(input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": validationMessage(err),
+		})
+		return
+	}
+
+	criteria, ok := rubricCriteria(input.Criteria)
+	if !ok {
+		c.JSON(400, gin.H{
+			"message": ErrBadRubricCriteria,
+		})
+		return
+	}
+
+	countyName, ok := e.routeCounty(c, principal)
+	if !ok {
+		return
+	}
+
+	existingRubric, err := e.db.GetRubricByID(c.Request.Context(), countyName, c.Param("rubricID"))
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	inUse, err := e.rubricInUse(c.Request.Context(), existingRubric)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	if inUse {
+		c.JSON(409, gin.H{
+			"message": ErrRubricInUse,
+		})
+		return
+	}
+
+	timestamp := utils.TimeNow()
+
+	rubric := db.Rubric{
+		ID:         existingRubric.ID,
+		CountyName: existingRubric.CountyName,
+		Year:       input.Year,
+		Name:       input.Name,
+		Criteria:   criteria,
+		CreatedBy:  existingRubric.CreatedBy,
+		GenericDatabaseInfo: db.GenericDatabaseInfo{
+			Created: existingRubric.Created,
+			Updated: timestamp.String(),
+		},
+	}
+
+	var output UpsertRubricOutput
+
+	output.Rubric, err = e.db.UpsertRubric(c.Request.Context(), rubric)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(200, output)
+
+}
+
+// DeleteRubric godoc
+// @Summary Delete a rubric
+// @Description Deletes one of the county's rubrics. Rubrics can't be deleted once judges have been assigned to
+// @Description score against them. Available to the agents for the county and admins.
+// @Tags Judging
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param countyName path string true "County name"
+// @Param rubricID path string true "Rubric ID"
+// @Success 204
+// @Failure 401
+// @Failure 403
+// @Failure 404
+// @Failure 409
+// @Router /county/{countyName}/rubric/{rubricID} [delete]
+func (e *env) deleteRubric(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	countyName, ok := e.routeCounty(c, principal)
+	if !ok {
+		return
+	}
+
+	rubric, err := e.db.GetRubricByID(c.Request.Context(), countyName, c.Param("rubricID"))
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	inUse, err := e.rubricInUse(c.Request.Context(), rubric)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	if inUse {
+		c.JSON(409, gin.H{
+			"message": ErrRubricInUse,
+		})
+		return
+	}
+
+	response, err := e.db.RemoveRubric(c.Request.Context(), countyName, rubric.ID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(204, response)
+
+}
@@ -0,0 +1,707 @@
+package api
+
+import (
+	"4h-recordbook-backend/internal/auth"
+	"4h-recordbook-backend/internal/utils"
+	"4h-recordbook-backend/pkg/db"
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/beevik/guid"
+	"github.com/gin-gonic/gin"
+)
+
+// members whose age doesn't fall in any configured division, or who haven't given their birthdate, are placed
+// against each other
+const AGE_DIVISION_OPEN = "open"
+
+type GetScoresheetsOutput struct {
+	Scoresheets []db.Scoresheet `json:"scoresheets"`
+}
+
+type GetScoresheetOutput struct {
+	Scoresheet db.Scoresheet `json:"scoresheet"`
+}
+
+type GetJudgeScoresheetOutput struct {
+	Scoresheet db.Scoresheet `json:"scoresheet"`
+	Rubric     db.Rubric     `json:"rubric"`
+}
+
+type AssignScoresheetInput struct {
+	ClubID       string `json:"club_id" validate:"required"`
+	MembershipID string `json:"membership_id" validate:"required"`
+	JudgeID      string `json:"judge_id" validate:"required"`
+	RubricID     string `json:"rubric_id" validate:"required"`
+}
+
+type CriterionScoreInput struct {
+	CriterionID string  `json:"criterion_id" validate:"required"`
+	Points      float64 `json:"points" validate:"gte=0"`
+	Comment     string  `json:"comment"`
+}
+
+type ScoreScoresheetInput struct {
+	Scores  []CriterionScoreInput `json:"scores" validate:"required,dive"`
+	Comment string                `json:"comment"`
+}
+
+// the division a member with the birthdate is placed in for the program year, by their age on its first day
+func (e *env) ageDivision(birthdate string, year string) string {
+
+	birth, err := utils.StringToDate(birthdate, e.timeZone)
+	if err != nil {
+		return AGE_DIVISION_OPEN
+	}
+
+	start, _, err := e.programYear.Bounds(year)
+	if err != nil {
+		return AGE_DIVISION_OPEN
+	}
+
+	age := birth.YearsTo(utils.Date(start))
+
+	for _, division := range e.config.AgeDivisions {
+		if age >= division.MinAge && age <= division.MaxAge {
+			return division.Name
+		}
+	}
+
+	return AGE_DIVISION_OPEN
+
+}
+
+// whether the user has been assigned to the county as a judge
+func (e *env) judgesCounty(ctx context.Context, userID string, countyName string) (bool, error) {
+
+	counties, err := e.judgeCounties(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	return slices.ContainsFunc(counties, func(county string) bool {
+		return strings.EqualFold(county, countyName)
+	}), nil
+
+}
+
+// reads the scoresheet in the route, which must have been assigned to the principal. the response is written when
+// it can't be read or belongs to another judge
+func (e *env) routeJudgeScoresheet(c *gin.Context, principal auth.Principal) (db.Scoresheet, bool) {
+
+	scoresheet, err := e.db.GetScoresheetByID(c.Request.Context(), c.Param("countyName"), c.Param("scoresheetID"))
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return db.Scoresheet{}, false
+	}
+
+	if scoresheet.JudgeID != principal.UserID {
+		c.JSON(403, gin.H{
+			"message": ErrNotScoresheetJudge,
+		})
+		return db.Scoresheet{}, false
+	}
+
+	return scoresheet, true
+
+}
+
+// GetCountyScoresheets godoc
+// @Summary Get a county's scoresheets
+// @Description Returns every judge's scoresheet for the county's record books in a program year, the current one by
+// @Description default. Available to the agents for the county and admins.
+// @Tags Judging
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param countyName path string true "County name"
+// @Param year query string false "Program year"
+// @Success 200 {object} api.GetScoresheetsOutput
+// @Failure 400
+// @Failure 401
+// @Failure 403
+// @Router /county/{countyName}/scoresheets [get]
+func (e *env) getCountyScoresheets(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	location := e.userLocation(c.Request.Context(), principal.UserID)
+	year := ternary(c.Query("year"), e.programYear.Current(location))
+	if !e.programYear.IsValid(year) {
+		c.JSON(400, gin.H{
+			"message": ErrBadProgramYear,
+		})
+		return
+	}
+
+	countyName, ok := e.routeCounty(c, principal)
+	if !ok {
+		return
+	}
+
+	var output GetScoresheetsOutput
+
+	output.Scoresheets, err = e.db.GetScoresheetsByCounty(c.Request.Context(), countyName, year)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(200, output)
+
+}
+
+// AssignScoresheet godoc
+// @Summary Assign a judge to a record book
+// @Description Assigns one of the county's judges to score a submitted record book against a rubric for its
+// @Description program year. A record book can have several judges, who all score it against the same rubric. The
+// @Description member's age division is fixed when their first judge is assigned. Available to the agents for the
+// @Description county and admins.
+// @Tags Judging
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param countyName path string true "County name"
+// @Param AssignScoresheetInput body api.AssignScoresheetInput true "Assignment information"
+// @Success 201 {object} api.GetScoresheetOutput
+// @Failure 400
+// @Failure 401
+// @Failure 403
+// @Failure 404
+// @Failure 409
+// @Router /county/{countyName}/scoresheets [post]
+func (e *env) assignScoresheet(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	var input AssignScoresheetInput
+	err = c.BindJSON(&input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrBadRequest,
+		})
+		return
+	}
+
+	err = e.validator.Struct(input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrMissingFields,
+		})
+		return
+	}
+
+	countyName, ok := e.routeCounty(c, principal)
+	if !ok {
+		return
+	}
+
+	club, err := e.db.GetClubByID(c.Request.Context(), input.ClubID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	if !strings.EqualFold(club.CountyName, countyName) {
+		c.JSON(400, gin.H{
+			"message": ErrClubNotInCounty,
+		})
+		return
+	}
+
+	membership, err := e.db.GetMembershipByID(c.Request.Context(), club.ID, input.MembershipID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	if membership.Role != db.MEMBERSHIP_ROLE_MEMBER || membership.Status != db.MEMBERSHIP_STATUS_APPROVED {
+		c.JSON(404, gin.H{
+			"message": ErrNotFound,
+		})
+		return
+	}
+
+	if input.JudgeID == membership.UserID {
+		c.JSON(400, gin.H{
+			"message": ErrOwnScoresheet,
+		})
+		return
+	}
+
+	submission, err := e.submissionForYear(c.Request.Context(), membership.UserID, membership.Year)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	if submission.Status != db.SUBMISSION_STATUS_SUBMITTED {
+		c.JSON(409, gin.H{
+			"message": ErrSubmissionNotSubmitted,
+		})
+		return
+	}
+
+	rubric, err := e.db.GetRubricByID(c.Request.Context(), countyName, input.RubricID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	if rubric.Year != membership.Year {
+		c.JSON(400, gin.H{
+			"message": ErrRubricYear,
+		})
+		return
+	}
+
+	judges, err := e.judgesCounty(c.Request.Context(), input.JudgeID, countyName)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	if !judges {
+		c.JSON(400, gin.H{
+			"message": ErrNotCountyJudge,
+		})
+		return
+	}
+
+	existing, err := e.db.GetScoresheetsByCounty(c.Request.Context(), countyName, membership.Year)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	member, err := e.db.GetUser(c.Request.Context(), membership.UserID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	division := e.ageDivision(member.Birthdate, membership.Year)
+
+	for _, scoresheet := range existing {
+		if scoresheet.MemberID != membership.UserID {
+			continue
+		}
+		if scoresheet.JudgeID == input.JudgeID {
+			c.JSON(409, gin.H{
+				"message": ErrScoresheetConflict,
+			})
+			return
+		}
+		if scoresheet.RubricID != rubric.ID {
+			c.JSON(409, gin.H{
+				"message": ErrRubricMismatch,
+			})
+			return
+		}
+		division = scoresheet.Division
+	}
+
+	judge, err := e.db.GetUser(c.Request.Context(), input.JudgeID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	g := guid.New()
+	timestamp := utils.TimeNow()
+
+	scoresheet := db.Scoresheet{
+		ID:           g.String(),
+		CountyName:   countyName,
+		Year:         membership.Year,
+		RubricID:     rubric.ID,
+		MemberID:     membership.UserID,
+		MemberName:   membership.UserName,
+		ClubID:       club.ID,
+		ClubName:     club.Name,
+		MembershipID: membership.ID,
+		Division:     division,
+		JudgeID:      judge.ID,
+		JudgeName:    membershipName(judge),
+		AssignedBy:   principal.UserID,
+		Status:       db.SCORESHEET_STATUS_ASSIGNED,
+		Scores:       []db.CriterionScore{},
+		GenericDatabaseInfo: db.GenericDatabaseInfo{
+			Created: timestamp.String(),
+			Updated: timestamp.String(),
+		},
+	}
+
+	var output GetScoresheetOutput
+
+	output.Scoresheet, err = e.db.UpsertScoresheet(c.Request.Context(), scoresheet)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(201, output)
+
+}
+
+// DeleteScoresheet godoc
+// @Summary Unassign a judge from a record book
+// @Description Deletes a judge's scoresheet, along with any scores they have given. Available to the agents for the
+// @Description county and admins.
+// @Tags Judging
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param countyName path string true "County name"
+// @Param scoresheetID path string true "Scoresheet ID"
+// @Success 204
+// @Failure 401
+// @Failure 403
+// @Failure 404
+// @Router /county/{countyName}/scoresheet/{scoresheetID} [delete]
+func (e *env) deleteScoresheet(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	countyName, ok := e.routeCounty(c, principal)
+	if !ok {
+		return
+	}
+
+	response, err := e.db.RemoveScoresheet(c.Request.Context(), countyName, c.Param("scoresheetID"))
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(204, response)
+
+}
+
+// GetJudgeScoresheets godoc
+// @Summary Get a judge's scoresheets
+// @Description Returns the scoresheets assigned to the signed-in judge in every county they judge for
+// @Tags Judging
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} api.GetScoresheetsOutput
+// @Failure 401
+// @Failure 403
+// @Router /judging/scoresheets [get]
+func (e *env) getJudgeScoresheets(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	counties, err := e.judgeCounties(c.Request.Context(), principal.UserID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	output := GetScoresheetsOutput{
+		Scoresheets: []db.Scoresheet{},
+	}
+
+	for _, countyName := range counties {
+
+		scoresheets, err := e.db.GetScoresheetsByJudge(c.Request.Context(), countyName, principal.UserID)
+		if err != nil {
+			response := InterpretCosmosError(err)
+			c.JSON(response.Code, gin.H{
+				"message": response.Message,
+			})
+			return
+		}
+
+		output.Scoresheets = append(output.Scoresheets, scoresheets...)
+
+	}
+
+	c.JSON(200, output)
+
+}
+
+// GetJudgeScoresheet godoc
+// @Summary Get one of a judge's scoresheets
+// @Description Returns a scoresheet assigned to the signed-in judge and the rubric it is scored against
+// @Tags Judging
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param countyName path string true "County name"
+// @Param scoresheetID path string true "Scoresheet ID"
+// @Success 200 {object} api.GetJudgeScoresheetOutput
+// @Failure 401
+// @Failure 403
+// @Failure 404
+// @Router /judging/scoresheets/{countyName}/{scoresheetID} [get]
+func (e *env) getJudgeScoresheet(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	scoresheet, ok := e.routeJudgeScoresheet(c, principal)
+	if !ok {
+		return
+	}
+
+	output := GetJudgeScoresheetOutput{
+		Scoresheet: scoresheet,
+	}
+
+	output.Rubric, err = e.db.GetRubricByID(c.Request.Context(), scoresheet.CountyName, scoresheet.RubricID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(200, output)
+
+}
+
+// GetJudgeScoresheetResume godoc
+// @Summary Get the record book a judge is scoring
+// @Description Returns the member's resume for the program year of a scoresheet assigned to the signed-in judge
+// @Tags Judging
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param countyName path string true "County name"
+// @Param scoresheetID path string true "Scoresheet ID"
+// @Success 200 {object} api.GetResumeOutput
+// @Failure 401
+// @Failure 403
+// @Failure 404
+// @Router /judging/scoresheets/{countyName}/{scoresheetID}/resume [get]
+func (e *env) getJudgeScoresheetResume(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	scoresheet, ok := e.routeJudgeScoresheet(c, principal)
+	if !ok {
+		return
+	}
+
+	var output GetResumeOutput
+
+	output.Resume, err = e.db.GetResume(c.Request.Context(), scoresheet.MemberID, scoresheet.Year)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(200, output)
+
+}
+
+// ScoreScoresheet godoc
+// @Summary Score a record book
+// @Description Saves the signed-in judge's points and comments for every criterion of the rubric, and an overall
+// @Description comment. The scores can be changed until the county publishes its results.
+// @Tags Judging
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param countyName path string true "County name"
+// @Param scoresheetID path string true "Scoresheet ID"
+// @Param ScoreScoresheetInput body api.ScoreScoresheetInput true "Scores"
+// @Success 200 {object} api.GetScoresheetOutput
+// @Failure 400
+// @Failure 401
+// @Failure 403
+// @Failure 404
+// @Failure 409
+// @Router /judging/scoresheets/{countyName}/{scoresheetID} [put]
+func (e *env) scoreScoresheet(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	var input ScoreScoresheetInput
+	err = c.BindJSON(&input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrBadRequest,
+		})
+		return
+	}
+
+	err = e.validator.Struct(input)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"message": ErrMissingFields,
+		})
+		return
+	}
+
+	scoresheet, ok := e.routeJudgeScoresheet(c, principal)
+	if !ok {
+		return
+	}
+
+	rubric, err := e.db.GetRubricByID(c.Request.Context(), scoresheet.CountyName, scoresheet.RubricID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	scores := []db.CriterionScore{}
+	total := 0.0
+
+	for _, criterion := range rubric.Criteria {
+
+		i := slices.IndexFunc(input.Scores, func(score CriterionScoreInput) bool {
+			return score.CriterionID == criterion.ID
+		})
+		if i < 0 || input.Scores[i].Points > criterion.MaxPoints {
+			c.JSON(400, gin.H{
+				"message": ErrBadScores,
+			})
+			return
+		}
+
+		scores = append(scores, db.CriterionScore{
+			CriterionID: criterion.ID,
+			Points:      input.Scores[i].Points,
+			Comment:     input.Scores[i].Comment,
+		})
+		total += input.Scores[i].Points
+
+	}
+
+	if len(input.Scores) != len(rubric.Criteria) {
+		c.JSON(400, gin.H{
+			"message": ErrBadScores,
+		})
+		return
+	}
+
+	submission, err := e.submissionForYear(c.Request.Context(), scoresheet.MemberID, scoresheet.Year)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	if submission.Status != db.SUBMISSION_STATUS_SUBMITTED {
+		c.JSON(409, gin.H{
+			"message": ErrScoresheetClosed,
+		})
+		return
+	}
+
+	timestamp := utils.TimeNow()
+
+	scoresheet.Scores = scores
+	scoresheet.Comment = input.Comment
+	scoresheet.Total = total
+	scoresheet.Status = db.SCORESHEET_STATUS_SCORED
+	scoresheet.ScoredAt = timestamp.String()
+	scoresheet.Updated = timestamp.String()
+
+	var output GetScoresheetOutput
+
+	output.Scoresheet, err = e.db.UpsertScoresheet(c.Request.Context(), scoresheet)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(200, output)
+
+}
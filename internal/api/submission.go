@@ -0,0 +1,768 @@
+package api
+
+import (
+	"4h-recordbook-backend/internal/auth"
+	"4h-recordbook-backend/internal/utils"
+	"4h-recordbook-backend/pkg/db"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+var errSubmissionTransition = errors.New(ErrSubmissionTransition)
+
+// routes that write outside the year of the records they name. a rollover only reads the locked year's project and
+// writes into the next year
+var recordLockExemptRoutes = []string{
+	"/project/:projectID/rollover",
+}
+
+// request body fields naming a record, and the route param that names the same kind of record
+var recordLockBodyFields = map[string]string{
+	"project_id":  "projectID",
+	"animal_id":   "animalID",
+	"dam_id":      "animalID",
+	"breeding_id": "breedingID",
+	"feed_id":     "feedID",
+}
+
+type GetSubmissionsOutput struct {
+	Submissions []db.Submission `json:"submissions"`
+}
+
+type GetSubmissionOutput struct {
+	Submission db.Submission `json:"submission"`
+}
+
+type ClubSubmission struct {
+	MembershipID string        `json:"membership_id"`
+	UserName     string        `json:"user_name"`
+	Submission   db.Submission `json:"submission"`
+}
+
+type GetClubSubmissionsOutput struct {
+	Submissions []ClubSubmission `json:"submissions"`
+}
+
+// the member's record book for the year. record books that nobody has signed yet are drafts, whether or not they
+// have been saved
+func (e *env) submissionForYear(ctx context.Context, userID string, year string) (db.Submission, error) {
+
+	submission, err := e.db.GetSubmissionByYear(ctx, userID, year)
+	if err == nil {
+		return submission, nil
+	}
+	if InterpretCosmosError(err).Code != 404 {
+		return db.Submission{}, err
+	}
+
+	timestamp := utils.TimeNow()
+
+	return db.Submission{
+		ID:      year,
+		UserID:  userID,
+		Year:    year,
+		Status:  db.SUBMISSION_STATUS_DRAFT,
+		History: []db.SubmissionEvent{},
+		GenericDatabaseInfo: db.GenericDatabaseInfo{
+			Created: timestamp.String(),
+			Updated: timestamp.String(),
+		},
+	}, nil
+
+}
+
+// moves the record book to the status and records who moved it
+func (e *env) moveSubmission(ctx context.Context, submission db.Submission, status string, actorID string, actorName string, actorRole auth.Role) (db.Submission, error) {
+
+	if !submission.CanMoveTo(status) {
+		return submission, errSubmissionTransition
+	}
+
+	timestamp := utils.TimeNow()
+
+	submission.History = append(submission.History, db.SubmissionEvent{
+		From:      submission.Status,
+		To:        status,
+		ActorID:   actorID,
+		ActorName: actorName,
+		ActorRole: string(actorRole),
+		At:        timestamp.String(),
+	})
+	submission.Status = status
+	submission.Updated = timestamp.String()
+
+	return e.db.UpsertSubmission(ctx, submission)
+
+}
+
+func respondSubmissionError(c *gin.Context, err error) {
+	if errors.Is(err, errSubmissionTransition) {
+		c.JSON(409, gin.H{
+			"message": ErrSubmissionTransition,
+		})
+		return
+	}
+	response := InterpretCosmosError(err)
+	c.JSON(response.Code, gin.H{
+		"message": response.Message,
+	})
+}
+
+// whether the principal judges the club's record books, as an agent for its county or an admin
+func (e *env) judgesClub(ctx context.Context, principal auth.Principal, club db.Club) (bool, error) {
+
+	if principal.HasRole(auth.ROLE_ADMIN) {
+		return true, nil
+	}
+
+	counties, err := e.agentCounties(ctx, principal.UserID)
+	if err != nil {
+		return false, err
+	}
+
+	return slices.ContainsFunc(counties, func(county string) bool {
+		return strings.EqualFold(county, club.CountyName)
+	}), nil
+
+}
+
+// reads the membership in the route, which must be an approved member of the club, and the member's record book for
+// the membership's year. the response is written when either can't be read
+func (e *env) routeMemberSubmission(c *gin.Context, club db.Club) (db.Membership, db.Submission, bool) {
+
+	membership, err := e.db.GetMembershipByID(c.Request.Context(), club.ID, c.Param("membershipID"))
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return db.Membership{}, db.Submission{}, false
+	}
+
+	if membership.Role != db.MEMBERSHIP_ROLE_MEMBER || membership.Status != db.MEMBERSHIP_STATUS_APPROVED {
+		c.JSON(404, gin.H{
+			"message": ErrNotFound,
+		})
+		return db.Membership{}, db.Submission{}, false
+	}
+
+	submission, err := e.submissionForYear(c.Request.Context(), membership.UserID, membership.Year)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return db.Membership{}, db.Submission{}, false
+	}
+
+	return membership, submission, true
+
+}
+
+// the project that the record named by a route param belongs to
+func (e *env) recordProjectID(ctx context.Context, userID string, param string, id string) (string, error) {
+	switch param {
+	case "projectID":
+		return id, nil
+	case "animalID":
+		record, err := e.db.GetAnimalByID(ctx, userID, id)
+		return record.ProjectID, err
+	case "feedID":
+		record, err := e.db.GetFeedByID(ctx, userID, id)
+		return record.ProjectID, err
+	case "feedPurchaseID":
+		record, err := e.db.GetFeedPurchaseByID(ctx, userID, id)
+		return record.ProjectID, err
+	case "dailyFeedID":
+		record, err := e.db.GetDailyFeedByID(ctx, userID, id)
+		return record.ProjectID, err
+	case "expenseID":
+		record, err := e.db.GetExpenseByID(ctx, userID, id)
+		return record.ProjectID, err
+	case "supplyID":
+		record, err := e.db.GetSupplyByID(ctx, userID, id)
+		return record.ProjectID, err
+	case "healthRecordID":
+		record, err := e.db.GetHealthRecordByID(ctx, userID, id)
+		if err != nil {
+			return "", err
+		}
+		return e.recordProjectID(ctx, userID, "animalID", record.AnimalID)
+	case "breedingID":
+		record, err := e.db.GetBreedingByID(ctx, userID, id)
+		return record.ProjectID, err
+	case "birthID":
+		record, err := e.db.GetBirthByID(ctx, userID, id)
+		return record.ProjectID, err
+	case "activityLogID":
+		record, err := e.db.GetActivityLogByID(ctx, userID, id)
+		return record.ProjectID, err
+	case "exhibitID":
+		record, err := e.db.GetExhibitByID(ctx, userID, id)
+		return record.ProjectID, err
+	case "projectGoalID":
+		record, err := e.db.GetProjectGoalByID(ctx, userID, id)
+		return record.ProjectID, err
+	case "projectBudgetItemID":
+		record, err := e.db.GetProjectBudgetItemByID(ctx, userID, id)
+		return record.ProjectID, err
+	}
+	return "", nil
+}
+
+// the program year of the record named by a route param
+func (e *env) recordYear(ctx context.Context, userID string, param string, id string) (string, error) {
+
+	if id == "" {
+		return "", nil
+	}
+
+	if param == "sectionID" {
+		section, err := e.db.GetSectionSummaryByID(ctx, userID, id)
+		return section.Year, err
+	}
+
+	projectID, err := e.recordProjectID(ctx, userID, param, id)
+	if err != nil || projectID == "" {
+		return "", err
+	}
+
+	project, err := e.db.GetProjectByID(ctx, userID, projectID)
+	return project.Year, err
+
+}
+
+type bodyRecordField struct {
+	name  string
+	value string
+}
+
+// the years and record IDs anywhere in a decoded request body, including in nested objects and arrays such as the
+// rows of a batch
+func bodyRecordFields(decoded interface{}) []bodyRecordField {
+
+	fields := []bodyRecordField{}
+
+	switch value := decoded.(type) {
+	case map[string]interface{}:
+		for name, child := range value {
+			id, ok := child.(string)
+			if ok && id != "" && (name == "year" || recordLockBodyFields[name] != "") {
+				fields = append(fields, bodyRecordField{name: name, value: id})
+				continue
+			}
+			fields = append(fields, bodyRecordFields(child)...)
+		}
+	case []interface{}:
+		for _, child := range value {
+			fields = append(fields, bodyRecordFields(child)...)
+		}
+	}
+
+	return fields
+
+}
+
+// the program years a write request touches: the years of the records named in its route and body, and any year in
+// its body, at any depth. records that can't be found are left to the handler to report
+func (e *env) requestYears(c *gin.Context, userID string) ([]string, error) {
+
+	years := []string{}
+	addYear := func(year string, err error) error {
+		if err != nil {
+			if InterpretCosmosError(err).Code == 404 {
+				return nil
+			}
+			return err
+		}
+		if year != "" && !slices.Contains(years, year) {
+			years = append(years, year)
+		}
+		return nil
+	}
+
+	for _, param := range c.Params {
+		err := addYear(e.recordYear(c.Request.Context(), userID, param.Key, param.Value))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if c.Request.Body == nil {
+		return years, nil
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return nil, err
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	var decoded interface{}
+	if json.Unmarshal(body, &decoded) != nil {
+		return years, nil
+	}
+
+	for _, field := range bodyRecordFields(decoded) {
+		if field.name == "year" {
+			_ = addYear(field.value, nil)
+			continue
+		}
+		err := addYear(e.recordYear(c.Request.Context(), userID, recordLockBodyFields[field.name], field.value))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return years, nil
+
+}
+
+// rejects changes to a member's records for any program year whose record book has been submitted. reads are never
+// locked, and the years a request touches are only worked out when the member has a locked year
+func (e *env) RecordLockMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead || slices.Contains(recordLockExemptRoutes, c.FullPath()) {
+			c.Next()
+			return
+		}
+
+		principal, err := auth.FromContext(c)
+		if err != nil {
+			c.AbortWithStatusJSON(401, gin.H{
+				"message": err.Error(),
+			})
+			return
+		}
+
+		submissions, err := e.db.GetSubmissionsByUser(c.Request.Context(), principal.UserID)
+		if err != nil {
+			response := InterpretCosmosError(err)
+			c.AbortWithStatusJSON(response.Code, gin.H{
+				"message": response.Message,
+			})
+			return
+		}
+
+		locked := []string{}
+		for _, submission := range submissions {
+			if submission.IsLocked() {
+				locked = append(locked, submission.Year)
+			}
+		}
+
+		if len(locked) == 0 {
+			c.Next()
+			return
+		}
+
+		years, err := e.requestYears(c, principal.UserID)
+		if err != nil {
+			response := InterpretCosmosError(err)
+			c.AbortWithStatusJSON(response.Code, gin.H{
+				"message": response.Message,
+			})
+			return
+		}
+
+		for _, year := range years {
+			if slices.Contains(locked, year) {
+				c.AbortWithStatusJSON(409, gin.H{
+					"message": ErrRecordsLocked,
+				})
+				return
+			}
+		}
+
+		c.Next()
+
+	}
+}
+
+// GetSubmissions godoc
+// @Summary Get a member's record books
+// @Description Returns the member's record books that have been through any of the sign-off workflow, by year
+// @Tags Submissions
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} api.GetSubmissionsOutput
+// @Failure 401
+// @Router /submissions [get]
+func (e *env) getSubmissions(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	var output GetSubmissionsOutput
+
+	output.Submissions, err = e.db.GetSubmissionsByUser(c.Request.Context(), principal.UserID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(200, output)
+
+}
+
+// GetSubmission godoc
+// @Summary Get a member's record book for a year
+// @Description Returns the status of the member's record book for a program year and who has signed it
+// @Tags Submissions
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param year path string true "Program year"
+// @Success 200 {object} api.GetSubmissionOutput
+// @Failure 400
+// @Failure 401
+// @Router /submission/{year} [get]
+func (e *env) getSubmission(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	year := c.Param("year")
+	if !e.programYear.IsValid(year) {
+		c.JSON(400, gin.H{
+			"message": ErrBadProgramYear,
+		})
+		return
+	}
+
+	var output GetSubmissionOutput
+
+	output.Submission, err = e.submissionForYear(c.Request.Context(), principal.UserID, year)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	c.JSON(200, output)
+
+}
+
+// SignSubmission godoc
+// @Summary Signs a member's record book
+// @Description Signs the member's record book for a program year. The member signs their draft first, and then a
+// @Description linked guardian acting for them with the X-Acting-As header signs it as their parent.
+// @Tags Submissions
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param year path string true "Program year"
+// @Success 200 {object} api.GetSubmissionOutput
+// @Failure 400
+// @Failure 401
+// @Failure 409
+// @Router /submission/{year}/sign [post]
+func (e *env) signSubmission(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	year := c.Param("year")
+	if !e.programYear.IsValid(year) {
+		c.JSON(400, gin.H{
+			"message": ErrBadProgramYear,
+		})
+		return
+	}
+
+	submission, err := e.submissionForYear(c.Request.Context(), principal.UserID, year)
+	if err != nil {
+		respondSubmissionError(c, err)
+		return
+	}
+
+	var output GetSubmissionOutput
+
+	if principal.IsActing() {
+		output.Submission, err = e.moveSubmission(c.Request.Context(), submission, db.SUBMISSION_STATUS_PARENT_SIGNED, principal.ActorID, principal.ActorName, auth.ROLE_GUARDIAN)
+	} else {
+		output.Submission, err = e.moveSubmission(c.Request.Context(), submission, db.SUBMISSION_STATUS_MEMBER_SIGNED, principal.UserID, principal.Name, auth.ROLE_MEMBER)
+	}
+	if err != nil {
+		respondSubmissionError(c, err)
+		return
+	}
+
+	c.JSON(200, output)
+
+}
+
+// GetClubSubmissions godoc
+// @Summary Get a club's record books
+// @Description Returns the record book of each approved member of the club for a program year, the current one by
+// @Description default. Available to the club's leaders, the agents for its county and admins.
+// @Tags Submissions
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param clubID path string true "Club ID"
+// @Param year query string false "Program year"
+// @Success 200 {object} api.GetClubSubmissionsOutput
+// @Failure 400
+// @Failure 401
+// @Failure 403
+// @Failure 404
+// @Router /club/{clubID}/submissions [get]
+func (e *env) getClubSubmissions(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	location := e.userLocation(c.Request.Context(), principal.UserID)
+	year := ternary(c.Query("year"), e.programYear.Current(location))
+	if !e.programYear.IsValid(year) {
+		c.JSON(400, gin.H{
+			"message": ErrBadProgramYear,
+		})
+		return
+	}
+
+	club, ok := e.routeClub(c, principal, false, year)
+	if !ok {
+		return
+	}
+
+	roster, err := e.db.GetMembershipsByClub(c.Request.Context(), club.ID, year)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	output := GetClubSubmissionsOutput{
+		Submissions: []ClubSubmission{},
+	}
+
+	for _, membership := range roster {
+
+		if membership.Role != db.MEMBERSHIP_ROLE_MEMBER || membership.Status != db.MEMBERSHIP_STATUS_APPROVED {
+			continue
+		}
+
+		submission, err := e.submissionForYear(c.Request.Context(), membership.UserID, year)
+		if err != nil {
+			response := InterpretCosmosError(err)
+			c.JSON(response.Code, gin.H{
+				"message": response.Message,
+			})
+			return
+		}
+
+		output.Submissions = append(output.Submissions, ClubSubmission{
+			MembershipID: membership.ID,
+			UserName:     membership.UserName,
+			Submission:   submission,
+		})
+
+	}
+
+	c.JSON(200, output)
+
+}
+
+// moves the record book of the member in the route for one of the club's leaders
+func (e *env) leaderMoveSubmission(c *gin.Context, status string) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	club, _, ok := e.routeClubMembership(c, principal)
+	if !ok {
+		return
+	}
+
+	_, submission, ok := e.routeMemberSubmission(c, club)
+	if !ok {
+		return
+	}
+
+	var output GetSubmissionOutput
+
+	output.Submission, err = e.moveSubmission(c.Request.Context(), submission, status, principal.UserID, principal.Name, auth.ROLE_LEADER)
+	if err != nil {
+		respondSubmissionError(c, err)
+		return
+	}
+
+	c.JSON(200, output)
+
+}
+
+// ApproveSubmission godoc
+// @Summary Approves a member's record book
+// @Description Approves the record book of one of the club's members once their parent has signed it.
+// @Description Only the club's leaders can approve record books.
+// @Tags Submissions
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param clubID path string true "Club ID"
+// @Param membershipID path string true "Membership ID"
+// @Success 200 {object} api.GetSubmissionOutput
+// @Failure 401
+// @Failure 403
+// @Failure 404
+// @Failure 409
+// @Router /club/{clubID}/members/{membershipID}/submission/approve [post]
+func (e *env) approveSubmission(c *gin.Context) {
+	e.leaderMoveSubmission(c, db.SUBMISSION_STATUS_LEADER_APPROVED)
+}
+
+// SubmitSubmission godoc
+// @Summary Submits a member's record book to the county
+// @Description Submits an approved record book to the county, after which the member's records for that year are
+// @Description locked. Only the club's leaders can submit record books.
+// @Tags Submissions
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param clubID path string true "Club ID"
+// @Param membershipID path string true "Membership ID"
+// @Success 200 {object} api.GetSubmissionOutput
+// @Failure 401
+// @Failure 403
+// @Failure 404
+// @Failure 409
+// @Router /club/{clubID}/members/{membershipID}/submission/submit [post]
+func (e *env) submitSubmission(c *gin.Context) {
+	e.leaderMoveSubmission(c, db.SUBMISSION_STATUS_SUBMITTED)
+}
+
+// ReopenSubmission godoc
+// @Summary Reopens a member's record book
+// @Description Returns a signed, approved or submitted record book to a draft, which unlocks the member's records
+// @Description for that year. It must then be signed again. Only the club's leaders can reopen record books.
+// @Tags Submissions
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param clubID path string true "Club ID"
+// @Param membershipID path string true "Membership ID"
+// @Success 200 {object} api.GetSubmissionOutput
+// @Failure 401
+// @Failure 403
+// @Failure 404
+// @Failure 409
+// @Router /club/{clubID}/members/{membershipID}/submission/reopen [post]
+func (e *env) reopenSubmission(c *gin.Context) {
+	e.leaderMoveSubmission(c, db.SUBMISSION_STATUS_DRAFT)
+}
+
+// JudgeSubmission godoc
+// @Summary Marks a member's record book as judged
+// @Description Marks a submitted record book as judged. Only the agents for the club's county and admins can.
+// @Tags Submissions
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param clubID path string true "Club ID"
+// @Param membershipID path string true "Membership ID"
+// @Success 200 {object} api.GetSubmissionOutput
+// @Failure 401
+// @Failure 403
+// @Failure 404
+// @Failure 409
+// @Router /club/{clubID}/members/{membershipID}/submission/judge [post]
+func (e *env) judgeSubmission(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	club, err := e.db.GetClubByID(c.Request.Context(), c.Param("clubID"))
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	judges, err := e.judgesClub(c.Request.Context(), principal, club)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	if !judges {
+		c.JSON(403, gin.H{
+			"message": ErrNotClubJudge,
+		})
+		return
+	}
+
+	_, submission, ok := e.routeMemberSubmission(c, club)
+	if !ok {
+		return
+	}
+
+	actorRole := auth.ROLE_AGENT
+	if principal.HasRole(auth.ROLE_ADMIN) {
+		actorRole = auth.ROLE_ADMIN
+	}
+
+	var output GetSubmissionOutput
+
+	output.Submission, err = e.moveSubmission(c.Request.Context(), submission, db.SUBMISSION_STATUS_JUDGED, principal.UserID, principal.Name, actorRole)
+	if err != nil {
+		respondSubmissionError(c, err)
+		return
+	}
+
+	c.JSON(200, output)
+
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"4h-recordbook-backend/internal/auth"
+	"4h-recordbook-backend/pkg/db"
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// a member whose 2024-2025 record book has been submitted, with a project in that year and one in the next
+type lockedYearDb struct {
+	db.Db
+}
+
+func (lockedYearDb) GetSubmissionsByUser(ctx context.Context, userID string) ([]db.Submission, error) {
+	return []db.Submission{
+		{ID: "2024-2025", UserID: userID, Year: "2024-2025", Status: db.SUBMISSION_STATUS_SUBMITTED},
+		{ID: "2025-2026", UserID: userID, Year: "2025-2026", Status: db.SUBMISSION_STATUS_MEMBER_SIGNED},
+	}, nil
+}
+
+func (lockedYearDb) GetProjectByID(ctx context.Context, userID string, projectID string) (db.Project, error) {
+	project := db.Project{ID: projectID, UserID: userID}
+	switch projectID {
+	case "locked":
+		project.Year = "2024-2025"
+	case "open":
+		project.Year = "2025-2026"
+	}
+	return project, nil
+}
+
+func (lockedYearDb) GetAnimalByID(ctx context.Context, userID string, animalID string) (db.Animal, error) {
+	return db.Animal{ID: animalID, UserID: userID, ProjectID: animalID}, nil
+}
+
+func TestRecordLockMiddleware(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	e := &env{db: lockedYearDb{}}
+
+	var received string
+	handler := func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		received = string(body)
+		c.Status(200)
+	}
+
+	router := gin.New()
+	records := router.Group("", func(c *gin.Context) {
+		auth.SetPrincipal(c, auth.Principal{UserID: "member"})
+	}, e.RecordLockMiddleware())
+	records.GET("/project/:projectID", handler)
+	records.PUT("/project/:projectID", handler)
+	records.POST("/project", handler)
+	records.POST("/project/:projectID/rollover", handler)
+	records.PUT("/animal/:animalID", handler)
+	records.POST("/daily-feed/batch", handler)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		code   int
+	}{
+		{"read of a locked year", "GET", "/project/locked", "", 200},
+		{"project in a locked year", "PUT", "/project/locked", "{}", 409},
+		{"project in an open year", "PUT", "/project/open", "{}", 200},
+		{"record under a locked project", "PUT", "/animal/locked", "{}", 409},
+		{"new project in a locked year", "POST", "/project", `{"year":"2024-2025"}`, 409},
+		{"new project in an open year", "POST", "/project", `{"year":"2025-2026"}`, 200},
+		{"rollover out of a locked year", "POST", "/project/locked/rollover", "{}", 200},
+		{"batch rows in a locked year", "POST", "/daily-feed/batch", `{"daily_feeds":[{"project_id":"open"},{"project_id":"locked"}]}`, 409},
+		{"batch split in a locked year", "POST", "/daily-feed/batch", `{"mode":"split","split":{"project_id":"locked"}}`, 409},
+		{"batch rows in an open year", "POST", "/daily-feed/batch", `{"daily_feeds":[{"project_id":"open","animal_id":"open"}]}`, 200},
+		{"malformed body", "POST", "/project", `{"year":`, 200},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			received = ""
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(test.method, test.path, strings.NewReader(test.body)))
+
+			if recorder.Code != test.code {
+				t.Fatalf("got %d, want %d: %s", recorder.Code, test.code, recorder.Body.String())
+			}
+			if test.code == 200 && test.method != "GET" && received != test.body {
+				t.Errorf("handler read body %q, want %q", received, test.body)
+			}
+
+		})
+	}
+
+}
@@ -1,9 +1,11 @@
 package api
 
 import (
+	"4h-recordbook-backend/internal/auth"
 	"4h-recordbook-backend/internal/utils"
 	"4h-recordbook-backend/pkg/db"
 	"strconv"
+	"time"
 
 	"github.com/beevik/guid"
 	"github.com/gin-gonic/gin"
@@ -15,18 +17,42 @@ type GetSuppliesOutput struct {
 }
 
 type GetSupplyOutput struct {
-	Supply db.Supply `json:"supply"`
+	Supply       db.Supply   `json:"supply"`
+	CurrentValue utils.Money `json:"current_value"`
 }
 
+// start and end values are calculated from the project dates for supplies that depreciate, and entered by hand otherwise
 type UpsertSupplyInput struct {
-	Description string   `json:"description" validate:"required"`
-	StartValue  *float64 `json:"start_value" validate:"required"`
-	EndValue    *float64 `json:"end_value" validate:"required"`
-	ProjectID   string   `json:"project_id" validate:"required"`
+	Description        string       `json:"description" validate:"required"`
+	StartValue         *utils.Money `json:"start_value" validate:"required_unless=DepreciationMethod straight_line"`
+	EndValue           *utils.Money `json:"end_value" validate:"required_unless=DepreciationMethod straight_line"`
+	AcquisitionDate    string       `json:"acquisition_date" validate:"required_if=DepreciationMethod straight_line"`
+	Cost               utils.Money  `json:"cost" validate:"gte=0"`
+	SalvageValue       utils.Money  `json:"salvage_value" validate:"gte=0,ltefield=Cost"`
+	UsefulLifeYears    int          `json:"useful_life_years" validate:"required_if=DepreciationMethod straight_line,gte=0"`
+	DepreciationMethod string       `json:"depreciation_method" validate:"omitempty,oneof=none straight_line"`
+	Category           string       `json:"category"`
+	OtherCategory      string       `json:"other_category"`
+	ProjectID          string       `json:"project_id" validate:"required"`
 }
 
 type UpsertSupplyOutput GetSupplyOutput
 
+type SupplyScheduleYear struct {
+	Year           string      `json:"year"`
+	StartDate      string      `json:"start_date"`
+	EndDate        string      `json:"end_date"`
+	BeginningValue utils.Money `json:"beginning_value"`
+	Depreciation   utils.Money `json:"depreciation"`
+	EndingValue    utils.Money `json:"ending_value"`
+}
+
+type GetSupplyScheduleOutput struct {
+	Supply       db.Supply            `json:"supply"`
+	CurrentValue utils.Money          `json:"current_value"`
+	Schedule     []SupplyScheduleYear `json:"schedule"`
+}
+
 // GetSupplies godoc
 // @Summary Get supplies by project
 // @Description Gets all of a user's supplies given a project ID
@@ -44,7 +70,7 @@ type UpsertSupplyOutput GetSupplyOutput
 // @Router /project/{projectID}/supply [get]
 func (e *env) getSupplies(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -62,7 +88,7 @@ func (e *env) getSupplies(c *gin.Context) {
 		SortByNewest: c.GetBool(CONTEXT_KEY_SORT_BY_NEWEST),
 	}
 
-	output.Supplies, err = e.db.GetSuppliesByProject(c.Request.Context(), claims.ID, projectID, paginationOptions)
+	output.Supplies, err = e.db.GetSuppliesByProject(c.Request.Context(), principal.UserID, projectID, paginationOptions)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -104,7 +130,7 @@ func (e *env) getSupplies(c *gin.Context) {
 // @Router /supply/{supplyID} [get]
 func (e *env) getSupply(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -116,7 +142,7 @@ func (e *env) getSupply(c *gin.Context) {
 
 	var output GetSupplyOutput
 
-	output.Supply, err = e.db.GetSupplyByID(c.Request.Context(), claims.ID, supplyID)
+	output.Supply, err = e.db.GetSupplyByID(c.Request.Context(), principal.UserID, supplyID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -125,6 +151,8 @@ func (e *env) getSupply(c *gin.Context) {
 		return
 	}
 
+	output.CurrentValue = currentSupplyValue(output.Supply, e.userLocation(c.Request.Context(), principal.UserID))
+
 	c.JSON(200, output)
 
 }
@@ -140,10 +168,11 @@ func (e *env) getSupply(c *gin.Context) {
 // @Success 201 {object} api.UpsertSupplyOutput
 // @Failure 400
 // @Failure 401
+// @Failure 404
 // @Router /supply [post]
 func (e *env) addSupply(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -168,22 +197,57 @@ func (e *env) addSupply(c *gin.Context) {
 		return
 	}
 
+	location := e.userLocation(c.Request.Context(), principal.UserID)
+
+	acquisitionDate := ""
+	if input.AcquisitionDate != "" {
+		parsed, err := utils.StringToDate(input.AcquisitionDate, location)
+		if err != nil {
+			c.JSON(400, gin.H{
+				"message": ErrBadDate,
+			})
+			return
+		}
+		acquisitionDate = parsed.String()
+	}
+
+	category, otherCategory, ok := e.expenseCategory(input.Category, input.OtherCategory)
+	if !ok {
+		c.JSON(400, gin.H{
+			"message": ErrBadExpenseCategory,
+		})
+		return
+	}
+
 	g := guid.New()
 	timestamp := utils.TimeNow()
 
 	supply := db.Supply{
-		ID:          g.String(),
-		Description: input.Description,
-		StartValue:  *input.StartValue,
-		EndValue:    *input.EndValue,
-		ProjectID:   input.ProjectID,
-		UserID:      claims.ID,
+		ID:                 g.String(),
+		Description:        input.Description,
+		StartValue:         moneyOrZero(input.StartValue),
+		EndValue:           moneyOrZero(input.EndValue),
+		AcquisitionDate:    acquisitionDate,
+		Cost:               input.Cost,
+		SalvageValue:       input.SalvageValue,
+		UsefulLifeYears:    input.UsefulLifeYears,
+		DepreciationMethod: ternary(input.DepreciationMethod, db.DEPRECIATION_METHOD_NONE),
+		Category:           category,
+		OtherCategory:      otherCategory,
+		ProjectID:          input.ProjectID,
+		UserID:             principal.UserID,
 		GenericDatabaseInfo: db.GenericDatabaseInfo{
 			Created: timestamp.String(),
 			Updated: timestamp.String(),
 		},
 	}
 
+	if supply.Depreciates() {
+		if !e.depreciateSupply(c, &supply) {
+			return
+		}
+	}
+
 	var output UpsertSupplyOutput
 
 	output.Supply, err = e.db.UpsertSupply(c.Request.Context(), supply)
@@ -195,6 +259,8 @@ func (e *env) addSupply(c *gin.Context) {
 		return
 	}
 
+	output.CurrentValue = currentSupplyValue(output.Supply, location)
+
 	c.JSON(201, output)
 
 }
@@ -215,7 +281,7 @@ func (e *env) addSupply(c *gin.Context) {
 // @Router /supply/{supplyID} [put]
 func (e *env) updateSupply(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -240,9 +306,23 @@ func (e *env) updateSupply(c *gin.Context) {
 		return
 	}
 
+	location := e.userLocation(c.Request.Context(), principal.UserID)
+
+	acquisitionDate := ""
+	if input.AcquisitionDate != "" {
+		parsed, err := utils.StringToDate(input.AcquisitionDate, location)
+		if err != nil {
+			c.JSON(400, gin.H{
+				"message": ErrBadDate,
+			})
+			return
+		}
+		acquisitionDate = parsed.String()
+	}
+
 	supplyID := c.Param("supplyID")
 
-	supply, err := e.db.GetSupplyByID(c.Request.Context(), claims.ID, supplyID)
+	supply, err := e.db.GetSupplyByID(c.Request.Context(), principal.UserID, supplyID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -251,21 +331,42 @@ func (e *env) updateSupply(c *gin.Context) {
 		return
 	}
 
+	category, otherCategory, ok := e.expenseCategory(ternary(input.Category, supply.Category), ternary(input.OtherCategory, supply.OtherCategory))
+	if !ok {
+		c.JSON(400, gin.H{
+			"message": ErrBadExpenseCategory,
+		})
+		return
+	}
+
 	timestamp := utils.TimeNow()
 
 	updatedSupply := db.Supply{
-		ID:          supply.ID,
-		Description: input.Description,
-		StartValue:  *input.StartValue,
-		EndValue:    *input.EndValue,
-		ProjectID:   supply.ProjectID,
-		UserID:      claims.ID,
+		ID:                 supply.ID,
+		Description:        input.Description,
+		StartValue:         moneyOrZero(input.StartValue),
+		EndValue:           moneyOrZero(input.EndValue),
+		AcquisitionDate:    acquisitionDate,
+		Cost:               input.Cost,
+		SalvageValue:       input.SalvageValue,
+		UsefulLifeYears:    input.UsefulLifeYears,
+		DepreciationMethod: ternary(input.DepreciationMethod, db.DEPRECIATION_METHOD_NONE),
+		Category:           category,
+		OtherCategory:      otherCategory,
+		ProjectID:          supply.ProjectID,
+		UserID:             principal.UserID,
 		GenericDatabaseInfo: db.GenericDatabaseInfo{
 			Created: supply.Created,
 			Updated: timestamp.String(),
 		},
 	}
 
+	if updatedSupply.Depreciates() {
+		if !e.depreciateSupply(c, &updatedSupply) {
+			return
+		}
+	}
+
 	var output UpsertSupplyOutput
 
 	output.Supply, err = e.db.UpsertSupply(c.Request.Context(), updatedSupply)
@@ -277,6 +378,8 @@ func (e *env) updateSupply(c *gin.Context) {
 		return
 	}
 
+	output.CurrentValue = currentSupplyValue(output.Supply, location)
+
 	c.JSON(200, output)
 
 }
@@ -295,7 +398,7 @@ func (e *env) updateSupply(c *gin.Context) {
 // @Router /supply/{supplyID} [delete]
 func (e *env) deleteSupply(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -305,7 +408,7 @@ func (e *env) deleteSupply(c *gin.Context) {
 
 	supplyID := c.Param("supplyID")
 
-	response, err := e.db.RemoveSupply(c.Request.Context(), claims.ID, supplyID)
+	response, err := e.db.RemoveSupply(c.Request.Context(), principal.UserID, supplyID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -317,3 +420,189 @@ func (e *env) deleteSupply(c *gin.Context) {
 	c.JSON(204, response)
 
 }
+
+// GetSupplySchedule godoc
+// @Summary Get a supply's depreciation schedule
+// @Description Gets a supply's current value and, for supplies that depreciate, its value at the start and end of each program year from acquisition until it is fully depreciated
+// @Tags Supply
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param supplyID path string true "Supply ID"
+// @Success 200 {object} api.GetSupplyScheduleOutput
+// @Failure 401
+// @Failure 404
+// @Router /supply/{supplyID}/schedule [get]
+func (e *env) getSupplySchedule(c *gin.Context) {
+
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	supplyID := c.Param("supplyID")
+
+	var output GetSupplyScheduleOutput
+
+	output.Supply, err = e.db.GetSupplyByID(c.Request.Context(), principal.UserID, supplyID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return
+	}
+
+	location := e.userLocation(c.Request.Context(), principal.UserID)
+
+	output.CurrentValue = currentSupplyValue(output.Supply, location)
+	output.Schedule = e.supplySchedule(output.Supply, location)
+
+	c.JSON(200, output)
+
+}
+
+func moneyOrZero(value *utils.Money) utils.Money {
+	if value == nil {
+		return 0
+	}
+	return *value
+}
+
+// straight-line value of a depreciating supply on the given date, never falling below its salvage value
+func supplyValue(supply db.Supply, on utils.Date, location *time.Location) utils.Money {
+
+	acquired, err := utils.StringToDate(supply.AcquisitionDate, location)
+	if err != nil || on.Before(acquired) {
+		return supply.Cost
+	}
+
+	// whole years since acquisition plus the fraction of the year in progress, so that anniversaries land exactly
+	wholeYears := 0
+	for wholeYears < supply.UsefulLifeYears && !acquired.AddYears(wholeYears+1).After(on) {
+		wholeYears++
+	}
+	if wholeYears >= supply.UsefulLifeYears {
+		return supply.SalvageValue
+	}
+
+	anniversary := acquired.AddYears(wholeYears).Time()
+	nextAnniversary := acquired.AddYears(wholeYears + 1).Time()
+	years := float64(wholeYears) + on.Time().Sub(anniversary).Hours()/nextAnniversary.Sub(anniversary).Hours()
+
+	return supply.Cost - (supply.Cost - supply.SalvageValue).Scale(years/float64(supply.UsefulLifeYears))
+
+}
+
+func currentSupplyValue(supply db.Supply, location *time.Location) utils.Money {
+	if !supply.Depreciates() {
+		return supply.EndValue
+	}
+	return supplyValue(supply, utils.Today(location), location)
+}
+
+// values a depreciating supply at the start and end of its project, which is what carries it over from one project
+// year to the next
+func (e *env) depreciateSupply(c *gin.Context, supply *db.Supply) bool {
+
+	project, err := e.db.GetProjectByID(c.Request.Context(), supply.UserID, supply.ProjectID)
+	if err != nil {
+		response := InterpretCosmosError(err)
+		c.JSON(response.Code, gin.H{
+			"message": response.Message,
+		})
+		return false
+	}
+
+	err = setSupplyValues(supply, project, e.userLocation(c.Request.Context(), supply.UserID))
+	if err != nil {
+		c.JSON(500, gin.H{
+			"message": err.Error(),
+		})
+		return false
+	}
+
+	return true
+
+}
+
+// project end dates are inclusive, so the end value is taken at the start of the following day. a project that ends the
+// day before the next one starts hands over the same value
+func setSupplyValues(supply *db.Supply, project db.Project, location *time.Location) error {
+
+	startDate, err := utils.StringToDate(project.StartDate, location)
+	if err != nil {
+		return err
+	}
+
+	endDate, err := utils.StringToDate(project.EndDate, location)
+	if err != nil {
+		return err
+	}
+
+	supply.StartValue = supplyValue(*supply, startDate, location)
+	supply.EndValue = supplyValue(*supply, endDate.AddDays(1), location)
+
+	return nil
+
+}
+
+// one row per program year, starting with the year the supply was acquired in and ending with the year it reaches its
+// salvage value
+func (e *env) supplySchedule(supply db.Supply, location *time.Location) []SupplyScheduleYear {
+
+	schedule := []SupplyScheduleYear{}
+
+	if !supply.Depreciates() {
+		return schedule
+	}
+
+	acquired, err := utils.StringToDate(supply.AcquisitionDate, location)
+	if err != nil {
+		return schedule
+	}
+
+	year := e.programYear.Of(acquired.Time())
+
+	for i := 0; i <= supply.UsefulLifeYears; i++ {
+
+		yearStart, nextYearStart, err := e.programYear.Bounds(year)
+		if err != nil {
+			break
+		}
+
+		start := utils.Date(yearStart)
+		if start.Before(acquired) {
+			start = acquired
+		}
+		end := utils.Date(nextYearStart)
+
+		beginningValue := supplyValue(supply, start, location)
+		endingValue := supplyValue(supply, end, location)
+
+		schedule = append(schedule, SupplyScheduleYear{
+			Year:           year,
+			StartDate:      start.String(),
+			EndDate:        end.AddDays(-1).String(),
+			BeginningValue: beginningValue,
+			Depreciation:   beginningValue - endingValue,
+			EndingValue:    endingValue,
+		})
+
+		if endingValue <= supply.SalvageValue {
+			break
+		}
+
+		year, err = e.programYear.Next(year)
+		if err != nil {
+			break
+		}
+
+	}
+
+	return schedule
+
+}
@@ -0,0 +1,140 @@
+package api
+
+import (
+	"4h-recordbook-backend/internal/utils"
+	"4h-recordbook-backend/pkg/db"
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+var errUnknownUnit = errors.New("unknown unit")
+
+// the unit a user's weights and feed amounts are shown in, falling back to pounds when the user can't be read
+func (e *env) preferredMassUnit(ctx context.Context, userID string) string {
+
+	user, err := e.requestUser(ctx, userID)
+	if err != nil {
+		return utils.MassUnit(utils.UNIT_SYSTEM_IMPERIAL)
+	}
+
+	return utils.MassUnit(user.UnitSystem)
+
+}
+
+// converts an entered quantity to pounds, using the preferred unit when none was given
+func massToPounds(value float64, unit string, preferred string) (float64, error) {
+
+	unit = ternary(strings.TrimSpace(unit), preferred)
+
+	pounds, err := utils.ToCanonicalMass(value, unit)
+	if err != nil {
+		return 0, errUnknownUnit
+	}
+
+	return pounds, nil
+
+}
+
+// feed amounts may also be given in the custom unit of the purchase they were drawn from, such as bags or flakes
+func (e *env) feedAmountToPounds(ctx context.Context, userID string, feedPurchaseID string, amount float64, unit string, preferred string, purchases map[string]db.FeedPurchase) (float64, error) {
+
+	unit = strings.TrimSpace(unit)
+	if unit == "" || utils.IsMassUnit(unit) {
+		return massToPounds(amount, unit, preferred)
+	}
+
+	feedPurchase, ok := purchases[feedPurchaseID]
+	if !ok {
+		var err error
+		feedPurchase, err = e.db.GetFeedPurchaseByID(ctx, userID, feedPurchaseID)
+		if err != nil {
+			return 0, err
+		}
+		purchases[feedPurchaseID] = feedPurchase
+	}
+
+	if feedPurchase.PurchaseUnit == "" || !strings.EqualFold(feedPurchase.PurchaseUnit, unit) {
+		return 0, errUnknownUnit
+	}
+
+	return utils.ToCanonicalMass(amount*feedPurchase.PurchaseUnitFactor, utils.CANONICAL_MASS_UNIT)
+
+}
+
+// records are stored in pounds and converted to the user's preferred unit on the way out
+func localizeAnimal(animal db.Animal, unit string) db.Animal {
+	animal.BeginningWeight = utils.FromCanonicalMass(animal.BeginningWeight, unit)
+	animal.EndWeight = utils.FromCanonicalMass(animal.EndWeight, unit)
+	animal.WeightUnit = unit
+	return animal
+}
+
+func localizeAnimals(animals []db.Animal, unit string) []db.Animal {
+	for i := range animals {
+		animals[i] = localizeAnimal(animals[i], unit)
+	}
+	return animals
+}
+
+func localizeDailyFeed(dailyFeed db.DailyFeed, unit string) db.DailyFeed {
+	dailyFeed.FeedAmount = utils.FromCanonicalMass(dailyFeed.FeedAmount, unit)
+	dailyFeed.FeedAmountUnit = unit
+	return dailyFeed
+}
+
+func localizeDailyFeeds(dailyFeeds []db.DailyFeed, unit string) []db.DailyFeed {
+	for i := range dailyFeeds {
+		dailyFeeds[i] = localizeDailyFeed(dailyFeeds[i], unit)
+	}
+	return dailyFeeds
+}
+
+func localizeFeedPurchase(feedPurchase db.FeedPurchase, unit string) db.FeedPurchase {
+	feedPurchase.AmountPurchased = utils.FromCanonicalMass(feedPurchase.AmountPurchased, unit)
+	feedPurchase.AmountUnit = unit
+	if feedPurchase.PurchaseUnit != "" {
+		feedPurchase.PurchaseUnitFactor = utils.FromCanonicalMass(feedPurchase.PurchaseUnitFactor, unit)
+	}
+	return feedPurchase
+}
+
+func localizeFeedPurchases(feedPurchases []db.FeedPurchase, unit string) []db.FeedPurchase {
+	for i := range feedPurchases {
+		feedPurchases[i] = localizeFeedPurchase(feedPurchases[i], unit)
+	}
+	return feedPurchases
+}
+
+// feed is planned by weight, so only feed items have a unit. the rest are counts
+func localizeProjectBudgetItem(item db.ProjectBudgetItem, unit string) db.ProjectBudgetItem {
+	if item.Category == db.BUDGET_CATEGORY_FEED {
+		item.PlannedQuantity = utils.FromCanonicalMass(item.PlannedQuantity, unit)
+		item.QuantityUnit = unit
+	}
+	return item
+}
+
+func localizeProjectBudgetItems(items []db.ProjectBudgetItem, unit string) []db.ProjectBudgetItem {
+	for i := range items {
+		items[i] = localizeProjectBudgetItem(items[i], unit)
+	}
+	return items
+}
+
+func unitErrorResponse(err error) (int, string) {
+	if errors.Is(err, errUnknownUnit) {
+		return 400, ErrBadUnit
+	}
+	response := InterpretCosmosError(err)
+	return response.Code, response.Message
+}
+
+func respondUnitError(c *gin.Context, err error) {
+	code, message := unitErrorResponse(err)
+	c.JSON(code, gin.H{
+		"message": message,
+	})
+}
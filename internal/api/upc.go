@@ -1,6 +1,7 @@
 package api
 
 import (
+	"4h-recordbook-backend/internal/auth"
 	"4h-recordbook-backend/pkg/upc"
 
 	"github.com/gin-gonic/gin"
@@ -24,7 +25,7 @@ type GetUpcProductOutput struct {
 // @Router /upc/{code} [get]
 func (e *env) getUpcProduct(c *gin.Context) {
 
-	_, err := decodeJWT(c)
+	_, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -1,10 +1,13 @@
 package api
 
 import (
+	"4h-recordbook-backend/internal/auth"
+	"4h-recordbook-backend/internal/middleware"
 	"4h-recordbook-backend/internal/utils"
 	"4h-recordbook-backend/pkg/db"
+	"context"
+	"time"
 
-	"github.com/beevik/guid"
 	"github.com/gin-gonic/gin"
 )
 
@@ -19,6 +22,8 @@ type UpdateUserInput struct {
 	MiddleNameInitial string `json:"middle_name_initial"`
 	LastNameInitial   string `json:"last_name_initial"`
 	CountyName        string `json:"county_name"`
+	UnitSystem        string `json:"unit_system"`
+	TimeZone          string `json:"time_zone"`
 }
 
 // GetUserProfile godoc
@@ -34,7 +39,7 @@ type UpdateUserInput struct {
 // @Router /user [get]
 func (e *env) getUserProfile(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -44,7 +49,7 @@ func (e *env) getUserProfile(c *gin.Context) {
 
 	var output GetUserProfileOutput
 
-	output.User, err = e.db.GetUser(c.Request.Context(), claims.ID)
+	output.User, err = e.db.GetUser(c.Request.Context(), principal.UserID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -59,7 +64,8 @@ func (e *env) getUserProfile(c *gin.Context) {
 
 // UpdateUserProfile godoc
 // @Summary Update a user
-// @Description Update the signed-in user's information
+// @Description Update the signed-in user's information. unit_system (imperial or metric) sets the units weights and feed amounts are shown in,
+// @Description and time_zone (an IANA name such as America/Los_Angeles) is used to read dates sent as timestamps.
 // @Tags User
 // @Accept json
 // @Produce json
@@ -72,7 +78,7 @@ func (e *env) getUserProfile(c *gin.Context) {
 // @Router /user [put]
 func (e *env) updateUserProfile(c *gin.Context) {
 
-	claims, err := decodeJWT(c)
+	principal, err := auth.FromContext(c)
 	if err != nil {
 		c.JSON(401, gin.H{
 			"message": err.Error(),
@@ -89,7 +95,24 @@ func (e *env) updateUserProfile(c *gin.Context) {
 		return
 	}
 
-	user, err := e.db.GetUser(c.Request.Context(), claims.ID)
+	if input.UnitSystem != "" && !utils.IsUnitSystem(input.UnitSystem) {
+		c.JSON(400, gin.H{
+			"message": ErrBadUnitSystem,
+		})
+		return
+	}
+
+	if input.TimeZone != "" {
+		_, err = time.LoadLocation(input.TimeZone)
+		if err != nil {
+			c.JSON(400, gin.H{
+				"message": ErrBadTimeZone,
+			})
+			return
+		}
+	}
+
+	user, err := e.db.GetUser(c.Request.Context(), principal.UserID)
 	if err != nil {
 		response := InterpretCosmosError(err)
 		c.JSON(response.Code, gin.H{
@@ -107,6 +130,8 @@ func (e *env) updateUserProfile(c *gin.Context) {
 		FirstName:         ternary(input.FirstName, user.FirstName),
 		MiddleNameInitial: ternary(input.MiddleNameInitial, user.MiddleNameInitial),
 		CountyName:        ternary(input.CountyName, user.CountyName),
+		UnitSystem:        ternary(input.UnitSystem, user.UnitSystem),
+		TimeZone:          ternary(input.TimeZone, user.TimeZone),
 		GenericDatabaseInfo: db.GenericDatabaseInfo{
 			Created: user.Created,
 			Updated: timestamp.String(),
@@ -126,130 +151,36 @@ func (e *env) updateUserProfile(c *gin.Context) {
 
 }
 
-// temporary signin and signup functions
-type SignInInput struct {
-	ID string `json:"id" validate:"required"`
-}
-
-// SignIn godoc
-// @Summary Sign in
-// @Description Placeholder route, sign in with ID
-// @Tags User
-// @Accept json
-// @Produce json
-// @Param ID body api.SignInInput true "User ID"
-// @Success 204
-// @Failure 400
-// @Failure 404
-// @Router /signin [post]
-func (e *env) signin(c *gin.Context) {
-
-	var input SignInInput
-	err := c.BindJSON(&input)
-	if err != nil {
-		c.JSON(400, gin.H{
-			"message": ErrBadRequest,
-		})
-		return
-	}
-
-	user, err := e.db.GetUser(c.Request.Context(), input.ID)
-	if err != nil {
-		response := InterpretCosmosError(err)
-		c.JSON(response.Code, gin.H{
-			"message": response.Message,
-		})
-		return
-	}
-
-	jwt, err := generateJWT(user.ID, user.FirstName)
-	if err != nil {
-		c.JSON(400, gin.H{
-			"message": ErrBadRequest,
-		})
-		return
-	}
-
-	c.JSON(200, gin.H{
-		"access_token": jwt,
-	})
-
-}
-
-type SignUpInput struct {
-	Email             string `json:"email" validate:"required"`
+// the rest of the user's information comes from their identity provider
+type RegisterInput struct {
 	Birthdate         string `json:"birthdate" validate:"required"`
-	FirstName         string `json:"first_name" validate:"required"`
 	MiddleNameInitial string `json:"middle_name_initial" validate:"required"`
-	LastNameInitial   string `json:"last_name_initial" validate:"required"`
 	CountyName        string `json:"county_name" validate:"required"`
 }
 
-// Signup godoc
-// @Summary Sign up
-// @Description Placeholder route, sign up with custom user information
-// @Tags User
-// @Accept json
-// @Produce json
-// @Param ID body api.SignUpInput true "User information"
-// @Success 204
-// @Failure 400
-// @Failure 409
-// @Router /signup [post]
-func (e *env) signup(c *gin.Context) {
-
-	var input SignUpInput
-	err := c.BindJSON(&input)
-	if err != nil {
-		c.JSON(400, gin.H{
-			"message": ErrBadRequest,
-		})
-		return
-	}
-
-	g := guid.New()
-	timestamp := utils.TimeNow()
-
-	user := db.User{
-		ID:                g.String(),
-		Email:             input.Email,
-		Birthdate:         input.Birthdate,
-		FirstName:         input.FirstName,
-		MiddleNameInitial: input.MiddleNameInitial,
-		CountyName:        input.CountyName,
-		GenericDatabaseInfo: db.GenericDatabaseInfo{
-			Created: timestamp.String(),
-			Updated: timestamp.String(),
-		},
-	}
-
-	response, err := e.db.UpsertUser(c.Request.Context(), user)
-	if err != nil {
-		response := InterpretCosmosError(err)
-		c.JSON(response.Code, gin.H{
-			"message": response.Message,
-		})
-		return
-	}
-
-	c.JSON(204, response)
-
-}
-
 // Register godoc
 // @Summary Register
 // @Description With a valid JWT, add a user database entry
 // @Tags User
 // @Accept json
 // @Produce json
-// @Param ID body api.SignUpInput true "User information"
+// @Param ID body api.RegisterInput true "User information"
 // @Success 204
 // @Failure 400
+// @Failure 401
 // @Failure 409
 // @Router /register [post]
 func (e *env) register(c *gin.Context) {
 
-	_, exists := e.db.GetUser(c, c.GetString("user_id"))
+	principal, err := auth.FromContext(c)
+	if err != nil {
+		c.JSON(401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	_, exists := e.db.GetUser(c, principal.UserID)
 
 	if(exists == nil) {
 		// Account exists
@@ -259,8 +190,8 @@ func (e *env) register(c *gin.Context) {
 		return
 	}
 
-	var input SignUpInput
-	err := c.BindJSON(&input)
+	var input RegisterInput
+	err = c.BindJSON(&input)
 	if err != nil {
 		c.JSON(400, gin.H{
 			"message": ErrBadRequest,
@@ -271,10 +202,10 @@ func (e *env) register(c *gin.Context) {
 	timestamp := utils.TimeNow()
 
 	user := db.User{
-		ID:                c.GetString("user_id"),
-		Email:             c.GetString("user_email"),
+		ID:                principal.UserID,
+		Email:             principal.Email,
 		Birthdate:         input.Birthdate,
-		FirstName:         c.GetString("user_name"),
+		FirstName:         principal.Name,
 		MiddleNameInitial: input.MiddleNameInitial,
 		CountyName:        input.CountyName,
 		GenericDatabaseInfo: db.GenericDatabaseInfo{
@@ -295,3 +226,15 @@ func (e *env) register(c *gin.Context) {
 	c.JSON(204, response)
 
 }
+
+// the user read once for the request by the GetUser middleware, or read from the database for any other user
+func (e *env) requestUser(ctx context.Context, userID string) (db.User, error) {
+
+	user, ok := middleware.UserFromContext(ctx)
+	if ok && user.ID == userID {
+		return user, nil
+	}
+
+	return e.db.GetUser(ctx, userID)
+
+}
@@ -0,0 +1,179 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt"
+)
+
+const CONTEXT_KEY_PRINCIPAL = "principal"
+
+var (
+	ErrNoToken          = errors.New("no authentication token provided")
+	ErrBadToken         = errors.New("bad token")
+	ErrUnknownIssuer    = errors.New("token was not issued by a trusted issuer")
+	ErrNotAuthenticated = errors.New("request is not authenticated")
+)
+
+// the signed-in user, as established by the provider that issued their token. when a guardian acts for a member,
+// the principal is the member and the actor fields hold the guardian
+type Principal struct {
+	UserID        string   `json:"user_id"`
+	Name          string   `json:"name"`
+	Email         string   `json:"email"`
+	EmailVerified bool     `json:"email_verified"`
+	Picture       string   `json:"picture"`
+	Scopes        []string `json:"scopes"`
+	Roles         []Role   `json:"roles"`
+	ActorID       string   `json:"actor_id,omitempty"`
+	ActorName     string   `json:"actor_name,omitempty"`
+	Issuer        string   `json:"issuer"`
+	Provider      string   `json:"provider"`
+}
+
+func (p Principal) HasScope(scope string) bool {
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// whether a guardian is acting for the principal, in which case the actor is the guardian
+func (p Principal) IsActing() bool {
+	return p.ActorID != "" && p.ActorID != p.UserID
+}
+
+// whoever is making the request
+func (p Principal) Actor() string {
+	if p.IsActing() {
+		return p.ActorID
+	}
+	return p.UserID
+}
+
+// a provider validates the tokens of one issuer and turns them into a principal
+type Provider interface {
+	Name() string
+	Issuer() string
+	Authenticate(ctx context.Context, token string) (Principal, error)
+}
+
+type principalKey struct{}
+
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+func PrincipalFromContext(ctx context.Context) (Principal, error) {
+	principal, ok := ctx.Value(principalKey{}).(Principal)
+	if !ok || principal.UserID == "" {
+		return Principal{}, ErrNotAuthenticated
+	}
+	return principal, nil
+}
+
+// the principal stored by Middleware. handlers behind Middleware can rely on it being present
+func FromContext(c *gin.Context) (Principal, error) {
+	value, ok := c.Get(CONTEXT_KEY_PRINCIPAL)
+	if !ok {
+		return PrincipalFromContext(c.Request.Context())
+	}
+	principal, ok := value.(Principal)
+	if !ok || principal.UserID == "" {
+		return Principal{}, ErrNotAuthenticated
+	}
+	return principal, nil
+}
+
+func bearerToken(c *gin.Context) (string, error) {
+
+	header := c.GetHeader("Authorization")
+	if header == "" {
+		return "", ErrNoToken
+	}
+
+	token, ok := strings.CutPrefix(header, "Bearer ")
+	if !ok || strings.TrimSpace(token) == "" {
+		return "", ErrBadToken
+	}
+
+	return strings.TrimSpace(token), nil
+
+}
+
+// the issuer a token claims to be from, read without verifying it so that the right provider can verify it
+func unverifiedIssuer(token string) (string, error) {
+
+	// map claims, since the standard claims can't hold the audience arrays that issuers send
+	claims := jwt.MapClaims{}
+
+	_, _, err := new(jwt.Parser).ParseUnverified(token, &claims)
+	if err != nil {
+		return "", ErrBadToken
+	}
+
+	issuer, _ := claims["iss"].(string)
+
+	return issuer, nil
+
+}
+
+// validates the bearer token once with the provider for its issuer and stores the resulting principal on both the gin
+// context and the request context
+func Middleware(providers ...Provider) gin.HandlerFunc {
+
+	byIssuer := make(map[string]Provider)
+	for _, provider := range providers {
+		byIssuer[provider.Issuer()] = provider
+	}
+
+	return func(c *gin.Context) {
+
+		token, err := bearerToken(c)
+		if err != nil {
+			abort(c, err)
+			return
+		}
+
+		issuer, err := unverifiedIssuer(token)
+		if err != nil {
+			abort(c, err)
+			return
+		}
+
+		provider, ok := byIssuer[issuer]
+		if !ok {
+			abort(c, ErrUnknownIssuer)
+			return
+		}
+
+		principal, err := provider.Authenticate(c.Request.Context(), token)
+		if err != nil {
+			abort(c, err)
+			return
+		}
+
+		SetPrincipal(c, principal)
+
+		c.Next()
+
+	}
+
+}
+
+func abort(c *gin.Context, err error) {
+	if errors.Is(err, ErrUserInfoUnavailable) {
+		c.AbortWithStatusJSON(503, gin.H{
+			"message": ErrUserInfoUnavailable.Error(),
+		})
+		return
+	}
+	c.AbortWithStatusJSON(401, gin.H{
+		"message": err.Error(),
+	})
+}
@@ -0,0 +1,296 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt"
+	"go.uber.org/zap"
+)
+
+const (
+	DEV_ISSUER_KEY_ID     = "dev-issuer"
+	DEV_ISSUER_KEY_BITS   = 2048
+	DEV_ISSUER_TOKEN_TTL  = time.Hour
+	DEV_ISSUER_TOKEN_TYPE = "Bearer"
+)
+
+var ErrUnknownDevUser = errors.New("no test user with that name")
+
+type DevUser struct {
+	Name    string
+	Subject string
+	Email   string
+	Roles   []Role
+}
+
+type DevIssuerOptions struct {
+	Name     string
+	Issuer   string
+	Audience string
+	Users    []DevUser
+}
+
+// a minimal OIDC issuer for development and integration tests. it publishes discovery, JWKS and userinfo endpoints
+// for clients and mints RS256 tokens for named test users. it is also the provider for its own tokens, verifying them
+// with its key and test users in process rather than calling its endpoints over the network. the signing key is
+// generated on start, so tokens don't outlive the process
+type DevIssuer struct {
+	logger   *zap.SugaredLogger
+	name     string
+	issuer   *url.URL
+	audience string
+	users    map[string]DevUser
+	key      *rsa.PrivateKey
+}
+
+type devClaims struct {
+	jwt.StandardClaims
+	Audience []string `json:"aud"`
+	Scope    string   `json:"scope"`
+	Name     string   `json:"name"`
+	Email    string   `json:"email"`
+	Verified bool     `json:"email_verified"`
+}
+
+type DevTokenInput struct {
+	User string `json:"user"`
+}
+
+type DevTokenOutput struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+func NewDevIssuer(logger *zap.SugaredLogger, options DevIssuerOptions) (*DevIssuer, error) {
+
+	issuer, err := url.Parse(options.Issuer)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(issuer.Path, "/") {
+		issuer.Path += "/"
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, DEV_ISSUER_KEY_BITS)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make(map[string]DevUser)
+	for _, user := range options.Users {
+		users[user.Name] = user
+	}
+
+	return &DevIssuer{
+		logger:   logger,
+		name:     options.Name,
+		issuer:   issuer,
+		audience: options.Audience,
+		users:    users,
+		key:      key,
+	}, nil
+
+}
+
+func (di *DevIssuer) Name() string {
+	return di.name
+}
+
+func (di *DevIssuer) Issuer() string {
+	return di.issuer.String()
+}
+
+func (di *DevIssuer) Audience() string {
+	return di.audience
+}
+
+func (di *DevIssuer) endpoint(path string) string {
+	return di.issuer.JoinPath(path).String()
+}
+
+func (di *DevIssuer) UserInfoURL() string {
+	return di.endpoint("userinfo")
+}
+
+// a signed access token for the named test user
+func (di *DevIssuer) Mint(userName string) (string, error) {
+
+	user, ok := di.users[userName]
+	if !ok {
+		return "", ErrUnknownDevUser
+	}
+
+	scopes := []string{"openid", "profile", "email"}
+	for _, role := range user.Roles {
+		scopes = append(scopes, ROLE_SCOPE_PREFIX+string(role))
+	}
+
+	now := time.Now()
+
+	claims := devClaims{
+		StandardClaims: jwt.StandardClaims{
+			Issuer:    di.Issuer(),
+			Subject:   user.Subject,
+			IssuedAt:  now.Unix(),
+			NotBefore: now.Unix(),
+			ExpiresAt: now.Add(DEV_ISSUER_TOKEN_TTL).Unix(),
+		},
+		Audience: []string{di.audience},
+		Scope:    strings.Join(scopes, " "),
+		Name:     user.Name,
+		Email:    user.Email,
+		Verified: true,
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = DEV_ISSUER_KEY_ID
+
+	return token.SignedString(di.key)
+
+}
+
+// the claims of a token this issuer signed for its audience, and the test user it was minted for
+func (di *DevIssuer) verify(token string) (devClaims, DevUser, error) {
+
+	claims := devClaims{}
+	parsed, err := jwt.ParseWithClaims(token, &claims, func(token *jwt.Token) (interface{}, error) {
+		if token.Method != jwt.SigningMethodRS256 {
+			return nil, ErrBadToken
+		}
+		return &di.key.PublicKey, nil
+	})
+	if err != nil || !parsed.Valid || claims.Issuer != di.Issuer() || !slices.Contains(claims.Audience, di.audience) {
+		return devClaims{}, DevUser{}, ErrBadToken
+	}
+
+	for _, user := range di.users {
+		if user.Subject == claims.Subject {
+			return claims, user, nil
+		}
+	}
+
+	return devClaims{}, DevUser{}, ErrBadToken
+
+}
+
+func (di *DevIssuer) Authenticate(ctx context.Context, token string) (Principal, error) {
+
+	claims, user, err := di.verify(token)
+	if err != nil {
+		di.logger.Infof("Failed to validate %s token: %v", di.name, err)
+		return Principal{}, err
+	}
+
+	return Principal{
+		UserID:        user.Subject,
+		Name:          user.Name,
+		Email:         user.Email,
+		EmailVerified: true,
+		Scopes:        strings.Fields(claims.Scope),
+		Issuer:        claims.Issuer,
+		Provider:      di.name,
+	}, nil
+
+}
+
+// mounts the issuer's endpoints under the path of its issuer URL
+func (di *DevIssuer) Register(router gin.IRoutes) {
+	path := strings.TrimSuffix(di.issuer.Path, "/")
+	router.GET(path+"/.well-known/openid-configuration", di.discovery)
+	router.GET(path+"/.well-known/jwks.json", di.jwks)
+	router.GET(path+"/userinfo", di.userInfo)
+	router.POST(path+"/token", di.token)
+}
+
+func (di *DevIssuer) discovery(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"issuer":                                di.Issuer(),
+		"jwks_uri":                              di.endpoint(".well-known/jwks.json"),
+		"userinfo_endpoint":                     di.UserInfoURL(),
+		"token_endpoint":                        di.endpoint("token"),
+		"response_types_supported":              []string{"token"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+	})
+}
+
+func (di *DevIssuer) jwks(c *gin.Context) {
+
+	publicKey := di.key.PublicKey
+
+	c.JSON(http.StatusOK, gin.H{
+		"keys": []gin.H{
+			{
+				"kty": "RSA",
+				"use": "sig",
+				"alg": "RS256",
+				"kid": DEV_ISSUER_KEY_ID,
+				"n":   base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+			},
+		},
+	})
+
+}
+
+func (di *DevIssuer) userInfo(c *gin.Context) {
+
+	token, err := bearerToken(c)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	_, user, err := di.verify(token)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, UserInfo{
+		Sub:      user.Subject,
+		Name:     user.Name,
+		Nickname: user.Name,
+		Email:    user.Email,
+		Verified: true,
+	})
+
+}
+
+func (di *DevIssuer) token(c *gin.Context) {
+
+	var input DevTokenInput
+	err := c.BindJSON(&input)
+	if err != nil {
+		return
+	}
+
+	token, err := di.Mint(input.User)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	di.logger.Debugf("Minted dev issuer token for %s", input.User)
+
+	c.JSON(http.StatusOK, DevTokenOutput{
+		AccessToken: token,
+		TokenType:   DEV_ISSUER_TOKEN_TYPE,
+		ExpiresIn:   int(DEV_ISSUER_TOKEN_TTL.Seconds()),
+	})
+
+}
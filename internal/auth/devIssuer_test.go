@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+func newTestDevIssuer(t *testing.T, audience string) *DevIssuer {
+	t.Helper()
+	issuer, err := NewDevIssuer(zap.NewNop().Sugar(), DevIssuerOptions{
+		Name: "dev",
+		// nothing listens here, so a token is only accepted if it is verified in process
+		Issuer:   "http://localhost:1/dev-issuer/",
+		Audience: audience,
+		Users: []DevUser{
+			{Name: "leader", Subject: "dev|leader", Email: "leader@example.com", Roles: []Role{ROLE_LEADER}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create dev issuer: %v", err)
+	}
+	return issuer
+}
+
+func TestDevIssuerAuthenticatesInProcess(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	issuer := newTestDevIssuer(t, "api")
+
+	router := gin.New()
+	issuer.Register(router)
+	router.GET("/whoami", Middleware(issuer), func(c *gin.Context) {
+		principal, _ := FromContext(c)
+		c.JSON(http.StatusOK, principal)
+	})
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	response, err := http.Post(server.URL+"/dev-issuer/token", "application/json", strings.NewReader(`{"user":"leader"}`))
+	if err != nil {
+		t.Fatalf("failed to request a token: %v", err)
+	}
+	var minted DevTokenOutput
+	err = json.NewDecoder(response.Body).Decode(&minted)
+	response.Body.Close()
+	if err != nil || response.StatusCode != http.StatusOK {
+		t.Fatalf("token endpoint responded with %d: %v", response.StatusCode, err)
+	}
+
+	forged, err := newTestDevIssuer(t, "api").Mint("leader")
+	if err != nil {
+		t.Fatalf("failed to mint a token from another key: %v", err)
+	}
+
+	otherAudience, err := newTestDevIssuer(t, "other").Mint("leader")
+	if err != nil {
+		t.Fatalf("failed to mint a token for another audience: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		code  int
+	}{
+		{"minted token", minted.AccessToken, http.StatusOK},
+		{"no token", "", http.StatusUnauthorized},
+		{"signed with another key", forged, http.StatusUnauthorized},
+		{"for another audience", otherAudience, http.StatusUnauthorized},
+		{"not a token", "not-a-token", http.StatusUnauthorized},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			request, _ := http.NewRequest(http.MethodGet, server.URL+"/whoami", nil)
+			if test.token != "" {
+				request.Header.Set("Authorization", "Bearer "+test.token)
+			}
+
+			response, err := http.DefaultClient.Do(request)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			defer response.Body.Close()
+
+			if response.StatusCode != test.code {
+				t.Fatalf("got %d, want %d", response.StatusCode, test.code)
+			}
+			if test.code != http.StatusOK {
+				return
+			}
+
+			var principal Principal
+			err = json.NewDecoder(response.Body).Decode(&principal)
+			if err != nil {
+				t.Fatalf("failed to decode principal: %v", err)
+			}
+			if principal.UserID != "dev|leader" || principal.Email != "leader@example.com" || !principal.EmailVerified || principal.Provider != "dev" {
+				t.Errorf("unexpected principal %+v", principal)
+			}
+			if !slices.Contains(principal.Scopes, ROLE_SCOPE_PREFIX+string(ROLE_LEADER)) {
+				t.Errorf("scopes %v are missing the leader role", principal.Scopes)
+			}
+
+		})
+	}
+
+}
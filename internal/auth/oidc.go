@@ -0,0 +1,163 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/auth0/go-jwt-middleware/v2/jwks"
+	"github.com/auth0/go-jwt-middleware/v2/validator"
+	"go.uber.org/zap"
+)
+
+const (
+	JWKS_CACHE_TTL     = 5 * time.Minute
+	ALLOWED_CLOCK_SKEW = time.Minute
+)
+
+type OIDCProviderOptions struct {
+	Name     string
+	Issuer   string
+	Audience string
+	// when set, the profile is read from the userinfo endpoint and cached for UserInfoTTL
+	UserInfoURL string
+	UserInfoTTL time.Duration
+	// used to fetch the issuer's signing keys and user information, defaults to http.DefaultClient
+	Client *http.Client
+}
+
+// validates RS256 access tokens against the signing keys the issuer publishes through OIDC discovery
+type OIDCProvider struct {
+	logger    *zap.SugaredLogger
+	name      string
+	issuer    string
+	validator *validator.Validator
+	userInfo  *UserInfoCache
+}
+
+// the scope, plus the profile claims an ID token carries when the issuer is set up to add them to access tokens
+type customClaims struct {
+	Scope    string `json:"scope"`
+	Name     string `json:"name"`
+	Nickname string `json:"nickname"`
+	Email    string `json:"email"`
+	Verified bool   `json:"email_verified"`
+	Picture  string `json:"picture"`
+}
+
+func (c customClaims) Validate(ctx context.Context) error {
+	return nil
+}
+
+func NewOIDCProvider(logger *zap.SugaredLogger, options OIDCProviderOptions) (*OIDCProvider, error) {
+
+	issuerURL, err := url.Parse(options.Issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	client := options.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	keys := jwks.NewCachingProvider(issuerURL, JWKS_CACHE_TTL, jwks.WithCustomClient(client))
+
+	tokenValidator, err := validator.New(
+		keys.KeyFunc,
+		validator.RS256,
+		issuerURL.String(),
+		[]string{options.Audience},
+		validator.WithCustomClaims(
+			func() validator.CustomClaims {
+				return &customClaims{}
+			},
+		),
+		validator.WithAllowedClockSkew(ALLOWED_CLOCK_SKEW),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := &OIDCProvider{
+		logger:    logger,
+		name:      options.Name,
+		issuer:    issuerURL.String(),
+		validator: tokenValidator,
+	}
+
+	if options.UserInfoURL != "" {
+		provider.userInfo = NewUserInfoCache(options.UserInfoURL, options.UserInfoTTL, client)
+	}
+
+	return provider, nil
+
+}
+
+func (p *OIDCProvider) Name() string {
+	return p.name
+}
+
+func (p *OIDCProvider) Issuer() string {
+	return p.issuer
+}
+
+func (p *OIDCProvider) Authenticate(ctx context.Context, token string) (Principal, error) {
+
+	validated, err := p.validator.ValidateToken(ctx, token)
+	if err != nil {
+		p.logger.Infof("Failed to validate %s token: %v", p.name, err)
+		return Principal{}, ErrBadToken
+	}
+
+	claims, ok := validated.(*validator.ValidatedClaims)
+	if !ok {
+		return Principal{}, ErrBadToken
+	}
+
+	principal := Principal{
+		UserID:   claims.RegisteredClaims.Subject,
+		Issuer:   claims.RegisteredClaims.Issuer,
+		Provider: p.name,
+	}
+
+	if custom, ok := claims.CustomClaims.(*customClaims); ok {
+		principal.Name = ternary(custom.Name, custom.Nickname)
+		principal.Email = custom.Email
+		principal.EmailVerified = custom.Verified
+		principal.Picture = custom.Picture
+		principal.Scopes = strings.Fields(custom.Scope)
+	}
+
+	if p.userInfo == nil {
+		return principal, nil
+	}
+
+	info, err := p.userInfo.Get(ctx, principal.UserID, token)
+	switch {
+	case err == nil:
+		principal.Name = ternary(ternary(info.Name, info.Nickname), principal.Name)
+		principal.Email = ternary(info.Email, principal.Email)
+		principal.EmailVerified = info.Verified || principal.EmailVerified
+		principal.Picture = ternary(info.Picture, principal.Picture)
+	case errors.Is(err, ErrUserInfoUnavailable) && principal.Email != "":
+		// the token's own profile claims are enough to carry on with until the endpoint recovers
+		p.logger.Warnf("Falling back to %s token claims: %v", p.name, err)
+	default:
+		p.logger.Infof("Failed to get %s user info: %v", p.name, err)
+		return Principal{}, err
+	}
+
+	return principal, nil
+
+}
+
+func ternary(s1 string, s2 string) string {
+	if s1 == "" {
+		return s2
+	}
+	return s1
+}
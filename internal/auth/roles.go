@@ -0,0 +1,180 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Role string
+
+type Permission string
+
+const (
+	ROLE_MEMBER   Role = "member"
+	ROLE_GUARDIAN Role = "guardian"
+	ROLE_LEADER   Role = "leader"
+	ROLE_AGENT    Role = "agent"
+	ROLE_JUDGE    Role = "judge"
+	ROLE_ADMIN    Role = "admin"
+
+	// token scopes of the form role:<role> grant that role for the life of the token
+	ROLE_SCOPE_PREFIX = "role:"
+)
+
+const (
+	PERMISSION_PROFILE_MANAGE Permission = "profile:manage"
+	PERMISSION_REFERENCE_READ Permission = "reference:read"
+	PERMISSION_RECORDS_READ   Permission = "records:read"
+	PERMISSION_RECORDS_WRITE  Permission = "records:write"
+	PERMISSION_LINKED_ACT     Permission = "linked:act"
+	PERMISSION_CLUB_READ      Permission = "club:read"
+	PERMISSION_CLUB_MANAGE    Permission = "club:manage"
+	PERMISSION_COUNTY_READ    Permission = "county:read"
+	PERMISSION_JUDGE          Permission = "submissions:judge"
+	PERMISSION_JUDGING_MANAGE Permission = "judging:manage"
+	PERMISSION_RECORDS_REVIEW Permission = "records:review"
+	PERMISSION_ROLES_MANAGE   Permission = "roles:manage"
+)
+
+var ROLES = []Role{ROLE_MEMBER, ROLE_GUARDIAN, ROLE_LEADER, ROLE_AGENT, ROLE_JUDGE, ROLE_ADMIN}
+
+// what each role may do. a principal may do anything that any of their roles may do
+var ROLE_PERMISSIONS = map[Role][]Permission{
+	ROLE_MEMBER: {
+		PERMISSION_PROFILE_MANAGE,
+		PERMISSION_REFERENCE_READ,
+		PERMISSION_RECORDS_READ,
+		PERMISSION_RECORDS_WRITE,
+	},
+	ROLE_GUARDIAN: {
+		PERMISSION_PROFILE_MANAGE,
+		PERMISSION_REFERENCE_READ,
+		PERMISSION_LINKED_ACT,
+	},
+	ROLE_LEADER: {
+		PERMISSION_PROFILE_MANAGE,
+		PERMISSION_REFERENCE_READ,
+		PERMISSION_CLUB_READ,
+		PERMISSION_CLUB_MANAGE,
+		PERMISSION_RECORDS_REVIEW,
+	},
+	ROLE_AGENT: {
+		PERMISSION_PROFILE_MANAGE,
+		PERMISSION_REFERENCE_READ,
+		PERMISSION_CLUB_READ,
+		PERMISSION_COUNTY_READ,
+		PERMISSION_JUDGE,
+		PERMISSION_JUDGING_MANAGE,
+		PERMISSION_RECORDS_REVIEW,
+	},
+	ROLE_JUDGE: {
+		PERMISSION_PROFILE_MANAGE,
+		PERMISSION_REFERENCE_READ,
+		PERMISSION_JUDGE,
+		PERMISSION_RECORDS_REVIEW,
+	},
+	ROLE_ADMIN: {
+		PERMISSION_PROFILE_MANAGE,
+		PERMISSION_REFERENCE_READ,
+		PERMISSION_CLUB_READ,
+		PERMISSION_COUNTY_READ,
+		PERMISSION_JUDGE,
+		PERMISSION_JUDGING_MANAGE,
+		PERMISSION_RECORDS_REVIEW,
+		PERMISSION_ROLES_MANAGE,
+	},
+}
+
+var ErrForbidden = errors.New("your role does not allow this")
+
+func IsRole(role string) bool {
+	return slices.Contains(ROLES, Role(role))
+}
+
+// the roles granted by a token's scopes
+func RolesFromScopes(scopes []string) []Role {
+	roles := []Role{}
+	for _, scope := range scopes {
+		role, ok := strings.CutPrefix(scope, ROLE_SCOPE_PREFIX)
+		if ok && IsRole(role) {
+			roles = append(roles, Role(role))
+		}
+	}
+	return roles
+}
+
+func (p Principal) HasRole(role Role) bool {
+	return slices.Contains(p.Roles, role)
+}
+
+func (p Principal) Permissions() []Permission {
+	permissions := []Permission{}
+	for _, role := range p.Roles {
+		for _, permission := range ROLE_PERMISSIONS[role] {
+			if !slices.Contains(permissions, permission) {
+				permissions = append(permissions, permission)
+			}
+		}
+	}
+	return permissions
+}
+
+func (p Principal) Can(permission Permission) bool {
+	for _, role := range p.Roles {
+		if slices.Contains(ROLE_PERMISSIONS[role], permission) {
+			return true
+		}
+	}
+	return false
+}
+
+// stores a principal whose roles have been resolved, replacing the one stored by Middleware
+func SetPrincipal(c *gin.Context, principal Principal) {
+	c.Set(CONTEXT_KEY_PRINCIPAL, principal)
+	c.Request = c.Request.WithContext(WithPrincipal(c.Request.Context(), principal))
+}
+
+// rejects the request with a 403 unless the principal has every one of the permissions. meant for route groups,
+// behind the middleware that resolves the principal's roles
+func RequirePermissions(permissions ...Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		principal, err := FromContext(c)
+		if err != nil {
+			abort(c, err)
+			return
+		}
+
+		for _, permission := range permissions {
+			if !principal.Can(permission) {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+					"message": ErrForbidden.Error(),
+				})
+				return
+			}
+		}
+
+		c.Next()
+
+	}
+}
+
+// requires the read permission for GET and HEAD requests and the write permission for everything else
+func RequireReadWrite(read Permission, write Permission) gin.HandlerFunc {
+
+	requireRead := RequirePermissions(read)
+	requireWrite := RequirePermissions(read, write)
+
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			requireRead(c)
+		} else {
+			requireWrite(c)
+		}
+	}
+
+}
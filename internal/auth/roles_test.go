@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequirePermissions(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		roles  []Role
+		method string
+		path   string
+		code   int
+	}{
+		{"member reads their records", []Role{ROLE_MEMBER}, http.MethodGet, "/records", http.StatusOK},
+		{"member writes their records", []Role{ROLE_MEMBER}, http.MethodPost, "/records", http.StatusOK},
+		{"guardian can't read records of their own", []Role{ROLE_GUARDIAN}, http.MethodGet, "/records", http.StatusForbidden},
+		{"guardian who is also a member writes records", []Role{ROLE_MEMBER, ROLE_GUARDIAN}, http.MethodPut, "/records", http.StatusOK},
+		{"agent reads a club", []Role{ROLE_AGENT}, http.MethodGet, "/club", http.StatusOK},
+		{"agent can't manage a club", []Role{ROLE_AGENT}, http.MethodPost, "/club", http.StatusForbidden},
+		{"leader manages a club", []Role{ROLE_MEMBER, ROLE_LEADER}, http.MethodDelete, "/club", http.StatusOK},
+		{"member can't read a club", []Role{ROLE_MEMBER}, http.MethodGet, "/club", http.StatusForbidden},
+		{"judge judges", []Role{ROLE_MEMBER, ROLE_JUDGE}, http.MethodGet, "/judging", http.StatusOK},
+		{"judge can't manage judging", []Role{ROLE_MEMBER, ROLE_JUDGE}, http.MethodGet, "/county", http.StatusForbidden},
+		{"agent manages judging", []Role{ROLE_AGENT}, http.MethodPost, "/county", http.StatusOK},
+		{"only admins manage roles", []Role{ROLE_MEMBER, ROLE_LEADER, ROLE_AGENT, ROLE_JUDGE}, http.MethodGet, "/roles", http.StatusForbidden},
+		{"admin manages roles", []Role{ROLE_ADMIN}, http.MethodPost, "/roles", http.StatusOK},
+		{"no principal", nil, http.MethodGet, "/records", http.StatusUnauthorized},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			router := gin.New()
+			router.Use(func(c *gin.Context) {
+				if test.roles != nil {
+					SetPrincipal(c, Principal{UserID: "user", Roles: test.roles})
+				}
+			})
+
+			ok := func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			}
+
+			router.Any("/records", RequireReadWrite(PERMISSION_RECORDS_READ, PERMISSION_RECORDS_WRITE), ok)
+			router.Any("/club", RequireReadWrite(PERMISSION_CLUB_READ, PERMISSION_CLUB_MANAGE), ok)
+			router.Any("/judging", RequirePermissions(PERMISSION_JUDGE), ok)
+			router.Any("/county", RequirePermissions(PERMISSION_JUDGING_MANAGE), ok)
+			router.Any("/roles", RequirePermissions(PERMISSION_ROLES_MANAGE), ok)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(test.method, test.path, nil))
+
+			if recorder.Code != test.code {
+				t.Errorf("got %d, want %d", recorder.Code, test.code)
+			}
+
+		})
+	}
+
+}
@@ -0,0 +1,172 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	DEFAULT_USERINFO_TTL = 5 * time.Minute
+	USERINFO_TIMEOUT     = 5 * time.Second
+
+	// expired entries are swept out once the cache grows past this many subjects
+	USERINFO_CACHE_SWEEP_SIZE = 1024
+)
+
+var ErrUserInfoUnavailable = errors.New("user information is temporarily unavailable")
+
+type UserInfo struct {
+	Sub      string `json:"sub"`
+	Name     string `json:"name"`
+	Nickname string `json:"nickname"`
+	Email    string `json:"email"`
+	Verified bool   `json:"email_verified"`
+	Picture  string `json:"picture"`
+}
+
+type userInfoEntry struct {
+	info    UserInfo
+	expires time.Time
+}
+
+// caches the userinfo endpoint's response for each subject so that it isn't called on every request. concurrent
+// lookups for the same subject share a single call
+type UserInfoCache struct {
+	endpoint string
+	ttl      time.Duration
+	client   *http.Client
+
+	mutex   sync.Mutex
+	entries map[string]userInfoEntry
+	group   singleflight.Group
+}
+
+func NewUserInfoCache(endpoint string, ttl time.Duration, client *http.Client) *UserInfoCache {
+
+	if ttl <= 0 {
+		ttl = DEFAULT_USERINFO_TTL
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &UserInfoCache{
+		endpoint: endpoint,
+		ttl:      ttl,
+		client:   client,
+		entries:  make(map[string]userInfoEntry),
+	}
+
+}
+
+// the user information for the token's subject. ErrBadToken means the endpoint rejected the token, and
+// ErrUserInfoUnavailable that it couldn't be reached or failed
+func (uc *UserInfoCache) Get(ctx context.Context, subject string, token string) (UserInfo, error) {
+
+	if info, ok := uc.cached(subject); ok {
+		return info, nil
+	}
+
+	result, err, _ := uc.group.Do(subject, func() (interface{}, error) {
+
+		if info, ok := uc.cached(subject); ok {
+			return info, nil
+		}
+
+		info, err := uc.fetch(ctx, token)
+		if err != nil {
+			return UserInfo{}, err
+		}
+
+		if info.Sub != subject {
+			return UserInfo{}, ErrBadToken
+		}
+
+		uc.store(subject, info)
+
+		return info, nil
+
+	})
+
+	return result.(UserInfo), err
+
+}
+
+func (uc *UserInfoCache) cached(subject string) (UserInfo, bool) {
+
+	uc.mutex.Lock()
+	defer uc.mutex.Unlock()
+
+	entry, ok := uc.entries[subject]
+	if !ok || time.Now().After(entry.expires) {
+		return UserInfo{}, false
+	}
+
+	return entry.info, true
+
+}
+
+func (uc *UserInfoCache) store(subject string, info UserInfo) {
+
+	uc.mutex.Lock()
+	defer uc.mutex.Unlock()
+
+	now := time.Now()
+
+	if len(uc.entries) >= USERINFO_CACHE_SWEEP_SIZE {
+		for key, entry := range uc.entries {
+			if now.After(entry.expires) {
+				delete(uc.entries, key)
+			}
+		}
+	}
+
+	uc.entries[subject] = userInfoEntry{
+		info:    info,
+		expires: now.Add(uc.ttl),
+	}
+
+}
+
+func (uc *UserInfoCache) fetch(ctx context.Context, token string) (UserInfo, error) {
+
+	var info UserInfo
+
+	// the call is shared with other requests, so it shouldn't be cut short when the request that started it is cancelled
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), USERINFO_TIMEOUT)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uc.endpoint, nil)
+	if err != nil {
+		return info, fmt.Errorf("%w: %v", ErrUserInfoUnavailable, err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	res, err := uc.client.Do(req)
+	if err != nil {
+		return info, fmt.Errorf("%w: %v", ErrUserInfoUnavailable, err)
+	}
+	defer res.Body.Close()
+
+	switch {
+	case res.StatusCode == http.StatusUnauthorized || res.StatusCode == http.StatusForbidden:
+		return info, ErrBadToken
+	case res.StatusCode != http.StatusOK:
+		return info, fmt.Errorf("%w: userinfo endpoint responded with %d", ErrUserInfoUnavailable, res.StatusCode)
+	}
+
+	err = json.NewDecoder(res.Body).Decode(&info)
+	if err != nil {
+		return info, fmt.Errorf("%w: %v", ErrUserInfoUnavailable, err)
+	}
+
+	return info, nil
+
+}
@@ -4,6 +4,8 @@ import (
 	_ "embed"
 	"encoding/json"
 	"os"
+	"slices"
+	"time"
 
 	"go.uber.org/zap"
 )
@@ -14,13 +16,62 @@ var configJSON []byte
 const (
 	MAX_PAGE_SIZE  = 500
 	PRODUCTION_ENV = "PRODUCTION"
+
+	// the Oregon 4-H program year runs October 1 to September 30
+	DEFAULT_PROGRAM_YEAR_START_MONTH = 10
+	DEFAULT_PROGRAM_YEAR_START_DAY   = 1
+
+	// always offered so that members can describe costs that don't fit the configured categories
+	EXPENSE_CATEGORY_OTHER = "other"
+	// feed purchases are totalled under this category on the expense summary
+	EXPENSE_CATEGORY_FEED = "feed"
+
+	// calendar dates sent as timestamps are read in the user's time zone, or this one when they haven't set one
+	DEFAULT_TIME_ZONE = "America/Los_Angeles"
+
+	DEFAULT_USERINFO_TTL_SECONDS = 300
+
+	// the local issuer is served by the API itself when it runs on its default port
+	DEFAULT_DEV_ISSUER   = "http://localhost:8080/dev-issuer/"
+	DEFAULT_DEV_AUDIENCE = "4h-recordbook-dev"
 )
 
+// the test users minted by the local issuer when the config doesn't name any
+var DEFAULT_DEV_USERS = []DevUser{
+	{Name: "member", Subject: "dev|member", Email: "member@example.com"},
+}
+
+// the categories on the record book's expense summary
+var DEFAULT_EXPENSE_CATEGORIES = []string{EXPENSE_CATEGORY_FEED, "vet_health", "equipment", "entry_fees", "bedding", "transport", EXPENSE_CATEGORY_OTHER}
+
+// the divisions judged record books are placed in, by the member's age on the first day of the program year
+var DEFAULT_AGE_DIVISIONS = []AgeDivision{
+	{Name: "junior", MinAge: 9, MaxAge: 11},
+	{Name: "intermediate", MinAge: 12, MaxAge: 14},
+	{Name: "senior", MinAge: 15, MaxAge: 19},
+}
+
 type Config struct {
-	MaxPageSize int      `json:"max_page_size"`
-	Database    Database `json:"cosmos"`
-	Upc         Upc      `json:"upc"`
-	Auth0		Auth0    `json:"auth0"`
+	MaxPageSize       int           `json:"max_page_size"`
+	Database          Database      `json:"cosmos"`
+	Upc               Upc           `json:"upc"`
+	Auth0             Auth0         `json:"auth0"`
+	ProgramYear       ProgramYear   `json:"program_year"`
+	ExpenseCategories []string      `json:"expense_categories"`
+	TimeZone          string        `json:"time_zone"`
+	DevIssuer         DevIssuer     `json:"dev_issuer"`
+	AgeDivisions      []AgeDivision `json:"age_divisions"`
+}
+
+type ProgramYear struct {
+	StartMonth int `json:"start_month"`
+	StartDay   int `json:"start_day"`
+}
+
+type AgeDivision struct {
+	Name   string `json:"name"`
+	MinAge int    `json:"min_age"`
+	MaxAge int    `json:"max_age"`
 }
 
 type Database struct {
@@ -46,8 +97,24 @@ type UpcParams struct {
 }
 
 type Auth0 struct {
-	Domain string `json:"domain"`
-	Audience string `json:"audience"`
+	Domain             string `json:"domain"`
+	Audience           string `json:"audience"`
+	UserinfoURL        string `json:"userinfo_url"`
+	UserinfoTTLSeconds int    `json:"userinfo_ttl_seconds"`
+}
+
+type DevIssuer struct {
+	Enabled  bool      `json:"enabled"`
+	Issuer   string    `json:"issuer"`
+	Audience string    `json:"audience"`
+	Users    []DevUser `json:"users"`
+}
+
+type DevUser struct {
+	Name    string   `json:"name"`
+	Subject string   `json:"subject"`
+	Email   string   `json:"email"`
+	Roles   []string `json:"roles"`
 }
 
 func New(logger *zap.SugaredLogger) (*Config, error) {
@@ -64,9 +131,58 @@ func New(logger *zap.SugaredLogger) (*Config, error) {
 
 	c.MaxPageSize = MAX_PAGE_SIZE
 
+	if c.ProgramYear.StartMonth < 1 || c.ProgramYear.StartMonth > 12 || c.ProgramYear.StartDay < 1 || c.ProgramYear.StartDay > 28 {
+		logger.Debug("Using default program year boundary")
+		c.ProgramYear.StartMonth = DEFAULT_PROGRAM_YEAR_START_MONTH
+		c.ProgramYear.StartDay = DEFAULT_PROGRAM_YEAR_START_DAY
+	}
+
+	if len(c.ExpenseCategories) == 0 {
+		logger.Debug("Using default expense categories")
+		c.ExpenseCategories = DEFAULT_EXPENSE_CATEGORIES
+	}
+
+	if !slices.Contains(c.ExpenseCategories, EXPENSE_CATEGORY_OTHER) {
+		c.ExpenseCategories = append(c.ExpenseCategories, EXPENSE_CATEGORY_OTHER)
+	}
+
+	if len(c.AgeDivisions) == 0 {
+		logger.Debug("Using default age divisions")
+		c.AgeDivisions = DEFAULT_AGE_DIVISIONS
+	}
+
+	if _, err := time.LoadLocation(c.TimeZone); c.TimeZone == "" || err != nil {
+		logger.Debug("Using default time zone")
+		c.TimeZone = DEFAULT_TIME_ZONE
+	}
+
+	if c.Auth0.UserinfoURL == "" && c.Auth0.Domain != "" {
+		c.Auth0.UserinfoURL = "https://" + c.Auth0.Domain + "/userinfo"
+	}
+
+	if c.Auth0.UserinfoTTLSeconds <= 0 {
+		c.Auth0.UserinfoTTLSeconds = DEFAULT_USERINFO_TTL_SECONDS
+	}
+
+	if c.DevIssuer.Issuer == "" {
+		c.DevIssuer.Issuer = DEFAULT_DEV_ISSUER
+	}
+
+	if c.DevIssuer.Audience == "" {
+		c.DevIssuer.Audience = DEFAULT_DEV_AUDIENCE
+	}
+
+	if len(c.DevIssuer.Users) == 0 {
+		c.DevIssuer.Users = DEFAULT_DEV_USERS
+	}
+
 	env := os.Getenv("APP_ENV")
 	if env == PRODUCTION_ENV {
 		logger.Debug("Running with production config")
+		if c.DevIssuer.Enabled {
+			logger.Warn("Ignoring the dev issuer in production")
+			c.DevIssuer.Enabled = false
+		}
 		c.Database.Current = c.Database.Production
 		c.Upc.Current = c.Upc.Production
 	} else {
@@ -75,9 +191,6 @@ func New(logger *zap.SugaredLogger) (*Config, error) {
 		c.Upc.Current = c.Upc.Development
 	}
 
-	os.Setenv("AUTH0_DOMAIN", c.Auth0.Domain)
-	os.Setenv("AUTH0_AUDIENCE", c.Auth0.Audience)
-
 	return &c, nil
 
 }
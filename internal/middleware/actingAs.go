@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"4h-recordbook-backend/internal/auth"
+	"4h-recordbook-backend/pkg/db"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	HEADER_ACTING_AS = "X-Acting-As"
+
+	ErrNotLinkedGuardian = "you are not a linked guardian of that member"
+	ErrGuardianLookup    = "failed to look up the guardian link"
+)
+
+// lets a guardian act for a linked member by sending the member's user ID in the X-Acting-As header. the principal
+// becomes the member, with the member role only, and keeps the guardian as its actor so that both are audited
+func ActingAs(database db.Db) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		memberID := c.GetHeader(HEADER_ACTING_AS)
+		if memberID == "" {
+			c.Next()
+			return
+		}
+
+		principal, err := auth.FromContext(c)
+		if err != nil {
+			c.AbortWithStatusJSON(401, gin.H{
+				"message": err.Error(),
+			})
+			return
+		}
+
+		if memberID == principal.UserID {
+			c.Next()
+			return
+		}
+
+		if !principal.Can(auth.PERMISSION_LINKED_ACT) {
+			c.AbortWithStatusJSON(403, gin.H{
+				"message": ErrNotLinkedGuardian,
+			})
+			return
+		}
+
+		link, err := database.GetAcceptedGuardianLink(c.Request.Context(), memberID, principal.UserID)
+		if err != nil {
+			c.AbortWithStatusJSON(503, gin.H{
+				"message": ErrGuardianLookup,
+			})
+			return
+		}
+
+		if link == (db.GuardianLink{}) {
+			c.AbortWithStatusJSON(403, gin.H{
+				"message": ErrNotLinkedGuardian,
+			})
+			return
+		}
+
+		auth.SetPrincipal(c, auth.Principal{
+			UserID:    link.MemberID,
+			Name:      link.MemberName,
+			Roles:     []auth.Role{auth.ROLE_MEMBER},
+			Issuer:    principal.Issuer,
+			Provider:  principal.Provider,
+			ActorID:   principal.UserID,
+			ActorName: principal.Name,
+		})
+
+		// the member's records are shown in the member's time zone and units. if the member can't be read, handlers
+		// read them again
+		member, err := database.GetUser(c.Request.Context(), link.MemberID)
+		if err == nil {
+			SetUser(c, member)
+		}
+
+		c.Next()
+
+	}
+}
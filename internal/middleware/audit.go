@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"4h-recordbook-backend/internal/auth"
+	"4h-recordbook-backend/internal/utils"
+	"4h-recordbook-backend/pkg/db"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/beevik/guid"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const AUDIT_TIMEOUT = 5 * time.Second
+
+// records every change a user's records go through, and every request a guardian makes for them, in the user's
+// audit trail. the entry is written after the response, so it holds the status the request finished with
+func Audit(database db.Db, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		c.Next()
+
+		principal, err := auth.FromContext(c)
+		if err != nil {
+			return
+		}
+
+		isRead := c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead
+		if isRead && !principal.IsActing() {
+			return
+		}
+
+		actorName := principal.Name
+		if principal.IsActing() {
+			actorName = principal.ActorName
+		}
+
+		timestamp := utils.TimeNow()
+
+		entry := db.AuditEntry{
+			ID:        guid.New().String(),
+			UserID:    principal.UserID,
+			ActorID:   principal.Actor(),
+			ActorName: actorName,
+			Method:    c.Request.Method,
+			Path:      c.Request.URL.Path,
+			Status:    c.Writer.Status(),
+			GenericDatabaseInfo: db.GenericDatabaseInfo{
+				Created: timestamp.String(),
+				Updated: timestamp.String(),
+			},
+		}
+
+		// the request's own deadline may already have passed
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), AUDIT_TIMEOUT)
+		defer cancel()
+
+		_, err = database.AddAuditEntry(ctx, entry)
+		if err != nil {
+			logger.Errorf("Failed to audit %s %s by %s for %s: %v", entry.Method, entry.Path, entry.ActorID, entry.UserID, err)
+		}
+
+	}
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"4h-recordbook-backend/internal/auth"
+	"4h-recordbook-backend/pkg/db"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+)
+
+const ErrRoleLookup = "failed to look up the user's roles"
+
+// resolves the principal's roles from their token scopes and the roles container. every user is a member, so that
+// granting another role adds to what they can do rather than taking away their own record book
+func GetRoles(database db.Db) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		principal, err := auth.FromContext(c)
+		if err != nil {
+			c.AbortWithStatusJSON(401, gin.H{
+				"message": err.Error(),
+			})
+			return
+		}
+
+		roles := []auth.Role{auth.ROLE_MEMBER}
+		for _, role := range auth.RolesFromScopes(principal.Scopes) {
+			if !slices.Contains(roles, role) {
+				roles = append(roles, role)
+			}
+		}
+
+		assignments, err := database.GetRoleAssignmentsByUser(c.Request.Context(), principal.UserID)
+		if err != nil {
+			c.AbortWithStatusJSON(503, gin.H{
+				"message": ErrRoleLookup,
+			})
+			return
+		}
+
+		for _, assignment := range assignments {
+			role := auth.Role(assignment.Role)
+			if auth.IsRole(assignment.Role) && !slices.Contains(roles, role) {
+				roles = append(roles, role)
+			}
+		}
+
+		principal.Roles = roles
+		auth.SetPrincipal(c, principal)
+
+		c.Next()
+
+	}
+}
@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"4h-recordbook-backend/internal/auth"
+	"4h-recordbook-backend/pkg/db"
+	"context"
+	"errors"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type roleAssignmentsDb struct {
+	db.Db
+	assignments []db.RoleAssignment
+	err         error
+}
+
+func (d roleAssignmentsDb) GetRoleAssignmentsByUser(ctx context.Context, userID string) ([]db.RoleAssignment, error) {
+	return d.assignments, d.err
+}
+
+func TestGetRoles(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name        string
+		scopes      []string
+		assignments []db.RoleAssignment
+		err         error
+		code        int
+		roles       []auth.Role
+	}{
+		{
+			name:  "no roles",
+			code:  200,
+			roles: []auth.Role{auth.ROLE_MEMBER},
+		},
+		{
+			name:        "assigned leader keeps the record book",
+			assignments: []db.RoleAssignment{{Role: "leader"}},
+			code:        200,
+			roles:       []auth.Role{auth.ROLE_MEMBER, auth.ROLE_LEADER},
+		},
+		{
+			name:        "guardian keeps the record book",
+			assignments: []db.RoleAssignment{{Role: "guardian"}},
+			code:        200,
+			roles:       []auth.Role{auth.ROLE_MEMBER, auth.ROLE_GUARDIAN},
+		},
+		{
+			name:        "scopes and assignments are merged without repeats",
+			scopes:      []string{"openid", "role:agent", "role:member"},
+			assignments: []db.RoleAssignment{{Role: "agent", CountyName: "Benton"}, {Role: "judge", CountyName: "Benton"}},
+			code:        200,
+			roles:       []auth.Role{auth.ROLE_MEMBER, auth.ROLE_AGENT, auth.ROLE_JUDGE},
+		},
+		{
+			name:        "unknown roles are ignored",
+			assignments: []db.RoleAssignment{{Role: "owner"}},
+			code:        200,
+			roles:       []auth.Role{auth.ROLE_MEMBER},
+		},
+		{
+			name: "lookup failure",
+			err:  errors.New("unavailable"),
+			code: 503,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			var roles []auth.Role
+
+			router := gin.New()
+			router.Use(func(c *gin.Context) {
+				auth.SetPrincipal(c, auth.Principal{UserID: "user", Scopes: test.scopes})
+			}, GetRoles(roleAssignmentsDb{assignments: test.assignments, err: test.err}))
+			router.GET("/", func(c *gin.Context) {
+				principal, _ := auth.FromContext(c)
+				roles = principal.Roles
+				c.Status(200)
+			})
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest("GET", "/", nil))
+
+			if recorder.Code != test.code {
+				t.Fatalf("got %d, want %d", recorder.Code, test.code)
+			}
+			if test.code == 200 && !slices.Equal(roles, test.roles) {
+				t.Errorf("got roles %v, want %v", roles, test.roles)
+			}
+
+		})
+	}
+
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"4h-recordbook-backend/internal/auth"
 	"4h-recordbook-backend/pkg/db"
 	"4h-recordbook-backend/internal/utils"
 	"context"
@@ -8,19 +9,32 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+const CONTEXT_KEY_USER = "user"
+
+type userKey struct{}
+
+// the user is read once per request and kept with it, so handlers needing the user's preferences don't read it again
 func GetUser(database db.Db) gin.HandlerFunc {
 	return func(c* gin.Context){
+		principal, err := auth.FromContext(c)
+		if err != nil {
+			c.AbortWithStatusJSON(401, gin.H{
+				"message": err.Error(),
+			})
+			return
+		}
+
 		// Attempt to get the user
 
-		_, err := database.GetUser(context.TODO(), c.GetString("user_id"));
+		user, err := database.GetUser(context.TODO(), principal.UserID);
 		if(err != nil){
 			// Force create user
 			timestamp := utils.TimeNow()
-			user := db.User{
-				ID:                c.GetString("user_id"),
-				Email:             c.GetString("user_email"),
+			user = db.User{
+				ID:                principal.UserID,
+				Email:             principal.Email,
 				Birthdate:         "TODO",
-				FirstName:         c.GetString("user_name"),
+				FirstName:         principal.Name,
 				MiddleNameInitial: "TODO",
 				CountyName:        "TODO",
 				GenericDatabaseInfo: db.GenericDatabaseInfo{
@@ -37,7 +51,19 @@ func GetUser(database db.Db) gin.HandlerFunc {
 			}
 		}
 		// User exists! Continue on.
+		SetUser(c, user)
 
 		return;
 	}
 }
+
+func SetUser(c *gin.Context, user db.User) {
+	c.Set(CONTEXT_KEY_USER, user)
+	c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), userKey{}, user))
+}
+
+// the user stored by GetUser, or the member when a guardian is acting for them
+func UserFromContext(ctx context.Context) (db.User, bool) {
+	user, ok := ctx.Value(userKey{}).(db.User)
+	return user, ok
+}
@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"4h-recordbook-backend/internal/auth"
+	"4h-recordbook-backend/pkg/db"
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// a guardian in one time zone linked to a member in another, counting how often each is read
+type linkedUsersDb struct {
+	db.Db
+	reads map[string]int
+}
+
+func (d linkedUsersDb) GetUser(ctx context.Context, userID string) (db.User, error) {
+	d.reads[userID]++
+	if userID == "member" {
+		return db.User{ID: "member", TimeZone: "America/Chicago"}, nil
+	}
+	return db.User{ID: "guardian", TimeZone: "America/New_York"}, nil
+}
+
+func (linkedUsersDb) GetAcceptedGuardianLink(ctx context.Context, memberID string, guardianID string) (db.GuardianLink, error) {
+	return db.GuardianLink{MemberID: memberID, GuardianID: guardianID}, nil
+}
+
+func TestGetUserKeepsTheUserWithTheRequest(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		actingAs string
+		want     string
+		reads    map[string]int
+	}{
+		{"own records", "", "America/New_York", map[string]int{"guardian": 1}},
+		{"acting for a member", "member", "America/Chicago", map[string]int{"guardian": 1, "member": 1}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			database := linkedUsersDb{reads: map[string]int{}}
+			var got db.User
+
+			router := gin.New()
+			router.Use(func(c *gin.Context) {
+				auth.SetPrincipal(c, auth.Principal{UserID: "guardian", Roles: []auth.Role{auth.ROLE_GUARDIAN}})
+			}, GetUser(database), ActingAs(database))
+			router.GET("/", func(c *gin.Context) {
+				got, _ = UserFromContext(c.Request.Context())
+				c.Status(200)
+			})
+
+			request := httptest.NewRequest("GET", "/", nil)
+			if test.actingAs != "" {
+				request.Header.Set(HEADER_ACTING_AS, test.actingAs)
+			}
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+
+			if recorder.Code != 200 {
+				t.Fatalf("got %d, want 200", recorder.Code)
+			}
+			if got.TimeZone != test.want {
+				t.Errorf("got time zone %q, want %q", got.TimeZone, test.want)
+			}
+			for userID, reads := range test.reads {
+				if database.reads[userID] != reads {
+					t.Errorf("read %s %d times, want %d", userID, database.reads[userID], reads)
+				}
+			}
+
+		})
+	}
+
+}
@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"time"
+	// user time zones are loaded by name, so the zone database is embedded in case the host doesn't have one
+	_ "time/tzdata"
+)
+
+const DATE_LAYOUT = "2006-01-02"
+
+// calendar dates such as birth dates and feed dates have no time of day or zone. they are formatted YYYY-MM-DD and
+// held as midnight UTC so that comparing and adding days never crosses a day boundary
+type Date time.Time
+
+func NewDate(year int, month time.Month, day int) Date {
+	return Date(time.Date(year, month, day, 0, 0, 0, 0, time.UTC))
+}
+
+// the calendar date of t as seen in the given location
+func DateOf(t time.Time, location *time.Location) Date {
+	year, month, day := t.In(location).Date()
+	return NewDate(year, month, day)
+}
+
+func Today(location *time.Location) Date {
+	return DateOf(time.Now(), location)
+}
+
+func (d Date) String() string {
+	return time.Time(d).Format(DATE_LAYOUT)
+}
+
+func (d Date) Time() time.Time {
+	return time.Time(d)
+}
+
+// accepts YYYY-MM-DD, or an RFC 3339 timestamp which is read as the calendar date it falls on in the given location.
+// clients that send midnight local time as a timestamp keep the date they meant
+func StringToDate(input string, location *time.Location) (Date, error) {
+
+	parsedDate, err := time.Parse(DATE_LAYOUT, input)
+	if err == nil {
+		return Date(parsedDate), nil
+	}
+
+	parsedTime, err := time.Parse(time.RFC3339Nano, input)
+	if err != nil {
+		return Date{}, err
+	}
+
+	return DateOf(parsedTime, location), nil
+
+}
+
+func (d Date) AddDays(days int) Date {
+	return Date(time.Time(d).AddDate(0, 0, days))
+}
+
+func (d Date) AddYears(years int) Date {
+	return Date(time.Time(d).AddDate(years, 0, 0))
+}
+
+// the whole years from d to other, such as someone's age on other when d is their birth date
+func (d Date) YearsTo(other Date) int {
+	years := time.Time(other).Year() - time.Time(d).Year()
+	if other.Before(d.AddYears(years)) {
+		years--
+	}
+	return years
+}
+
+func (d Date) Before(other Date) bool {
+	return time.Time(d).Before(time.Time(other))
+}
+
+func (d Date) After(other Date) bool {
+	return time.Time(d).After(time.Time(other))
+}
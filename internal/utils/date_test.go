@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDateYearsTo(t *testing.T) {
+
+	date := func(year int, month time.Month, day int) Date {
+		return Date(time.Date(year, month, day, 0, 0, 0, 0, time.UTC))
+	}
+
+	tests := []struct {
+		name  string
+		from  Date
+		to    Date
+		years int
+	}{
+		{"same day", date(2010, time.May, 4), date(2010, time.May, 4), 0},
+		{"day before a birthday", date(2010, time.May, 4), date(2025, time.May, 3), 14},
+		{"on a birthday", date(2010, time.May, 4), date(2025, time.May, 4), 15},
+		{"day after a birthday", date(2010, time.May, 4), date(2025, time.May, 5), 15},
+		{"birthday later in the program year", date(2012, time.December, 1), date(2025, time.October, 1), 12},
+		{"leap day birthday before march", date(2008, time.February, 29), date(2025, time.February, 28), 16},
+		{"leap day birthday from march", date(2008, time.February, 29), date(2025, time.March, 1), 17},
+		{"leap day birthday in a leap year", date(2008, time.February, 29), date(2024, time.February, 29), 16},
+		{"before the date", date(2025, time.May, 4), date(2024, time.May, 4), -1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.from.YearsTo(test.to)
+			if got != test.years {
+				t.Errorf("YearsTo = %d, want %d", got, test.years)
+			}
+		})
+	}
+
+}
@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// an amount of money in whole cents. it is written to and read from JSON as a plain decimal number, e.g. 12.34, so it
+// can stand in for the float64 fields it replaces, and any extra precision is rounded half away from zero to the cent
+type Money int64
+
+func MoneyFromFloat(amount float64) Money {
+	return Money(math.Round(amount * 100))
+}
+
+func (m Money) Float64() float64 {
+	return float64(m) / 100
+}
+
+// multiplies by a factor and rounds to the cent
+func (m Money) Scale(factor float64) Money {
+	return Money(math.Round(float64(m) * factor))
+}
+
+func (m Money) String() string {
+	sign := ""
+	cents := int64(m)
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+func (m *Money) UnmarshalJSON(data []byte) error {
+
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	data = bytes.Trim(data, `"`)
+
+	amount, ok := new(big.Rat).SetString(string(data))
+	if !ok {
+		return fmt.Errorf("%q is not an amount of money", data)
+	}
+	amount.Mul(amount, big.NewRat(100, 1))
+
+	remainder := new(big.Int)
+	cents, remainder := new(big.Int).QuoRem(amount.Num(), amount.Denom(), remainder)
+
+	// round half away from zero
+	remainder.Abs(remainder).Mul(remainder, big.NewInt(2))
+	if remainder.Cmp(amount.Denom()) >= 0 {
+		cents.Add(cents, big.NewInt(int64(amount.Sign())))
+	}
+
+	if !cents.IsInt64() {
+		return fmt.Errorf("%q is too large an amount of money", data)
+	}
+
+	*m = Money(cents.Int64())
+
+	return nil
+
+}
@@ -0,0 +1,123 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+var programYearPattern = regexp.MustCompile(`^(\d{4})(?:-(\d{4}))?$`)
+
+var legacyYearPattern = regexp.MustCompile(`^\d{4}$`)
+
+// a program year runs from its start month and day until the day before the same date in the following year. program years
+// that start on January 1 are labelled by a single year, e.g. "2025", and the rest by both years they span, e.g. "2025-2026"
+type ProgramYear struct {
+	StartMonth time.Month
+	StartDay   int
+}
+
+func NewProgramYear(startMonth int, startDay int) ProgramYear {
+	return ProgramYear{
+		StartMonth: time.Month(startMonth),
+		StartDay:   startDay,
+	}
+}
+
+func (py ProgramYear) spansCalendarYears() bool {
+	return py.StartMonth != time.January || py.StartDay != 1
+}
+
+func (py ProgramYear) startOf(year int) time.Time {
+	return time.Date(year, py.StartMonth, py.StartDay, 0, 0, 0, 0, time.UTC)
+}
+
+// the calendar year in which the program year containing t started
+func (py ProgramYear) startYear(t time.Time) int {
+	year := t.Year()
+	if time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Before(py.startOf(year)) {
+		year--
+	}
+	return year
+}
+
+func (py ProgramYear) label(startYear int) string {
+	if py.spansCalendarYears() {
+		return fmt.Sprintf("%d-%d", startYear, startYear+1)
+	}
+	return strconv.Itoa(startYear)
+}
+
+func (py ProgramYear) Of(t time.Time) string {
+	return py.label(py.startYear(t))
+}
+
+// the program year of today's date in the given location
+func (py ProgramYear) Current(location *time.Location) string {
+	return py.Of(time.Now().In(location))
+}
+
+func (py ProgramYear) parse(label string) (int, error) {
+
+	matches := programYearPattern.FindStringSubmatch(label)
+	if matches == nil {
+		return 0, fmt.Errorf("program year %q is malformed", label)
+	}
+
+	startYear, _ := strconv.Atoi(matches[1])
+
+	if py.label(startYear) != label {
+		return 0, fmt.Errorf("program year %q should be written as %q", label, py.label(startYear))
+	}
+
+	return startYear, nil
+
+}
+
+func (py ProgramYear) IsValid(label string) bool {
+	_, err := py.parse(label)
+	return err == nil
+}
+
+// the first day of the program year and the first day of the next one
+func (py ProgramYear) Bounds(label string) (time.Time, time.Time, error) {
+
+	startYear, err := py.parse(label)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	return py.startOf(startYear), py.startOf(startYear + 1), nil
+
+}
+
+// the label for a year written before program years were configurable, when records were labelled by a single
+// calendar year. that year is taken to be the one the program year ends in, e.g. "2025" is "2024-2025" when program
+// years start on October 1. labels that are already valid are returned as they are
+func (py ProgramYear) FromLegacy(label string) (string, error) {
+
+	if py.IsValid(label) {
+		return label, nil
+	}
+
+	if !legacyYearPattern.MatchString(label) {
+		return "", fmt.Errorf("program year %q is malformed", label)
+	}
+
+	year, _ := strconv.Atoi(label)
+
+	return py.label(year - 1), nil
+
+}
+
+func (py ProgramYear) Next(label string) (string, error) {
+
+	startYear, err := py.parse(label)
+	if err != nil {
+		return "", err
+	}
+
+	return py.label(startYear + 1), nil
+
+}
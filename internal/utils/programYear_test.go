@@ -0,0 +1,133 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+var (
+	octoberYear  = NewProgramYear(10, 1)
+	calendarYear = NewProgramYear(1, 1)
+)
+
+func TestProgramYearOf(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		programYear ProgramYear
+		date        time.Time
+		want        string
+	}{
+		{"day before the start", octoberYear, time.Date(2025, 9, 30, 23, 59, 0, 0, time.UTC), "2024-2025"},
+		{"first day", octoberYear, time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC), "2025-2026"},
+		{"new year's day", octoberYear, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), "2025-2026"},
+		{"calendar year", calendarYear, time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC), "2025"},
+		{"calendar year start", calendarYear, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), "2026"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.programYear.Of(test.date); got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+
+}
+
+func TestProgramYearIsValid(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		programYear ProgramYear
+		label       string
+		want        bool
+	}{
+		{"spanning label", octoberYear, "2025-2026", true},
+		{"single year when spanning", octoberYear, "2025", false},
+		{"years not consecutive", octoberYear, "2025-2027", false},
+		{"malformed", octoberYear, "25-26", false},
+		{"empty", octoberYear, "", false},
+		{"calendar label", calendarYear, "2025", true},
+		{"spanning label for calendar years", calendarYear, "2025-2026", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.programYear.IsValid(test.label); got != test.want {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+
+}
+
+func TestProgramYearNext(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		programYear ProgramYear
+		label       string
+		want        string
+		wantErr     bool
+	}{
+		{"spanning", octoberYear, "2025-2026", "2026-2027", false},
+		{"calendar", calendarYear, "2025", "2026", false},
+		{"invalid", octoberYear, "2025", "", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := test.programYear.Next(test.label)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error %v", err, test.wantErr)
+			}
+			if got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+
+}
+
+func TestProgramYearBounds(t *testing.T) {
+
+	start, end, err := octoberYear.Bounds("2025-2026")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !start.Equal(time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)) || !end.Equal(time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("got %v to %v", start, end)
+	}
+
+}
+
+func TestProgramYearFromLegacy(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		programYear ProgramYear
+		label       string
+		want        string
+		wantErr     bool
+	}{
+		{"calendar year becomes the program year ending in it", octoberYear, "2025", "2024-2025", false},
+		{"program year is kept", octoberYear, "2025-2026", "2025-2026", false},
+		{"calendar years are already valid", calendarYear, "2025", "2025", false},
+		{"malformed", octoberYear, "last year", "", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := test.programYear.FromLegacy(test.label)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error %v", err, test.wantErr)
+			}
+			if got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+
+}
@@ -25,3 +25,7 @@ func StringToTimestamp(input string) (Timestamp, error) {
 	return Timestamp(parsedTime), nil
 
 }
+
+func (t Timestamp) Before(other Timestamp) bool {
+	return time.Time(t).Before(time.Time(other))
+}
@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	UNIT_SYSTEM_IMPERIAL = "imperial"
+	UNIT_SYSTEM_METRIC   = "metric"
+
+	UNIT_POUND    = "lb"
+	UNIT_OUNCE    = "oz"
+	UNIT_KILOGRAM = "kg"
+	UNIT_GRAM     = "g"
+
+	// weights and feed amounts are stored in pounds
+	CANONICAL_MASS_UNIT = UNIT_POUND
+)
+
+var poundsPerUnit = map[string]float64{
+	UNIT_POUND:    1,
+	UNIT_OUNCE:    1.0 / 16,
+	UNIT_KILOGRAM: 2.20462262185,
+	UNIT_GRAM:     0.00220462262185,
+}
+
+func IsUnitSystem(system string) bool {
+	return system == UNIT_SYSTEM_IMPERIAL || system == UNIT_SYSTEM_METRIC
+}
+
+func IsMassUnit(unit string) bool {
+	_, ok := poundsPerUnit[unit]
+	return ok
+}
+
+// the unit weights are shown in for a unit system. users without a preference see imperial units
+func MassUnit(system string) string {
+	if system == UNIT_SYSTEM_METRIC {
+		return UNIT_KILOGRAM
+	}
+	return UNIT_POUND
+}
+
+// conversions are rounded to the thousandth to keep floating point noise out of stored and returned values
+func roundQuantity(value float64) float64 {
+	return math.Round(value*1000) / 1000
+}
+
+func ToCanonicalMass(value float64, unit string) (float64, error) {
+	factor, ok := poundsPerUnit[unit]
+	if !ok {
+		return 0, fmt.Errorf("%q is not a unit of mass", unit)
+	}
+	return roundQuantity(value * factor), nil
+}
+
+func FromCanonicalMass(value float64, unit string) float64 {
+	factor, ok := poundsPerUnit[unit]
+	if !ok {
+		return value
+	}
+	return roundQuantity(value / factor)
+}
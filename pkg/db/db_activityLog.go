@@ -0,0 +1,211 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/data/azcosmos"
+)
+
+type ActivityLog struct {
+	ID          string  `json:"id"`
+	Date        string  `json:"date"`
+	Title       string  `json:"title"`
+	Description string  `json:"description"`
+	Hours       float64 `json:"hours"`
+	ProjectID   string  `json:"project_id"`
+	UserID      string  `json:"user_id"`
+	GenericDatabaseInfo
+}
+
+func (al ActivityLog) GetID() string {
+	return al.ID
+}
+
+func (env *env) GetActivityLogsByProject(ctx context.Context, userID string, projectID string, paginationOptions PaginationOptions) ([]ActivityLog, error) {
+
+	env.logger.Info("Getting activity logs by project")
+
+	container, err := env.client.NewContainer("activitylogs")
+	if err != nil {
+		return []ActivityLog{}, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	sortOrder := "ASC"
+	if paginationOptions.SortByNewest {
+		sortOrder = "DESC"
+	}
+
+	query := fmt.Sprintf("SELECT * FROM activitylogs al WHERE al.user_id = @user_id AND al.project_id = @project_id ORDER BY al.created %s", sortOrder)
+
+	queryOptions := azcosmos.QueryOptions{
+		QueryParameters: []azcosmos.QueryParameter{
+			{Name: "@user_id", Value: userID},
+			{Name: "@project_id", Value: projectID},
+		},
+		PageSizeHint: int32(paginationOptions.PerPage),
+	}
+
+	pager := container.NewQueryItemsPager(query, partitionKey, &queryOptions)
+
+	activityLogs := []ActivityLog{}
+	currentPage := 0
+
+	for pager.More() {
+
+		if currentPage == paginationOptions.Page {
+			response, err := pager.NextPage(ctx)
+			if err != nil {
+				return []ActivityLog{}, err
+			}
+
+			for _, bytes := range response.Items {
+				activityLog := ActivityLog{}
+				err := json.Unmarshal(bytes, &activityLog)
+				if err != nil {
+					return []ActivityLog{}, err
+				}
+				activityLogs = append(activityLogs, activityLog)
+			}
+
+			return activityLogs, nil
+
+		} else {
+			_, err := pager.NextPage(ctx)
+			if err != nil {
+				return []ActivityLog{}, err
+			}
+			currentPage++
+		}
+
+	}
+
+	return activityLogs, nil
+
+}
+
+func (env *env) GetProjectDependentActivityLogs(ctx context.Context, userID string, projectID string) ([]Identifiable, error) {
+
+	env.logger.Info("Getting project dependent activity logs")
+
+	container, err := env.client.NewContainer("activitylogs")
+	if err != nil {
+		return []Identifiable{}, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	query := "SELECT * FROM activitylogs al WHERE al.user_id = @user_id AND al.project_id = @project_id"
+
+	queryOptions := azcosmos.QueryOptions{
+		QueryParameters: []azcosmos.QueryParameter{
+			{Name: "@user_id", Value: userID},
+			{Name: "@project_id", Value: projectID},
+		},
+	}
+
+	pager := container.NewQueryItemsPager(query, partitionKey, &queryOptions)
+
+	activityLogs := []ActivityLog{}
+
+	for pager.More() {
+
+		response, err := pager.NextPage(ctx)
+		if err != nil {
+			return []Identifiable{}, err
+		}
+
+		for _, bytes := range response.Items {
+			activityLog := ActivityLog{}
+			err := json.Unmarshal(bytes, &activityLog)
+			if err != nil {
+				return []Identifiable{}, err
+			}
+			activityLogs = append(activityLogs, activityLog)
+		}
+
+	}
+
+	identifiables := []Identifiable{}
+
+	for _, al := range activityLogs {
+		identifiables = append(identifiables, al)
+	}
+
+	return identifiables, nil
+
+}
+
+func (env *env) GetActivityLogByID(ctx context.Context, userID string, activityLogID string) (ActivityLog, error) {
+
+	env.logger.Info("Getting activity log by ID")
+	activityLog := ActivityLog{}
+
+	container, err := env.client.NewContainer("activitylogs")
+	if err != nil {
+		return activityLog, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	response, err := container.ReadItem(ctx, partitionKey, activityLogID, nil)
+	if err != nil {
+		return activityLog, err
+	}
+
+	err = json.Unmarshal(response.Value, &activityLog)
+	if err != nil {
+		return activityLog, err
+	}
+
+	return activityLog, nil
+
+}
+
+func (env *env) UpsertActivityLog(ctx context.Context, activityLog ActivityLog) (ActivityLog, error) {
+
+	env.logger.Info("Upserting activity log")
+
+	container, err := env.client.NewContainer("activitylogs")
+	if err != nil {
+		return activityLog, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(activityLog.UserID)
+
+	marshalled, err := json.Marshal(activityLog)
+	if err != nil {
+		return activityLog, err
+	}
+
+	_, err = container.UpsertItem(ctx, partitionKey, marshalled, nil)
+	if err != nil {
+		return activityLog, err
+	}
+
+	return activityLog, nil
+
+}
+
+func (env *env) RemoveActivityLog(ctx context.Context, userID string, activityLogID string) (interface{}, error) {
+
+	env.logger.Info("Removing activity log")
+
+	container, err := env.client.NewContainer("activitylogs")
+	if err != nil {
+		return nil, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	response, err := container.DeleteItem(ctx, partitionKey, activityLogID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+
+}
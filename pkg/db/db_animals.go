@@ -1,6 +1,7 @@
 package db
 
 import (
+	"4h-recordbook-backend/internal/utils"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -9,31 +10,98 @@ import (
 )
 
 type Animal struct {
-	ID              string  `json:"id"`
-	Name            string  `json:"name"`
-	Species         string  `json:"species"`
-	BirthDate       string  `json:"birth_date"`
-	PurchaseDate    string  `json:"purchase_date"`
-	SireBreed       string  `json:"sire_breed"`
-	DamBreed        string  `json:"dam_breed"`
-	BeginningWeight float64 `json:"beginning_weight"`
-	BeginningDate   string  `json:"beginning_date"`
-	EndWeight       float64 `json:"end_weight"`
-	EndDate         string  `json:"end_date"`
-	AnimalCost      float64 `json:"animal_cost"`
-	SalePrice       float64 `json:"sale_price"`
-	YieldGrade      string  `json:"yield_grade"`
-	QualityGrade    string  `json:"quality_grade"`
-	UserID          string  `json:"user_id"`
-	ProjectID       string  `json:"project_id"`
+	ID              string             `json:"id"`
+	Name            string             `json:"name"`
+	Species         string             `json:"species"`
+	BirthDate       string             `json:"birth_date"`
+	PurchaseDate    string             `json:"purchase_date"`
+	SireBreed       string             `json:"sire_breed"`
+	DamBreed        string             `json:"dam_breed"`
+	BeginningWeight float64            `json:"beginning_weight"`
+	BeginningDate   string             `json:"beginning_date"`
+	EndWeight       float64            `json:"end_weight"`
+	WeightUnit      string             `json:"weight_unit"`
+	EndDate         string             `json:"end_date"`
+	AnimalCost      utils.Money        `json:"animal_cost"`
+	SalePrice       utils.Money        `json:"sale_price"`
+	YieldGrade      string             `json:"yield_grade"`
+	QualityGrade    string             `json:"quality_grade"`
+	Status          string             `json:"status"`
+	DispositionDate string             `json:"disposition_date"`
+	Buyer           string             `json:"buyer"`
+	Auction         string             `json:"auction"`
+	Identifiers     []AnimalIdentifier `json:"identifiers"`
+	DamID           string             `json:"dam_id"`
+	SireID          string             `json:"sire_id"`
+	BirthID         string             `json:"birth_id"`
+	OriginAnimalID  string             `json:"origin_animal_id"`
+	UserID          string             `json:"user_id"`
+	ProjectID       string             `json:"project_id"`
 	GenericDatabaseInfo
 }
 
+type AnimalIdentifier struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+const (
+	IDENTIFIER_TYPE_EAR_TAG     = "ear_tag"
+	IDENTIFIER_TYPE_TATTOO      = "tattoo"
+	IDENTIFIER_TYPE_SCRAPIE_TAG = "scrapie_tag"
+	IDENTIFIER_TYPE_PREMISES_ID = "premises_id"
+	IDENTIFIER_TYPE_RFID        = "rfid"
+)
+
 func (a Animal) GetID() string {
 	return a.ID
 }
 
-func (env *env) GetAnimalsByProject(ctx context.Context, userID string, projectID string, paginationOptions PaginationOptions) ([]Animal, error) {
+const (
+	ANIMAL_STATUS_ACTIVE      = "active"
+	ANIMAL_STATUS_SOLD        = "sold"
+	ANIMAL_STATUS_RETAINED    = "retained"
+	ANIMAL_STATUS_DECEASED    = "deceased"
+	ANIMAL_STATUS_TRANSFERRED = "transferred"
+)
+
+// allowed status changes, keyed by the current status. sold, deceased and transferred animals are final
+var AnimalStatusTransitions = map[string][]string{
+	ANIMAL_STATUS_ACTIVE:      {ANIMAL_STATUS_SOLD, ANIMAL_STATUS_RETAINED, ANIMAL_STATUS_DECEASED, ANIMAL_STATUS_TRANSFERRED},
+	ANIMAL_STATUS_RETAINED:    {ANIMAL_STATUS_ACTIVE, ANIMAL_STATUS_SOLD, ANIMAL_STATUS_DECEASED, ANIMAL_STATUS_TRANSFERRED},
+	ANIMAL_STATUS_SOLD:        {},
+	ANIMAL_STATUS_DECEASED:    {},
+	ANIMAL_STATUS_TRANSFERRED: {},
+}
+
+// animals saved before statuses existed have no status and are treated as active
+func (a Animal) CurrentStatus() string {
+	if a.Status == "" {
+		return ANIMAL_STATUS_ACTIVE
+	}
+	return a.Status
+}
+
+// animals carried over into a later project are copies of the same animal. they share the ID of the animal as it
+// was first recorded
+func (a Animal) OriginID() string {
+	if a.OriginAnimalID == "" {
+		return a.ID
+	}
+	return a.OriginAnimalID
+}
+
+func (a Animal) CanTransitionTo(status string) bool {
+	for _, next := range AnimalStatusTransitions[a.CurrentStatus()] {
+		if next == status {
+			return true
+		}
+	}
+	return false
+}
+
+// an empty status returns animals of every status
+func (env *env) GetAnimalsByProject(ctx context.Context, userID string, projectID string, status string, paginationOptions PaginationOptions) ([]Animal, error) {
 
 	env.logger.Info("Getting animals by project")
 
@@ -49,7 +117,14 @@ func (env *env) GetAnimalsByProject(ctx context.Context, userID string, projectI
 		sortOrder = "DESC"
 	}
 
-	query := fmt.Sprintf("SELECT * FROM animals a WHERE a.user_id = @user_id AND a.project_id = @project_id ORDER BY a.created %s", sortOrder)
+	statusFilter := ""
+	if status == ANIMAL_STATUS_ACTIVE {
+		statusFilter = "AND (NOT IS_DEFINED(a.status) OR a.status = '' OR a.status = @status)"
+	} else if status != "" {
+		statusFilter = "AND a.status = @status"
+	}
+
+	query := fmt.Sprintf("SELECT * FROM animals a WHERE a.user_id = @user_id AND a.project_id = @project_id %s ORDER BY a.created %s", statusFilter, sortOrder)
 
 	queryOptions := azcosmos.QueryOptions{
 		QueryParameters: []azcosmos.QueryParameter{
@@ -59,6 +134,10 @@ func (env *env) GetAnimalsByProject(ctx context.Context, userID string, projectI
 		PageSizeHint: int32(paginationOptions.PerPage),
 	}
 
+	if statusFilter != "" {
+		queryOptions.QueryParameters = append(queryOptions.QueryParameters, azcosmos.QueryParameter{Name: "@status", Value: status})
+	}
+
 	pager := container.NewQueryItemsPager(query, partitionKey, &queryOptions)
 
 	animals := []Animal{}
@@ -97,6 +176,52 @@ func (env *env) GetAnimalsByProject(ctx context.Context, userID string, projectI
 
 }
 
+func (env *env) GetAllAnimalsByProject(ctx context.Context, userID string, projectID string) ([]Animal, error) {
+
+	env.logger.Info("Getting all animals by project")
+
+	container, err := env.client.NewContainer("animals")
+	if err != nil {
+		return []Animal{}, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	query := "SELECT * FROM animals a WHERE a.user_id = @user_id AND a.project_id = @project_id"
+
+	queryOptions := azcosmos.QueryOptions{
+		QueryParameters: []azcosmos.QueryParameter{
+			{Name: "@user_id", Value: userID},
+			{Name: "@project_id", Value: projectID},
+		},
+	}
+
+	pager := container.NewQueryItemsPager(query, partitionKey, &queryOptions)
+
+	animals := []Animal{}
+
+	for pager.More() {
+
+		response, err := pager.NextPage(ctx)
+		if err != nil {
+			return []Animal{}, err
+		}
+
+		for _, bytes := range response.Items {
+			animal := Animal{}
+			err := json.Unmarshal(bytes, &animal)
+			if err != nil {
+				return []Animal{}, err
+			}
+			animals = append(animals, animal)
+		}
+
+	}
+
+	return animals, nil
+
+}
+
 func (env *env) GetProjectDependentAnimals(ctx context.Context, userID string, projectID string) ([]Identifiable, error) {
 
 	env.logger.Info("Getting project dependent animals")
@@ -149,6 +274,62 @@ func (env *env) GetProjectDependentAnimals(ctx context.Context, userID string, p
 
 }
 
+// an empty identifier type matches identifiers of any type
+func (env *env) GetAnimalsByIdentifier(ctx context.Context, userID string, identifierType string, value string) ([]Animal, error) {
+
+	env.logger.Info("Getting animals by identifier")
+
+	container, err := env.client.NewContainer("animals")
+	if err != nil {
+		return []Animal{}, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	typeFilter := ""
+	if identifierType != "" {
+		typeFilter = "AND i.type = @type"
+	}
+
+	query := fmt.Sprintf("SELECT * FROM animals a WHERE a.user_id = @user_id AND EXISTS(SELECT VALUE i FROM i IN a.identifiers WHERE i[\"value\"] = @value %s)", typeFilter)
+
+	queryOptions := azcosmos.QueryOptions{
+		QueryParameters: []azcosmos.QueryParameter{
+			{Name: "@user_id", Value: userID},
+			{Name: "@value", Value: value},
+		},
+	}
+
+	if identifierType != "" {
+		queryOptions.QueryParameters = append(queryOptions.QueryParameters, azcosmos.QueryParameter{Name: "@type", Value: identifierType})
+	}
+
+	pager := container.NewQueryItemsPager(query, partitionKey, &queryOptions)
+
+	animals := []Animal{}
+
+	for pager.More() {
+
+		response, err := pager.NextPage(ctx)
+		if err != nil {
+			return []Animal{}, err
+		}
+
+		for _, bytes := range response.Items {
+			animal := Animal{}
+			err := json.Unmarshal(bytes, &animal)
+			if err != nil {
+				return []Animal{}, err
+			}
+			animals = append(animals, animal)
+		}
+
+	}
+
+	return animals, nil
+
+}
+
 func (env *env) GetAnimalByID(ctx context.Context, userID string, animalID string) (Animal, error) {
 
 	env.logger.Info("Getting animal by ID")
@@ -200,6 +381,30 @@ func (env *env) UpsertAnimal(ctx context.Context, animal Animal) (Animal, error)
 
 }
 
+// all animals in a batch are written in a single transaction, see upsertBatch
+func (env *env) UpsertAnimalBatch(ctx context.Context, userID string, animals []Animal) ([]int, error) {
+
+	env.logger.Info("Upserting animal batch")
+
+	items := [][]byte{}
+
+	for _, animal := range animals {
+		if animal.UserID != userID {
+			return []int{}, fmt.Errorf("animal %s does not belong to the batch partition", animal.ID)
+		}
+
+		marshalled, err := json.Marshal(animal)
+		if err != nil {
+			return []int{}, err
+		}
+
+		items = append(items, marshalled)
+	}
+
+	return env.upsertBatch(ctx, "animals", userID, items)
+
+}
+
 func (env *env) RemoveAnimal(ctx context.Context, userID string, animalID string) (interface{}, error) {
 
 	env.logger.Info("Removing animal")
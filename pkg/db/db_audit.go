@@ -0,0 +1,108 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/data/azcosmos"
+)
+
+// one request against a user's records. the actor is whoever made the request, which is a guardian when they were
+// acting for the user and the user themselves otherwise
+type AuditEntry struct {
+	ID        string `json:"id"`
+	UserID    string `json:"user_id"`
+	ActorID   string `json:"actor_id"`
+	ActorName string `json:"actor_name"`
+	Method    string `json:"method"`
+	Path      string `json:"path"`
+	Status    int    `json:"status"`
+	GenericDatabaseInfo
+}
+
+func (env *env) GetAuditEntriesByUser(ctx context.Context, userID string, paginationOptions PaginationOptions) ([]AuditEntry, error) {
+
+	env.logger.Info("Getting audit entries")
+
+	container, err := env.client.NewContainer("audit")
+	if err != nil {
+		return []AuditEntry{}, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	sortOrder := "ASC"
+	if paginationOptions.SortByNewest {
+		sortOrder = "DESC"
+	}
+
+	query := fmt.Sprintf("SELECT * FROM audit a WHERE a.user_id = @user_id ORDER BY a.created %s", sortOrder)
+
+	queryOptions := azcosmos.QueryOptions{
+		QueryParameters: []azcosmos.QueryParameter{
+			{Name: "@user_id", Value: userID},
+		},
+		PageSizeHint: int32(paginationOptions.PerPage),
+	}
+
+	pager := container.NewQueryItemsPager(query, partitionKey, &queryOptions)
+
+	entries := []AuditEntry{}
+	currentPage := 0
+
+	for pager.More() {
+
+		if currentPage == paginationOptions.Page {
+			response, err := pager.NextPage(ctx)
+			if err != nil {
+				return []AuditEntry{}, err
+			}
+
+			for _, bytes := range response.Items {
+				entry := AuditEntry{}
+				err := json.Unmarshal(bytes, &entry)
+				if err != nil {
+					return []AuditEntry{}, err
+				}
+				entries = append(entries, entry)
+			}
+
+			return entries, nil
+
+		} else {
+			_, err := pager.NextPage(ctx)
+			if err != nil {
+				return []AuditEntry{}, err
+			}
+			currentPage++
+		}
+
+	}
+
+	return entries, nil
+
+}
+
+func (env *env) AddAuditEntry(ctx context.Context, entry AuditEntry) (AuditEntry, error) {
+
+	container, err := env.client.NewContainer("audit")
+	if err != nil {
+		return entry, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(entry.UserID)
+
+	marshalled, err := json.Marshal(entry)
+	if err != nil {
+		return entry, err
+	}
+
+	_, err = container.CreateItem(ctx, partitionKey, marshalled, nil)
+	if err != nil {
+		return entry, err
+	}
+
+	return entry, nil
+
+}
@@ -0,0 +1,470 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/data/azcosmos"
+)
+
+type Breeding struct {
+	ID              string `json:"id"`
+	DamID           string `json:"dam_id"`
+	SireID          string `json:"sire_id"`
+	SireName        string `json:"sire_name"`
+	SireBreed       string `json:"sire_breed"`
+	Method          string `json:"method"`
+	BreedingDate    string `json:"breeding_date"`
+	ExpectedDueDate string `json:"expected_due_date"`
+	Notes           string `json:"notes"`
+	ProjectID       string `json:"project_id"`
+	UserID          string `json:"user_id"`
+	GenericDatabaseInfo
+}
+
+func (b Breeding) GetID() string {
+	return b.ID
+}
+
+type Birth struct {
+	ID           string   `json:"id"`
+	BreedingID   string   `json:"breeding_id"`
+	DamID        string   `json:"dam_id"`
+	BirthType    string   `json:"birth_type"`
+	BirthDate    string   `json:"birth_date"`
+	BornAlive    int      `json:"born_alive"`
+	BornDead     int      `json:"born_dead"`
+	LitterSize   int      `json:"litter_size"`
+	OffspringIDs []string `json:"offspring_ids"`
+	Notes        string   `json:"notes"`
+	ProjectID    string   `json:"project_id"`
+	UserID       string   `json:"user_id"`
+	GenericDatabaseInfo
+}
+
+func (b Birth) GetID() string {
+	return b.ID
+}
+
+// typical gestation length in days, used to estimate a due date from the breeding date
+var GestationDays = map[string]int{
+	"swine":  114,
+	"pig":    114,
+	"sheep":  147,
+	"goat":   150,
+	"cattle": 283,
+	"beef":   283,
+	"dairy":  283,
+	"rabbit": 31,
+}
+
+/*******************************
+* BREEDINGS
+********************************/
+
+func (env *env) GetBreedingsByDam(ctx context.Context, userID string, damID string, paginationOptions PaginationOptions) ([]Breeding, error) {
+
+	env.logger.Info("Getting breedings by dam")
+
+	container, err := env.client.NewContainer("breedings")
+	if err != nil {
+		return []Breeding{}, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	sortOrder := "ASC"
+	if paginationOptions.SortByNewest {
+		sortOrder = "DESC"
+	}
+
+	query := fmt.Sprintf("SELECT * FROM breedings b WHERE b.user_id = @user_id AND b.dam_id = @dam_id ORDER BY b.created %s", sortOrder)
+
+	queryOptions := azcosmos.QueryOptions{
+		QueryParameters: []azcosmos.QueryParameter{
+			{Name: "@user_id", Value: userID},
+			{Name: "@dam_id", Value: damID},
+		},
+		PageSizeHint: int32(paginationOptions.PerPage),
+	}
+
+	pager := container.NewQueryItemsPager(query, partitionKey, &queryOptions)
+
+	breedings := []Breeding{}
+	currentPage := 0
+
+	for pager.More() {
+
+		if currentPage == paginationOptions.Page {
+			response, err := pager.NextPage(ctx)
+			if err != nil {
+				return []Breeding{}, err
+			}
+
+			for _, bytes := range response.Items {
+				breeding := Breeding{}
+				err := json.Unmarshal(bytes, &breeding)
+				if err != nil {
+					return []Breeding{}, err
+				}
+				breedings = append(breedings, breeding)
+			}
+
+			return breedings, nil
+
+		} else {
+			_, err := pager.NextPage(ctx)
+			if err != nil {
+				return []Breeding{}, err
+			}
+			currentPage++
+		}
+
+	}
+
+	return breedings, nil
+
+}
+
+func (env *env) GetAnimalDependentBreedings(ctx context.Context, userID string, animalID string) ([]Identifiable, error) {
+
+	env.logger.Info("Getting animal dependent breedings")
+
+	container, err := env.client.NewContainer("breedings")
+	if err != nil {
+		return []Identifiable{}, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	query := "SELECT * FROM breedings b WHERE b.user_id = @user_id AND b.dam_id = @dam_id"
+
+	queryOptions := azcosmos.QueryOptions{
+		QueryParameters: []azcosmos.QueryParameter{
+			{Name: "@user_id", Value: userID},
+			{Name: "@dam_id", Value: animalID},
+		},
+	}
+
+	pager := container.NewQueryItemsPager(query, partitionKey, &queryOptions)
+
+	breedings := []Breeding{}
+
+	for pager.More() {
+
+		response, err := pager.NextPage(ctx)
+		if err != nil {
+			return []Identifiable{}, err
+		}
+
+		for _, bytes := range response.Items {
+			breeding := Breeding{}
+			err := json.Unmarshal(bytes, &breeding)
+			if err != nil {
+				return []Identifiable{}, err
+			}
+			breedings = append(breedings, breeding)
+		}
+
+	}
+
+	identifiables := []Identifiable{}
+
+	for _, b := range breedings {
+		identifiables = append(identifiables, b)
+	}
+
+	return identifiables, nil
+
+}
+
+func (env *env) GetBreedingByID(ctx context.Context, userID string, breedingID string) (Breeding, error) {
+
+	env.logger.Info("Getting breeding by ID")
+	breeding := Breeding{}
+
+	container, err := env.client.NewContainer("breedings")
+	if err != nil {
+		return breeding, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	response, err := container.ReadItem(ctx, partitionKey, breedingID, nil)
+	if err != nil {
+		return breeding, err
+	}
+
+	err = json.Unmarshal(response.Value, &breeding)
+	if err != nil {
+		return breeding, err
+	}
+
+	return breeding, nil
+
+}
+
+func (env *env) UpsertBreeding(ctx context.Context, breeding Breeding) (Breeding, error) {
+
+	env.logger.Info("Upserting breeding")
+
+	container, err := env.client.NewContainer("breedings")
+	if err != nil {
+		return breeding, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(breeding.UserID)
+
+	marshalled, err := json.Marshal(breeding)
+	if err != nil {
+		return breeding, err
+	}
+
+	_, err = container.UpsertItem(ctx, partitionKey, marshalled, nil)
+	if err != nil {
+		return breeding, err
+	}
+
+	return breeding, nil
+
+}
+
+func (env *env) RemoveBreeding(ctx context.Context, userID string, breedingID string) (interface{}, error) {
+
+	env.logger.Info("Removing breeding")
+
+	container, err := env.client.NewContainer("breedings")
+	if err != nil {
+		return nil, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	response, err := container.DeleteItem(ctx, partitionKey, breedingID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, dependent := range env.dependentsMap["breedings"] {
+		identifiables, err := dependent.GetRelated(ctx, userID, breedingID)
+		if err != nil {
+			return nil, err
+		}
+		for _, identifiable := range identifiables {
+			_, err := dependent.Delete(ctx, userID, identifiable.GetID())
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return response, nil
+
+}
+
+/*******************************
+* BIRTHS
+********************************/
+
+func (env *env) GetBirthsByDam(ctx context.Context, userID string, damID string, paginationOptions PaginationOptions) ([]Birth, error) {
+
+	env.logger.Info("Getting births by dam")
+
+	container, err := env.client.NewContainer("births")
+	if err != nil {
+		return []Birth{}, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	sortOrder := "ASC"
+	if paginationOptions.SortByNewest {
+		sortOrder = "DESC"
+	}
+
+	query := fmt.Sprintf("SELECT * FROM births b WHERE b.user_id = @user_id AND b.dam_id = @dam_id ORDER BY b.created %s", sortOrder)
+
+	queryOptions := azcosmos.QueryOptions{
+		QueryParameters: []azcosmos.QueryParameter{
+			{Name: "@user_id", Value: userID},
+			{Name: "@dam_id", Value: damID},
+		},
+		PageSizeHint: int32(paginationOptions.PerPage),
+	}
+
+	pager := container.NewQueryItemsPager(query, partitionKey, &queryOptions)
+
+	births := []Birth{}
+	currentPage := 0
+
+	for pager.More() {
+
+		if currentPage == paginationOptions.Page {
+			response, err := pager.NextPage(ctx)
+			if err != nil {
+				return []Birth{}, err
+			}
+
+			for _, bytes := range response.Items {
+				birth := Birth{}
+				err := json.Unmarshal(bytes, &birth)
+				if err != nil {
+					return []Birth{}, err
+				}
+				births = append(births, birth)
+			}
+
+			return births, nil
+
+		} else {
+			_, err := pager.NextPage(ctx)
+			if err != nil {
+				return []Birth{}, err
+			}
+			currentPage++
+		}
+
+	}
+
+	return births, nil
+
+}
+
+func (env *env) GetAnimalDependentBirths(ctx context.Context, userID string, animalID string) ([]Identifiable, error) {
+
+	env.logger.Info("Getting animal dependent births")
+
+	return env.getDependentBirths(ctx, userID, "dam_id", animalID)
+
+}
+
+func (env *env) GetBreedingDependentBirths(ctx context.Context, userID string, breedingID string) ([]Identifiable, error) {
+
+	env.logger.Info("Getting breeding dependent births")
+
+	return env.getDependentBirths(ctx, userID, "breeding_id", breedingID)
+
+}
+
+func (env *env) getDependentBirths(ctx context.Context, userID string, field string, id string) ([]Identifiable, error) {
+
+	container, err := env.client.NewContainer("births")
+	if err != nil {
+		return []Identifiable{}, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	query := fmt.Sprintf("SELECT * FROM births b WHERE b.user_id = @user_id AND b.%s = @id", field)
+
+	queryOptions := azcosmos.QueryOptions{
+		QueryParameters: []azcosmos.QueryParameter{
+			{Name: "@user_id", Value: userID},
+			{Name: "@id", Value: id},
+		},
+	}
+
+	pager := container.NewQueryItemsPager(query, partitionKey, &queryOptions)
+
+	births := []Birth{}
+
+	for pager.More() {
+
+		response, err := pager.NextPage(ctx)
+		if err != nil {
+			return []Identifiable{}, err
+		}
+
+		for _, bytes := range response.Items {
+			birth := Birth{}
+			err := json.Unmarshal(bytes, &birth)
+			if err != nil {
+				return []Identifiable{}, err
+			}
+			births = append(births, birth)
+		}
+
+	}
+
+	identifiables := []Identifiable{}
+
+	for _, b := range births {
+		identifiables = append(identifiables, b)
+	}
+
+	return identifiables, nil
+
+}
+
+func (env *env) GetBirthByID(ctx context.Context, userID string, birthID string) (Birth, error) {
+
+	env.logger.Info("Getting birth by ID")
+	birth := Birth{}
+
+	container, err := env.client.NewContainer("births")
+	if err != nil {
+		return birth, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	response, err := container.ReadItem(ctx, partitionKey, birthID, nil)
+	if err != nil {
+		return birth, err
+	}
+
+	err = json.Unmarshal(response.Value, &birth)
+	if err != nil {
+		return birth, err
+	}
+
+	return birth, nil
+
+}
+
+func (env *env) UpsertBirth(ctx context.Context, birth Birth) (Birth, error) {
+
+	env.logger.Info("Upserting birth")
+
+	container, err := env.client.NewContainer("births")
+	if err != nil {
+		return birth, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(birth.UserID)
+
+	marshalled, err := json.Marshal(birth)
+	if err != nil {
+		return birth, err
+	}
+
+	_, err = container.UpsertItem(ctx, partitionKey, marshalled, nil)
+	if err != nil {
+		return birth, err
+	}
+
+	return birth, nil
+
+}
+
+// offspring recorded by a birth are animals in their own right and are kept when the birth is removed
+func (env *env) RemoveBirth(ctx context.Context, userID string, birthID string) (interface{}, error) {
+
+	env.logger.Info("Removing birth")
+
+	container, err := env.client.NewContainer("births")
+	if err != nil {
+		return nil, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	response, err := container.DeleteItem(ctx, partitionKey, birthID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+
+}
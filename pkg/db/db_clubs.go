@@ -0,0 +1,358 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/data/azcosmos"
+)
+
+const (
+	MEMBERSHIP_STATUS_PENDING  = "pending"
+	MEMBERSHIP_STATUS_APPROVED = "approved"
+
+	MEMBERSHIP_ROLE_MEMBER = "member"
+	MEMBERSHIP_ROLE_LEADER = "leader"
+)
+
+// clubs are shared between their members and leaders, so each is kept in its own partition of the clubs container
+type Club struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	CountyName string `json:"county_name"`
+	JoinCode   string `json:"join_code,omitempty"`
+	CreatedBy  string `json:"created_by"`
+	GenericDatabaseInfo
+}
+
+// points a join code at its club. codes are the IDs of their own partition, so that they can be read directly
+type ClubCode struct {
+	ID     string `json:"id"`
+	ClubID string `json:"club_id"`
+}
+
+// a member or leader of a club for one program year. the roster copy is kept in the club's partition of the
+// memberships container, and a copy with the same ID is kept in the user's partition of the user_memberships
+// container so that users can list their clubs. the roster copy is the one that grants access
+type Membership struct {
+	ID         string `json:"id"`
+	ClubID     string `json:"club_id"`
+	ClubName   string `json:"club_name"`
+	CountyName string `json:"county_name"`
+	UserID     string `json:"user_id"`
+	UserName   string `json:"user_name"`
+	Year       string `json:"year"`
+	Role       string `json:"role"`
+	Status     string `json:"status"`
+	ApprovedBy string `json:"approved_by"`
+	ApprovedAt string `json:"approved_at"`
+	GenericDatabaseInfo
+}
+
+func (env *env) GetClubByID(ctx context.Context, clubID string) (Club, error) {
+
+	env.logger.Info("Getting club by ID")
+
+	container, err := env.client.NewContainer("clubs")
+	if err != nil {
+		return Club{}, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(clubID)
+
+	response, err := container.ReadItem(ctx, partitionKey, clubID, nil)
+	if err != nil {
+		return Club{}, err
+	}
+
+	club := Club{}
+	err = json.Unmarshal(response.Value, &club)
+	if err != nil {
+		return Club{}, err
+	}
+
+	return club, nil
+
+}
+
+func (env *env) UpsertClub(ctx context.Context, club Club) (Club, error) {
+
+	env.logger.Info("Upserting club")
+
+	container, err := env.client.NewContainer("clubs")
+	if err != nil {
+		return club, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(club.ID)
+
+	marshalled, err := json.Marshal(club)
+	if err != nil {
+		return club, err
+	}
+
+	_, err = container.UpsertItem(ctx, partitionKey, marshalled, nil)
+	if err != nil {
+		return club, err
+	}
+
+	return club, nil
+
+}
+
+func (env *env) GetClubCode(ctx context.Context, code string) (ClubCode, error) {
+
+	env.logger.Info("Getting club code")
+
+	container, err := env.client.NewContainer("club_codes")
+	if err != nil {
+		return ClubCode{}, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(code)
+
+	response, err := container.ReadItem(ctx, partitionKey, code, nil)
+	if err != nil {
+		return ClubCode{}, err
+	}
+
+	clubCode := ClubCode{}
+	err = json.Unmarshal(response.Value, &clubCode)
+	if err != nil {
+		return ClubCode{}, err
+	}
+
+	return clubCode, nil
+
+}
+
+// fails with a conflict when the code is already taken
+func (env *env) AddClubCode(ctx context.Context, clubCode ClubCode) (ClubCode, error) {
+
+	env.logger.Info("Adding club code")
+
+	container, err := env.client.NewContainer("club_codes")
+	if err != nil {
+		return clubCode, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(clubCode.ID)
+
+	marshalled, err := json.Marshal(clubCode)
+	if err != nil {
+		return clubCode, err
+	}
+
+	_, err = container.CreateItem(ctx, partitionKey, marshalled, nil)
+	if err != nil {
+		return clubCode, err
+	}
+
+	return clubCode, nil
+
+}
+
+func (env *env) RemoveClubCode(ctx context.Context, code string) (interface{}, error) {
+
+	env.logger.Info("Removing club code")
+
+	container, err := env.client.NewContainer("club_codes")
+	if err != nil {
+		return nil, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(code)
+
+	response, err := container.DeleteItem(ctx, partitionKey, code, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+
+}
+
+func (env *env) queryMemberships(ctx context.Context, containerName string, partition string, query string, parameters []azcosmos.QueryParameter) ([]Membership, error) {
+
+	container, err := env.client.NewContainer(containerName)
+	if err != nil {
+		return []Membership{}, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(partition)
+
+	queryOptions := azcosmos.QueryOptions{
+		QueryParameters: parameters,
+	}
+
+	pager := container.NewQueryItemsPager(query, partitionKey, &queryOptions)
+
+	memberships := []Membership{}
+
+	for pager.More() {
+		response, err := pager.NextPage(ctx)
+		if err != nil {
+			return []Membership{}, err
+		}
+
+		for _, bytes := range response.Items {
+			membership := Membership{}
+			err := json.Unmarshal(bytes, &membership)
+			if err != nil {
+				return []Membership{}, err
+			}
+			memberships = append(memberships, membership)
+		}
+	}
+
+	return memberships, nil
+
+}
+
+// the club's roster for a program year, or for every year when the year is empty
+func (env *env) GetMembershipsByClub(ctx context.Context, clubID string, year string) ([]Membership, error) {
+
+	env.logger.Info("Getting memberships by club")
+
+	if year == "" {
+		query := "SELECT * FROM memberships m WHERE m.club_id = @club_id ORDER BY m.created ASC"
+		return env.queryMemberships(ctx, "memberships", clubID, query, []azcosmos.QueryParameter{
+			{Name: "@club_id", Value: clubID},
+		})
+	}
+
+	query := "SELECT * FROM memberships m WHERE m.club_id = @club_id AND m.year = @year ORDER BY m.created ASC"
+	return env.queryMemberships(ctx, "memberships", clubID, query, []azcosmos.QueryParameter{
+		{Name: "@club_id", Value: clubID},
+		{Name: "@year", Value: year},
+	})
+
+}
+
+// the user's own copies of their memberships in every club and year
+func (env *env) GetMembershipsByUser(ctx context.Context, userID string) ([]Membership, error) {
+
+	env.logger.Info("Getting memberships by user")
+
+	query := "SELECT * FROM user_memberships m WHERE m.user_id = @user_id ORDER BY m.created ASC"
+	return env.queryMemberships(ctx, "user_memberships", userID, query, []azcosmos.QueryParameter{
+		{Name: "@user_id", Value: userID},
+	})
+
+}
+
+func (env *env) GetMembershipByID(ctx context.Context, clubID string, membershipID string) (Membership, error) {
+
+	env.logger.Info("Getting membership by ID")
+
+	container, err := env.client.NewContainer("memberships")
+	if err != nil {
+		return Membership{}, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(clubID)
+
+	response, err := container.ReadItem(ctx, partitionKey, membershipID, nil)
+	if err != nil {
+		return Membership{}, err
+	}
+
+	membership := Membership{}
+	err = json.Unmarshal(response.Value, &membership)
+	if err != nil {
+		return Membership{}, err
+	}
+
+	return membership, nil
+
+}
+
+func (env *env) GetUserMembershipByID(ctx context.Context, userID string, membershipID string) (Membership, error) {
+
+	env.logger.Info("Getting user membership by ID")
+
+	container, err := env.client.NewContainer("user_memberships")
+	if err != nil {
+		return Membership{}, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	response, err := container.ReadItem(ctx, partitionKey, membershipID, nil)
+	if err != nil {
+		return Membership{}, err
+	}
+
+	membership := Membership{}
+	err = json.Unmarshal(response.Value, &membership)
+	if err != nil {
+		return Membership{}, err
+	}
+
+	return membership, nil
+
+}
+
+// writes both copies of the membership, the user's first so that the roster never lists a membership the user
+// can't see
+func (env *env) UpsertMembership(ctx context.Context, membership Membership) (Membership, error) {
+
+	env.logger.Info("Upserting membership")
+
+	marshalled, err := json.Marshal(membership)
+	if err != nil {
+		return membership, err
+	}
+
+	userContainer, err := env.client.NewContainer("user_memberships")
+	if err != nil {
+		return membership, err
+	}
+
+	_, err = userContainer.UpsertItem(ctx, azcosmos.NewPartitionKeyString(membership.UserID), marshalled, nil)
+	if err != nil {
+		return membership, err
+	}
+
+	clubContainer, err := env.client.NewContainer("memberships")
+	if err != nil {
+		return membership, err
+	}
+
+	_, err = clubContainer.UpsertItem(ctx, azcosmos.NewPartitionKeyString(membership.ClubID), marshalled, nil)
+	if err != nil {
+		return membership, err
+	}
+
+	return membership, nil
+
+}
+
+// removes both copies of the membership, the roster's first so that access ends before the user's copy goes
+func (env *env) RemoveMembership(ctx context.Context, membership Membership) (interface{}, error) {
+
+	env.logger.Info("Removing membership")
+
+	clubContainer, err := env.client.NewContainer("memberships")
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = clubContainer.DeleteItem(ctx, azcosmos.NewPartitionKeyString(membership.ClubID), membership.ID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	userContainer, err := env.client.NewContainer("user_memberships")
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := userContainer.DeleteItem(ctx, azcosmos.NewPartitionKeyString(membership.UserID), membership.ID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+
+}
@@ -0,0 +1,290 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/data/azcosmos"
+)
+
+const (
+	COMMENT_ENTITY_PROJECT = "project"
+	COMMENT_ENTITY_ANIMAL  = "animal"
+	COMMENT_ENTITY_EXPENSE = "expense"
+	COMMENT_ENTITY_SECTION = "section"
+
+	// visible to the member, their guardians and the people overseeing their records
+	COMMENT_VISIBILITY_ALL = "all"
+	// visible only to the people overseeing the member's records, such as judges conferring before results are
+	// published
+	COMMENT_VISIBILITY_STAFF = "staff"
+)
+
+var COMMENT_ENTITY_TYPES = []string{COMMENT_ENTITY_PROJECT, COMMENT_ENTITY_ANIMAL, COMMENT_ENTITY_EXPENSE, COMMENT_ENTITY_SECTION}
+
+// a comment on one of a member's records. it is kept with the member's records, whoever wrote it. the first comment
+// on a thread is its own thread ID and carries the thread's visibility and whether it has been resolved
+type Comment struct {
+	ID         string `json:"id"`
+	UserID     string `json:"user_id"`
+	EntityType string `json:"entity_type"`
+	EntityID   string `json:"entity_id"`
+	ThreadID   string `json:"thread_id"`
+	AuthorID   string `json:"author_id"`
+	AuthorName string `json:"author_name"`
+	AuthorRole string `json:"author_role"`
+	Body       string `json:"body"`
+	Visibility string `json:"visibility"`
+	Resolved   bool   `json:"resolved"`
+	ResolvedBy string `json:"resolved_by"`
+	ResolvedAt string `json:"resolved_at"`
+	GenericDatabaseInfo
+}
+
+func (c Comment) GetID() string {
+	return c.ID
+}
+
+func (c Comment) IsThread() bool {
+	return c.ID == c.ThreadID
+}
+
+// when a user last read a thread on a member's records. it is kept with the reader, so that each user has their own
+// unread counts
+type CommentRead struct {
+	ID       string `json:"id"`
+	UserID   string `json:"user_id"`
+	MemberID string `json:"member_id"`
+	ThreadID string `json:"thread_id"`
+	ReadAt   string `json:"read_at"`
+	GenericDatabaseInfo
+}
+
+// the comments on the member's records, oldest first. when the entity type is empty every record's comments are
+// returned
+func (env *env) GetCommentsByUser(ctx context.Context, userID string, entityType string, entityID string) ([]Comment, error) {
+
+	env.logger.Info("Getting comments")
+
+	container, err := env.client.NewContainer("comments")
+	if err != nil {
+		return []Comment{}, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	query := "SELECT * FROM comments c WHERE c.user_id = @user_id ORDER BY c.created ASC"
+	parameters := []azcosmos.QueryParameter{
+		{Name: "@user_id", Value: userID},
+	}
+
+	if entityType != "" {
+		query = "SELECT * FROM comments c WHERE c.user_id = @user_id AND c.entity_type = @entity_type AND c.entity_id = @entity_id ORDER BY c.created ASC"
+		parameters = append(parameters,
+			azcosmos.QueryParameter{Name: "@entity_type", Value: entityType},
+			azcosmos.QueryParameter{Name: "@entity_id", Value: entityID},
+		)
+	}
+
+	queryOptions := azcosmos.QueryOptions{
+		QueryParameters: parameters,
+	}
+
+	pager := container.NewQueryItemsPager(query, partitionKey, &queryOptions)
+
+	comments := []Comment{}
+
+	for pager.More() {
+		response, err := pager.NextPage(ctx)
+		if err != nil {
+			return []Comment{}, err
+		}
+
+		for _, bytes := range response.Items {
+			comment := Comment{}
+			err := json.Unmarshal(bytes, &comment)
+			if err != nil {
+				return []Comment{}, err
+			}
+			comments = append(comments, comment)
+		}
+	}
+
+	return comments, nil
+
+}
+
+func (env *env) GetCommentByID(ctx context.Context, userID string, commentID string) (Comment, error) {
+
+	env.logger.Info("Getting comment by ID")
+
+	container, err := env.client.NewContainer("comments")
+	if err != nil {
+		return Comment{}, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	response, err := container.ReadItem(ctx, partitionKey, commentID, nil)
+	if err != nil {
+		return Comment{}, err
+	}
+
+	comment := Comment{}
+	err = json.Unmarshal(response.Value, &comment)
+	if err != nil {
+		return Comment{}, err
+	}
+
+	return comment, nil
+
+}
+
+func (env *env) UpsertComment(ctx context.Context, comment Comment) (Comment, error) {
+
+	env.logger.Info("Upserting comment")
+
+	container, err := env.client.NewContainer("comments")
+	if err != nil {
+		return comment, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(comment.UserID)
+
+	marshalled, err := json.Marshal(comment)
+	if err != nil {
+		return comment, err
+	}
+
+	_, err = container.UpsertItem(ctx, partitionKey, marshalled, nil)
+	if err != nil {
+		return comment, err
+	}
+
+	return comment, nil
+
+}
+
+func (env *env) RemoveComment(ctx context.Context, userID string, commentID string) (interface{}, error) {
+
+	env.logger.Info("Removing comment")
+
+	container, err := env.client.NewContainer("comments")
+	if err != nil {
+		return nil, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	response, err := container.DeleteItem(ctx, partitionKey, commentID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+
+}
+
+// the comments on one record, so they are removed with it
+func (env *env) recordDependentComments(ctx context.Context, userID string, entityType string, entityID string) ([]Identifiable, error) {
+
+	comments, err := env.GetCommentsByUser(ctx, userID, entityType, entityID)
+	if err != nil {
+		return []Identifiable{}, err
+	}
+
+	identifiables := []Identifiable{}
+
+	for _, comment := range comments {
+		identifiables = append(identifiables, comment)
+	}
+
+	return identifiables, nil
+
+}
+
+func (env *env) GetProjectDependentComments(ctx context.Context, userID string, projectID string) ([]Identifiable, error) {
+	return env.recordDependentComments(ctx, userID, COMMENT_ENTITY_PROJECT, projectID)
+}
+
+func (env *env) GetAnimalDependentComments(ctx context.Context, userID string, animalID string) ([]Identifiable, error) {
+	return env.recordDependentComments(ctx, userID, COMMENT_ENTITY_ANIMAL, animalID)
+}
+
+func (env *env) GetExpenseDependentComments(ctx context.Context, userID string, expenseID string) ([]Identifiable, error) {
+	return env.recordDependentComments(ctx, userID, COMMENT_ENTITY_EXPENSE, expenseID)
+}
+
+func (env *env) GetSectionDependentComments(ctx context.Context, userID string, sectionID string) ([]Identifiable, error) {
+	return env.recordDependentComments(ctx, userID, COMMENT_ENTITY_SECTION, sectionID)
+}
+
+// when the reader last read each thread on the member's records
+func (env *env) GetCommentReadsByUser(ctx context.Context, userID string, memberID string) ([]CommentRead, error) {
+
+	env.logger.Info("Getting comment reads")
+
+	container, err := env.client.NewContainer("comment_reads")
+	if err != nil {
+		return []CommentRead{}, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	query := "SELECT * FROM comment_reads r WHERE r.user_id = @user_id AND r.member_id = @member_id"
+
+	queryOptions := azcosmos.QueryOptions{
+		QueryParameters: []azcosmos.QueryParameter{
+			{Name: "@user_id", Value: userID},
+			{Name: "@member_id", Value: memberID},
+		},
+	}
+
+	pager := container.NewQueryItemsPager(query, partitionKey, &queryOptions)
+
+	reads := []CommentRead{}
+
+	for pager.More() {
+		response, err := pager.NextPage(ctx)
+		if err != nil {
+			return []CommentRead{}, err
+		}
+
+		for _, bytes := range response.Items {
+			read := CommentRead{}
+			err := json.Unmarshal(bytes, &read)
+			if err != nil {
+				return []CommentRead{}, err
+			}
+			reads = append(reads, read)
+		}
+	}
+
+	return reads, nil
+
+}
+
+func (env *env) UpsertCommentRead(ctx context.Context, read CommentRead) (CommentRead, error) {
+
+	env.logger.Info("Upserting comment read")
+
+	container, err := env.client.NewContainer("comment_reads")
+	if err != nil {
+		return read, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(read.UserID)
+
+	marshalled, err := json.Marshal(read)
+	if err != nil {
+		return read, err
+	}
+
+	_, err = container.UpsertItem(ctx, partitionKey, marshalled, nil)
+	if err != nil {
+		return read, err
+	}
+
+	return read, nil
+
+}
@@ -12,6 +12,7 @@ type DailyFeed struct {
 	ID             string  `json:"id"`
 	FeedDate       string  `json:"feed_date"`
 	FeedAmount     float64 `json:"feed_amount"`
+	FeedAmountUnit string  `json:"feed_amount_unit"`
 	AnimalID       string  `json:"animal_id"`
 	FeedID         string  `json:"feed_id"`
 	FeedPurchaseID string  `json:"feed_purchase_id"`
@@ -263,3 +264,27 @@ func (env *env) RemoveDailyFeed(ctx context.Context, userID string, dailyFeedID
 	return response, nil
 
 }
+
+// all daily feeds in a batch are written in a single transaction, see upsertBatch
+func (env *env) UpsertDailyFeedBatch(ctx context.Context, userID string, dailyFeeds []DailyFeed) ([]int, error) {
+
+	env.logger.Info("Upserting daily feed batch")
+
+	items := [][]byte{}
+
+	for _, dailyFeed := range dailyFeeds {
+		if dailyFeed.UserID != userID {
+			return []int{}, fmt.Errorf("daily feed %s does not belong to the batch partition", dailyFeed.ID)
+		}
+
+		marshalled, err := json.Marshal(dailyFeed)
+		if err != nil {
+			return []int{}, err
+		}
+
+		items = append(items, marshalled)
+	}
+
+	return env.upsertBatch(ctx, "dailyfeeds", userID, items)
+
+}
@@ -0,0 +1,214 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/data/azcosmos"
+)
+
+type Exhibit struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	ShowName    string `json:"show_name"`
+	Date        string `json:"date"`
+	Division    string `json:"division"`
+	Placing     string `json:"placing"`
+	Award       string `json:"award"`
+	ProjectID   string `json:"project_id"`
+	UserID      string `json:"user_id"`
+	GenericDatabaseInfo
+}
+
+func (ex Exhibit) GetID() string {
+	return ex.ID
+}
+
+func (env *env) GetExhibitsByProject(ctx context.Context, userID string, projectID string, paginationOptions PaginationOptions) ([]Exhibit, error) {
+
+	env.logger.Info("Getting exhibits by project")
+
+	container, err := env.client.NewContainer("exhibits")
+	if err != nil {
+		return []Exhibit{}, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	sortOrder := "ASC"
+	if paginationOptions.SortByNewest {
+		sortOrder = "DESC"
+	}
+
+	query := fmt.Sprintf("SELECT * FROM exhibits ex WHERE ex.user_id = @user_id AND ex.project_id = @project_id ORDER BY ex.created %s", sortOrder)
+
+	queryOptions := azcosmos.QueryOptions{
+		QueryParameters: []azcosmos.QueryParameter{
+			{Name: "@user_id", Value: userID},
+			{Name: "@project_id", Value: projectID},
+		},
+		PageSizeHint: int32(paginationOptions.PerPage),
+	}
+
+	pager := container.NewQueryItemsPager(query, partitionKey, &queryOptions)
+
+	exhibits := []Exhibit{}
+	currentPage := 0
+
+	for pager.More() {
+
+		if currentPage == paginationOptions.Page {
+			response, err := pager.NextPage(ctx)
+			if err != nil {
+				return []Exhibit{}, err
+			}
+
+			for _, bytes := range response.Items {
+				exhibit := Exhibit{}
+				err := json.Unmarshal(bytes, &exhibit)
+				if err != nil {
+					return []Exhibit{}, err
+				}
+				exhibits = append(exhibits, exhibit)
+			}
+
+			return exhibits, nil
+
+		} else {
+			_, err := pager.NextPage(ctx)
+			if err != nil {
+				return []Exhibit{}, err
+			}
+			currentPage++
+		}
+
+	}
+
+	return exhibits, nil
+
+}
+
+func (env *env) GetProjectDependentExhibits(ctx context.Context, userID string, projectID string) ([]Identifiable, error) {
+
+	env.logger.Info("Getting project dependent exhibits")
+
+	container, err := env.client.NewContainer("exhibits")
+	if err != nil {
+		return []Identifiable{}, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	query := "SELECT * FROM exhibits ex WHERE ex.user_id = @user_id AND ex.project_id = @project_id"
+
+	queryOptions := azcosmos.QueryOptions{
+		QueryParameters: []azcosmos.QueryParameter{
+			{Name: "@user_id", Value: userID},
+			{Name: "@project_id", Value: projectID},
+		},
+	}
+
+	pager := container.NewQueryItemsPager(query, partitionKey, &queryOptions)
+
+	exhibits := []Exhibit{}
+
+	for pager.More() {
+
+		response, err := pager.NextPage(ctx)
+		if err != nil {
+			return []Identifiable{}, err
+		}
+
+		for _, bytes := range response.Items {
+			exhibit := Exhibit{}
+			err := json.Unmarshal(bytes, &exhibit)
+			if err != nil {
+				return []Identifiable{}, err
+			}
+			exhibits = append(exhibits, exhibit)
+		}
+
+	}
+
+	identifiables := []Identifiable{}
+
+	for _, ex := range exhibits {
+		identifiables = append(identifiables, ex)
+	}
+
+	return identifiables, nil
+
+}
+
+func (env *env) GetExhibitByID(ctx context.Context, userID string, exhibitID string) (Exhibit, error) {
+
+	env.logger.Info("Getting exhibit by ID")
+	exhibit := Exhibit{}
+
+	container, err := env.client.NewContainer("exhibits")
+	if err != nil {
+		return exhibit, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	response, err := container.ReadItem(ctx, partitionKey, exhibitID, nil)
+	if err != nil {
+		return exhibit, err
+	}
+
+	err = json.Unmarshal(response.Value, &exhibit)
+	if err != nil {
+		return exhibit, err
+	}
+
+	return exhibit, nil
+
+}
+
+func (env *env) UpsertExhibit(ctx context.Context, exhibit Exhibit) (Exhibit, error) {
+
+	env.logger.Info("Upserting exhibit")
+
+	container, err := env.client.NewContainer("exhibits")
+	if err != nil {
+		return exhibit, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(exhibit.UserID)
+
+	marshalled, err := json.Marshal(exhibit)
+	if err != nil {
+		return exhibit, err
+	}
+
+	_, err = container.UpsertItem(ctx, partitionKey, marshalled, nil)
+	if err != nil {
+		return exhibit, err
+	}
+
+	return exhibit, nil
+
+}
+
+func (env *env) RemoveExhibit(ctx context.Context, userID string, exhibitID string) (interface{}, error) {
+
+	env.logger.Info("Removing exhibit")
+
+	container, err := env.client.NewContainer("exhibits")
+	if err != nil {
+		return nil, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	response, err := container.DeleteItem(ctx, partitionKey, exhibitID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+
+}
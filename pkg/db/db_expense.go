@@ -1,6 +1,7 @@
 package db
 
 import (
+	"4h-recordbook-backend/internal/utils"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -9,13 +10,15 @@ import (
 )
 
 type Expense struct {
-	ID        string  `json:"id"`
-	Date      string  `json:"date"`
-	Items     string  `json:"items"`
-	Quantity  float64 `json:"quantity"`
-	Cost      float64 `json:"cost"`
-	ProjectID string  `json:"project_id"`
-	UserID    string  `json:"user_id"`
+	ID            string      `json:"id"`
+	Date          string      `json:"date"`
+	Items         string      `json:"items"`
+	Quantity      float64     `json:"quantity"`
+	Cost          utils.Money `json:"cost"`
+	Category      string      `json:"category"`
+	OtherCategory string      `json:"other_category"`
+	ProjectID     string      `json:"project_id"`
+	UserID        string      `json:"user_id"`
 	GenericDatabaseInfo
 }
 
@@ -87,6 +90,52 @@ func (env *env) GetExpensesByProject(ctx context.Context, userID string, project
 
 }
 
+func (env *env) GetAllExpensesByProject(ctx context.Context, userID string, projectID string) ([]Expense, error) {
+
+	env.logger.Info("Getting all expenses by project")
+
+	container, err := env.client.NewContainer("expenses")
+	if err != nil {
+		return []Expense{}, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	query := "SELECT * FROM expenses e WHERE e.user_id = @user_id AND e.project_id = @project_id"
+
+	queryOptions := azcosmos.QueryOptions{
+		QueryParameters: []azcosmos.QueryParameter{
+			{Name: "@user_id", Value: userID},
+			{Name: "@project_id", Value: projectID},
+		},
+	}
+
+	pager := container.NewQueryItemsPager(query, partitionKey, &queryOptions)
+
+	expenses := []Expense{}
+
+	for pager.More() {
+
+		response, err := pager.NextPage(ctx)
+		if err != nil {
+			return []Expense{}, err
+		}
+
+		for _, bytes := range response.Items {
+			expense := Expense{}
+			err := json.Unmarshal(bytes, &expense)
+			if err != nil {
+				return []Expense{}, err
+			}
+			expenses = append(expenses, expense)
+		}
+
+	}
+
+	return expenses, nil
+
+}
+
 func (env *env) GetProjectDependentExpenses(ctx context.Context, userID string, projectID string) ([]Identifiable, error) {
 
 	env.logger.Info("Getting project dependent expenses")
@@ -139,6 +188,60 @@ func (env *env) GetProjectDependentExpenses(ctx context.Context, userID string,
 
 }
 
+// returns the distinct labels a user has given to expenses and supplies filed under the "other" category
+func (env *env) GetOtherExpenseCategoriesByUser(ctx context.Context, userID string) ([]string, error) {
+
+	env.logger.Info("Getting other expense categories by user")
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	seen := make(map[string]bool)
+	otherCategories := []string{}
+
+	for _, containerName := range []string{"expenses", "supplies"} {
+
+		container, err := env.client.NewContainer(containerName)
+		if err != nil {
+			return []string{}, err
+		}
+
+		query := fmt.Sprintf("SELECT DISTINCT VALUE c.other_category FROM %s c WHERE c.user_id = @user_id AND c.other_category != ''", containerName)
+
+		queryOptions := azcosmos.QueryOptions{
+			QueryParameters: []azcosmos.QueryParameter{
+				{Name: "@user_id", Value: userID},
+			},
+		}
+
+		pager := container.NewQueryItemsPager(query, partitionKey, &queryOptions)
+
+		for pager.More() {
+
+			response, err := pager.NextPage(ctx)
+			if err != nil {
+				return []string{}, err
+			}
+
+			for _, bytes := range response.Items {
+				otherCategory := ""
+				err := json.Unmarshal(bytes, &otherCategory)
+				if err != nil {
+					return []string{}, err
+				}
+				if !seen[otherCategory] {
+					seen[otherCategory] = true
+					otherCategories = append(otherCategories, otherCategory)
+				}
+			}
+
+		}
+
+	}
+
+	return otherCategories, nil
+
+}
+
 func (env *env) GetExpenseByID(ctx context.Context, userID string, expenseID string) (Expense, error) {
 
 	env.logger.Info("Getting expense by ID")
@@ -206,6 +309,19 @@ func (env *env) RemoveExpense(ctx context.Context, userID string, expenseID stri
 		return nil, err
 	}
 
+	for _, dependent := range env.dependentsMap["expenses"] {
+		identifiables, err := dependent.GetRelated(ctx, userID, expenseID)
+		if err != nil {
+			return nil, err
+		}
+		for _, identifiable := range identifiables {
+			_, err := dependent.Delete(ctx, userID, identifiable.GetID())
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	return response, nil
 
 }
@@ -84,6 +84,52 @@ func (env *env) GetFeedsByProject(ctx context.Context, userID string, projectID
 
 }
 
+func (env *env) GetAllFeedsByProject(ctx context.Context, userID string, projectID string) ([]Feed, error) {
+
+	env.logger.Info("Getting all feeds by project")
+
+	container, err := env.client.NewContainer("feeds")
+	if err != nil {
+		return []Feed{}, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	query := "SELECT * FROM feeds f WHERE f.user_id = @user_id AND f.project_id = @project_id ORDER BY f.created ASC"
+
+	queryOptions := azcosmos.QueryOptions{
+		QueryParameters: []azcosmos.QueryParameter{
+			{Name: "@user_id", Value: userID},
+			{Name: "@project_id", Value: projectID},
+		},
+	}
+
+	pager := container.NewQueryItemsPager(query, partitionKey, &queryOptions)
+
+	feeds := []Feed{}
+
+	for pager.More() {
+
+		response, err := pager.NextPage(ctx)
+		if err != nil {
+			return []Feed{}, err
+		}
+
+		for _, bytes := range response.Items {
+			feed := Feed{}
+			err := json.Unmarshal(bytes, &feed)
+			if err != nil {
+				return []Feed{}, err
+			}
+			feeds = append(feeds, feed)
+		}
+
+	}
+
+	return feeds, nil
+
+}
+
 func (env *env) GetProjectDependentFeeds(ctx context.Context, userID string, projectID string) ([]Identifiable, error) {
 
 	env.logger.Info("Getting project dependent feeds")
@@ -187,6 +233,29 @@ func (env *env) UpsertFeed(ctx context.Context, feed Feed) (Feed, error) {
 
 }
 
+func (env *env) UpsertFeedBatch(ctx context.Context, userID string, feeds []Feed) ([]int, error) {
+
+	env.logger.Info("Upserting feed batch")
+
+	items := [][]byte{}
+
+	for _, feed := range feeds {
+		if feed.UserID != userID {
+			return []int{}, fmt.Errorf("feed %s does not belong to the batch partition", feed.ID)
+		}
+
+		marshalled, err := json.Marshal(feed)
+		if err != nil {
+			return []int{}, err
+		}
+
+		items = append(items, marshalled)
+	}
+
+	return env.upsertBatch(ctx, "feeds", userID, items)
+
+}
+
 func (env *env) RemoveFeed(ctx context.Context, userID string, feedID string) (interface{}, error) {
 
 	env.logger.Info("Removing feed")
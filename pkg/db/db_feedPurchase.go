@@ -1,6 +1,7 @@
 package db
 
 import (
+	"4h-recordbook-backend/internal/utils"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -9,13 +10,17 @@ import (
 )
 
 type FeedPurchase struct {
-	ID              string  `json:"id"`
-	DatePurchased   string  `json:"date_purchased"`
-	AmountPurchased float64 `json:"amount_purchased"`
-	TotalCost       float64 `json:"total_cost"`
-	FeedID          string  `json:"feed_id"`
-	ProjectID       string  `json:"project_id"`
-	UserID          string  `json:"user_id"`
+	ID                 string      `json:"id"`
+	DatePurchased      string      `json:"date_purchased"`
+	AmountPurchased    float64     `json:"amount_purchased"`
+	AmountUnit         string      `json:"amount_unit"`
+	PurchaseUnit       string      `json:"purchase_unit"`
+	PurchaseQuantity   float64     `json:"purchase_quantity"`
+	PurchaseUnitFactor float64     `json:"purchase_unit_factor"`
+	TotalCost          utils.Money `json:"total_cost"`
+	FeedID             string      `json:"feed_id"`
+	ProjectID          string      `json:"project_id"`
+	UserID             string      `json:"user_id"`
 	GenericDatabaseInfo
 }
 
@@ -87,6 +92,52 @@ func (env *env) GetFeedPurchasesByProject(ctx context.Context, userID string, pr
 
 }
 
+func (env *env) GetAllFeedPurchasesByProject(ctx context.Context, userID string, projectID string) ([]FeedPurchase, error) {
+
+	env.logger.Info("Getting all feed purchases by project")
+
+	container, err := env.client.NewContainer("feedpurchases")
+	if err != nil {
+		return []FeedPurchase{}, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	query := "SELECT * FROM feedpurchases fp WHERE fp.user_id = @user_id AND fp.project_id = @project_id"
+
+	queryOptions := azcosmos.QueryOptions{
+		QueryParameters: []azcosmos.QueryParameter{
+			{Name: "@user_id", Value: userID},
+			{Name: "@project_id", Value: projectID},
+		},
+	}
+
+	pager := container.NewQueryItemsPager(query, partitionKey, &queryOptions)
+
+	feedPurchases := []FeedPurchase{}
+
+	for pager.More() {
+
+		response, err := pager.NextPage(ctx)
+		if err != nil {
+			return []FeedPurchase{}, err
+		}
+
+		for _, bytes := range response.Items {
+			feedPurchase := FeedPurchase{}
+			err := json.Unmarshal(bytes, &feedPurchase)
+			if err != nil {
+				return []FeedPurchase{}, err
+			}
+			feedPurchases = append(feedPurchases, feedPurchase)
+		}
+
+	}
+
+	return feedPurchases, nil
+
+}
+
 func (env *env) GetFeedDependentFeedPurchases(ctx context.Context, userID string, feedID string) ([]Identifiable, error) {
 
 	env.logger.Info("Getting feed dependent feed purchases")
@@ -0,0 +1,189 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/data/azcosmos"
+)
+
+const (
+	GUARDIAN_LINK_STATUS_PENDING  = "pending"
+	GUARDIAN_LINK_STATUS_ACCEPTED = "accepted"
+)
+
+// links a guardian to a youth member. the link is kept in both of their partitions under the same ID, so that each
+// of them can list their links. the copy in the member's partition is the one that grants access, and is the only
+// one holding the invite code
+type GuardianLink struct {
+	ID            string `json:"id"`
+	UserID        string `json:"user_id"`
+	MemberID      string `json:"member_id"`
+	MemberName    string `json:"member_name"`
+	GuardianID    string `json:"guardian_id"`
+	GuardianName  string `json:"guardian_name"`
+	GuardianEmail string `json:"guardian_email"`
+	Status        string `json:"status"`
+	Code          string `json:"code,omitempty"`
+	AcceptedAt    string `json:"accepted_at"`
+	GenericDatabaseInfo
+}
+
+func (env *env) GetGuardianLinksByUser(ctx context.Context, userID string) ([]GuardianLink, error) {
+
+	env.logger.Info("Getting guardian links")
+
+	container, err := env.client.NewContainer("guardian_links")
+	if err != nil {
+		return []GuardianLink{}, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	query := "SELECT * FROM guardian_links g WHERE g.user_id = @user_id ORDER BY g.created ASC"
+
+	queryOptions := azcosmos.QueryOptions{
+		QueryParameters: []azcosmos.QueryParameter{
+			{Name: "@user_id", Value: userID},
+		},
+	}
+
+	pager := container.NewQueryItemsPager(query, partitionKey, &queryOptions)
+
+	links := []GuardianLink{}
+
+	for pager.More() {
+		response, err := pager.NextPage(ctx)
+		if err != nil {
+			return []GuardianLink{}, err
+		}
+
+		for _, bytes := range response.Items {
+			link := GuardianLink{}
+			err := json.Unmarshal(bytes, &link)
+			if err != nil {
+				return []GuardianLink{}, err
+			}
+			links = append(links, link)
+		}
+	}
+
+	return links, nil
+
+}
+
+func (env *env) GetGuardianLinkByID(ctx context.Context, userID string, guardianLinkID string) (GuardianLink, error) {
+
+	env.logger.Info("Getting guardian link by ID")
+
+	container, err := env.client.NewContainer("guardian_links")
+	if err != nil {
+		return GuardianLink{}, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	response, err := container.ReadItem(ctx, partitionKey, guardianLinkID, nil)
+	if err != nil {
+		return GuardianLink{}, err
+	}
+
+	link := GuardianLink{}
+	err = json.Unmarshal(response.Value, &link)
+	if err != nil {
+		return GuardianLink{}, err
+	}
+
+	return link, nil
+
+}
+
+// the accepted link between a member and a guardian, read from the member's partition. the link is empty when there
+// is none
+func (env *env) GetAcceptedGuardianLink(ctx context.Context, memberID string, guardianID string) (GuardianLink, error) {
+
+	env.logger.Info("Getting accepted guardian link")
+
+	container, err := env.client.NewContainer("guardian_links")
+	if err != nil {
+		return GuardianLink{}, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(memberID)
+
+	query := "SELECT * FROM guardian_links g WHERE g.user_id = @user_id AND g.member_id = @user_id AND g.guardian_id = @guardian_id AND g.status = @status"
+
+	queryOptions := azcosmos.QueryOptions{
+		QueryParameters: []azcosmos.QueryParameter{
+			{Name: "@user_id", Value: memberID},
+			{Name: "@guardian_id", Value: guardianID},
+			{Name: "@status", Value: GUARDIAN_LINK_STATUS_ACCEPTED},
+		},
+	}
+
+	pager := container.NewQueryItemsPager(query, partitionKey, &queryOptions)
+
+	for pager.More() {
+		response, err := pager.NextPage(ctx)
+		if err != nil {
+			return GuardianLink{}, err
+		}
+
+		for _, bytes := range response.Items {
+			link := GuardianLink{}
+			err := json.Unmarshal(bytes, &link)
+			if err != nil {
+				return GuardianLink{}, err
+			}
+			return link, nil
+		}
+	}
+
+	return GuardianLink{}, nil
+
+}
+
+func (env *env) UpsertGuardianLink(ctx context.Context, link GuardianLink) (GuardianLink, error) {
+
+	env.logger.Info("Upserting guardian link")
+
+	container, err := env.client.NewContainer("guardian_links")
+	if err != nil {
+		return link, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(link.UserID)
+
+	marshalled, err := json.Marshal(link)
+	if err != nil {
+		return link, err
+	}
+
+	_, err = container.UpsertItem(ctx, partitionKey, marshalled, nil)
+	if err != nil {
+		return link, err
+	}
+
+	return link, nil
+
+}
+
+func (env *env) RemoveGuardianLink(ctx context.Context, userID string, guardianLinkID string) (interface{}, error) {
+
+	env.logger.Info("Removing guardian link")
+
+	container, err := env.client.NewContainer("guardian_links")
+	if err != nil {
+		return nil, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	response, err := container.DeleteItem(ctx, partitionKey, guardianLinkID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+
+}
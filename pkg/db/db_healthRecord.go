@@ -0,0 +1,265 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/data/azcosmos"
+)
+
+type HealthRecord struct {
+	ID                string  `json:"id"`
+	RecordType        string  `json:"record_type"`
+	Product           string  `json:"product"`
+	Dosage            float64 `json:"dosage"`
+	DosageUnit        string  `json:"dosage_unit"`
+	Route             string  `json:"route"`
+	AdministeredBy    string  `json:"administered_by"`
+	DateAdministered  string  `json:"date_administered"`
+	WithdrawalDays    int     `json:"withdrawal_days"`
+	WithdrawalEndDate string  `json:"withdrawal_end_date"`
+	Notes             string  `json:"notes"`
+	AnimalID          string  `json:"animal_id"`
+	ProjectID         string  `json:"project_id"`
+	UserID            string  `json:"user_id"`
+	GenericDatabaseInfo
+}
+
+func (hr HealthRecord) GetID() string {
+	return hr.ID
+}
+
+func (env *env) GetHealthRecordsByAnimal(ctx context.Context, userID string, animalID string, paginationOptions PaginationOptions) ([]HealthRecord, error) {
+
+	env.logger.Info("Getting health records by animal")
+
+	container, err := env.client.NewContainer("healthrecords")
+	if err != nil {
+		return []HealthRecord{}, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	sortOrder := "ASC"
+	if paginationOptions.SortByNewest {
+		sortOrder = "DESC"
+	}
+
+	query := fmt.Sprintf("SELECT * FROM healthrecords hr WHERE hr.user_id = @user_id AND hr.animal_id = @animal_id ORDER BY hr.created %s", sortOrder)
+
+	queryOptions := azcosmos.QueryOptions{
+		QueryParameters: []azcosmos.QueryParameter{
+			{Name: "@user_id", Value: userID},
+			{Name: "@animal_id", Value: animalID},
+		},
+		PageSizeHint: int32(paginationOptions.PerPage),
+	}
+
+	pager := container.NewQueryItemsPager(query, partitionKey, &queryOptions)
+
+	healthRecords := []HealthRecord{}
+	currentPage := 0
+
+	for pager.More() {
+
+		if currentPage == paginationOptions.Page {
+			response, err := pager.NextPage(ctx)
+			if err != nil {
+				return []HealthRecord{}, err
+			}
+
+			for _, bytes := range response.Items {
+				healthRecord := HealthRecord{}
+				err := json.Unmarshal(bytes, &healthRecord)
+				if err != nil {
+					return []HealthRecord{}, err
+				}
+				healthRecords = append(healthRecords, healthRecord)
+			}
+
+			return healthRecords, nil
+
+		} else {
+			_, err := pager.NextPage(ctx)
+			if err != nil {
+				return []HealthRecord{}, err
+			}
+			currentPage++
+		}
+
+	}
+
+	return healthRecords, nil
+
+}
+
+// returns every health record in the project that has a withdrawal period, regardless of whether it has ended
+func (env *env) GetWithdrawalHealthRecordsByProject(ctx context.Context, userID string, projectID string) ([]HealthRecord, error) {
+
+	env.logger.Info("Getting health records with withdrawal periods by project")
+
+	container, err := env.client.NewContainer("healthrecords")
+	if err != nil {
+		return []HealthRecord{}, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	query := "SELECT * FROM healthrecords hr WHERE hr.user_id = @user_id AND hr.project_id = @project_id AND hr.withdrawal_days > 0"
+
+	queryOptions := azcosmos.QueryOptions{
+		QueryParameters: []azcosmos.QueryParameter{
+			{Name: "@user_id", Value: userID},
+			{Name: "@project_id", Value: projectID},
+		},
+	}
+
+	pager := container.NewQueryItemsPager(query, partitionKey, &queryOptions)
+
+	healthRecords := []HealthRecord{}
+
+	for pager.More() {
+
+		response, err := pager.NextPage(ctx)
+		if err != nil {
+			return []HealthRecord{}, err
+		}
+
+		for _, bytes := range response.Items {
+			healthRecord := HealthRecord{}
+			err := json.Unmarshal(bytes, &healthRecord)
+			if err != nil {
+				return []HealthRecord{}, err
+			}
+			healthRecords = append(healthRecords, healthRecord)
+		}
+
+	}
+
+	return healthRecords, nil
+
+}
+
+func (env *env) GetAnimalDependentHealthRecords(ctx context.Context, userID string, animalID string) ([]Identifiable, error) {
+
+	env.logger.Info("Getting animal dependent health records")
+
+	container, err := env.client.NewContainer("healthrecords")
+	if err != nil {
+		return []Identifiable{}, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	query := "SELECT * FROM healthrecords hr WHERE hr.user_id = @user_id AND hr.animal_id = @animal_id"
+
+	queryOptions := azcosmos.QueryOptions{
+		QueryParameters: []azcosmos.QueryParameter{
+			{Name: "@user_id", Value: userID},
+			{Name: "@animal_id", Value: animalID},
+		},
+	}
+
+	pager := container.NewQueryItemsPager(query, partitionKey, &queryOptions)
+
+	healthRecords := []HealthRecord{}
+
+	for pager.More() {
+
+		response, err := pager.NextPage(ctx)
+		if err != nil {
+			return []Identifiable{}, err
+		}
+
+		for _, bytes := range response.Items {
+			healthRecord := HealthRecord{}
+			err := json.Unmarshal(bytes, &healthRecord)
+			if err != nil {
+				return []Identifiable{}, err
+			}
+			healthRecords = append(healthRecords, healthRecord)
+		}
+
+	}
+
+	identifiables := []Identifiable{}
+
+	for _, hr := range healthRecords {
+		identifiables = append(identifiables, hr)
+	}
+
+	return identifiables, nil
+
+}
+
+func (env *env) GetHealthRecordByID(ctx context.Context, userID string, healthRecordID string) (HealthRecord, error) {
+
+	env.logger.Info("Getting health record by ID")
+	healthRecord := HealthRecord{}
+
+	container, err := env.client.NewContainer("healthrecords")
+	if err != nil {
+		return healthRecord, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	response, err := container.ReadItem(ctx, partitionKey, healthRecordID, nil)
+	if err != nil {
+		return healthRecord, err
+	}
+
+	err = json.Unmarshal(response.Value, &healthRecord)
+	if err != nil {
+		return healthRecord, err
+	}
+
+	return healthRecord, nil
+
+}
+
+func (env *env) UpsertHealthRecord(ctx context.Context, healthRecord HealthRecord) (HealthRecord, error) {
+
+	env.logger.Info("Upserting health record")
+
+	container, err := env.client.NewContainer("healthrecords")
+	if err != nil {
+		return healthRecord, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(healthRecord.UserID)
+
+	marshalled, err := json.Marshal(healthRecord)
+	if err != nil {
+		return healthRecord, err
+	}
+
+	_, err = container.UpsertItem(ctx, partitionKey, marshalled, nil)
+	if err != nil {
+		return healthRecord, err
+	}
+
+	return healthRecord, nil
+
+}
+
+func (env *env) RemoveHealthRecord(ctx context.Context, userID string, healthRecordID string) (interface{}, error) {
+
+	env.logger.Info("Removing health record")
+
+	container, err := env.client.NewContainer("healthrecords")
+	if err != nil {
+		return nil, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	response, err := container.DeleteItem(ctx, partitionKey, healthRecordID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+
+}
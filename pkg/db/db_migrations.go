@@ -0,0 +1,250 @@
+package db
+
+import (
+	"4h-recordbook-backend/internal/utils"
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/data/azcosmos"
+)
+
+// rewrites every document in a container that belongs to the user. documents are read into T, passed to convert when it
+// is given, and written back, so any conversion T makes while unmarshalling is saved too
+func migrateContainer[T any](ctx context.Context, env *env, containerName string, userID string, convert func(*T)) (int, error) {
+
+	container, err := env.client.NewContainer(containerName)
+	if err != nil {
+		return 0, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	query := "SELECT * FROM c WHERE c.user_id = @user_id"
+
+	queryOptions := azcosmos.QueryOptions{
+		QueryParameters: []azcosmos.QueryParameter{
+			{Name: "@user_id", Value: userID},
+		},
+	}
+
+	pager := container.NewQueryItemsPager(query, partitionKey, &queryOptions)
+
+	migrated := 0
+
+	for pager.More() {
+
+		response, err := pager.NextPage(ctx)
+		if err != nil {
+			return migrated, err
+		}
+
+		for _, bytes := range response.Items {
+
+			var item T
+			err := json.Unmarshal(bytes, &item)
+			if err != nil {
+				return migrated, err
+			}
+
+			if convert != nil {
+				convert(&item)
+			}
+
+			marshalled, err := json.Marshal(item)
+			if err != nil {
+				return migrated, err
+			}
+
+			_, err = container.UpsertItem(ctx, partitionKey, marshalled, nil)
+			if err != nil {
+				return migrated, err
+			}
+
+			migrated++
+
+		}
+
+	}
+
+	return migrated, nil
+
+}
+
+// rounds the money fields of a user's documents to whole cents. documents written before money was stored as exact
+// cents may hold floating point drift, e.g. 12.340000000000001. safe to run more than once
+func (env *env) MigrateMoney(ctx context.Context, userID string) (int, error) {
+
+	env.logger.Info("Migrating money fields")
+
+	return runMigrations([]func() (int, error){
+		func() (int, error) {
+			return migrateContainer[Animal](ctx, env, "animals", userID, nil)
+		},
+		func() (int, error) {
+			return migrateContainer[FeedPurchase](ctx, env, "feedpurchases", userID, nil)
+		},
+		func() (int, error) {
+			return migrateContainer[Expense](ctx, env, "expenses", userID, nil)
+		},
+		func() (int, error) {
+			return migrateContainer[Supply](ctx, env, "supplies", userID, nil)
+		},
+		func() (int, error) {
+			return migrateContainer[ProjectBudgetItem](ctx, env, "projectbudgetitems", userID, nil)
+		},
+	})
+
+}
+
+func runMigrations(migrations []func() (int, error)) (int, error) {
+
+	total := 0
+
+	for _, migrate := range migrations {
+		migrated, err := migrate()
+		total += migrated
+		if err != nil {
+			return total, err
+		}
+	}
+
+	return total, nil
+
+}
+
+// relabels the years of a user's projects and resume sections as program years. documents written before program years
+// were configurable are labelled by a single calendar year, which no longer matches the labels that projects and
+// sections are looked up by. projects take the program year their start date falls in, and everything else the
+// program year ending in its calendar year. labels that are already program years, or can't be read, are left as they
+// are. safe to run more than once, so run it after MigrateDates
+func (env *env) MigrateYears(ctx context.Context, userID string, programYear utils.ProgramYear) (int, error) {
+
+	env.logger.Info("Migrating years")
+
+	toProgramYear := func(year string) string {
+		if label, err := programYear.FromLegacy(year); err == nil {
+			return label
+		}
+		return year
+	}
+
+	return runMigrations([]func() (int, error){
+		func() (int, error) {
+			return migrateContainer(ctx, env, "projects", userID, func(p *map[string]interface{}) {
+				year, _ := (*p)["year"].(string)
+				if programYear.IsValid(year) {
+					return
+				}
+				startDate, _ := (*p)["start_date"].(string)
+				if start, err := utils.StringToDate(startDate, time.UTC); err == nil {
+					(*p)["year"] = programYear.Of(start.Time())
+					return
+				}
+				(*p)["year"] = toProgramYear(year)
+			})
+		},
+		func() (int, error) {
+			// every resume section shares the container, so documents are rewritten as they are without a section type
+			return migrateContainer(ctx, env, "sections", userID, func(s *map[string]interface{}) {
+				if year, ok := (*s)["year"].(string); ok {
+					(*s)["year"] = toProgramYear(year)
+				}
+			})
+		},
+	})
+
+}
+
+// rewrites the calendar dates of a user's documents as YYYY-MM-DD. documents written before dates were stored that way
+// hold RFC 3339 timestamps, which are read as the date they fall on in the user's time zone, or the given fallback zone
+// when the user hasn't set one. values that can't be read are left as they are. safe to run more than once
+func (env *env) MigrateDates(ctx context.Context, userID string, fallback *time.Location) (int, error) {
+
+	env.logger.Info("Migrating dates")
+
+	location := fallback
+	user, err := env.GetUser(ctx, userID)
+	if err == nil && user.TimeZone != "" {
+		if userLocation, err := time.LoadLocation(user.TimeZone); err == nil {
+			location = userLocation
+		}
+	}
+
+	toDates := func(dates ...*string) {
+		for _, date := range dates {
+			if parsed, err := utils.StringToDate(*date, location); err == nil {
+				*date = parsed.String()
+			}
+		}
+	}
+
+	return runMigrations([]func() (int, error){
+		func() (int, error) {
+			return migrateContainer(ctx, env, "activitylogs", userID, func(a *ActivityLog) {
+				toDates(&a.Date)
+			})
+		},
+		func() (int, error) {
+			return migrateContainer(ctx, env, "animals", userID, func(a *Animal) {
+				toDates(&a.BirthDate, &a.PurchaseDate, &a.BeginningDate, &a.EndDate, &a.DispositionDate)
+			})
+		},
+		func() (int, error) {
+			return migrateContainer(ctx, env, "breedings", userID, func(b *Breeding) {
+				toDates(&b.BreedingDate, &b.ExpectedDueDate)
+			})
+		},
+		func() (int, error) {
+			return migrateContainer(ctx, env, "births", userID, func(b *Birth) {
+				toDates(&b.BirthDate)
+			})
+		},
+		func() (int, error) {
+			return migrateContainer(ctx, env, "dailyfeeds", userID, func(df *DailyFeed) {
+				toDates(&df.FeedDate)
+			})
+		},
+		func() (int, error) {
+			return migrateContainer(ctx, env, "events", userID, func(ev *Event) {
+				toDates(&ev.StartDate, &ev.EndDate)
+			})
+		},
+		func() (int, error) {
+			return migrateContainer(ctx, env, "exhibits", userID, func(ex *Exhibit) {
+				toDates(&ex.Date)
+			})
+		},
+		func() (int, error) {
+			return migrateContainer(ctx, env, "expenses", userID, func(ex *Expense) {
+				toDates(&ex.Date)
+			})
+		},
+		func() (int, error) {
+			return migrateContainer(ctx, env, "feedpurchases", userID, func(fp *FeedPurchase) {
+				toDates(&fp.DatePurchased)
+			})
+		},
+		func() (int, error) {
+			return migrateContainer(ctx, env, "healthrecords", userID, func(hr *HealthRecord) {
+				toDates(&hr.DateAdministered, &hr.WithdrawalEndDate)
+			})
+		},
+		func() (int, error) {
+			return migrateContainer(ctx, env, "projectgoals", userID, func(pg *ProjectGoal) {
+				toDates(&pg.TargetDate, &pg.CompletionDate)
+			})
+		},
+		func() (int, error) {
+			return migrateContainer(ctx, env, "projects", userID, func(p *Project) {
+				toDates(&p.StartDate, &p.EndDate)
+			})
+		},
+		func() (int, error) {
+			return migrateContainer(ctx, env, "supplies", userID, func(s *Supply) {
+				toDates(&s.AcquisitionDate)
+			})
+		},
+	})
+
+}
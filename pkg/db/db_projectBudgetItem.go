@@ -0,0 +1,274 @@
+package db
+
+import (
+	"4h-recordbook-backend/internal/utils"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/data/azcosmos"
+)
+
+type ProjectBudgetItem struct {
+	ID              string      `json:"id"`
+	Category        string      `json:"category"`
+	Description     string      `json:"description"`
+	PlannedAmount   utils.Money `json:"planned_amount"`
+	PlannedQuantity float64     `json:"planned_quantity"`
+	QuantityUnit    string      `json:"quantity_unit"`
+	ProjectID       string      `json:"project_id"`
+	UserID          string      `json:"user_id"`
+	GenericDatabaseInfo
+}
+
+func (pbi ProjectBudgetItem) GetID() string {
+	return pbi.ID
+}
+
+// budget categories and the records their actual totals come from
+const (
+	BUDGET_CATEGORY_ANIMAL_PURCHASE = "animal_purchase" // animal cost
+	BUDGET_CATEGORY_FEED            = "feed"            // feed purchases
+	BUDGET_CATEGORY_EXPENSES        = "expenses"        // expenses
+	BUDGET_CATEGORY_INCOME          = "income"          // animal sale prices
+)
+
+var BudgetCategories = []string{
+	BUDGET_CATEGORY_ANIMAL_PURCHASE,
+	BUDGET_CATEGORY_FEED,
+	BUDGET_CATEGORY_EXPENSES,
+	BUDGET_CATEGORY_INCOME,
+}
+
+func (env *env) GetProjectBudgetItemsByProject(ctx context.Context, userID string, projectID string, paginationOptions PaginationOptions) ([]ProjectBudgetItem, error) {
+
+	env.logger.Info("Getting budget items by project")
+
+	container, err := env.client.NewContainer("projectbudgetitems")
+	if err != nil {
+		return []ProjectBudgetItem{}, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	sortOrder := "ASC"
+	if paginationOptions.SortByNewest {
+		sortOrder = "DESC"
+	}
+
+	query := fmt.Sprintf("SELECT * FROM projectbudgetitems pbi WHERE pbi.user_id = @user_id AND pbi.project_id = @project_id ORDER BY pbi.created %s", sortOrder)
+
+	queryOptions := azcosmos.QueryOptions{
+		QueryParameters: []azcosmos.QueryParameter{
+			{Name: "@user_id", Value: userID},
+			{Name: "@project_id", Value: projectID},
+		},
+		PageSizeHint: int32(paginationOptions.PerPage),
+	}
+
+	pager := container.NewQueryItemsPager(query, partitionKey, &queryOptions)
+
+	projectBudgetItems := []ProjectBudgetItem{}
+	currentPage := 0
+
+	for pager.More() {
+
+		if currentPage == paginationOptions.Page {
+			response, err := pager.NextPage(ctx)
+			if err != nil {
+				return []ProjectBudgetItem{}, err
+			}
+
+			for _, bytes := range response.Items {
+				projectBudgetItem := ProjectBudgetItem{}
+				err := json.Unmarshal(bytes, &projectBudgetItem)
+				if err != nil {
+					return []ProjectBudgetItem{}, err
+				}
+				projectBudgetItems = append(projectBudgetItems, projectBudgetItem)
+			}
+
+			return projectBudgetItems, nil
+
+		} else {
+			_, err := pager.NextPage(ctx)
+			if err != nil {
+				return []ProjectBudgetItem{}, err
+			}
+			currentPage++
+		}
+
+	}
+
+	return projectBudgetItems, nil
+
+}
+
+func (env *env) GetAllProjectBudgetItemsByProject(ctx context.Context, userID string, projectID string) ([]ProjectBudgetItem, error) {
+
+	env.logger.Info("Getting all budget items by project")
+
+	container, err := env.client.NewContainer("projectbudgetitems")
+	if err != nil {
+		return []ProjectBudgetItem{}, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	query := "SELECT * FROM projectbudgetitems pbi WHERE pbi.user_id = @user_id AND pbi.project_id = @project_id"
+
+	queryOptions := azcosmos.QueryOptions{
+		QueryParameters: []azcosmos.QueryParameter{
+			{Name: "@user_id", Value: userID},
+			{Name: "@project_id", Value: projectID},
+		},
+	}
+
+	pager := container.NewQueryItemsPager(query, partitionKey, &queryOptions)
+
+	projectBudgetItems := []ProjectBudgetItem{}
+
+	for pager.More() {
+
+		response, err := pager.NextPage(ctx)
+		if err != nil {
+			return []ProjectBudgetItem{}, err
+		}
+
+		for _, bytes := range response.Items {
+			projectBudgetItem := ProjectBudgetItem{}
+			err := json.Unmarshal(bytes, &projectBudgetItem)
+			if err != nil {
+				return []ProjectBudgetItem{}, err
+			}
+			projectBudgetItems = append(projectBudgetItems, projectBudgetItem)
+		}
+
+	}
+
+	return projectBudgetItems, nil
+
+}
+
+func (env *env) GetProjectDependentProjectBudgetItems(ctx context.Context, userID string, projectID string) ([]Identifiable, error) {
+
+	env.logger.Info("Getting project dependent budget items")
+
+	container, err := env.client.NewContainer("projectbudgetitems")
+	if err != nil {
+		return []Identifiable{}, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	query := "SELECT * FROM projectbudgetitems pbi WHERE pbi.user_id = @user_id AND pbi.project_id = @project_id"
+
+	queryOptions := azcosmos.QueryOptions{
+		QueryParameters: []azcosmos.QueryParameter{
+			{Name: "@user_id", Value: userID},
+			{Name: "@project_id", Value: projectID},
+		},
+	}
+
+	pager := container.NewQueryItemsPager(query, partitionKey, &queryOptions)
+
+	projectBudgetItems := []ProjectBudgetItem{}
+
+	for pager.More() {
+
+		response, err := pager.NextPage(ctx)
+		if err != nil {
+			return []Identifiable{}, err
+		}
+
+		for _, bytes := range response.Items {
+			projectBudgetItem := ProjectBudgetItem{}
+			err := json.Unmarshal(bytes, &projectBudgetItem)
+			if err != nil {
+				return []Identifiable{}, err
+			}
+			projectBudgetItems = append(projectBudgetItems, projectBudgetItem)
+		}
+
+	}
+
+	identifiables := []Identifiable{}
+
+	for _, pbi := range projectBudgetItems {
+		identifiables = append(identifiables, pbi)
+	}
+
+	return identifiables, nil
+
+}
+
+func (env *env) GetProjectBudgetItemByID(ctx context.Context, userID string, projectBudgetItemID string) (ProjectBudgetItem, error) {
+
+	env.logger.Info("Getting budget item by ID")
+	projectBudgetItem := ProjectBudgetItem{}
+
+	container, err := env.client.NewContainer("projectbudgetitems")
+	if err != nil {
+		return projectBudgetItem, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	response, err := container.ReadItem(ctx, partitionKey, projectBudgetItemID, nil)
+	if err != nil {
+		return projectBudgetItem, err
+	}
+
+	err = json.Unmarshal(response.Value, &projectBudgetItem)
+	if err != nil {
+		return projectBudgetItem, err
+	}
+
+	return projectBudgetItem, nil
+
+}
+
+func (env *env) UpsertProjectBudgetItem(ctx context.Context, projectBudgetItem ProjectBudgetItem) (ProjectBudgetItem, error) {
+
+	env.logger.Info("Upserting budget item")
+
+	container, err := env.client.NewContainer("projectbudgetitems")
+	if err != nil {
+		return projectBudgetItem, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(projectBudgetItem.UserID)
+
+	marshalled, err := json.Marshal(projectBudgetItem)
+	if err != nil {
+		return projectBudgetItem, err
+	}
+
+	_, err = container.UpsertItem(ctx, partitionKey, marshalled, nil)
+	if err != nil {
+		return projectBudgetItem, err
+	}
+
+	return projectBudgetItem, nil
+
+}
+
+func (env *env) RemoveProjectBudgetItem(ctx context.Context, userID string, projectBudgetItemID string) (interface{}, error) {
+
+	env.logger.Info("Removing budget item")
+
+	container, err := env.client.NewContainer("projectbudgetitems")
+	if err != nil {
+		return nil, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	response, err := container.DeleteItem(ctx, partitionKey, projectBudgetItemID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+
+}
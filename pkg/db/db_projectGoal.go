@@ -0,0 +1,265 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/data/azcosmos"
+)
+
+type ProjectGoal struct {
+	ID             string `json:"id"`
+	Goal           string `json:"goal"`
+	Target         string `json:"target"`
+	Status         string `json:"status"`
+	TargetDate     string `json:"target_date"`
+	CompletionDate string `json:"completion_date"`
+	ProjectID      string `json:"project_id"`
+	UserID         string `json:"user_id"`
+	GenericDatabaseInfo
+}
+
+const (
+	GOAL_STATUS_NOT_STARTED = "not_started"
+	GOAL_STATUS_IN_PROGRESS = "in_progress"
+	GOAL_STATUS_COMPLETED   = "completed"
+	GOAL_STATUS_ABANDONED   = "abandoned"
+)
+
+func (pg ProjectGoal) GetID() string {
+	return pg.ID
+}
+
+func (env *env) GetProjectGoalsByProject(ctx context.Context, userID string, projectID string, paginationOptions PaginationOptions) ([]ProjectGoal, error) {
+
+	env.logger.Info("Getting project goals by project")
+
+	container, err := env.client.NewContainer("projectgoals")
+	if err != nil {
+		return []ProjectGoal{}, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	sortOrder := "ASC"
+	if paginationOptions.SortByNewest {
+		sortOrder = "DESC"
+	}
+
+	query := fmt.Sprintf("SELECT * FROM projectgoals pg WHERE pg.user_id = @user_id AND pg.project_id = @project_id ORDER BY pg.created %s", sortOrder)
+
+	queryOptions := azcosmos.QueryOptions{
+		QueryParameters: []azcosmos.QueryParameter{
+			{Name: "@user_id", Value: userID},
+			{Name: "@project_id", Value: projectID},
+		},
+		PageSizeHint: int32(paginationOptions.PerPage),
+	}
+
+	pager := container.NewQueryItemsPager(query, partitionKey, &queryOptions)
+
+	projectGoals := []ProjectGoal{}
+	currentPage := 0
+
+	for pager.More() {
+
+		if currentPage == paginationOptions.Page {
+			response, err := pager.NextPage(ctx)
+			if err != nil {
+				return []ProjectGoal{}, err
+			}
+
+			for _, bytes := range response.Items {
+				projectGoal := ProjectGoal{}
+				err := json.Unmarshal(bytes, &projectGoal)
+				if err != nil {
+					return []ProjectGoal{}, err
+				}
+				projectGoals = append(projectGoals, projectGoal)
+			}
+
+			return projectGoals, nil
+
+		} else {
+			_, err := pager.NextPage(ctx)
+			if err != nil {
+				return []ProjectGoal{}, err
+			}
+			currentPage++
+		}
+
+	}
+
+	return projectGoals, nil
+
+}
+
+func (env *env) GetProjectDependentProjectGoals(ctx context.Context, userID string, projectID string) ([]Identifiable, error) {
+
+	env.logger.Info("Getting project dependent project goals")
+
+	container, err := env.client.NewContainer("projectgoals")
+	if err != nil {
+		return []Identifiable{}, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	query := "SELECT * FROM projectgoals pg WHERE pg.user_id = @user_id AND pg.project_id = @project_id"
+
+	queryOptions := azcosmos.QueryOptions{
+		QueryParameters: []azcosmos.QueryParameter{
+			{Name: "@user_id", Value: userID},
+			{Name: "@project_id", Value: projectID},
+		},
+	}
+
+	pager := container.NewQueryItemsPager(query, partitionKey, &queryOptions)
+
+	projectGoals := []ProjectGoal{}
+
+	for pager.More() {
+
+		response, err := pager.NextPage(ctx)
+		if err != nil {
+			return []Identifiable{}, err
+		}
+
+		for _, bytes := range response.Items {
+			projectGoal := ProjectGoal{}
+			err := json.Unmarshal(bytes, &projectGoal)
+			if err != nil {
+				return []Identifiable{}, err
+			}
+			projectGoals = append(projectGoals, projectGoal)
+		}
+
+	}
+
+	identifiables := []Identifiable{}
+
+	for _, pg := range projectGoals {
+		identifiables = append(identifiables, pg)
+	}
+
+	return identifiables, nil
+
+}
+
+func (env *env) GetAllProjectGoalsByProject(ctx context.Context, userID string, projectID string) ([]ProjectGoal, error) {
+
+	env.logger.Info("Getting all project goals by project")
+
+	container, err := env.client.NewContainer("projectgoals")
+	if err != nil {
+		return []ProjectGoal{}, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	query := "SELECT * FROM projectgoals pg WHERE pg.user_id = @user_id AND pg.project_id = @project_id"
+
+	queryOptions := azcosmos.QueryOptions{
+		QueryParameters: []azcosmos.QueryParameter{
+			{Name: "@user_id", Value: userID},
+			{Name: "@project_id", Value: projectID},
+		},
+	}
+
+	pager := container.NewQueryItemsPager(query, partitionKey, &queryOptions)
+
+	projectGoals := []ProjectGoal{}
+
+	for pager.More() {
+
+		response, err := pager.NextPage(ctx)
+		if err != nil {
+			return []ProjectGoal{}, err
+		}
+
+		for _, bytes := range response.Items {
+			projectGoal := ProjectGoal{}
+			err := json.Unmarshal(bytes, &projectGoal)
+			if err != nil {
+				return []ProjectGoal{}, err
+			}
+			projectGoals = append(projectGoals, projectGoal)
+		}
+
+	}
+
+	return projectGoals, nil
+
+}
+
+func (env *env) GetProjectGoalByID(ctx context.Context, userID string, projectGoalID string) (ProjectGoal, error) {
+
+	env.logger.Info("Getting project goal by ID")
+	projectGoal := ProjectGoal{}
+
+	container, err := env.client.NewContainer("projectgoals")
+	if err != nil {
+		return projectGoal, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	response, err := container.ReadItem(ctx, partitionKey, projectGoalID, nil)
+	if err != nil {
+		return projectGoal, err
+	}
+
+	err = json.Unmarshal(response.Value, &projectGoal)
+	if err != nil {
+		return projectGoal, err
+	}
+
+	return projectGoal, nil
+
+}
+
+func (env *env) UpsertProjectGoal(ctx context.Context, projectGoal ProjectGoal) (ProjectGoal, error) {
+
+	env.logger.Info("Upserting project goal")
+
+	container, err := env.client.NewContainer("projectgoals")
+	if err != nil {
+		return projectGoal, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(projectGoal.UserID)
+
+	marshalled, err := json.Marshal(projectGoal)
+	if err != nil {
+		return projectGoal, err
+	}
+
+	_, err = container.UpsertItem(ctx, partitionKey, marshalled, nil)
+	if err != nil {
+		return projectGoal, err
+	}
+
+	return projectGoal, nil
+
+}
+
+func (env *env) RemoveProjectGoal(ctx context.Context, userID string, projectGoalID string) (interface{}, error) {
+
+	env.logger.Info("Removing project goal")
+
+	container, err := env.client.NewContainer("projectgoals")
+	if err != nil {
+		return nil, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	response, err := container.DeleteItem(ctx, partitionKey, projectGoalID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+
+}
@@ -0,0 +1,146 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/data/azcosmos"
+)
+
+// a project has at most one reflection, which shares the project's ID
+type ProjectReflection struct {
+	ID            string `json:"id"`
+	WhatILearned  string `json:"what_i_learned"`
+	Challenges    string `json:"challenges"`
+	NextYearPlans string `json:"next_year_plans"`
+	ProjectID     string `json:"project_id"`
+	UserID        string `json:"user_id"`
+	GenericDatabaseInfo
+}
+
+func (pr ProjectReflection) GetID() string {
+	return pr.ID
+}
+
+func (env *env) GetProjectDependentProjectReflections(ctx context.Context, userID string, projectID string) ([]Identifiable, error) {
+
+	env.logger.Info("Getting project dependent project reflections")
+
+	container, err := env.client.NewContainer("projectreflections")
+	if err != nil {
+		return []Identifiable{}, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	query := "SELECT * FROM projectreflections pr WHERE pr.user_id = @user_id AND pr.project_id = @project_id"
+
+	queryOptions := azcosmos.QueryOptions{
+		QueryParameters: []azcosmos.QueryParameter{
+			{Name: "@user_id", Value: userID},
+			{Name: "@project_id", Value: projectID},
+		},
+	}
+
+	pager := container.NewQueryItemsPager(query, partitionKey, &queryOptions)
+
+	projectReflections := []ProjectReflection{}
+
+	for pager.More() {
+
+		response, err := pager.NextPage(ctx)
+		if err != nil {
+			return []Identifiable{}, err
+		}
+
+		for _, bytes := range response.Items {
+			projectReflection := ProjectReflection{}
+			err := json.Unmarshal(bytes, &projectReflection)
+			if err != nil {
+				return []Identifiable{}, err
+			}
+			projectReflections = append(projectReflections, projectReflection)
+		}
+
+	}
+
+	identifiables := []Identifiable{}
+
+	for _, pr := range projectReflections {
+		identifiables = append(identifiables, pr)
+	}
+
+	return identifiables, nil
+
+}
+
+func (env *env) GetProjectReflection(ctx context.Context, userID string, projectID string) (ProjectReflection, error) {
+
+	env.logger.Info("Getting project reflection")
+	projectReflection := ProjectReflection{}
+
+	container, err := env.client.NewContainer("projectreflections")
+	if err != nil {
+		return projectReflection, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	response, err := container.ReadItem(ctx, partitionKey, projectID, nil)
+	if err != nil {
+		return projectReflection, err
+	}
+
+	err = json.Unmarshal(response.Value, &projectReflection)
+	if err != nil {
+		return projectReflection, err
+	}
+
+	return projectReflection, nil
+
+}
+
+func (env *env) UpsertProjectReflection(ctx context.Context, projectReflection ProjectReflection) (ProjectReflection, error) {
+
+	env.logger.Info("Upserting project reflection")
+
+	container, err := env.client.NewContainer("projectreflections")
+	if err != nil {
+		return projectReflection, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(projectReflection.UserID)
+
+	marshalled, err := json.Marshal(projectReflection)
+	if err != nil {
+		return projectReflection, err
+	}
+
+	_, err = container.UpsertItem(ctx, partitionKey, marshalled, nil)
+	if err != nil {
+		return projectReflection, err
+	}
+
+	return projectReflection, nil
+
+}
+
+func (env *env) RemoveProjectReflection(ctx context.Context, userID string, projectReflectionID string) (interface{}, error) {
+
+	env.logger.Info("Removing project reflection")
+
+	container, err := env.client.NewContainer("projectreflections")
+	if err != nil {
+		return nil, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	response, err := container.DeleteItem(ctx, partitionKey, projectReflectionID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+
+}
@@ -4,24 +4,125 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"strconv"
-	"time"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/data/azcosmos"
 )
 
 type Project struct {
-	ID          string `json:"id"`
-	Year        string `json:"year"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Type        string `json:"type"`
-	StartDate   string `json:"start_date"`
-	EndDate     string `json:"end_date"`
-	UserID      string `json:"user_id"`
+	ID                string `json:"id"`
+	Year              string `json:"year"`
+	Name              string `json:"name"`
+	Description       string `json:"description"`
+	Type              string `json:"type"`
+	Kind              string `json:"kind"`
+	StartDate         string `json:"start_date"`
+	EndDate           string `json:"end_date"`
+	PreviousProjectID string `json:"previous_project_id"`
+	UserID            string `json:"user_id"`
 	GenericDatabaseInfo
 }
 
+const (
+	PROJECT_KIND_LIVESTOCK           = "livestock"
+	PROJECT_KIND_STILL_LIFE          = "still_life"
+	PROJECT_KIND_SCIENCE_ENGINEERING = "science_engineering"
+)
+
+const (
+	RECORD_PAGE_ANIMALS        = "animals"
+	RECORD_PAGE_FEEDS          = "feeds"
+	RECORD_PAGE_FEED_PURCHASES = "feed_purchases"
+	RECORD_PAGE_DAILY_FEEDS    = "daily_feeds"
+	RECORD_PAGE_EXPENSES       = "expenses"
+	RECORD_PAGE_SUPPLIES       = "supplies"
+	RECORD_PAGE_ACTIVITY_LOGS  = "activity_logs"
+	RECORD_PAGE_EXHIBITS       = "exhibits"
+	RECORD_PAGE_GOALS          = "goals"
+	RECORD_PAGE_BUDGET         = "budget"
+	RECORD_PAGE_REFLECTION     = "reflection"
+)
+
+type RecordPage struct {
+	Key   string `json:"key"`
+	Title string `json:"title"`
+}
+
+type ProjectKind struct {
+	Kind        string       `json:"kind"`
+	Title       string       `json:"title"`
+	RecordPages []RecordPage `json:"record_pages"`
+}
+
+// the record pages that make up a project's record book, by project kind
+var ProjectKinds = []ProjectKind{
+	{
+		Kind:  PROJECT_KIND_LIVESTOCK,
+		Title: "Livestock",
+		RecordPages: []RecordPage{
+			{Key: RECORD_PAGE_GOALS, Title: "Project Goals"},
+			{Key: RECORD_PAGE_BUDGET, Title: "Project Budget"},
+			{Key: RECORD_PAGE_ANIMALS, Title: "Animals"},
+			{Key: RECORD_PAGE_FEEDS, Title: "Feeds"},
+			{Key: RECORD_PAGE_FEED_PURCHASES, Title: "Feed Purchases"},
+			{Key: RECORD_PAGE_DAILY_FEEDS, Title: "Daily Feed Log"},
+			{Key: RECORD_PAGE_EXPENSES, Title: "Expenses"},
+			{Key: RECORD_PAGE_SUPPLIES, Title: "Supply Inventory"},
+			{Key: RECORD_PAGE_ACTIVITY_LOGS, Title: "Project Activity Log"},
+			{Key: RECORD_PAGE_EXHIBITS, Title: "Shows and Exhibits"},
+			{Key: RECORD_PAGE_REFLECTION, Title: "What I Learned"},
+		},
+	},
+	{
+		Kind:  PROJECT_KIND_STILL_LIFE,
+		Title: "Still Life / Exhibit",
+		RecordPages: []RecordPage{
+			{Key: RECORD_PAGE_GOALS, Title: "Project Goals"},
+			{Key: RECORD_PAGE_BUDGET, Title: "Project Budget"},
+			{Key: RECORD_PAGE_ACTIVITY_LOGS, Title: "Project Activity Log"},
+			{Key: RECORD_PAGE_EXHIBITS, Title: "Exhibits"},
+			{Key: RECORD_PAGE_EXPENSES, Title: "Expenses"},
+			{Key: RECORD_PAGE_SUPPLIES, Title: "Supply Inventory"},
+			{Key: RECORD_PAGE_REFLECTION, Title: "What I Learned"},
+		},
+	},
+	{
+		Kind:  PROJECT_KIND_SCIENCE_ENGINEERING,
+		Title: "Science / Engineering",
+		RecordPages: []RecordPage{
+			{Key: RECORD_PAGE_GOALS, Title: "Project Goals"},
+			{Key: RECORD_PAGE_BUDGET, Title: "Project Budget"},
+			{Key: RECORD_PAGE_ACTIVITY_LOGS, Title: "Build Log"},
+			{Key: RECORD_PAGE_EXHIBITS, Title: "Exhibits and Competitions"},
+			{Key: RECORD_PAGE_EXPENSES, Title: "Expenses"},
+			{Key: RECORD_PAGE_SUPPLIES, Title: "Parts and Supply Inventory"},
+			{Key: RECORD_PAGE_REFLECTION, Title: "What I Learned"},
+		},
+	},
+}
+
+// projects saved before kinds existed are livestock projects
+func (p Project) CurrentKind() string {
+	if p.Kind == "" {
+		return PROJECT_KIND_LIVESTOCK
+	}
+	return p.Kind
+}
+
+func (p Project) HasRecordPage(key string) bool {
+	for _, kind := range ProjectKinds {
+		if kind.Kind != p.CurrentKind() {
+			continue
+		}
+		for _, page := range kind.RecordPages {
+			if page.Key == key {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (env *env) GetProjectByID(ctx context.Context, userID string, projectID string) (Project, error) {
 
 	env.logger.Info("Getting project by ID")
@@ -48,9 +149,9 @@ func (env *env) GetProjectByID(ctx context.Context, userID string, projectID str
 
 }
 
-func (env *env) GetCurrentProjects(ctx context.Context, userID string, paginationOptions PaginationOptions) ([]Project, error) {
+func (env *env) GetProjectsByYear(ctx context.Context, userID string, year string, paginationOptions PaginationOptions) ([]Project, error) {
 
-	env.logger.Info("Getting current projects")
+	env.logger.Info("Getting projects by year")
 
 	container, err := env.client.NewContainer("projects")
 	if err != nil {
@@ -59,9 +160,6 @@ func (env *env) GetCurrentProjects(ctx context.Context, userID string, paginatio
 
 	partitionKey := azcosmos.NewPartitionKeyString(userID)
 
-	now := time.Now()
-	year := strconv.Itoa(now.Year())
-
 	sortOrder := "ASC"
 	if paginationOptions.SortByNewest {
 		sortOrder = "DESC"
@@ -115,6 +213,53 @@ func (env *env) GetCurrentProjects(ctx context.Context, userID string, paginatio
 
 }
 
+// returns the projects that were rolled over from the given project
+func (env *env) GetProjectsByPreviousProject(ctx context.Context, userID string, projectID string) ([]Project, error) {
+
+	env.logger.Info("Getting projects by previous project")
+
+	container, err := env.client.NewContainer("projects")
+	if err != nil {
+		return []Project{}, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	query := "SELECT * FROM projects p WHERE p.user_id = @user_id AND p.previous_project_id = @previous_project_id ORDER BY p.created ASC"
+
+	queryOptions := azcosmos.QueryOptions{
+		QueryParameters: []azcosmos.QueryParameter{
+			{Name: "@user_id", Value: userID},
+			{Name: "@previous_project_id", Value: projectID},
+		},
+	}
+
+	pager := container.NewQueryItemsPager(query, partitionKey, &queryOptions)
+
+	projects := []Project{}
+
+	for pager.More() {
+
+		response, err := pager.NextPage(ctx)
+		if err != nil {
+			return []Project{}, err
+		}
+
+		for _, bytes := range response.Items {
+			project := Project{}
+			err := json.Unmarshal(bytes, &project)
+			if err != nil {
+				return []Project{}, err
+			}
+			projects = append(projects, project)
+		}
+
+	}
+
+	return projects, nil
+
+}
+
 func (env *env) GetProjectsByUser(ctx context.Context, userID string, paginationOptions PaginationOptions) ([]Project, error) {
 
 	env.logger.Info("Getting projects")
@@ -235,3 +380,105 @@ func (env *env) RemoveProject(ctx context.Context, userID string, projectID stri
 	return response, nil
 
 }
+
+// the number of the user's projects in the program year of the given type, ignoring case
+func (env *env) CountProjectsByYearAndType(ctx context.Context, userID string, year string, projectType string) (int, error) {
+
+	env.logger.Info("Counting projects by year and type")
+
+	container, err := env.client.NewContainer("projects")
+	if err != nil {
+		return 0, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	query := "SELECT VALUE COUNT(1) FROM projects p WHERE p.user_id = @user_id AND p.year = @year AND LOWER(p.type) = @type"
+
+	queryOptions := azcosmos.QueryOptions{
+		QueryParameters: []azcosmos.QueryParameter{
+			{Name: "@user_id", Value: userID},
+			{Name: "@year", Value: year},
+			{Name: "@type", Value: strings.ToLower(projectType)},
+		},
+	}
+
+	pager := container.NewQueryItemsPager(query, partitionKey, &queryOptions)
+
+	total := 0
+
+	for pager.More() {
+		response, err := pager.NextPage(ctx)
+		if err != nil {
+			return 0, err
+		}
+
+		for _, bytes := range response.Items {
+			count := 0
+			err := json.Unmarshal(bytes, &count)
+			if err != nil {
+				return 0, err
+			}
+			total += count
+		}
+	}
+
+	return total, nil
+
+}
+
+// the containers counted by GetProjectRecordCounts, by record page
+var projectRecordContainers = map[string]string{
+	RECORD_PAGE_DAILY_FEEDS: "dailyfeeds",
+	RECORD_PAGE_EXPENSES:    "expenses",
+	RECORD_PAGE_SUPPLIES:    "supplies",
+}
+
+// the number of daily feeds, expenses and supplies a project has, by record page
+func (env *env) GetProjectRecordCounts(ctx context.Context, userID string, projectID string) (map[string]int, error) {
+
+	env.logger.Info("Getting project record counts")
+
+	counts := make(map[string]int)
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	queryOptions := azcosmos.QueryOptions{
+		QueryParameters: []azcosmos.QueryParameter{
+			{Name: "@user_id", Value: userID},
+			{Name: "@project_id", Value: projectID},
+		},
+	}
+
+	for recordPage, containerName := range projectRecordContainers {
+
+		container, err := env.client.NewContainer(containerName)
+		if err != nil {
+			return counts, err
+		}
+
+		query := "SELECT VALUE COUNT(1) FROM r WHERE r.user_id = @user_id AND r.project_id = @project_id"
+
+		pager := container.NewQueryItemsPager(query, partitionKey, &queryOptions)
+
+		for pager.More() {
+			response, err := pager.NextPage(ctx)
+			if err != nil {
+				return counts, err
+			}
+
+			for _, bytes := range response.Items {
+				count := 0
+				err := json.Unmarshal(bytes, &count)
+				if err != nil {
+					return counts, err
+				}
+				counts[recordPage] += count
+			}
+		}
+
+	}
+
+	return counts, nil
+
+}
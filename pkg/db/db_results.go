@@ -0,0 +1,107 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/data/azcosmos"
+)
+
+// a member's points for one of the rubric's criteria, averaged over their judges, and the judges' comments on it
+type ResultScore struct {
+	CriterionID string   `json:"criterion_id"`
+	Name        string   `json:"name"`
+	MaxPoints   float64  `json:"max_points"`
+	Points      float64  `json:"points"`
+	Comments    []string `json:"comments"`
+}
+
+// the published judging of a member's record book for one program year. its ID is the year, so that there is only
+// ever one per year. the placing is within the member's age division in the county, out of the number of entries
+type Result struct {
+	ID          string        `json:"id"`
+	UserID      string        `json:"user_id"`
+	Year        string        `json:"year"`
+	CountyName  string        `json:"county_name"`
+	RubricID    string        `json:"rubric_id"`
+	RubricName  string        `json:"rubric_name"`
+	Division    string        `json:"division"`
+	Total       float64       `json:"total"`
+	MaxTotal    float64       `json:"max_total"`
+	Percent     float64       `json:"percent"`
+	Placing     int           `json:"placing"`
+	Entries     int           `json:"entries"`
+	Scores      []ResultScore `json:"scores"`
+	Comments    []string      `json:"comments"`
+	PublishedBy string        `json:"published_by"`
+	PublishedAt string        `json:"published_at"`
+	GenericDatabaseInfo
+}
+
+func (env *env) GetResultsByUser(ctx context.Context, userID string) ([]Result, error) {
+
+	env.logger.Info("Getting results")
+
+	container, err := env.client.NewContainer("results")
+	if err != nil {
+		return []Result{}, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	query := "SELECT * FROM results r WHERE r.user_id = @user_id ORDER BY r.year ASC"
+
+	queryOptions := azcosmos.QueryOptions{
+		QueryParameters: []azcosmos.QueryParameter{
+			{Name: "@user_id", Value: userID},
+		},
+	}
+
+	pager := container.NewQueryItemsPager(query, partitionKey, &queryOptions)
+
+	results := []Result{}
+
+	for pager.More() {
+		response, err := pager.NextPage(ctx)
+		if err != nil {
+			return []Result{}, err
+		}
+
+		for _, bytes := range response.Items {
+			result := Result{}
+			err := json.Unmarshal(bytes, &result)
+			if err != nil {
+				return []Result{}, err
+			}
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+
+}
+
+func (env *env) UpsertResult(ctx context.Context, result Result) (Result, error) {
+
+	env.logger.Info("Upserting result")
+
+	container, err := env.client.NewContainer("results")
+	if err != nil {
+		return result, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(result.UserID)
+
+	marshalled, err := json.Marshal(result)
+	if err != nil {
+		return result, err
+	}
+
+	_, err = container.UpsertItem(ctx, partitionKey, marshalled, nil)
+	if err != nil {
+		return result, err
+	}
+
+	return result, nil
+
+}
@@ -34,88 +34,133 @@ type GenericSectionInfo struct {
 	GenericDatabaseInfo
 }
 
+// every entry of one resume section, oldest first, for a single program year or for every year when year is empty
+func getResumeSection[T any](ctx context.Context, env *env, userID string, section int, year string) ([]T, error) {
+
+	container, err := env.client.NewContainer("sections")
+	if err != nil {
+		return []T{}, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	query := "SELECT * FROM sections s WHERE s.user_id = @user_id AND s.section = @section ORDER BY s.created ASC"
+	parameters := []azcosmos.QueryParameter{
+		{Name: "@user_id", Value: userID},
+		{Name: "@section", Value: section},
+	}
+
+	if year != "" {
+		query = "SELECT * FROM sections s WHERE s.user_id = @user_id AND s.section = @section AND s.year = @year ORDER BY s.created ASC"
+		parameters = append(parameters, azcosmos.QueryParameter{Name: "@year", Value: year})
+	}
+
+	queryOptions := azcosmos.QueryOptions{
+		QueryParameters: parameters,
+	}
+
+	pager := container.NewQueryItemsPager(query, partitionKey, &queryOptions)
+
+	entries := []T{}
+
+	for pager.More() {
+		response, err := pager.NextPage(ctx)
+		if err != nil {
+			return []T{}, err
+		}
+
+		for _, bytes := range response.Items {
+			var entry T
+			err := json.Unmarshal(bytes, &entry)
+			if err != nil {
+				return []T{}, err
+			}
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, nil
+
+}
+
 /*******************************
 * ALL SECTIONS
 ********************************/
-func (env *env) GetResume(ctx context.Context, userID string) (Resume, error) {
+
+// returns the sections for a single program year, or for every year when year is empty
+func (env *env) GetResume(ctx context.Context, userID string, year string) (Resume, error) {
 
 	env.logger.Info("Getting resume")
 	resume := Resume{}
 
 	var err error
 
-	paginationOptions := PaginationOptions{
-		Page:         0,
-		PerPage:      500,
-		SortByNewest: false,
-	}
-
-	resume.Section1Data, err = env.GetSection1sByUser(ctx, userID, paginationOptions)
+	resume.Section1Data, err = getResumeSection[Section1](ctx, env, userID, 1, year)
 	if err != nil {
 		return resume, err
 	}
 
-	resume.Section2Data, err = env.GetSection2sByUser(ctx, userID, paginationOptions)
+	resume.Section2Data, err = getResumeSection[Section2](ctx, env, userID, 2, year)
 	if err != nil {
 		return resume, err
 	}
 
-	resume.Section3Data, err = env.GetSection3sByUser(ctx, userID, paginationOptions)
+	resume.Section3Data, err = getResumeSection[Section3](ctx, env, userID, 3, year)
 	if err != nil {
 		return resume, err
 	}
 
-	resume.Section4Data, err = env.GetSection4sByUser(ctx, userID, paginationOptions)
+	resume.Section4Data, err = getResumeSection[Section4](ctx, env, userID, 4, year)
 	if err != nil {
 		return resume, err
 	}
 
-	resume.Section5Data, err = env.GetSection5sByUser(ctx, userID, paginationOptions)
+	resume.Section5Data, err = getResumeSection[Section5](ctx, env, userID, 5, year)
 	if err != nil {
 		return resume, err
 	}
 
-	resume.Section6Data, err = env.GetSection6sByUser(ctx, userID, paginationOptions)
+	resume.Section6Data, err = getResumeSection[Section6](ctx, env, userID, 6, year)
 	if err != nil {
 		return resume, err
 	}
 
-	resume.Section7Data, err = env.GetSection7sByUser(ctx, userID, paginationOptions)
+	resume.Section7Data, err = getResumeSection[Section7](ctx, env, userID, 7, year)
 	if err != nil {
 		return resume, err
 	}
 
-	resume.Section8Data, err = env.GetSection8sByUser(ctx, userID, paginationOptions)
+	resume.Section8Data, err = getResumeSection[Section8](ctx, env, userID, 8, year)
 	if err != nil {
 		return resume, err
 	}
 
-	resume.Section9Data, err = env.GetSection9sByUser(ctx, userID, paginationOptions)
+	resume.Section9Data, err = getResumeSection[Section9](ctx, env, userID, 9, year)
 	if err != nil {
 		return resume, err
 	}
 
-	resume.Section10Data, err = env.GetSection10sByUser(ctx, userID, paginationOptions)
+	resume.Section10Data, err = getResumeSection[Section10](ctx, env, userID, 10, year)
 	if err != nil {
 		return resume, err
 	}
 
-	resume.Section11Data, err = env.GetSection11sByUser(ctx, userID, paginationOptions)
+	resume.Section11Data, err = getResumeSection[Section11](ctx, env, userID, 11, year)
 	if err != nil {
 		return resume, err
 	}
 
-	resume.Section12Data, err = env.GetSection12sByUser(ctx, userID, paginationOptions)
+	resume.Section12Data, err = getResumeSection[Section12](ctx, env, userID, 12, year)
 	if err != nil {
 		return resume, err
 	}
 
-	resume.Section13Data, err = env.GetSection13sByUser(ctx, userID, paginationOptions)
+	resume.Section13Data, err = getResumeSection[Section13](ctx, env, userID, 13, year)
 	if err != nil {
 		return resume, err
 	}
 
-	resume.Section14Data, err = env.GetSection14sByUser(ctx, userID, paginationOptions)
+	resume.Section14Data, err = getResumeSection[Section14](ctx, env, userID, 14, year)
 	if err != nil {
 		return resume, err
 	}
@@ -132,6 +177,7 @@ type Section1 struct {
 	ID               string `json:"id"`
 	Nickname         string `json:"nickname"`
 	Grade            int    `json:"grade"`
+	ClubID           string `json:"club_id"`
 	ClubName         string `json:"club_name"`
 	NumInClub        int    `json:"num_in_club"`
 	ClubLeader       string `json:"club_leader"`
@@ -2052,3 +2098,81 @@ func (env *env) RemoveSection(ctx context.Context, userID string, sectionID stri
 	return response, nil
 
 }
+
+// the section number and last update of each of a user's resume entries, without the entries themselves
+type SectionSummary struct {
+	ID      string `json:"id"`
+	Section int    `json:"section"`
+	Year    string `json:"year"`
+	Updated string `json:"updated"`
+}
+
+func (env *env) GetSectionSummariesByYear(ctx context.Context, userID string, year string) ([]SectionSummary, error) {
+
+	env.logger.Info("Getting section summaries by year")
+
+	container, err := env.client.NewContainer("sections")
+	if err != nil {
+		return []SectionSummary{}, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	query := "SELECT s.id, s.section, s.year, s.updated FROM sections s WHERE s.user_id = @user_id AND s.year = @year"
+
+	queryOptions := azcosmos.QueryOptions{
+		QueryParameters: []azcosmos.QueryParameter{
+			{Name: "@user_id", Value: userID},
+			{Name: "@year", Value: year},
+		},
+	}
+
+	pager := container.NewQueryItemsPager(query, partitionKey, &queryOptions)
+
+	summaries := []SectionSummary{}
+
+	for pager.More() {
+		response, err := pager.NextPage(ctx)
+		if err != nil {
+			return []SectionSummary{}, err
+		}
+
+		for _, bytes := range response.Items {
+			summary := SectionSummary{}
+			err := json.Unmarshal(bytes, &summary)
+			if err != nil {
+				return []SectionSummary{}, err
+			}
+			summaries = append(summaries, summary)
+		}
+	}
+
+	return summaries, nil
+
+}
+
+func (env *env) GetSectionSummaryByID(ctx context.Context, userID string, sectionID string) (SectionSummary, error) {
+
+	env.logger.Info("Getting section summary by ID")
+
+	container, err := env.client.NewContainer("sections")
+	if err != nil {
+		return SectionSummary{}, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	response, err := container.ReadItem(ctx, partitionKey, sectionID, nil)
+	if err != nil {
+		return SectionSummary{}, err
+	}
+
+	summary := SectionSummary{}
+	err = json.Unmarshal(response.Value, &summary)
+	if err != nil {
+		return SectionSummary{}, err
+	}
+
+	return summary, nil
+
+}
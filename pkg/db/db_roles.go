@@ -0,0 +1,106 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/data/azcosmos"
+)
+
+// a role granted to a user. agents are assigned to the county they work in
+type RoleAssignment struct {
+	ID         string `json:"id"`
+	UserID     string `json:"user_id"`
+	Role       string `json:"role"`
+	CountyName string `json:"county_name"`
+	GrantedBy  string `json:"granted_by"`
+	GenericDatabaseInfo
+}
+
+func (env *env) GetRoleAssignmentsByUser(ctx context.Context, userID string) ([]RoleAssignment, error) {
+
+	env.logger.Info("Getting role assignments")
+
+	container, err := env.client.NewContainer("roles")
+	if err != nil {
+		return []RoleAssignment{}, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	query := "SELECT * FROM roles r WHERE r.user_id = @user_id ORDER BY r.created ASC"
+
+	queryOptions := azcosmos.QueryOptions{
+		QueryParameters: []azcosmos.QueryParameter{
+			{Name: "@user_id", Value: userID},
+		},
+	}
+
+	pager := container.NewQueryItemsPager(query, partitionKey, &queryOptions)
+
+	assignments := []RoleAssignment{}
+
+	for pager.More() {
+		response, err := pager.NextPage(ctx)
+		if err != nil {
+			return []RoleAssignment{}, err
+		}
+
+		for _, bytes := range response.Items {
+			assignment := RoleAssignment{}
+			err := json.Unmarshal(bytes, &assignment)
+			if err != nil {
+				return []RoleAssignment{}, err
+			}
+			assignments = append(assignments, assignment)
+		}
+	}
+
+	return assignments, nil
+
+}
+
+func (env *env) UpsertRoleAssignment(ctx context.Context, assignment RoleAssignment) (RoleAssignment, error) {
+
+	env.logger.Info("Upserting role assignment")
+
+	container, err := env.client.NewContainer("roles")
+	if err != nil {
+		return assignment, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(assignment.UserID)
+
+	marshalled, err := json.Marshal(assignment)
+	if err != nil {
+		return assignment, err
+	}
+
+	_, err = container.UpsertItem(ctx, partitionKey, marshalled, nil)
+	if err != nil {
+		return assignment, err
+	}
+
+	return assignment, nil
+
+}
+
+func (env *env) RemoveRoleAssignment(ctx context.Context, userID string, assignmentID string) (interface{}, error) {
+
+	env.logger.Info("Removing role assignment")
+
+	container, err := env.client.NewContainer("roles")
+	if err != nil {
+		return nil, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	response, err := container.DeleteItem(ctx, partitionKey, assignmentID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+
+}
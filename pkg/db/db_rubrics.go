@@ -0,0 +1,165 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/data/azcosmos"
+)
+
+// one thing a rubric scores record books on, out of its maximum points
+type RubricCriterion struct {
+	ID          string  `json:"id"`
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	MaxPoints   float64 `json:"max_points"`
+}
+
+// how a county scores the record books submitted to it in a program year
+type Rubric struct {
+	ID         string            `json:"id"`
+	CountyName string            `json:"county_name"`
+	Year       string            `json:"year"`
+	Name       string            `json:"name"`
+	Criteria   []RubricCriterion `json:"criteria"`
+	CreatedBy  string            `json:"created_by"`
+	GenericDatabaseInfo
+}
+
+func (r Rubric) MaxTotal() float64 {
+	total := 0.0
+	for _, criterion := range r.Criteria {
+		total += criterion.MaxPoints
+	}
+	return total
+}
+
+func (r Rubric) Criterion(criterionID string) (RubricCriterion, bool) {
+	for _, criterion := range r.Criteria {
+		if criterion.ID == criterionID {
+			return criterion, true
+		}
+	}
+	return RubricCriterion{}, false
+}
+
+// the county's rubrics for a program year, or for every year when the year is empty
+func (env *env) GetRubricsByCounty(ctx context.Context, countyName string, year string) ([]Rubric, error) {
+
+	env.logger.Info("Getting rubrics by county")
+
+	container, err := env.client.NewContainer("rubrics")
+	if err != nil {
+		return []Rubric{}, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(countyName)
+
+	query := "SELECT * FROM rubrics r WHERE r.county_name = @county_name ORDER BY r.created ASC"
+	parameters := []azcosmos.QueryParameter{
+		{Name: "@county_name", Value: countyName},
+	}
+
+	if year != "" {
+		query = "SELECT * FROM rubrics r WHERE r.county_name = @county_name AND r.year = @year ORDER BY r.created ASC"
+		parameters = append(parameters, azcosmos.QueryParameter{Name: "@year", Value: year})
+	}
+
+	queryOptions := azcosmos.QueryOptions{
+		QueryParameters: parameters,
+	}
+
+	pager := container.NewQueryItemsPager(query, partitionKey, &queryOptions)
+
+	rubrics := []Rubric{}
+
+	for pager.More() {
+		response, err := pager.NextPage(ctx)
+		if err != nil {
+			return []Rubric{}, err
+		}
+
+		for _, bytes := range response.Items {
+			rubric := Rubric{}
+			err := json.Unmarshal(bytes, &rubric)
+			if err != nil {
+				return []Rubric{}, err
+			}
+			rubrics = append(rubrics, rubric)
+		}
+	}
+
+	return rubrics, nil
+
+}
+
+func (env *env) GetRubricByID(ctx context.Context, countyName string, rubricID string) (Rubric, error) {
+
+	env.logger.Info("Getting rubric by ID")
+
+	container, err := env.client.NewContainer("rubrics")
+	if err != nil {
+		return Rubric{}, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(countyName)
+
+	response, err := container.ReadItem(ctx, partitionKey, rubricID, nil)
+	if err != nil {
+		return Rubric{}, err
+	}
+
+	rubric := Rubric{}
+	err = json.Unmarshal(response.Value, &rubric)
+	if err != nil {
+		return Rubric{}, err
+	}
+
+	return rubric, nil
+
+}
+
+func (env *env) UpsertRubric(ctx context.Context, rubric Rubric) (Rubric, error) {
+
+	env.logger.Info("Upserting rubric")
+
+	container, err := env.client.NewContainer("rubrics")
+	if err != nil {
+		return rubric, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(rubric.CountyName)
+
+	marshalled, err := json.Marshal(rubric)
+	if err != nil {
+		return rubric, err
+	}
+
+	_, err = container.UpsertItem(ctx, partitionKey, marshalled, nil)
+	if err != nil {
+		return rubric, err
+	}
+
+	return rubric, nil
+
+}
+
+func (env *env) RemoveRubric(ctx context.Context, countyName string, rubricID string) (interface{}, error) {
+
+	env.logger.Info("Removing rubric")
+
+	container, err := env.client.NewContainer("rubrics")
+	if err != nil {
+		return nil, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(countyName)
+
+	response, err := container.DeleteItem(ctx, partitionKey, rubricID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+
+}
@@ -0,0 +1,178 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/data/azcosmos"
+)
+
+const (
+	SCORESHEET_STATUS_ASSIGNED = "assigned"
+	SCORESHEET_STATUS_SCORED   = "scored"
+)
+
+// a judge's points and comment for one of the rubric's criteria
+type CriterionScore struct {
+	CriterionID string  `json:"criterion_id"`
+	Points      float64 `json:"points"`
+	Comment     string  `json:"comment"`
+}
+
+// one judge's scoring of one member's record book. it is created when the judge is assigned to the record book and
+// scored by the judge afterwards. the member's age division is fixed when the judge is assigned
+type Scoresheet struct {
+	ID           string           `json:"id"`
+	CountyName   string           `json:"county_name"`
+	Year         string           `json:"year"`
+	RubricID     string           `json:"rubric_id"`
+	MemberID     string           `json:"member_id"`
+	MemberName   string           `json:"member_name"`
+	ClubID       string           `json:"club_id"`
+	ClubName     string           `json:"club_name"`
+	MembershipID string           `json:"membership_id"`
+	Division     string           `json:"division"`
+	JudgeID      string           `json:"judge_id"`
+	JudgeName    string           `json:"judge_name"`
+	AssignedBy   string           `json:"assigned_by"`
+	Status       string           `json:"status"`
+	Scores       []CriterionScore `json:"scores"`
+	Comment      string           `json:"comment"`
+	Total        float64          `json:"total"`
+	ScoredAt     string           `json:"scored_at"`
+	GenericDatabaseInfo
+}
+
+func (env *env) queryScoresheets(ctx context.Context, countyName string, query string, parameters []azcosmos.QueryParameter) ([]Scoresheet, error) {
+
+	container, err := env.client.NewContainer("scoresheets")
+	if err != nil {
+		return []Scoresheet{}, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(countyName)
+
+	queryOptions := azcosmos.QueryOptions{
+		QueryParameters: parameters,
+	}
+
+	pager := container.NewQueryItemsPager(query, partitionKey, &queryOptions)
+
+	scoresheets := []Scoresheet{}
+
+	for pager.More() {
+		response, err := pager.NextPage(ctx)
+		if err != nil {
+			return []Scoresheet{}, err
+		}
+
+		for _, bytes := range response.Items {
+			scoresheet := Scoresheet{}
+			err := json.Unmarshal(bytes, &scoresheet)
+			if err != nil {
+				return []Scoresheet{}, err
+			}
+			scoresheets = append(scoresheets, scoresheet)
+		}
+	}
+
+	return scoresheets, nil
+
+}
+
+// the county's scoresheets for a program year
+func (env *env) GetScoresheetsByCounty(ctx context.Context, countyName string, year string) ([]Scoresheet, error) {
+
+	env.logger.Info("Getting scoresheets by county")
+
+	query := "SELECT * FROM scoresheets s WHERE s.county_name = @county_name AND s.year = @year ORDER BY s.created ASC"
+	return env.queryScoresheets(ctx, countyName, query, []azcosmos.QueryParameter{
+		{Name: "@county_name", Value: countyName},
+		{Name: "@year", Value: year},
+	})
+
+}
+
+// the scoresheets the county has assigned to the judge, in every year
+func (env *env) GetScoresheetsByJudge(ctx context.Context, countyName string, judgeID string) ([]Scoresheet, error) {
+
+	env.logger.Info("Getting scoresheets by judge")
+
+	query := "SELECT * FROM scoresheets s WHERE s.county_name = @county_name AND s.judge_id = @judge_id ORDER BY s.created ASC"
+	return env.queryScoresheets(ctx, countyName, query, []azcosmos.QueryParameter{
+		{Name: "@county_name", Value: countyName},
+		{Name: "@judge_id", Value: judgeID},
+	})
+
+}
+
+func (env *env) GetScoresheetByID(ctx context.Context, countyName string, scoresheetID string) (Scoresheet, error) {
+
+	env.logger.Info("Getting scoresheet by ID")
+
+	container, err := env.client.NewContainer("scoresheets")
+	if err != nil {
+		return Scoresheet{}, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(countyName)
+
+	response, err := container.ReadItem(ctx, partitionKey, scoresheetID, nil)
+	if err != nil {
+		return Scoresheet{}, err
+	}
+
+	scoresheet := Scoresheet{}
+	err = json.Unmarshal(response.Value, &scoresheet)
+	if err != nil {
+		return Scoresheet{}, err
+	}
+
+	return scoresheet, nil
+
+}
+
+func (env *env) UpsertScoresheet(ctx context.Context, scoresheet Scoresheet) (Scoresheet, error) {
+
+	env.logger.Info("Upserting scoresheet")
+
+	container, err := env.client.NewContainer("scoresheets")
+	if err != nil {
+		return scoresheet, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(scoresheet.CountyName)
+
+	marshalled, err := json.Marshal(scoresheet)
+	if err != nil {
+		return scoresheet, err
+	}
+
+	_, err = container.UpsertItem(ctx, partitionKey, marshalled, nil)
+	if err != nil {
+		return scoresheet, err
+	}
+
+	return scoresheet, nil
+
+}
+
+func (env *env) RemoveScoresheet(ctx context.Context, countyName string, scoresheetID string) (interface{}, error) {
+
+	env.logger.Info("Removing scoresheet")
+
+	container, err := env.client.NewContainer("scoresheets")
+	if err != nil {
+		return nil, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(countyName)
+
+	response, err := container.DeleteItem(ctx, partitionKey, scoresheetID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+
+}
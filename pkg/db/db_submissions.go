@@ -0,0 +1,152 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/data/azcosmos"
+)
+
+const (
+	SUBMISSION_STATUS_DRAFT           = "draft"
+	SUBMISSION_STATUS_MEMBER_SIGNED   = "member_signed"
+	SUBMISSION_STATUS_PARENT_SIGNED   = "parent_signed"
+	SUBMISSION_STATUS_LEADER_APPROVED = "leader_approved"
+	SUBMISSION_STATUS_SUBMITTED       = "submitted"
+	SUBMISSION_STATUS_JUDGED          = "judged"
+)
+
+// allowed status changes, keyed by the current status. any signed record book can be reopened to a draft until it
+// has been judged
+var SubmissionStatusTransitions = map[string][]string{
+	SUBMISSION_STATUS_DRAFT:           {SUBMISSION_STATUS_MEMBER_SIGNED},
+	SUBMISSION_STATUS_MEMBER_SIGNED:   {SUBMISSION_STATUS_PARENT_SIGNED, SUBMISSION_STATUS_DRAFT},
+	SUBMISSION_STATUS_PARENT_SIGNED:   {SUBMISSION_STATUS_LEADER_APPROVED, SUBMISSION_STATUS_DRAFT},
+	SUBMISSION_STATUS_LEADER_APPROVED: {SUBMISSION_STATUS_SUBMITTED, SUBMISSION_STATUS_DRAFT},
+	SUBMISSION_STATUS_SUBMITTED:       {SUBMISSION_STATUS_JUDGED, SUBMISSION_STATUS_DRAFT},
+	SUBMISSION_STATUS_JUDGED:          {},
+}
+
+// one step of a record book through the sign-off workflow, and who took it
+type SubmissionEvent struct {
+	From      string `json:"from"`
+	To        string `json:"to"`
+	ActorID   string `json:"actor_id"`
+	ActorName string `json:"actor_name"`
+	ActorRole string `json:"actor_role"`
+	At        string `json:"at"`
+}
+
+// a member's record book for one program year. its ID is the year, so that there is only ever one per year
+type Submission struct {
+	ID      string            `json:"id"`
+	UserID  string            `json:"user_id"`
+	Year    string            `json:"year"`
+	Status  string            `json:"status"`
+	History []SubmissionEvent `json:"history"`
+	GenericDatabaseInfo
+}
+
+// submitted and judged record books can't be edited
+func (s Submission) IsLocked() bool {
+	return s.Status == SUBMISSION_STATUS_SUBMITTED || s.Status == SUBMISSION_STATUS_JUDGED
+}
+
+func (s Submission) CanMoveTo(status string) bool {
+	return slices.Contains(SubmissionStatusTransitions[s.Status], status)
+}
+
+func (env *env) GetSubmissionsByUser(ctx context.Context, userID string) ([]Submission, error) {
+
+	env.logger.Info("Getting submissions")
+
+	container, err := env.client.NewContainer("submissions")
+	if err != nil {
+		return []Submission{}, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	query := "SELECT * FROM submissions s WHERE s.user_id = @user_id ORDER BY s.year ASC"
+
+	queryOptions := azcosmos.QueryOptions{
+		QueryParameters: []azcosmos.QueryParameter{
+			{Name: "@user_id", Value: userID},
+		},
+	}
+
+	pager := container.NewQueryItemsPager(query, partitionKey, &queryOptions)
+
+	submissions := []Submission{}
+
+	for pager.More() {
+		response, err := pager.NextPage(ctx)
+		if err != nil {
+			return []Submission{}, err
+		}
+
+		for _, bytes := range response.Items {
+			submission := Submission{}
+			err := json.Unmarshal(bytes, &submission)
+			if err != nil {
+				return []Submission{}, err
+			}
+			submissions = append(submissions, submission)
+		}
+	}
+
+	return submissions, nil
+
+}
+
+func (env *env) GetSubmissionByYear(ctx context.Context, userID string, year string) (Submission, error) {
+
+	env.logger.Info("Getting submission by year")
+
+	container, err := env.client.NewContainer("submissions")
+	if err != nil {
+		return Submission{}, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	response, err := container.ReadItem(ctx, partitionKey, year, nil)
+	if err != nil {
+		return Submission{}, err
+	}
+
+	submission := Submission{}
+	err = json.Unmarshal(response.Value, &submission)
+	if err != nil {
+		return Submission{}, err
+	}
+
+	return submission, nil
+
+}
+
+func (env *env) UpsertSubmission(ctx context.Context, submission Submission) (Submission, error) {
+
+	env.logger.Info("Upserting submission")
+
+	container, err := env.client.NewContainer("submissions")
+	if err != nil {
+		return submission, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(submission.UserID)
+
+	marshalled, err := json.Marshal(submission)
+	if err != nil {
+		return submission, err
+	}
+
+	_, err = container.UpsertItem(ctx, partitionKey, marshalled, nil)
+	if err != nil {
+		return submission, err
+	}
+
+	return submission, nil
+
+}
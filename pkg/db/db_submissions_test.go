@@ -0,0 +1,66 @@
+package db
+
+import "testing"
+
+func TestSubmissionCanMoveTo(t *testing.T) {
+
+	tests := []struct {
+		from string
+		to   string
+		want bool
+	}{
+		{SUBMISSION_STATUS_DRAFT, SUBMISSION_STATUS_MEMBER_SIGNED, true},
+		{SUBMISSION_STATUS_DRAFT, SUBMISSION_STATUS_PARENT_SIGNED, false},
+		{SUBMISSION_STATUS_DRAFT, SUBMISSION_STATUS_SUBMITTED, false},
+		{SUBMISSION_STATUS_MEMBER_SIGNED, SUBMISSION_STATUS_PARENT_SIGNED, true},
+		{SUBMISSION_STATUS_MEMBER_SIGNED, SUBMISSION_STATUS_LEADER_APPROVED, false},
+		{SUBMISSION_STATUS_MEMBER_SIGNED, SUBMISSION_STATUS_DRAFT, true},
+		{SUBMISSION_STATUS_PARENT_SIGNED, SUBMISSION_STATUS_LEADER_APPROVED, true},
+		{SUBMISSION_STATUS_PARENT_SIGNED, SUBMISSION_STATUS_SUBMITTED, false},
+		{SUBMISSION_STATUS_PARENT_SIGNED, SUBMISSION_STATUS_DRAFT, true},
+		{SUBMISSION_STATUS_LEADER_APPROVED, SUBMISSION_STATUS_SUBMITTED, true},
+		{SUBMISSION_STATUS_LEADER_APPROVED, SUBMISSION_STATUS_JUDGED, false},
+		{SUBMISSION_STATUS_LEADER_APPROVED, SUBMISSION_STATUS_DRAFT, true},
+		{SUBMISSION_STATUS_SUBMITTED, SUBMISSION_STATUS_JUDGED, true},
+		{SUBMISSION_STATUS_SUBMITTED, SUBMISSION_STATUS_DRAFT, true},
+		{SUBMISSION_STATUS_SUBMITTED, SUBMISSION_STATUS_LEADER_APPROVED, false},
+		{SUBMISSION_STATUS_JUDGED, SUBMISSION_STATUS_DRAFT, false},
+		{SUBMISSION_STATUS_JUDGED, SUBMISSION_STATUS_SUBMITTED, false},
+		{"unknown", SUBMISSION_STATUS_MEMBER_SIGNED, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.from+" to "+test.to, func(t *testing.T) {
+			submission := Submission{Status: test.from}
+			if got := submission.CanMoveTo(test.to); got != test.want {
+				t.Errorf("CanMoveTo = %v, want %v", got, test.want)
+			}
+		})
+	}
+
+}
+
+func TestSubmissionIsLocked(t *testing.T) {
+
+	tests := []struct {
+		status string
+		want   bool
+	}{
+		{SUBMISSION_STATUS_DRAFT, false},
+		{SUBMISSION_STATUS_MEMBER_SIGNED, false},
+		{SUBMISSION_STATUS_PARENT_SIGNED, false},
+		{SUBMISSION_STATUS_LEADER_APPROVED, false},
+		{SUBMISSION_STATUS_SUBMITTED, true},
+		{SUBMISSION_STATUS_JUDGED, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.status, func(t *testing.T) {
+			submission := Submission{Status: test.status}
+			if got := submission.IsLocked(); got != test.want {
+				t.Errorf("IsLocked = %v, want %v", got, test.want)
+			}
+		})
+	}
+
+}
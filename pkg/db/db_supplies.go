@@ -1,6 +1,7 @@
 package db
 
 import (
+	"4h-recordbook-backend/internal/utils"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -9,12 +10,19 @@ import (
 )
 
 type Supply struct {
-	ID          string  `json:"id"`
-	Description string  `json:"description"`
-	StartValue  float64 `json:"start_value"`
-	EndValue    float64 `json:"end_value"`
-	ProjectID   string  `json:"project_id"`
-	UserID      string  `json:"user_id"`
+	ID                 string      `json:"id"`
+	Description        string      `json:"description"`
+	StartValue         utils.Money `json:"start_value"`
+	EndValue           utils.Money `json:"end_value"`
+	AcquisitionDate    string      `json:"acquisition_date"`
+	Cost               utils.Money `json:"cost"`
+	SalvageValue       utils.Money `json:"salvage_value"`
+	UsefulLifeYears    int         `json:"useful_life_years"`
+	DepreciationMethod string      `json:"depreciation_method"`
+	Category           string      `json:"category"`
+	OtherCategory      string      `json:"other_category"`
+	ProjectID          string      `json:"project_id"`
+	UserID             string      `json:"user_id"`
 	GenericDatabaseInfo
 }
 
@@ -22,6 +30,16 @@ func (s Supply) GetID() string {
 	return s.ID
 }
 
+const (
+	DEPRECIATION_METHOD_NONE          = "none"
+	DEPRECIATION_METHOD_STRAIGHT_LINE = "straight_line"
+)
+
+// supplies saved before depreciation existed have no method and keep the start and end values that were entered
+func (s Supply) Depreciates() bool {
+	return s.DepreciationMethod == DEPRECIATION_METHOD_STRAIGHT_LINE && s.UsefulLifeYears > 0
+}
+
 func (env *env) GetSuppliesByProject(ctx context.Context, userID string, projectID string, paginationOptions PaginationOptions) ([]Supply, error) {
 
 	env.logger.Info("Getting supplies by project")
@@ -86,6 +104,52 @@ func (env *env) GetSuppliesByProject(ctx context.Context, userID string, project
 
 }
 
+func (env *env) GetAllSuppliesByProject(ctx context.Context, userID string, projectID string) ([]Supply, error) {
+
+	env.logger.Info("Getting all supplies by project")
+
+	container, err := env.client.NewContainer("supplies")
+	if err != nil {
+		return []Supply{}, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	query := "SELECT * FROM supplies s WHERE s.user_id = @user_id AND s.project_id = @project_id"
+
+	queryOptions := azcosmos.QueryOptions{
+		QueryParameters: []azcosmos.QueryParameter{
+			{Name: "@user_id", Value: userID},
+			{Name: "@project_id", Value: projectID},
+		},
+	}
+
+	pager := container.NewQueryItemsPager(query, partitionKey, &queryOptions)
+
+	supplies := []Supply{}
+
+	for pager.More() {
+
+		response, err := pager.NextPage(ctx)
+		if err != nil {
+			return []Supply{}, err
+		}
+
+		for _, bytes := range response.Items {
+			supply := Supply{}
+			err := json.Unmarshal(bytes, &supply)
+			if err != nil {
+				return []Supply{}, err
+			}
+			supplies = append(supplies, supply)
+		}
+
+	}
+
+	return supplies, nil
+
+}
+
 func (env *env) GetProjectDependentSupplies(ctx context.Context, userID string, projectID string) ([]Identifiable, error) {
 
 	env.logger.Info("Getting project dependent supplies")
@@ -189,6 +253,29 @@ func (env *env) UpsertSupply(ctx context.Context, supply Supply) (Supply, error)
 
 }
 
+func (env *env) UpsertSupplyBatch(ctx context.Context, userID string, supplies []Supply) ([]int, error) {
+
+	env.logger.Info("Upserting supply batch")
+
+	items := [][]byte{}
+
+	for _, supply := range supplies {
+		if supply.UserID != userID {
+			return []int{}, fmt.Errorf("supply %s does not belong to the batch partition", supply.ID)
+		}
+
+		marshalled, err := json.Marshal(supply)
+		if err != nil {
+			return []int{}, err
+		}
+
+		items = append(items, marshalled)
+	}
+
+	return env.upsertBatch(ctx, "supplies", userID, items)
+
+}
+
 func (env *env) RemoveSupply(ctx context.Context, userID string, supplyID string) (interface{}, error) {
 
 	env.logger.Info("Removing supply")
@@ -15,6 +15,8 @@ type User struct {
 	MiddleNameInitial string `json:"middle_name_initial"`
 	LastNameInitial   string `json:"last_name_initial"`
 	CountyName        string `json:"county_name"`
+	UnitSystem        string `json:"unit_system"`
+	TimeZone          string `json:"time_zone"`
 	GenericDatabaseInfo
 }
 
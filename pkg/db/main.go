@@ -2,7 +2,10 @@ package db
 
 import (
 	"4h-recordbook-backend/internal/config"
+	"4h-recordbook-backend/internal/utils"
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/data/azcosmos"
 	"github.com/go-playground/validator/v10"
@@ -17,11 +20,12 @@ type Db interface {
 	AddBookmark(context.Context, Bookmark) (Bookmark, error)
 	RemoveBookmark(context.Context, string, string) (interface{}, error)
 	GetProjectByID(context.Context, string, string) (Project, error)
-	GetCurrentProjects(context.Context, string, PaginationOptions) ([]Project, error)
+	GetProjectsByYear(context.Context, string, string, PaginationOptions) ([]Project, error)
 	GetProjectsByUser(context.Context, string, PaginationOptions) ([]Project, error)
+	GetProjectsByPreviousProject(context.Context, string, string) ([]Project, error)
 	UpsertProject(context.Context, Project) (Project, error)
 	RemoveProject(context.Context, string, string) (interface{}, error)
-	GetResume(context.Context, string) (Resume, error)
+	GetResume(context.Context, string, string) (Resume, error)
 	GetSection1ByID(context.Context, string, string) (Section1, error)
 	GetSection2ByID(context.Context, string, string) (Section2, error)
 	GetSection3ByID(context.Context, string, string) (Section3, error)
@@ -75,17 +79,22 @@ type Db interface {
 	GetSectionDependentEventSections(context.Context, string, string) ([]Identifiable, error)
 	UpsertEventSection(context.Context, EventSection) (EventSection, error)
 	RemoveEventSection(context.Context, string, string) (interface{}, error)
-	GetAnimalsByProject(context.Context, string, string, PaginationOptions) ([]Animal, error)
+	GetAnimalsByProject(context.Context, string, string, string, PaginationOptions) ([]Animal, error)
+	GetAllAnimalsByProject(context.Context, string, string) ([]Animal, error)
 	GetProjectDependentAnimals(context.Context, string, string) ([]Identifiable, error)
 	GetAnimalByID(context.Context, string, string) (Animal, error)
+	GetAnimalsByIdentifier(context.Context, string, string, string) ([]Animal, error)
 	UpsertAnimal(context.Context, Animal) (Animal, error)
 	RemoveAnimal(context.Context, string, string) (interface{}, error)
 	GetFeedsByProject(context.Context, string, string, PaginationOptions) ([]Feed, error)
+	GetAllFeedsByProject(context.Context, string, string) ([]Feed, error)
 	GetProjectDependentFeeds(context.Context, string, string) ([]Identifiable, error)
 	GetFeedByID(context.Context, string, string) (Feed, error)
 	UpsertFeed(context.Context, Feed) (Feed, error)
+	UpsertFeedBatch(context.Context, string, []Feed) ([]int, error)
 	RemoveFeed(context.Context, string, string) (interface{}, error)
 	GetFeedPurchasesByProject(context.Context, string, string, PaginationOptions) ([]FeedPurchase, error)
+	GetAllFeedPurchasesByProject(context.Context, string, string) ([]FeedPurchase, error)
 	GetFeedDependentFeedPurchases(context.Context, string, string) ([]Identifiable, error)
 	GetFeedPurchaseByID(context.Context, string, string) (FeedPurchase, error)
 	UpsertFeedPurchase(context.Context, FeedPurchase) (FeedPurchase, error)
@@ -95,19 +104,123 @@ type Db interface {
 	GetFeedDependentDailyFeeds(context.Context, string, string) ([]Identifiable, error)
 	GetDailyFeedByID(context.Context, string, string) (DailyFeed, error)
 	UpsertDailyFeed(context.Context, DailyFeed) (DailyFeed, error)
+	UpsertDailyFeedBatch(context.Context, string, []DailyFeed) ([]int, error)
 	RemoveDailyFeed(context.Context, string, string) (interface{}, error)
 	GetExpensesByProject(context.Context, string, string, PaginationOptions) ([]Expense, error)
+	GetAllExpensesByProject(context.Context, string, string) ([]Expense, error)
+	GetOtherExpenseCategoriesByUser(context.Context, string) ([]string, error)
 	GetProjectDependentExpenses(context.Context, string, string) ([]Identifiable, error)
 	GetExpenseByID(context.Context, string, string) (Expense, error)
 	UpsertExpense(context.Context, Expense) (Expense, error)
 	RemoveExpense(context.Context, string, string) (interface{}, error)
 	GetSuppliesByProject(context.Context, string, string, PaginationOptions) ([]Supply, error)
+	GetAllSuppliesByProject(context.Context, string, string) ([]Supply, error)
 	GetProjectDependentSupplies(context.Context, string, string) ([]Identifiable, error)
 	GetSupplyByID(context.Context, string, string) (Supply, error)
 	UpsertSupply(context.Context, Supply) (Supply, error)
+	UpsertSupplyBatch(context.Context, string, []Supply) ([]int, error)
 	RemoveSupply(context.Context, string, string) (interface{}, error)
+	GetHealthRecordsByAnimal(context.Context, string, string, PaginationOptions) ([]HealthRecord, error)
+	GetWithdrawalHealthRecordsByProject(context.Context, string, string) ([]HealthRecord, error)
+	GetAnimalDependentHealthRecords(context.Context, string, string) ([]Identifiable, error)
+	GetHealthRecordByID(context.Context, string, string) (HealthRecord, error)
+	UpsertHealthRecord(context.Context, HealthRecord) (HealthRecord, error)
+	RemoveHealthRecord(context.Context, string, string) (interface{}, error)
+	UpsertAnimalBatch(context.Context, string, []Animal) ([]int, error)
+	GetBreedingsByDam(context.Context, string, string, PaginationOptions) ([]Breeding, error)
+	GetAnimalDependentBreedings(context.Context, string, string) ([]Identifiable, error)
+	GetBreedingByID(context.Context, string, string) (Breeding, error)
+	UpsertBreeding(context.Context, Breeding) (Breeding, error)
+	RemoveBreeding(context.Context, string, string) (interface{}, error)
+	GetBirthsByDam(context.Context, string, string, PaginationOptions) ([]Birth, error)
+	GetAnimalDependentBirths(context.Context, string, string) ([]Identifiable, error)
+	GetBreedingDependentBirths(context.Context, string, string) ([]Identifiable, error)
+	GetBirthByID(context.Context, string, string) (Birth, error)
+	UpsertBirth(context.Context, Birth) (Birth, error)
+	RemoveBirth(context.Context, string, string) (interface{}, error)
+	GetActivityLogsByProject(context.Context, string, string, PaginationOptions) ([]ActivityLog, error)
+	GetProjectDependentActivityLogs(context.Context, string, string) ([]Identifiable, error)
+	GetActivityLogByID(context.Context, string, string) (ActivityLog, error)
+	UpsertActivityLog(context.Context, ActivityLog) (ActivityLog, error)
+	RemoveActivityLog(context.Context, string, string) (interface{}, error)
+	GetExhibitsByProject(context.Context, string, string, PaginationOptions) ([]Exhibit, error)
+	GetProjectDependentExhibits(context.Context, string, string) ([]Identifiable, error)
+	GetExhibitByID(context.Context, string, string) (Exhibit, error)
+	UpsertExhibit(context.Context, Exhibit) (Exhibit, error)
+	RemoveExhibit(context.Context, string, string) (interface{}, error)
+	GetProjectGoalsByProject(context.Context, string, string, PaginationOptions) ([]ProjectGoal, error)
+	GetAllProjectGoalsByProject(context.Context, string, string) ([]ProjectGoal, error)
+	GetProjectDependentProjectGoals(context.Context, string, string) ([]Identifiable, error)
+	GetProjectGoalByID(context.Context, string, string) (ProjectGoal, error)
+	UpsertProjectGoal(context.Context, ProjectGoal) (ProjectGoal, error)
+	RemoveProjectGoal(context.Context, string, string) (interface{}, error)
+	GetProjectDependentProjectReflections(context.Context, string, string) ([]Identifiable, error)
+	GetProjectReflection(context.Context, string, string) (ProjectReflection, error)
+	UpsertProjectReflection(context.Context, ProjectReflection) (ProjectReflection, error)
+	RemoveProjectReflection(context.Context, string, string) (interface{}, error)
+	GetProjectBudgetItemsByProject(context.Context, string, string, PaginationOptions) ([]ProjectBudgetItem, error)
+	GetAllProjectBudgetItemsByProject(context.Context, string, string) ([]ProjectBudgetItem, error)
+	GetProjectDependentProjectBudgetItems(context.Context, string, string) ([]Identifiable, error)
+	GetProjectBudgetItemByID(context.Context, string, string) (ProjectBudgetItem, error)
+	UpsertProjectBudgetItem(context.Context, ProjectBudgetItem) (ProjectBudgetItem, error)
+	RemoveProjectBudgetItem(context.Context, string, string) (interface{}, error)
+	GetRoleAssignmentsByUser(context.Context, string) ([]RoleAssignment, error)
+	UpsertRoleAssignment(context.Context, RoleAssignment) (RoleAssignment, error)
+	RemoveRoleAssignment(context.Context, string, string) (interface{}, error)
+	GetGuardianLinksByUser(context.Context, string) ([]GuardianLink, error)
+	GetGuardianLinkByID(context.Context, string, string) (GuardianLink, error)
+	GetAcceptedGuardianLink(context.Context, string, string) (GuardianLink, error)
+	UpsertGuardianLink(context.Context, GuardianLink) (GuardianLink, error)
+	RemoveGuardianLink(context.Context, string, string) (interface{}, error)
+	GetAuditEntriesByUser(context.Context, string, PaginationOptions) ([]AuditEntry, error)
+	AddAuditEntry(context.Context, AuditEntry) (AuditEntry, error)
+	GetClubByID(context.Context, string) (Club, error)
+	UpsertClub(context.Context, Club) (Club, error)
+	GetClubCode(context.Context, string) (ClubCode, error)
+	AddClubCode(context.Context, ClubCode) (ClubCode, error)
+	RemoveClubCode(context.Context, string) (interface{}, error)
+	GetMembershipsByClub(context.Context, string, string) ([]Membership, error)
+	GetMembershipsByUser(context.Context, string) ([]Membership, error)
+	GetMembershipByID(context.Context, string, string) (Membership, error)
+	GetUserMembershipByID(context.Context, string, string) (Membership, error)
+	UpsertMembership(context.Context, Membership) (Membership, error)
+	RemoveMembership(context.Context, Membership) (interface{}, error)
+	GetSectionSummariesByYear(context.Context, string, string) ([]SectionSummary, error)
+	GetProjectRecordCounts(context.Context, string, string) (map[string]int, error)
+	CountProjectsByYearAndType(context.Context, string, string, string) (int, error)
+	GetSectionSummaryByID(context.Context, string, string) (SectionSummary, error)
+	GetSubmissionsByUser(context.Context, string) ([]Submission, error)
+	GetSubmissionByYear(context.Context, string, string) (Submission, error)
+	UpsertSubmission(context.Context, Submission) (Submission, error)
+	GetRubricsByCounty(context.Context, string, string) ([]Rubric, error)
+	GetRubricByID(context.Context, string, string) (Rubric, error)
+	UpsertRubric(context.Context, Rubric) (Rubric, error)
+	RemoveRubric(context.Context, string, string) (interface{}, error)
+	GetScoresheetsByCounty(context.Context, string, string) ([]Scoresheet, error)
+	GetScoresheetsByJudge(context.Context, string, string) ([]Scoresheet, error)
+	GetScoresheetByID(context.Context, string, string) (Scoresheet, error)
+	UpsertScoresheet(context.Context, Scoresheet) (Scoresheet, error)
+	RemoveScoresheet(context.Context, string, string) (interface{}, error)
+	GetResultsByUser(context.Context, string) ([]Result, error)
+	UpsertResult(context.Context, Result) (Result, error)
+	GetCommentsByUser(context.Context, string, string, string) ([]Comment, error)
+	GetCommentByID(context.Context, string, string) (Comment, error)
+	UpsertComment(context.Context, Comment) (Comment, error)
+	RemoveComment(context.Context, string, string) (interface{}, error)
+	GetProjectDependentComments(context.Context, string, string) ([]Identifiable, error)
+	GetAnimalDependentComments(context.Context, string, string) ([]Identifiable, error)
+	GetExpenseDependentComments(context.Context, string, string) ([]Identifiable, error)
+	GetSectionDependentComments(context.Context, string, string) ([]Identifiable, error)
+	GetCommentReadsByUser(context.Context, string, string) ([]CommentRead, error)
+	UpsertCommentRead(context.Context, CommentRead) (CommentRead, error)
+	MigrateMoney(context.Context, string) (int, error)
+	MigrateDates(context.Context, string, *time.Location) (int, error)
+	MigrateYears(context.Context, string, utils.ProgramYear) (int, error)
 }
 
+// cosmos limits a transactional batch to 100 operations
+const MAX_BATCH_SIZE = 100
+
 type Identifiable interface {
 	GetID() string
 }
@@ -135,6 +248,41 @@ type env struct {
 	dependentsMap map[string][]Dependent
 }
 
+// writes every item in a single transactional batch within the user's partition. the returned status codes are in
+// the same order as the items, and if any of them is not a 2xx code then none of the items were written
+func (env *env) upsertBatch(ctx context.Context, containerName string, userID string, items [][]byte) ([]int, error) {
+
+	if len(items) == 0 || len(items) > MAX_BATCH_SIZE {
+		return []int{}, fmt.Errorf("batch must contain between 1 and %d items", MAX_BATCH_SIZE)
+	}
+
+	container, err := env.client.NewContainer(containerName)
+	if err != nil {
+		return []int{}, err
+	}
+
+	partitionKey := azcosmos.NewPartitionKeyString(userID)
+
+	batch := container.NewTransactionalBatch(partitionKey)
+
+	for _, item := range items {
+		batch.UpsertItem(item, nil)
+	}
+
+	response, err := container.ExecuteTransactionalBatch(ctx, batch, nil)
+	if err != nil {
+		return []int{}, err
+	}
+
+	statusCodes := []int{}
+	for _, result := range response.OperationResults {
+		statusCodes = append(statusCodes, int(result.StatusCode))
+	}
+
+	return statusCodes, nil
+
+}
+
 func New(logger *zap.SugaredLogger, cfg *config.Config) (Db, error) {
 
 	logger.Info("Creating new database client")
@@ -169,6 +317,28 @@ func New(logger *zap.SugaredLogger, cfg *config.Config) (Db, error) {
 			GetRelated: e.GetAnimalDependentDailyFeeds,
 			Delete:     e.RemoveDailyFeed,
 		},
+		{
+			GetRelated: e.GetAnimalDependentHealthRecords,
+			Delete:     e.RemoveHealthRecord,
+		},
+		{
+			GetRelated: e.GetAnimalDependentBreedings,
+			Delete:     e.RemoveBreeding,
+		},
+		{
+			GetRelated: e.GetAnimalDependentBirths,
+			Delete:     e.RemoveBirth,
+		},
+		{
+			GetRelated: e.GetAnimalDependentComments,
+			Delete:     e.RemoveComment,
+		},
+	}
+	dependentsMap["breedings"] = []Dependent{
+		{
+			GetRelated: e.GetBreedingDependentBirths,
+			Delete:     e.RemoveBirth,
+		},
 	}
 	dependentsMap["feeds"] = []Dependent{
 		{
@@ -197,12 +367,46 @@ func New(logger *zap.SugaredLogger, cfg *config.Config) (Db, error) {
 			GetRelated: e.GetProjectDependentSupplies,
 			Delete:     e.RemoveSupply,
 		},
+		{
+			GetRelated: e.GetProjectDependentActivityLogs,
+			Delete:     e.RemoveActivityLog,
+		},
+		{
+			GetRelated: e.GetProjectDependentExhibits,
+			Delete:     e.RemoveExhibit,
+		},
+		{
+			GetRelated: e.GetProjectDependentProjectGoals,
+			Delete:     e.RemoveProjectGoal,
+		},
+		{
+			GetRelated: e.GetProjectDependentProjectReflections,
+			Delete:     e.RemoveProjectReflection,
+		},
+		{
+			GetRelated: e.GetProjectDependentProjectBudgetItems,
+			Delete:     e.RemoveProjectBudgetItem,
+		},
+		{
+			GetRelated: e.GetProjectDependentComments,
+			Delete:     e.RemoveComment,
+		},
+	}
+	dependentsMap["expenses"] = []Dependent{
+		{
+			GetRelated: e.GetExpenseDependentComments,
+			Delete:     e.RemoveComment,
+		},
 	}
 	dependentsMap["sections"] = []Dependent{
 		{
 			GetRelated: e.GetSectionDependentEventSections,
 			Delete:     e.RemoveEventSection,
 		},
+		{
+			GetRelated: e.GetSectionDependentComments,
+			Delete:     e.RemoveComment,
+		},
 	}
 	dependentsMap["events"] = []Dependent{
 		{